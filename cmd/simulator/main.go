@@ -0,0 +1,69 @@
+// Command simulator serves the core, datahub and scoreezy endpoints the
+// front office uses, for local development without external services.
+// Point FO_CORE_HOST, FO_DATAHUB_HOST and FO_SCOREEZY_HOST at it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"front-office/internal/simulator"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	addr := flag.String("addr", ":3010", "address to listen on")
+	scenarioPath := flag.String("scenario", "", "optional YAML or JSON scenario with latency, error rates and fixtures per product slug")
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
+
+	scenario, err := simulator.LoadScenario(*scenarioPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load scenario")
+	}
+
+	sim, err := simulator.New(scenario)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid scenario")
+	}
+
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sim.ServeHTTP(w, r)
+			log.Info().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Dur("duration", time.Since(start)).
+				Msg("simulated")
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Str("addr", *addr).Msg("simulator listening")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("simulator stopped")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to shutdown simulator")
+	}
+}
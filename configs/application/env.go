@@ -39,6 +39,14 @@ type Environment struct {
 	GenretailV3                    string
 	AllowingDomains                string
 	RedisAddr                      string
+	PasswordMinLength              string
+	PasswordMaxAgeDays             string
+	PasswordHistoryDepth           string
+	PasswordExpiryWarningDays      string
+	SSORedirectURL                 string
+	UsageSheetDefsPath             string
+	PartnerStubHost                string
+	PartnerStubProducts            string
 }
 
 func GetEnvironment(key string) string {
@@ -87,9 +95,35 @@ func LoadEnvironment() *Environment {
 		ScoreezyHost:                   GetEnvironment("FO_SCOREEZY_HOST"),
 		CoreModuleKey:                  GetEnvironment("FO_CORE_KEY"),
 		RedisAddr:                      GetEnvironment("FO_REDIS_URL"),
+		PasswordMinLength:              GetEnvironment("FO_PASSWORD_MIN_LENGTH"),
+		PasswordMaxAgeDays:             GetEnvironment("FO_PASSWORD_MAX_AGE_DAYS"),
+		PasswordHistoryDepth:           GetEnvironment("FO_PASSWORD_HISTORY_DEPTH"),
+		PasswordExpiryWarningDays:      GetEnvironment("FO_PASSWORD_EXPIRY_WARNING_DAYS"),
+		SSORedirectURL:                 GetEnvironment("FO_SSO_REDIRECT_URL"),
+		UsageSheetDefsPath:             GetEnvironment("FO_USAGE_SHEET_DEFS"),
+		PartnerStubHost:                GetEnvironment("FO_PARTNER_STUB_HOST"),
+		PartnerStubProducts:            GetEnvironment("FO_PARTNER_STUB_PRODUCTS"),
 	}
 }
 
+// PartnerHost returns the host a datahub product calls its partner on. The
+// products listed in FO_PARTNER_STUB_PRODUCTS, or every product with "*",
+// call the stub server on FO_PARTNER_STUB_HOST instead of the datahub.
+func (e *Environment) PartnerHost(route string) string {
+	if strings.TrimSpace(e.PartnerStubHost) == "" {
+		return e.ProductCatalogHost
+	}
+
+	for _, stubbed := range strings.Split(e.PartnerStubProducts, ",") {
+		stubbed = strings.TrimSpace(stubbed)
+		if stubbed == "*" || stubbed == route {
+			return e.PartnerStubHost
+		}
+	}
+
+	return e.ProductCatalogHost
+}
+
 func (e *Environment) Validate() error {
 	var missing []string
 
@@ -47,7 +47,7 @@ func (s *fiberServer) Start() error {
 		AllowOrigins:     s.Cfg.App.FrontendBaseUrl,
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		ExposeHeaders:    "Set-Cookie",
+		ExposeHeaders:    "Set-Cookie,X-Impersonated-By,X-Impersonation-Expires-At,X-Workbook-Password-Id",
 	}))
 
 	s.App.Use(func(c *fiber.Ctx) error {
@@ -0,0 +1,119 @@
+package apiclient
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(service Service) Controller {
+	return &controller{svc: service}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	CreateClient(c *fiber.Ctx) error
+	GetClients(c *fiber.Ctx) error
+	UpdateClient(c *fiber.Ctx) error
+	RotateSecret(c *fiber.Ctx) error
+	RevokeClient(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) CreateClient(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*createAPIClientRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.CreateClient(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(helper.SuccessResponse(
+		"succeed to create api client, store the secret now as it will not be shown again",
+		result,
+	))
+}
+
+func (ctrl *controller) GetClients(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.GetClients(authCtx.CompanyIdStr())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get api clients",
+		result,
+	))
+}
+
+func (ctrl *controller) UpdateClient(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*updateAPIClientRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.UpdateClient(authCtx, c.Params("id"), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to update api client",
+		result,
+	))
+}
+
+func (ctrl *controller) RotateSecret(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.RotateSecret(authCtx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to rotate api client secret, store the secret now as it will not be shown again",
+		result,
+	))
+}
+
+func (ctrl *controller) RevokeClient(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	if err := ctrl.svc.RevokeClient(authCtx, c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+		"succeed to revoke api client",
+		nil,
+	))
+}
@@ -0,0 +1,28 @@
+package apiclient
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) Service {
+	repo := NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	service := NewService(repo, memberRepo, operationRepo)
+	controller := NewController(service)
+
+	apiGroup.Get("/", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetClients)
+	apiGroup.Post("/", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(createAPIClientRequest{}), controller.CreateClient)
+	apiGroup.Put("/:id", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(updateAPIClientRequest{}), controller.UpdateClient)
+	apiGroup.Post("/:id/rotate-secret", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.RotateSecret)
+	apiGroup.Delete("/:id", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.RevokeClient)
+
+	// returned so the product routes can authenticate api clients
+	return service
+}
@@ -0,0 +1,77 @@
+package apiclient
+
+import (
+	"front-office/pkg/common/constant"
+	"time"
+)
+
+const (
+	clientIdPrefix = "aif_"
+
+	// signed requests older or newer than this are rejected
+	signatureMaxSkew = 5 * time.Minute
+)
+
+var supportedScopes = []string{
+	constant.ScopeProductRequest,
+	constant.ScopeJobRead,
+}
+
+var supportedProductSlugs = []string{
+	constant.SlugPhoneLiveStatus,
+	constant.SlugPhoneNIKMatching,
+	constant.SlugRecycleNumber,
+	constant.SlugNPWPVerification,
+	constant.SlugLoanRecordChecker,
+	constant.Slug7DaysMultipleLoan,
+	constant.Slug30DaysMultipleLoan,
+	constant.Slug90DaysMultipleLoan,
+	constant.SlugTaxComplianceStatus,
+	constant.SlugTaxScore,
+	constant.SlugTaxVerificationDetail,
+	constant.SlugNegativeRecord,
+	constant.SlugGenRetailV3,
+}
+
+type APIClient struct {
+	Id           uint       `json:"id"`
+	ClientId     string     `json:"client_id"`
+	CompanyId    uint       `json:"company_id"`
+	MemberId     uint       `json:"member_id"`
+	Name         string     `json:"name"`
+	Scopes       []string   `json:"scopes"`
+	ProductSlugs []string   `json:"product_slugs"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+}
+
+// apiClientSecret is only returned when a client is created or its secret is
+// rotated, and by the internal credential lookup.
+type apiClientSecret struct {
+	APIClient
+	Secret string `json:"secret"`
+}
+
+type createAPIClientRequest struct {
+	Name         string   `json:"name" validate:"required~Field Name is required"`
+	Scopes       []string `json:"scopes"`
+	ProductSlugs []string `json:"product_slugs"`
+}
+
+type updateAPIClientRequest struct {
+	Name         *string  `json:"name"`
+	Scopes       []string `json:"scopes"`
+	ProductSlugs []string `json:"product_slugs"`
+}
+
+type createAPIClientPayload struct {
+	ClientId     string   `json:"client_id"`
+	Secret       string   `json:"secret"`
+	CompanyId    uint     `json:"company_id"`
+	MemberId     uint     `json:"member_id"`
+	Name         string   `json:"name"`
+	Scopes       []string `json:"scopes"`
+	ProductSlugs []string `json:"product_slugs"`
+	Active       bool     `json:"active"`
+}
@@ -0,0 +1,170 @@
+package apiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	CreateAPIClientAPI(payload *createAPIClientPayload) (*APIClient, error)
+	GetAPIClientsAPI(companyId string) ([]*APIClient, error)
+	GetAPIClientAPI(id, companyId string) (*APIClient, error)
+	UpdateAPIClientAPI(id string, payload map[string]interface{}) error
+	GetAPIClientSecretAPI(clientId string) (*apiClientSecret, error)
+}
+
+func (repo *repository) CreateAPIClientAPI(payload *createAPIClientPayload) (*APIClient, error) {
+	url := fmt.Sprintf("%s/api/core/api-clients", repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*APIClient](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetAPIClientsAPI(companyId string) ([]*APIClient, error) {
+	url := fmt.Sprintf("%s/api/core/company/%s/api-clients", repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*APIClient](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetAPIClientAPI(id, companyId string) (*APIClient, error) {
+	url := fmt.Sprintf("%s/api/core/company/%s/api-clients/%s", repo.cfg.App.AifcoreHost, companyId, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*APIClient](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpdateAPIClientAPI(id string, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/core/api-clients/%s", repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+// GetAPIClientSecretAPI is an internal lookup used to authenticate api
+// clients, it is the only call that returns the client secret.
+func (repo *repository) GetAPIClientSecretAPI(clientId string) (*apiClientSecret, error) {
+	url := fmt.Sprintf("%s/api/core/api-clients/credential/%s", repo.cfg.App.AifcoreHost, clientId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XAPIKey, repo.cfg.App.CoreModuleKey)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*apiClientSecret](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
@@ -0,0 +1,307 @@
+package apiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockClient struct {
+	mock.Mock
+}
+
+func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+	t.Helper()
+
+	mockClient := new(MockClient)
+	mockClient.On("Do", mock.Anything).Return(response, err)
+
+	repo := NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockHost, CoreModuleKey: constant.DummyAPIKey},
+	}, mockClient, nil)
+
+	return repo, mockClient
+}
+
+func newInvalidHostRepo(marshalFn func(v any) ([]byte, error)) Repository {
+	return NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+	}, new(MockClient), marshalFn)
+}
+
+func jsonResponse(t *testing.T, data any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func invalidJSONResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+	}
+}
+
+func TestCreateAPIClientAPI(t *testing.T) {
+	payload := &createAPIClientPayload{Name: "loan-origination", CompanyId: 1, MemberId: 1}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*APIClient]{
+			Success: true,
+			Data:    &APIClient{Id: 1, ClientId: "aif_client"},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.CreateAPIClientAPI(payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "aif_client", result.ClientId)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		result, err := repo.CreateAPIClientAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).CreateAPIClientAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.CreateAPIClientAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.CreateAPIClientAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetAPIClientsAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[[]*APIClient]{
+			Success: true,
+			Data:    []*APIClient{{Id: 1}, {Id: 2}},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetAPIClientsAPI(constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetAPIClientsAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetAPIClientsAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetAPIClientsAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetAPIClientAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*APIClient]{
+			Success: true,
+			Data:    &APIClient{Id: 1, Active: true},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetAPIClientAPI(constant.DummyId, constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Active)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetAPIClientAPI(constant.DummyId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetAPIClientAPI(constant.DummyId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetAPIClientAPI(constant.DummyId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestUpdateAPIClientAPI(t *testing.T) {
+	payload := map[string]interface{}{"active": false}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[any]{Success: true})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.UpdateAPIClientAPI(constant.DummyId, payload)
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		err := repo.UpdateAPIClientAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		err := newInvalidHostRepo(nil).UpdateAPIClientAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		err := repo.UpdateAPIClientAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		err := repo.UpdateAPIClientAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetAPIClientSecretAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*apiClientSecret]{
+			Success: true,
+			Data:    &apiClientSecret{APIClient: APIClient{ClientId: "aif_client"}, Secret: "secret"},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetAPIClientSecretAPI("aif_client")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", result.Secret)
+		mockClient.AssertCalled(t, "Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get(constant.XAPIKey) == constant.DummyAPIKey
+		}))
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetAPIClientSecretAPI("aif_client")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetAPIClientSecretAPI("aif_client")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetAPIClientSecretAPI("aif_client")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
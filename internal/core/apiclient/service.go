@@ -0,0 +1,308 @@
+package apiclient
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(
+	repo Repository,
+	memberRepo member.Repository,
+	operationRepo operation.Repository,
+) Service {
+	return &service{
+		repo,
+		memberRepo,
+		operationRepo,
+	}
+}
+
+type service struct {
+	repo          Repository
+	memberRepo    member.Repository
+	operationRepo operation.Repository
+}
+
+type Service interface {
+	CreateClient(authCtx *model.AuthContext, req *createAPIClientRequest) (*apiClientSecret, error)
+	GetClients(companyId string) ([]*APIClient, error)
+	UpdateClient(authCtx *model.AuthContext, id string, req *updateAPIClientRequest) (*APIClient, error)
+	RotateSecret(authCtx *model.AuthContext, id string) (*apiClientSecret, error)
+	RevokeClient(authCtx *model.AuthContext, id string) error
+	AuthenticateClient(cred *model.APIClientCredential) (*model.APIClientPrincipal, error)
+}
+
+func (svc *service) CreateClient(authCtx *model.AuthContext, req *createAPIClientRequest) (*apiClientSecret, error) {
+	if err := validateGrants(req.Scopes, req.ProductSlugs); err != nil {
+		return nil, err
+	}
+
+	clientId, secret, err := generateCredentials()
+	if err != nil {
+		return nil, apperror.Internal("failed to generate api client credentials", err)
+	}
+
+	client, err := svc.repo.CreateAPIClientAPI(&createAPIClientPayload{
+		ClientId:     clientId,
+		Secret:       secret,
+		CompanyId:    authCtx.CompanyId,
+		MemberId:     authCtx.UserId,
+		Name:         req.Name,
+		Scopes:       req.Scopes,
+		ProductSlugs: req.ProductSlugs,
+		Active:       true,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to create api client")
+	}
+
+	svc.addLogOperation(authCtx, constant.EventCreateAPIClient)
+
+	return &apiClientSecret{APIClient: *client, Secret: secret}, nil
+}
+
+func (svc *service) GetClients(companyId string) ([]*APIClient, error) {
+	clients, err := svc.repo.GetAPIClientsAPI(companyId)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch api clients")
+	}
+
+	return clients, nil
+}
+
+func (svc *service) UpdateClient(authCtx *model.AuthContext, id string, req *updateAPIClientRequest) (*APIClient, error) {
+	client, err := svc.getActiveClient(authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		client.Name = *req.Name
+	}
+	if req.Scopes != nil {
+		client.Scopes = req.Scopes
+	}
+	if req.ProductSlugs != nil {
+		client.ProductSlugs = req.ProductSlugs
+	}
+
+	if err := validateGrants(client.Scopes, client.ProductSlugs); err != nil {
+		return nil, err
+	}
+
+	if err := svc.repo.UpdateAPIClientAPI(id, map[string]interface{}{
+		"name":          client.Name,
+		"scopes":        client.Scopes,
+		"product_slugs": client.ProductSlugs,
+		"updated_at":    time.Now(),
+	}); err != nil {
+		return nil, apperror.MapRepoError(err, "failed to update api client")
+	}
+
+	return client, nil
+}
+
+func (svc *service) RotateSecret(authCtx *model.AuthContext, id string) (*apiClientSecret, error) {
+	client, err := svc.getActiveClient(authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, secret, err := generateCredentials()
+	if err != nil {
+		return nil, apperror.Internal("failed to generate api client credentials", err)
+	}
+
+	if err := svc.repo.UpdateAPIClientAPI(id, map[string]interface{}{
+		"secret":     secret,
+		"updated_at": time.Now(),
+	}); err != nil {
+		return nil, apperror.MapRepoError(err, "failed to rotate api client secret")
+	}
+
+	svc.addLogOperation(authCtx, constant.EventRotateAPIClientSecret)
+
+	return &apiClientSecret{APIClient: *client, Secret: secret}, nil
+}
+
+func (svc *service) RevokeClient(authCtx *model.AuthContext, id string) error {
+	if _, err := svc.getActiveClient(authCtx, id); err != nil {
+		return err
+	}
+
+	if err := svc.repo.UpdateAPIClientAPI(id, map[string]interface{}{
+		"active":     false,
+		"revoked_at": time.Now(),
+	}); err != nil {
+		return apperror.MapRepoError(err, "failed to revoke api client")
+	}
+
+	svc.addLogOperation(authCtx, constant.EventRevokeAPIClient)
+
+	return nil
+}
+
+func (svc *service) AuthenticateClient(cred *model.APIClientCredential) (*model.APIClientPrincipal, error) {
+	if cred.ClientId == "" {
+		return nil, apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	client, err := svc.repo.GetAPIClientSecretAPI(cred.ClientId)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.Unauthorized(constant.InvalidAPIClientAuth)
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchAPIClient)
+	}
+	if client == nil || !client.Active || client.RevokedAt != nil || client.Secret == "" {
+		return nil, apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	if cred.IsSigned() {
+		if err := verifySignature(client.Secret, cred, time.Now()); err != nil {
+			return nil, err
+		}
+	} else if subtle.ConstantTimeCompare([]byte(cred.Secret), []byte(client.Secret)) != 1 {
+		return nil, apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	// calls run on behalf of the member that owns the client
+	owner, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{
+		Id:        helper.ConvertUintToString(client.MemberId),
+		CompanyId: helper.ConvertUintToString(client.CompanyId),
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+	if owner == nil || owner.MemberId == 0 || !owner.Active || owner.CompanyId != client.CompanyId {
+		return nil, apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	return &model.APIClientPrincipal{
+		AuthContext: model.AuthContext{
+			UserId:      owner.MemberId,
+			CompanyId:   owner.CompanyId,
+			RoleId:      owner.RoleId,
+			QuotaType:   uint(owner.QuotaType),
+			APIKey:      owner.Key,
+			APIClientId: client.ClientId,
+		},
+		Scopes:       client.Scopes,
+		ProductSlugs: client.ProductSlugs,
+	}, nil
+}
+
+// SignRequest returns the hex encoded HMAC-SHA256 signature an api client
+// sends in the X-Signature header. The signed string is the method, the
+// request path with query, the unix timestamp from X-Timestamp and the hex
+// encoded SHA-256 of the body, joined by newlines.
+func SignRequest(secret, method, path, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + hex.EncodeToString(bodyHash[:])))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifySignature(secret string, cred *model.APIClientCredential, now time.Time) error {
+	ts, err := strconv.ParseInt(cred.Timestamp, 10, 64)
+	if err != nil {
+		return apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	skew := now.Sub(time.Unix(ts, 0))
+	if skew > signatureMaxSkew || skew < -signatureMaxSkew {
+		return apperror.Unauthorized(constant.ExpiredAPIClientSignature)
+	}
+
+	expected := SignRequest(secret, cred.Method, cred.Path, cred.Timestamp, cred.Body)
+	if !hmac.Equal([]byte(expected), []byte(cred.Signature)) {
+		return apperror.Unauthorized(constant.InvalidAPIClientAuth)
+	}
+
+	return nil
+}
+
+func (svc *service) getActiveClient(authCtx *model.AuthContext, id string) (*APIClient, error) {
+	client, err := svc.repo.GetAPIClientAPI(id, authCtx.CompanyIdStr())
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchAPIClient)
+	}
+	if client == nil || client.Id == 0 || client.CompanyId != authCtx.CompanyId {
+		return nil, apperror.NotFound(constant.APIClientNotFound)
+	}
+	if !client.Active || client.RevokedAt != nil {
+		return nil, apperror.Conflict(constant.APIClientAlreadyRevoked)
+	}
+
+	return client, nil
+}
+
+func (svc *service) addLogOperation(authCtx *model.AuthContext, action string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Action:    action,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", action).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+func validateGrants(scopes, productSlugs []string) error {
+	if len(scopes) == 0 {
+		return apperror.BadRequest("scopes must not be empty")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(supportedScopes, scope) {
+			return apperror.BadRequest(fmt.Sprintf(constant.InvalidAPIClientScope, scope))
+		}
+	}
+
+	if len(productSlugs) == 0 {
+		return apperror.BadRequest("product_slugs must not be empty")
+	}
+	for _, slug := range productSlugs {
+		if !slices.Contains(supportedProductSlugs, slug) {
+			return apperror.BadRequest(fmt.Sprintf("unsupported product slug: %s", slug))
+		}
+	}
+
+	return nil
+}
+
+func generateCredentials() (clientId, secret string, err error) {
+	idBytes := make([]byte, 12)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	return clientIdPrefix + hex.EncodeToString(idBytes), hex.EncodeToString(secretBytes), nil
+}
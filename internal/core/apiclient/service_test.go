@@ -0,0 +1,150 @@
+package apiclient
+
+import (
+	"front-office/internal/core/member"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientId = "aif_client"
+	testSecret   = "client-secret"
+)
+
+type stubRepo struct {
+	Repository
+	client *apiClientSecret
+}
+
+func (r *stubRepo) GetAPIClientSecretAPI(string) (*apiClientSecret, error) {
+	return r.client, nil
+}
+
+type stubMemberRepo struct {
+	member.Repository
+	owner *member.MstMember
+}
+
+func (r *stubMemberRepo) GetMemberAPI(*member.MemberParams) (*member.MstMember, error) {
+	return r.owner, nil
+}
+
+func setupAuthService(mod func(*apiClientSecret, *member.MstMember)) Service {
+	client := &apiClientSecret{
+		APIClient: APIClient{
+			ClientId:     testClientId,
+			CompanyId:    1,
+			MemberId:     7,
+			Scopes:       []string{constant.ScopeProductRequest},
+			ProductSlugs: []string{constant.SlugPhoneLiveStatus},
+			Active:       true,
+		},
+		Secret: testSecret,
+	}
+	owner := &member.MstMember{MemberId: 7, CompanyId: 1, RoleId: 2, Active: true, Key: "member-api-key"}
+
+	if mod != nil {
+		mod(client, owner)
+	}
+
+	return NewService(&stubRepo{client: client}, &stubMemberRepo{owner: owner}, nil)
+}
+
+func signedCredential(secret string, at time.Time) *model.APIClientCredential {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	body := []byte(`{"phone_number":"6281234567890"}`)
+	path := "/api/fo/products/identity/phone-live-status/single-request"
+
+	return &model.APIClientCredential{
+		ClientId:  testClientId,
+		Timestamp: ts,
+		Signature: SignRequest(secret, http.MethodPost, path, ts, body),
+		Method:    http.MethodPost,
+		Path:      path,
+		Body:      body,
+	}
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	t.Run("Bearer", func(t *testing.T) {
+		principal, err := setupAuthService(nil).AuthenticateClient(&model.APIClientCredential{
+			ClientId: testClientId,
+			Secret:   testSecret,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), principal.UserId)
+		assert.Equal(t, "member-api-key", principal.APIKey)
+		assert.Equal(t, testClientId, principal.APIClientId)
+		assert.True(t, principal.HasScope(constant.ScopeProductRequest))
+		assert.True(t, principal.AllowsProduct(constant.SlugPhoneLiveStatus))
+		assert.False(t, principal.AllowsProduct(constant.SlugTaxScore))
+	})
+
+	t.Run("BearerWrongSecret", func(t *testing.T) {
+		_, err := setupAuthService(nil).AuthenticateClient(&model.APIClientCredential{
+			ClientId: testClientId,
+			Secret:   "wrong",
+		})
+
+		assert.ErrorContains(t, err, constant.InvalidAPIClientAuth)
+	})
+
+	t.Run("HMAC", func(t *testing.T) {
+		principal, err := setupAuthService(nil).AuthenticateClient(signedCredential(testSecret, time.Now()))
+
+		require.NoError(t, err)
+		assert.Equal(t, testClientId, principal.APIClientId)
+	})
+
+	t.Run("HMACTamperedBody", func(t *testing.T) {
+		cred := signedCredential(testSecret, time.Now())
+		cred.Body = []byte(`{"phone_number":"6289999999999"}`)
+
+		_, err := setupAuthService(nil).AuthenticateClient(cred)
+
+		assert.ErrorContains(t, err, constant.InvalidAPIClientAuth)
+	})
+
+	t.Run("HMACStaleTimestamp", func(t *testing.T) {
+		_, err := setupAuthService(nil).AuthenticateClient(signedCredential(testSecret, time.Now().Add(-10*time.Minute)))
+
+		assert.ErrorContains(t, err, constant.ExpiredAPIClientSignature)
+	})
+
+	t.Run("RevokedClient", func(t *testing.T) {
+		svc := setupAuthService(func(c *apiClientSecret, _ *member.MstMember) {
+			now := time.Now()
+			c.Active = false
+			c.RevokedAt = &now
+		})
+
+		_, err := svc.AuthenticateClient(&model.APIClientCredential{ClientId: testClientId, Secret: testSecret})
+
+		assert.ErrorContains(t, err, constant.InvalidAPIClientAuth)
+	})
+
+	t.Run("InactiveOwner", func(t *testing.T) {
+		svc := setupAuthService(func(_ *apiClientSecret, m *member.MstMember) {
+			m.Active = false
+		})
+
+		_, err := svc.AuthenticateClient(&model.APIClientCredential{ClientId: testClientId, Secret: testSecret})
+
+		assert.ErrorContains(t, err, constant.InvalidAPIClientAuth)
+	})
+}
+
+func TestValidateGrants(t *testing.T) {
+	assert.NoError(t, validateGrants([]string{constant.ScopeJobRead}, []string{constant.SlugTaxScore}))
+	assert.Error(t, validateGrants(nil, []string{constant.SlugTaxScore}))
+	assert.Error(t, validateGrants([]string{"admin"}, []string{constant.SlugTaxScore}))
+	assert.Error(t, validateGrants([]string{constant.ScopeJobRead}, []string{"UNKNOWN_product"}))
+}
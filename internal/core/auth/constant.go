@@ -5,4 +5,8 @@ const (
 
 	mailStatusPending = "pending"
 	mailStatusResend  = "resend"
+
+	ssoStateExpiresMinutes = 10
+	ssoStateCookieName     = "aif_sso_state"
+	ssoStateCookiePath     = "/api/fo/users/sso"
 )
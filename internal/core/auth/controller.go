@@ -55,6 +55,8 @@ type Controller interface {
 	RequestPasswordReset(c *fiber.Ctx) error
 	PasswordReset(c *fiber.Ctx) error
 	ChangePassword(c *fiber.Ctx) error
+	SSOLogin(c *fiber.Ctx) error
+	SSOCallback(c *fiber.Ctx) error
 }
 
 func (ctrl *controller) RegisterMember(c *fiber.Ctx) error {
@@ -257,6 +259,60 @@ func (ctrl *controller) PasswordReset(c *fiber.Ctx) error {
 	))
 }
 
+func (ctrl *controller) SSOLogin(c *fiber.Ctx) error {
+	companyId := c.Params("companyId")
+	if companyId == "" {
+		return apperror.BadRequest(constant.MissingCompanyId)
+	}
+
+	result, err := ctrl.svc.SSOAuthorize(companyId)
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     ssoStateCookieName,
+		Value:    result.StateToken,
+		Expires:  time.Now().Add(ssoStateExpiresMinutes * time.Minute),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Lax",
+		Path:     ssoStateCookiePath,
+	})
+
+	return c.Redirect(result.AuthURL, fiber.StatusFound)
+}
+
+func (ctrl *controller) SSOCallback(c *fiber.Ctx) error {
+	if idpErr := c.Query("error"); idpErr != "" {
+		return apperror.Unauthorized(fmt.Sprintf("%s: %s", constant.SSOLoginFailed, idpErr))
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	stateToken := c.Cookies(ssoStateCookieName)
+	if code == "" || state == "" || stateToken == "" {
+		return apperror.BadRequest(constant.InvalidSSOState)
+	}
+
+	clearAuthCookie(c, ssoStateCookieName, ssoStateCookiePath)
+
+	result, err := ctrl.svc.SSOCallback(stateToken, state, code)
+	if err != nil {
+		return err
+	}
+
+	if err := setTokenCookie(c, "aif_token", result.AccessToken, ctrl.cfg.App.JwtExpiresMinutes, "/"); err != nil {
+		return apperror.Internal("failed to set access token cookie", err)
+	}
+
+	if err := setTokenCookie(c, "aif_refresh_token", result.RefreshToken, ctrl.cfg.App.JwtRefreshTokenExpiresMinutes, "/api/fo/users/refresh-access"); err != nil {
+		return apperror.Internal("failed to set refresh token cookie", err)
+	}
+
+	return c.Redirect(ctrl.cfg.App.FrontendBaseUrl, fiber.StatusFound)
+}
+
 func setTokenCookie(c *fiber.Ctx, name, value, durationStr, path string) error {
 	minutes, err := strconv.Atoi(durationStr)
 	if err != nil {
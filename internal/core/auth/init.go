@@ -5,12 +5,14 @@ import (
 	"front-office/internal/core/activation"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/member"
+	"front-office/internal/core/passwordpolicy"
 	"front-office/internal/core/passwordreset"
 	"front-office/internal/core/role"
 	"front-office/internal/mail"
 	"front-office/internal/middleware"
 
 	"front-office/pkg/httpclient"
+	"front-office/pkg/oidc"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,12 +24,14 @@ func SetupInit(authAPI fiber.Router, cfg *application.Config, client httpclient.
 	activationTokenRepo := activation.NewRepository(cfg, client, nil)
 	passwordResetRepo := passwordreset.NewRepository(cfg, client, nil)
 	logOperationRepo := operation.NewRepository(cfg, client, nil)
+	passwordPolicyRepo := passwordpolicy.NewRepository(cfg, client, nil)
 
 	serviceUser := member.NewService(memberRepo, roleRepo, logOperationRepo, mailSvc)
 	serviceActivationToken := activation.NewService(activationTokenRepo, cfg)
 	servicePasswordResetToken := passwordreset.NewService(passwordResetRepo, cfg)
 	serviceLogOperation := operation.NewService(logOperationRepo)
-	service := NewService(cfg, repo, memberRepo, roleRepo, logOperationRepo, activationTokenRepo, passwordResetRepo, mailSvc)
+	servicePasswordPolicy := passwordpolicy.NewService(cfg, passwordPolicyRepo, logOperationRepo)
+	service := NewService(cfg, repo, memberRepo, roleRepo, logOperationRepo, activationTokenRepo, passwordResetRepo, servicePasswordPolicy, oidc.NewClient(client), mailSvc)
 
 	controller := NewController(service, serviceUser, serviceActivationToken, servicePasswordResetToken, serviceLogOperation, cfg)
 
@@ -39,5 +43,7 @@ func SetupInit(authAPI fiber.Router, cfg *application.Config, client httpclient.
 	authAPI.Put("/send-email-activation/:email", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.RequestActivation)
 	authAPI.Post("/request-password-reset", middleware.ValidateRequest(requestPasswordResetRequest{}), controller.RequestPasswordReset)
 	authAPI.Put("/password-reset/:token", middleware.ValidateRequest(passwordResetRequest{}), controller.PasswordReset)
+	authAPI.Get("/sso/callback", controller.SSOCallback)
+	authAPI.Get("/sso/:companyId/login", controller.SSOLogin)
 	authAPI.Put("/change-password", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(changePasswordRequest{}), controller.ChangePassword)
 }
@@ -1,5 +1,7 @@
 package auth
 
+import "time"
+
 type RegisterAdminRequest struct {
 	Name            string `json:"name" validate:"required~Field Name is required"`
 	Email           string `json:"email" validate:"required~Field Email is required, email~Only email pattern are allowed"`
@@ -29,6 +31,10 @@ type loginResponse struct {
 	TierLevel          uint        `json:"tier_level"`
 	Image              string      `json:"image"`
 	SubscriberProducts interface{} `json:"subscriber_products"`
+
+	ForcePasswordChange   bool       `json:"force_password_change"`
+	PasswordExpiresAt     *time.Time `json:"password_expires_at,omitempty"`
+	PasswordExpiryWarning string     `json:"password_expiry_warning,omitempty"`
 }
 
 type loginResponseData struct {
@@ -42,6 +48,9 @@ type loginResponseData struct {
 	Image              string      `json:"image"`
 	ApiKey             string      `json:"api_key"`
 	SubscriberProducts interface{} `json:"subscriber_products"`
+
+	PasswordChangedAt   *time.Time `json:"password_changed_at"`
+	ForcePasswordChange bool       `json:"force_password_change"`
 }
 
 type tokenPayload struct {
@@ -66,3 +75,25 @@ type changePasswordRequest struct {
 	NewPassword        string `json:"new_password" validate:"required~Field New Password is required, min(8)~Field Password must have at least 8 characters"`
 	ConfirmNewPassword string `json:"confirm_password" validate:"required~Field Confirmation New Password is required"`
 }
+
+type ssoConfig struct {
+	CompanyId    uint            `json:"company_id"`
+	Enabled      bool            `json:"enabled"`
+	Issuer       string          `json:"issuer"`
+	ClientId     string          `json:"client_id"`
+	ClientSecret string          `json:"client_secret"`
+	Scopes       []string        `json:"scopes"`
+	EmailClaim   string          `json:"email_claim"`
+	RoleClaim    string          `json:"role_claim"`
+	RoleMapping  map[string]uint `json:"role_mapping"`
+}
+
+type ssoAuthorizeResult struct {
+	AuthURL    string
+	StateToken string
+}
+
+type ssoLoginResult struct {
+	AccessToken  string
+	RefreshToken string
+}
@@ -39,6 +39,7 @@ type Repository interface {
 	ChangePasswordAPI(userId string, req *changePasswordRequest) error
 	PasswordResetAPI(userId, token string, req *passwordResetRequest) error
 	AuthMemberAPI(req *userLoginRequest) (*loginResponseData, error)
+	GetSSOConfigAPI(companyId string) (*ssoConfig, error)
 }
 
 // func (repo *repository) CreateAdmin(company *company.MstCompany, user *member.MstMember, activationToken *activationtoken.MstActivationToken) (*member.MstMember, error) {
@@ -205,3 +206,28 @@ func (repo *repository) AuthMemberAPI(payload *userLoginRequest) (*loginResponse
 
 	return apiResp.Data, nil
 }
+
+func (repo *repository) GetSSOConfigAPI(companyId string) (*ssoConfig, error) {
+	url := fmt.Sprintf("%s/api/core/company/%s/sso", repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XAPIKey, repo.cfg.App.CoreModuleKey)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*ssoConfig](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
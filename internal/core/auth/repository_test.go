@@ -356,3 +356,72 @@ func TestAuthMemberAPI(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestGetSSOConfigAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		mockData := model.AifcoreAPIResponse[*ssoConfig]{
+			Success: true,
+			Data: &ssoConfig{
+				CompanyId: constant.DummyIdInt,
+				Enabled:   true,
+				Issuer:    constant.MockHost,
+				ClientId:  constant.DummyId,
+			},
+		}
+		body, err := json.Marshal(mockData)
+		require.NoError(t, err)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetSSOConfigAPI(constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Enabled)
+		assert.Equal(t, constant.MockHost, result.Issuer)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		mockClient := new(MockClient)
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+		}, mockClient, nil)
+
+		result, err := repo.GetSSOConfigAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
+
+		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+
+		result, err := repo.GetSSOConfigAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetSSOConfigAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -7,12 +7,14 @@ import (
 	"front-office/internal/core/activation"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/member"
+	"front-office/internal/core/passwordpolicy"
 	"front-office/internal/core/passwordreset"
 	"front-office/internal/core/role"
 	"front-office/internal/mail"
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/helper"
+	"front-office/pkg/oidc"
 
 	"strconv"
 	"time"
@@ -28,6 +30,8 @@ func NewService(
 	operationRepo operation.Repository,
 	activationRepo activation.Repository,
 	passwordResetRepo passwordreset.Repository,
+	policySvc passwordpolicy.Service,
+	oidcClient *oidc.Client,
 	mailSvc *mail.SendMailService,
 ) Service {
 	return &service{
@@ -38,6 +42,8 @@ func NewService(
 		operationRepo,
 		activationRepo,
 		passwordResetRepo,
+		policySvc,
+		oidcClient,
 		mailSvc,
 	}
 }
@@ -50,6 +56,8 @@ type service struct {
 	operationRepo     operation.Repository
 	activationRepo    activation.Repository
 	passwordResetRepo passwordreset.Repository
+	policySvc         passwordpolicy.Service
+	oidcClient        *oidc.Client
 	mailSvc           *mail.SendMailService
 }
 
@@ -64,6 +72,8 @@ type Service interface {
 	PasswordReset(token string, req *passwordResetRequest) error
 	VerifyMember(token string, req *passwordResetRequest) error
 	ChangePassword(userId string, req *changePasswordRequest) error
+	SSOAuthorize(companyId string) (*ssoAuthorizeResult, error)
+	SSOCallback(stateToken, state, code string) (*ssoLoginResult, error)
 }
 
 // func (svc *service) RegisterAdminSvc(req *RegisterAdminRequest) (*user.User, string, error) {
@@ -171,18 +181,21 @@ func (svc *service) VerifyMember(token string, req *passwordResetRequest) error
 		return apperror.Forbidden(constant.ActivationTokenExpired)
 	}
 
-	if !helper.ValidatePasswordStrength(req.Password) {
-		return apperror.BadRequest(constant.InvalidPassword)
-	}
-
 	if req.Password != req.ConfirmPassword {
 		return apperror.BadRequest(constant.ConfirmPasswordMismatch)
 	}
 
+	companyId := helper.ConvertUintToString(user.CompanyId)
+	if err := svc.policySvc.ValidateNewPassword(companyId, userId, user.Password, req.Password); err != nil {
+		return err
+	}
+
 	if err := svc.repo.VerifyMemberAPI(userId, req); err != nil {
 		return apperror.MapRepoError(err, "failed to verify member")
 	}
 
+	svc.policySvc.RecordPassword(companyId, userId, req.Password)
+
 	return nil
 }
 
@@ -208,19 +221,23 @@ func (svc *service) PasswordReset(token string, req *passwordResetRequest) error
 		return apperror.Forbidden(constant.InvalidPasswordResetLink)
 	}
 
-	if !helper.ValidatePasswordStrength(req.Password) {
-		return apperror.BadRequest(constant.InvalidPassword)
-	}
-
 	if req.Password != req.ConfirmPassword {
 		return apperror.BadRequest(constant.ConfirmPasswordMismatch)
 	}
 
-	err = svc.repo.PasswordResetAPI(strconv.Itoa(int(data.MemberId)), token, req)
+	memberId := strconv.Itoa(int(data.MemberId))
+	companyId := helper.ConvertUintToString(data.Member.CompanyId)
+	if err := svc.policySvc.ValidateNewPassword(companyId, memberId, data.Member.Password, req.Password); err != nil {
+		return err
+	}
+
+	err = svc.repo.PasswordResetAPI(memberId, token, req)
 	if err != nil {
 		return apperror.MapRepoError(err, "failed to password reset")
 	}
 
+	svc.policySvc.RecordPassword(companyId, memberId, req.Password)
+
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
 		MemberId:  data.MemberId,
 		CompanyId: data.Member.CompanyId,
@@ -486,15 +503,29 @@ func (svc *service) LoginMember(req *userLoginRequest) (accessToken, refreshToke
 	}
 
 	loginResp = &loginResponse{
-		Id:                 user.MemberId,
-		Name:               user.Name,
-		Email:              user.Email,
-		CompanyId:          user.CompanyId,
-		CompanyName:        user.CompanyName,
-		QuotaType:          user.QuotaType,
-		TierLevel:          user.RoleId,
-		Image:              user.Image,
-		SubscriberProducts: user.SubscriberProducts,
+		Id:                  user.MemberId,
+		Name:                user.Name,
+		Email:               user.Email,
+		CompanyId:           user.CompanyId,
+		CompanyName:         user.CompanyName,
+		QuotaType:           user.QuotaType,
+		TierLevel:           user.RoleId,
+		Image:               user.Image,
+		SubscriberProducts:  user.SubscriberProducts,
+		ForcePasswordChange: user.ForcePasswordChange,
+	}
+
+	if user.PasswordChangedAt != nil {
+		expiry := svc.policySvc.CheckExpiry(helper.ConvertUintToString(user.CompanyId), user.PasswordChangedAt)
+		loginResp.PasswordExpiresAt = expiry.ExpiresAt
+
+		switch {
+		case expiry.Expired:
+			loginResp.ForcePasswordChange = true
+			loginResp.PasswordExpiryWarning = constant.PasswordExpired
+		case expiry.Warning:
+			loginResp.PasswordExpiryWarning = fmt.Sprintf(constant.PasswordExpiresSoon, expiry.DaysLeft)
+		}
 	}
 
 	return accessToken, refreshToken, loginResp, nil
@@ -545,14 +576,19 @@ func (svc *service) ChangePassword(userId string, reqBody *changePasswordRequest
 		return apperror.MapRepoError(err, constant.FailedFetchMember)
 	}
 
-	if !helper.ValidatePasswordStrength(reqBody.NewPassword) {
-		return apperror.BadRequest(constant.InvalidPassword)
+	if user.MemberId == 0 {
+		return apperror.NotFound(constant.UserNotFound)
 	}
 
 	if reqBody.NewPassword != reqBody.ConfirmNewPassword {
 		return apperror.BadRequest(constant.ConfirmPasswordMismatch)
 	}
 
+	companyId := helper.ConvertUintToString(user.CompanyId)
+	if err := svc.policySvc.ValidateNewPassword(companyId, userId, user.Password, reqBody.NewPassword); err != nil {
+		return err
+	}
+
 	if err := svc.repo.ChangePasswordAPI(userId, reqBody); err != nil {
 		var apiErr *apperror.ExternalAPIError
 		if errors.As(err, &apiErr) {
@@ -562,6 +598,8 @@ func (svc *service) ChangePassword(userId string, reqBody *changePasswordRequest
 		return apperror.Internal("failed to change password", err)
 	}
 
+	svc.policySvc.RecordPassword(companyId, userId, reqBody.NewPassword)
+
 	if err := svc.mailSvc.SendWithTemplate(
 		user.Email,
 		nil,
@@ -592,6 +630,243 @@ func (svc *service) ChangePassword(userId string, reqBody *changePasswordRequest
 	return nil
 }
 
+func (svc *service) SSOAuthorize(companyId string) (*ssoAuthorizeResult, error) {
+	cfg, provider, err := svc.ssoProvider(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := oidc.RandomString(16)
+	if err != nil {
+		return nil, apperror.Internal("failed to generate sso state", err)
+	}
+
+	nonce, err := oidc.RandomString(16)
+	if err != nil {
+		return nil, apperror.Internal("failed to generate sso nonce", err)
+	}
+
+	verifier, challenge, err := oidc.GeneratePKCE()
+	if err != nil {
+		return nil, apperror.Internal("failed to generate pkce verifier", err)
+	}
+
+	stateToken, err := helper.GenerateTokenWithClaims(svc.cfg.App.JwtSecretKey, ssoStateExpiresMinutes, constant.TokenTypeSSOState, map[string]any{
+		"company_id":    cfg.CompanyId,
+		"state":         state,
+		"nonce":         nonce,
+		"code_verifier": verifier,
+	})
+	if err != nil {
+		return nil, apperror.Internal("failed to sign sso state", err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &ssoAuthorizeResult{
+		AuthURL: provider.AuthCodeURL(&oidc.AuthCodeParams{
+			ClientId:      cfg.ClientId,
+			RedirectURI:   svc.cfg.App.SSORedirectURL,
+			Scopes:        scopes,
+			State:         state,
+			Nonce:         nonce,
+			CodeChallenge: challenge,
+		}),
+		StateToken: stateToken,
+	}, nil
+}
+
+func (svc *service) SSOCallback(stateToken, state, code string) (*ssoLoginResult, error) {
+	claims, err := helper.ExtractClaimsFromJWT(stateToken, svc.cfg.App.JwtSecretKey)
+	if err != nil {
+		return nil, apperror.Unauthorized(constant.InvalidSSOState)
+	}
+
+	tokenType, _ := (*claims)["token_type"].(string)
+	expectedState, _ := (*claims)["state"].(string)
+	nonce, _ := (*claims)["nonce"].(string)
+	verifier, _ := (*claims)["code_verifier"].(string)
+	if tokenType != constant.TokenTypeSSOState || expectedState == "" || expectedState != state {
+		return nil, apperror.Unauthorized(constant.InvalidSSOState)
+	}
+
+	companyId, err := helper.ExtractCompanyIdFromClaims(claims)
+	if err != nil {
+		return nil, apperror.Unauthorized(constant.InvalidSSOState)
+	}
+	companyIdStr := helper.ConvertUintToString(companyId)
+
+	cfg, provider, err := svc.ssoProvider(companyIdStr)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := provider.Exchange(&oidc.ExchangeParams{
+		ClientId:     cfg.ClientId,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURI:  svc.cfg.App.SSORedirectURL,
+		Code:         code,
+		CodeVerifier: verifier,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("company_id", companyIdStr).Msg("sso code exchange failed")
+
+		return nil, apperror.Unauthorized(constant.SSOLoginFailed)
+	}
+
+	idClaims, err := provider.VerifyIDToken(token.IDToken, cfg.ClientId, nonce)
+	if err != nil {
+		log.Warn().Err(err).Str("company_id", companyIdStr).Msg("sso id token rejected")
+
+		return nil, apperror.Unauthorized(constant.SSOLoginFailed)
+	}
+
+	user, err := svc.resolveSSOMember(cfg, idClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &tokenPayload{
+		MemberId:  user.MemberId,
+		CompanyId: user.CompanyId,
+		RoleId:    user.RoleId,
+		QuotaType: uint(user.QuotaType),
+		ApiKey:    user.Key,
+	}
+
+	accessToken, err := svc.generateToken(payload, constant.TokenTypeAccess)
+	if err != nil {
+		return nil, apperror.Internal("generate access token failed", err)
+	}
+
+	refreshToken, err := svc.generateToken(payload, constant.TokenTypeRefresh)
+	if err != nil {
+		return nil, apperror.Internal("generate refresh token failed", err)
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  user.MemberId,
+		CompanyId: user.CompanyId,
+		Action:    constant.EventSignInSSO,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", constant.EventSignInSSO).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+
+	return &ssoLoginResult{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (svc *service) ssoProvider(companyId string) (*ssoConfig, *oidc.Provider, error) {
+	if svc.cfg.App.SSORedirectURL == "" {
+		return nil, nil, apperror.Internal(constant.SSONotConfigured, errors.New("FO_SSO_REDIRECT_URL is not set"))
+	}
+
+	cfg, err := svc.repo.GetSSOConfigAPI(companyId)
+	if err != nil {
+		return nil, nil, apperror.MapRepoError(err, "failed to fetch sso config")
+	}
+	if cfg == nil || !cfg.Enabled || cfg.Issuer == "" || cfg.ClientId == "" {
+		return nil, nil, apperror.NotFound(constant.SSONotConfigured)
+	}
+
+	provider, err := svc.oidcClient.Provider(cfg.Issuer)
+	if err != nil {
+		return nil, nil, apperror.BadGateway(err.Error())
+	}
+
+	return cfg, provider, nil
+}
+
+// resolveSSOMember maps the verified IdP claims to an existing active member
+// of the company and syncs the member role when a role mapping is configured.
+func (svc *service) resolveSSOMember(cfg *ssoConfig, claims map[string]interface{}) (*member.MstMember, error) {
+	emailClaim := cfg.EmailClaim
+	if emailClaim == "" {
+		emailClaim = "email"
+	}
+
+	email, _ := claims[emailClaim].(string)
+	if email == "" {
+		return nil, apperror.Forbidden(constant.SSOUserNotAllowed)
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, apperror.Forbidden(constant.SSOUserNotAllowed)
+	}
+
+	user, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{Email: email})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+	if user == nil || user.MemberId == 0 || user.CompanyId != cfg.CompanyId || !user.Active {
+		return nil, apperror.Forbidden(constant.SSOUserNotAllowed)
+	}
+
+	if cfg.RoleClaim == "" || len(cfg.RoleMapping) == 0 {
+		return user, nil
+	}
+
+	roleId, ok := mapSSORole(claims[cfg.RoleClaim], cfg.RoleMapping)
+	if !ok {
+		return nil, apperror.Forbidden(constant.SSOUserNotAllowed)
+	}
+
+	if roleId != user.RoleId {
+		memberId := helper.ConvertUintToString(user.MemberId)
+		if err := svc.memberRepo.UpdateMemberAPI(memberId, map[string]interface{}{
+			"role_id":    roleId,
+			"updated_at": time.Now(),
+		}); err != nil {
+			return nil, apperror.MapRepoError(err, constant.FailedUpdateMember)
+		}
+
+		user.RoleId = roleId
+	}
+
+	return user, nil
+}
+
+// mapSSORole accepts a single string or a list of group names and returns the
+// mapped role with the lowest id, i.e. the most privileged one.
+func mapSSORole(claim interface{}, mapping map[string]uint) (uint, bool) {
+	var groups []string
+
+	switch v := claim.(type) {
+	case string:
+		groups = []string{v}
+	case []interface{}:
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+
+	var (
+		roleId uint
+		found  bool
+	)
+	for _, g := range groups {
+		mapped, ok := mapping[g]
+		if !ok {
+			continue
+		}
+		if !found || mapped < roleId {
+			roleId = mapped
+			found = true
+		}
+	}
+
+	return roleId, found
+}
+
 func (svc *service) generateToken(payload *tokenPayload, tokenType string) (string, error) {
 	var minutesStr, secret string
 
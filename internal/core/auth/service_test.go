@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/oidc"
+	"front-office/pkg/oidc/oidctest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuthRepo struct {
+	Repository
+	ssoCfg *ssoConfig
+}
+
+func (r *stubAuthRepo) GetSSOConfigAPI(string) (*ssoConfig, error) {
+	return r.ssoCfg, nil
+}
+
+type stubMemberRepo struct {
+	member.Repository
+	user    *member.MstMember
+	updates map[string]interface{}
+}
+
+func (r *stubMemberRepo) GetMemberAPI(*member.MemberParams) (*member.MstMember, error) {
+	return r.user, nil
+}
+
+func (r *stubMemberRepo) UpdateMemberAPI(_ string, fields map[string]interface{}) error {
+	r.updates = fields
+	return nil
+}
+
+type stubOperationRepo struct {
+	operation.Repository
+	actions []string
+}
+
+func (r *stubOperationRepo) AddLogOperation(req *operation.AddLogRequest) error {
+	r.actions = append(r.actions, req.Action)
+	return nil
+}
+
+func setupSSOService(t *testing.T, server *oidctest.Server, user *member.MstMember, cfgMod func(*ssoConfig)) (Service, *stubMemberRepo, *stubOperationRepo) {
+	t.Helper()
+
+	ssoCfg := &ssoConfig{
+		CompanyId:    1,
+		Enabled:      true,
+		Issuer:       server.Issuer(),
+		ClientId:     server.ClientId,
+		ClientSecret: server.ClientSecret,
+	}
+	if cfgMod != nil {
+		cfgMod(ssoCfg)
+	}
+
+	cfg := &application.Config{App: &application.Environment{
+		JwtSecretKey:                  "access-secret",
+		JwtRefreshSecretKey:           "refresh-secret",
+		JwtExpiresMinutes:             "15",
+		JwtRefreshTokenExpiresMinutes: "60",
+		SSORedirectURL:                "http://localhost/api/fo/users/sso/callback",
+	}}
+
+	memberRepo := &stubMemberRepo{user: user}
+	operationRepo := &stubOperationRepo{}
+	oidcClient := oidc.NewClient(httpclient.NewDefaultClient(5 * time.Second))
+
+	svc := NewService(cfg, &stubAuthRepo{ssoCfg: ssoCfg}, memberRepo, nil, operationRepo, nil, nil, nil, oidcClient, nil)
+
+	return svc, memberRepo, operationRepo
+}
+
+func runSSOFlow(t *testing.T, svc Service, server *oidctest.Server) (*ssoLoginResult, error) {
+	t.Helper()
+
+	authorize, err := svc.SSOAuthorize("1")
+	require.NoError(t, err)
+
+	location, err := server.Authorize(authorize.AuthURL)
+	require.NoError(t, err)
+
+	return svc.SSOCallback(authorize.StateToken, location.Query().Get("state"), location.Query().Get("code"))
+}
+
+func TestSSOLogin(t *testing.T) {
+	server := oidctest.NewServer("front-office", "client-secret")
+	defer server.Close()
+
+	activeMember := &member.MstMember{MemberId: 7, CompanyId: 1, RoleId: 2, Active: true, Key: "api-key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		server.SetClaims(map[string]any{"sub": "u-7", "email": "member@bank.co.id", "email_verified": true})
+		svc, _, operationRepo := setupSSOService(t, server, activeMember, nil)
+
+		result, err := runSSOFlow(t, svc, server)
+		require.NoError(t, err)
+
+		claims, err := helper.ExtractClaimsFromJWT(result.AccessToken, "access-secret")
+		require.NoError(t, err)
+
+		userId, _ := helper.ExtractUserIdFromClaims(claims)
+		tokenType, _ := helper.ExtractTokenTypeFromClaims(claims)
+		assert.Equal(t, uint(7), userId)
+		assert.Equal(t, constant.TokenTypeAccess, tokenType)
+		assert.NotEmpty(t, result.RefreshToken)
+		assert.Equal(t, []string{constant.EventSignInSSO}, operationRepo.actions)
+	})
+
+	t.Run("RoleMapping", func(t *testing.T) {
+		server.SetClaims(map[string]any{"email": "member@bank.co.id", "groups": []string{"staff", "fo-admins"}})
+		svc, memberRepo, _ := setupSSOService(t, server, &member.MstMember{MemberId: 7, CompanyId: 1, RoleId: 2, Active: true}, func(c *ssoConfig) {
+			c.RoleClaim = "groups"
+			c.RoleMapping = map[string]uint{"fo-admins": 1, "staff": 2}
+		})
+
+		_, err := runSSOFlow(t, svc, server)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), memberRepo.updates["role_id"])
+	})
+
+	t.Run("MemberOfOtherCompany", func(t *testing.T) {
+		server.SetClaims(map[string]any{"email": "member@bank.co.id"})
+		svc, _, _ := setupSSOService(t, server, &member.MstMember{MemberId: 7, CompanyId: 2, Active: true}, nil)
+
+		_, err := runSSOFlow(t, svc, server)
+		assert.ErrorContains(t, err, constant.SSOUserNotAllowed)
+	})
+
+	t.Run("StateMismatch", func(t *testing.T) {
+		svc, _, _ := setupSSOService(t, server, activeMember, nil)
+
+		authorize, err := svc.SSOAuthorize("1")
+		require.NoError(t, err)
+
+		location, err := server.Authorize(authorize.AuthURL)
+		require.NoError(t, err)
+
+		_, err = svc.SSOCallback(authorize.StateToken, "forged-state", location.Query().Get("code"))
+		assert.ErrorContains(t, err, constant.InvalidSSOState)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		svc, _, _ := setupSSOService(t, server, activeMember, func(c *ssoConfig) { c.Enabled = false })
+
+		_, err := svc.SSOAuthorize("1")
+		assert.ErrorContains(t, err, constant.SSONotConfigured)
+	})
+}
+
+func TestSSOAuthorizeURL(t *testing.T) {
+	server := oidctest.NewServer("front-office", "client-secret")
+	defer server.Close()
+
+	svc, _, _ := setupSSOService(t, server, nil, nil)
+
+	result, err := svc.SSOAuthorize("1")
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(result.AuthURL)
+	require.NoError(t, err)
+
+	q := authURL.Query()
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.NotEmpty(t, q.Get("code_challenge"))
+	assert.NotEmpty(t, q.Get("nonce"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+}
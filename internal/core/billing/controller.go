@@ -27,22 +27,48 @@ type Controller interface {
 	ExportUsage(c *fiber.Ctx) error
 	SendMonthlyUsageReport(c *fiber.Ctx) error
 	GetUsageReport(c *fiber.Ctx) error
+	GetUsageBreakdown(c *fiber.Ctx) error
+	GetReconciliation(c *fiber.Ctx) error
+	ExportReconciliation(c *fiber.Ctx) error
+	GetReportSchedule(c *fiber.Ctx) error
+	UpdateReportSchedule(c *fiber.Ctx) error
+	GetInvoices(c *fiber.Ctx) error
+	DownloadInvoice(c *fiber.Ctx) error
+	GetQuotaDashboard(c *fiber.Ctx) error
+	GetQuotaAlertConfig(c *fiber.Ctx) error
+	GetBillingPolicy(c *fiber.Ctx) error
+	PreviewSheetDefinition(c *fiber.Ctx) error
+	RetrieveWorkbookPassword(c *fiber.Ctx) error
+	UpdateQuotaAlertConfig(c *fiber.Ctx) error
+	GetBalance(c *fiber.Ctx) error
+	GetTopups(c *fiber.Ctx) error
+	RequestTopup(c *fiber.Ctx) error
+	DownloadProforma(c *fiber.Ctx) error
+	CheckPaymentConfirmation(c *fiber.Ctx) error
+	SubmitPaymentConfirmation(c *fiber.Ctx) error
+	GetPaymentProof(c *fiber.Ctx) error
+	GetTopupsAwaitingApproval(c *fiber.Ctx) error
+	GetPaymentProofForReview(c *fiber.Ctx) error
+	ApproveTopup(c *fiber.Ctx) error
+	RejectTopup(c *fiber.Ctx) error
 }
 
 func (ctrl *controller) ExportUsage(c *fiber.Ctx) error {
-	var err error
-	req, err := parseDownloadRequest(c)
+	authCtx, err := helper.GetAuthContext(c)
 	if err != nil {
-		return apperror.BadRequest(err.Error())
+		return apperror.Unauthorized(err.Error())
+	}
+
+	query, err := parseUsageQuery(c, authCtx)
+	if err != nil {
+		return err
 	}
 
 	result, err := ctrl.svc.ExportUsageXlsx(downloadUsageXlsxInput{
-		CompanyId:       req.CompanyId,
-		Year:            req.Year,
-		Month:           req.Month,
-		Groups:          req.Groups,
-		PricingStrategy: req.PricingStrategy,
-		Password:        req.Password,
+		Query:           *query,
+		Groups:          parseGroups(c.Query("groups")),
+		PricingStrategy: strings.ToUpper(c.Query("pricing_strategy")),
+		RequestedBy:     authCtx.UserId,
 	})
 	if err != nil {
 		log.Error().
@@ -52,9 +78,60 @@ func (ctrl *controller) ExportUsage(c *fiber.Ctx) error {
 		return err
 	}
 
+	return sendWorkbook(c, result)
+}
+
+func (ctrl *controller) GetReconciliation(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	query, err := parseUsageQuery(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetReconciliation(authCtx, query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to reconcile usage",
+		result,
+	))
+}
+
+func (ctrl *controller) ExportReconciliation(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	query, err := parseUsageQuery(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.ExportReconciliationXlsx(authCtx, query)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("failed to generate reconciliation report")
+
+		return err
+	}
+
+	return sendWorkbook(c, result)
+}
+
+func sendWorkbook(c *fiber.Ctx, result *downloadUsageXlsxResult) error {
 	c.Set(constant.HeaderContentType, result.ContentType)
 	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
 	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+	// the workbook password is fetched once with this id
+	c.Set(constant.HeaderWorkbookPasswordId, result.PasswordId)
 
 	return c.Send(result.Data)
 }
@@ -90,6 +167,485 @@ func (ctrl *controller) GetUsageReport(c *fiber.Ctx) error {
 	))
 }
 
+func (ctrl *controller) GetUsageBreakdown(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	query, err := parseUsageQuery(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetUsageBreakdown(query)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get usage breakdown",
+		result,
+	))
+}
+
+func (ctrl *controller) GetReportSchedule(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetReportSchedule(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get usage report schedule",
+		result,
+	))
+}
+
+func (ctrl *controller) UpdateReportSchedule(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*updateReportScheduleRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.UpdateReportSchedule(authCtx, uint(companyId), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to update usage report schedule",
+		result,
+	))
+}
+
+func (ctrl *controller) GetInvoices(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetInvoices(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get invoices",
+		result,
+	))
+}
+
+func (ctrl *controller) DownloadInvoice(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	year, month, err := parseYearMonth(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.DownloadInvoice(uint(companyId), year, month, strings.ToLower(c.Query("format")))
+	if err != nil {
+		return err
+	}
+
+	c.Set(constant.HeaderContentType, result.ContentType)
+	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
+	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+
+	return c.Send(result.Data)
+}
+
+func (ctrl *controller) GetQuotaDashboard(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetQuotaDashboard(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get quota dashboard",
+		result,
+	))
+}
+
+func (ctrl *controller) GetQuotaAlertConfig(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetQuotaAlertConfig(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get quota alert config",
+		result,
+	))
+}
+
+func (ctrl *controller) GetBillingPolicy(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetBillingPolicy(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get billing policy",
+		result,
+	))
+}
+
+func (ctrl *controller) UpdateQuotaAlertConfig(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*updateQuotaAlertConfigRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.UpdateQuotaAlertConfig(authCtx, uint(companyId), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to update quota alert config",
+		result,
+	))
+}
+
+func (ctrl *controller) PreviewSheetDefinition(c *fiber.Ctx) error {
+	var req previewSheetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	result, err := ctrl.svc.PreviewSheetDefinition(&req)
+	if err != nil {
+		return err
+	}
+
+	if c.Query("format") == "xlsx" {
+		c.Set(constant.HeaderContentType, constant.MimeXlsx)
+		c.Set(constant.HeaderContentDisposition, `attachment; filename="preview_`+result.SheetName+`.xlsx"`)
+
+		return c.Send(result.Workbook)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to preview sheet definition",
+		result,
+	))
+}
+
+func (ctrl *controller) GetBalance(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetBalance(uint(companyId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get balance",
+		result,
+	))
+}
+
+func (ctrl *controller) GetTopups(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.GetTopups(uint(companyId), strings.ToLower(c.Query("status")))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get top-ups",
+		result,
+	))
+}
+
+func (ctrl *controller) RequestTopup(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*createTopupRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.RequestTopup(authCtx, uint(companyId), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(helper.SuccessResponse(
+		"succeed to request top-up",
+		result,
+	))
+}
+
+func (ctrl *controller) DownloadProforma(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctrl.svc.DownloadProforma(uint(companyId), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(constant.HeaderContentType, result.ContentType)
+	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
+	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+
+	return c.Send(result.Data)
+}
+
+// SubmitPaymentConfirmation runs after the upload middleware stored the
+// proof, the file is removed again when the confirmation fails.
+// CheckPaymentConfirmation rejects a confirmation the top-up does not take
+// before the proof is uploaded.
+func (ctrl *controller) CheckPaymentConfirmation(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.svc.CheckPaymentConfirmation(uint(companyId), c.Params("id"), c.FormValue("paid_at")); err != nil {
+		return err
+	}
+
+	return c.Next()
+}
+
+func (ctrl *controller) SubmitPaymentConfirmation(c *fiber.Ctx) error {
+	filename, _ := c.Locals("filename").(string)
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		discardPaymentProof(filename)
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		discardPaymentProof(filename)
+		return err
+	}
+
+	result, err := ctrl.svc.SubmitPaymentConfirmation(authCtx, uint(companyId), c.Params("id"), &paymentConfirmationRequest{
+		PaidAt:   c.FormValue("paid_at"),
+		Note:     c.FormValue("note"),
+		Filename: filename,
+	})
+	if err != nil {
+		discardPaymentProof(filename)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to submit payment confirmation",
+		result,
+	))
+}
+
+func (ctrl *controller) GetPaymentProof(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return err
+	}
+
+	path, err := ctrl.svc.GetPaymentProof(uint(companyId), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Download(path)
+}
+
+func (ctrl *controller) GetTopupsAwaitingApproval(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.GetTopupsAwaitingApproval(authCtx)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get top-ups awaiting approval",
+		result,
+	))
+}
+
+func (ctrl *controller) GetPaymentProofForReview(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	path, err := ctrl.svc.GetPaymentProofForReview(authCtx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Download(path)
+}
+
+func (ctrl *controller) ApproveTopup(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*reviewTopupRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.ApproveTopup(authCtx, c.Params("id"), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to approve top-up",
+		result,
+	))
+}
+
+func (ctrl *controller) RejectTopup(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*reviewTopupRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.RejectTopup(authCtx, c.Params("id"), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to reject top-up",
+		result,
+	))
+}
+
 func parseDownloadRequest(c *fiber.Ctx) (*downloadUsageXlsxRequest, error) {
 	authCtx, err := helper.GetAuthContext(c)
 	if err != nil {
@@ -115,10 +671,84 @@ func parseDownloadRequest(c *fiber.Ctx) (*downloadUsageXlsxRequest, error) {
 		Month:           month,
 		Groups:          groups,
 		PricingStrategy: pricingStrategy,
-		Password:        authCtx.APIKey,
+		RequestedBy:     authCtx.UserId,
 	}, nil
 }
 
+// parseUsageQuery reads the range, filters and grouping of a usage query.
+// Without start_date and end_date the month of year and month is used.
+func parseUsageQuery(c *fiber.Ctx, authCtx *model.AuthContext) (*usageQuery, error) {
+	companyId, err := parseAndValidateCompanyId(c, authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIds, err := parseMemberIds(c.Query("member_ids"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &usageQuery{
+		CompanyId:    uint(companyId),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		ProductSlugs: parseGroups(c.Query("product_slugs")),
+		MemberIds:    memberIds,
+		GroupBy:      strings.ToLower(strings.TrimSpace(c.Query("group_by"))),
+	}, nil
+}
+
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	start, end := c.Query(constant.StartDate), c.Query(constant.EndDate)
+
+	if start == "" && end == "" {
+		year, month, err := parseYearMonth(c)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(0, 1, -1), nil
+	}
+
+	if start == "" {
+		return time.Time{}, time.Time{}, apperror.BadRequest(constant.MissingStartDate)
+	}
+	if end == "" {
+		return time.Time{}, time.Time{}, apperror.BadRequest(constant.MissingEndDate)
+	}
+
+	startDate, err := time.Parse(constant.FormatYYYYMMDD, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, apperror.BadRequest(constant.InvalidStartDateFormat)
+	}
+
+	endDate, err := time.Parse(constant.FormatYYYYMMDD, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, apperror.BadRequest(constant.InvalidEndDateFormat)
+	}
+
+	return startDate, endDate, nil
+}
+
+func parseMemberIds(raw string) ([]uint, error) {
+	var ids []uint
+	for _, part := range parseGroups(raw) {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || id == 0 {
+			return nil, apperror.BadRequest(constant.InvalidMemberIds)
+		}
+		ids = append(ids, uint(id))
+	}
+
+	return ids, nil
+}
+
 func parseAndValidateCompanyId(c *fiber.Ctx, authCtx *model.AuthContext) (uint64, error) {
 	companyId := c.Query("company_id")
 	if companyId == "0" {
@@ -176,3 +806,20 @@ func parseGroups(raw string) []string {
 
 	return groups
 }
+
+func (ctrl *controller) RetrieveWorkbookPassword(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.RetrieveWorkbookPassword(authCtx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to retrieve workbook password, it will not be shown again",
+		result,
+	))
+}
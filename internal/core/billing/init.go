@@ -2,8 +2,11 @@ package billing
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/company"
 	"front-office/internal/core/internalteam"
+	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
 	"front-office/internal/mail"
 	"front-office/internal/middleware"
 	"front-office/pkg/httpclient"
@@ -18,28 +21,53 @@ func SetupInit(billingAPI fiber.Router, cfg *application.Config, client httpclie
 	repo := NewRepository(cfg, client, nil)
 	transactionRepo := transaction.NewRepository(cfg, client, nil)
 	internalTeamRepo := internalteam.NewRepository(cfg, client, nil)
-	service := NewService(cfg, repo, transactionRepo, internalTeamRepo, mailSvc)
+	companyRepo := company.NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	sheetDefs, err := loadSheetDefs(cfg.App.UsageSheetDefsPath)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Str("path", cfg.App.UsageSheetDefsPath).
+			Msg("invalid usage sheet definitions")
+	}
+
+	service := NewService(cfg, repo, transactionRepo, internalTeamRepo, companyRepo, memberRepo, operationRepo, mailSvc, sheetDefs)
 	controller := NewController(service)
 
 	billingAPI.Get("/usage", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetUsageReport)
+	billingAPI.Get("/usage/breakdown", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetUsageBreakdown)
 	billingAPI.Get("/usage/export", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.ExportUsage)
+	billingAPI.Get("/reconciliation", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetReconciliation)
+	billingAPI.Get("/reconciliation/export", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.ExportReconciliation)
+	billingAPI.Post("/workbook-passwords/:id/retrieve", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.RetrieveWorkbookPassword)
+	billingAPI.Get("/report-schedule", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetReportSchedule)
+	billingAPI.Put("/report-schedule", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(updateReportScheduleRequest{}), controller.UpdateReportSchedule)
+	billingAPI.Get("/invoices", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetInvoices)
+	billingAPI.Get("/invoices/download", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.DownloadInvoice)
+	billingAPI.Get("/quota", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetQuotaDashboard)
+	billingAPI.Get("/quota/alert-config", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetQuotaAlertConfig)
+	billingAPI.Put("/quota/alert-config", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(updateQuotaAlertConfigRequest{}), controller.UpdateQuotaAlertConfig)
+	billingAPI.Post("/sheet-definitions/preview", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.PreviewSheetDefinition)
+	billingAPI.Get("/policy", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetBillingPolicy)
+	billingAPI.Get("/balance", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetBalance)
+	billingAPI.Get("/topups", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetTopups)
+	billingAPI.Post("/topups", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(createTopupRequest{}), controller.RequestTopup)
+	billingAPI.Get("/topups/:id/proforma", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.DownloadProforma)
+	billingAPI.Post("/topups/:id/payment-confirmation", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.CheckPaymentConfirmation, middleware.FileUploadWithConfig(middleware.PaymentProofUpload), controller.SubmitPaymentConfirmation)
+	billingAPI.Get("/topups/:id/payment-proof", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetPaymentProof)
+	billingAPI.Get("/topup-reviews", middleware.GetJWTPayloadFromCookie(cfg), controller.GetTopupsAwaitingApproval)
+	billingAPI.Get("/topup-reviews/:id/payment-proof", middleware.GetJWTPayloadFromCookie(cfg), controller.GetPaymentProofForReview)
+	billingAPI.Put("/topup-reviews/:id/approve", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(reviewTopupRequest{}), controller.ApproveTopup)
+	billingAPI.Put("/topup-reviews/:id/reject", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(reviewTopupRequest{}), controller.RejectTopup)
 	billingAPI.Post("/send-monthly-report", controller.SendMonthlyUsageReport)
 
 	setupCron(service)
 }
 
-func targetRunDay(now time.Time) int {
-	day3 := time.Date(now.Year(), now.Month(), 3, 0, 0, 0, 0, now.Location())
-	switch day3.Weekday() {
-	case time.Saturday:
-		return 5
-	case time.Sunday:
-		return 4
-	default:
-		return 3
-	}
-}
-
+// setupCron checks the report schedules every 15 minutes, a company gets its
+// report on the first check after its delivery day and time. Low quota is
+// checked hourly.
 func setupCron(service Service) {
 	jakartaTime, err := time.LoadLocation("Asia/Jakarta")
 	if err != nil {
@@ -48,25 +76,24 @@ func setupCron(service Service) {
 
 	scd := gocron.NewScheduler(jakartaTime)
 
-	_, err = scd.Every(1).Day().At("09:00").Do(func() {
+	_, err = scd.Cron("*/15 * * * *").Do(func() {
 		now := time.Now().In(jakartaTime)
-		target := targetRunDay(now)
 
-		if now.Day() != target {
-			return
+		if err := service.SendScheduledUsageReports(now); err != nil {
+			log.Error().Err(err).Msg("failed to send scheduled usage reports")
 		}
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to register monthly usage report cron")
+	}
 
-		log.Info().
-			Int("day", now.Day()).
-			Str("weekday", now.Weekday().String()).
-			Msg("running monthly usage report")
-
-		if err := service.SendMonthlyUsageReport(); err != nil {
-			log.Error().Err(err).Msg("failed to send monthly usage report")
+	_, err = scd.Cron("0 * * * *").Do(func() {
+		if err := service.CheckQuotaAlerts(time.Now().In(jakartaTime)); err != nil {
+			log.Error().Err(err).Msg("failed to check low quota alerts")
 		}
 	})
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to register monthly usage report cron")
+		log.Fatal().Err(err).Msg("failed to register low quota alert cron")
 	}
 
 	scd.StartAsync()
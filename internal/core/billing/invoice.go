@@ -0,0 +1,286 @@
+package billing
+
+import (
+	"fmt"
+	"front-office/pkg/pdf"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// priceInvoice turns a month of paid usage into invoice lines and totals.
+// Tiered products are priced per tier (graduated), the minimum commitment
+// tops the subtotal up before PPN is applied.
+func priceInvoice(plan *pricingPlan, basePrice float64, usage []usagePerProduct) invoiceDraft {
+	prices := make(map[string]productPrice, len(plan.Products))
+	for _, p := range plan.Products {
+		prices[p.ProductSlug] = p
+	}
+
+	var draft invoiceDraft
+	for _, product := range usage {
+		if product.TotalPay <= 0 {
+			continue
+		}
+
+		var amount float64
+		price, ok := prices[product.ProductSlug]
+		switch {
+		case ok && len(price.Tiers) > 0:
+			amount = tieredAmount(product.TotalPay, price.Tiers)
+		case ok:
+			amount = float64(product.TotalPay) * price.UnitPrice
+		default:
+			amount = float64(product.TotalPay) * basePrice
+		}
+		amount = math.Round(amount)
+
+		draft.Lines = append(draft.Lines, invoiceLine{
+			ProductSlug: product.ProductSlug,
+			Description: product.ProductName,
+			Quantity:    product.TotalPay,
+			UnitPrice:   math.Round(amount/float64(product.TotalPay)*100) / 100,
+			Amount:      amount,
+		})
+		draft.Subtotal += amount
+	}
+
+	if plan.MinimumCommitment > draft.Subtotal {
+		draft.CommitmentAdjustment = math.Round(plan.MinimumCommitment - draft.Subtotal)
+	}
+
+	draft.TaxBase = draft.Subtotal + draft.CommitmentAdjustment
+	draft.VATRate = vatRate
+	draft.VAT = math.Round(draft.TaxBase * vatRate)
+	draft.Total = draft.TaxBase + draft.VAT
+
+	return draft
+}
+
+func tieredAmount(quantity int, tiers []priceTier) float64 {
+	sorted := make([]priceTier, len(tiers))
+	copy(sorted, tiers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].UpTo == 0 || sorted[j].UpTo == 0 {
+			return sorted[j].UpTo == 0 && sorted[i].UpTo != 0
+		}
+
+		return sorted[i].UpTo < sorted[j].UpTo
+	})
+
+	var amount float64
+	remaining, lower := quantity, 0
+	for _, tier := range sorted {
+		if remaining == 0 {
+			break
+		}
+
+		units := remaining
+		if tier.UpTo > 0 {
+			units = min(remaining, tier.UpTo-lower)
+			lower = tier.UpTo
+		}
+
+		amount += float64(units) * tier.UnitPrice
+		remaining -= units
+	}
+
+	// usage above the last bounded tier stays at its price
+	if remaining > 0 {
+		amount += float64(remaining) * sorted[len(sorted)-1].UnitPrice
+	}
+
+	return amount
+}
+
+func invoiceFilename(inv *invoice, format string) string {
+	number := strings.NewReplacer("/", "-", " ", "_").Replace(inv.Number)
+
+	return fmt.Sprintf("invoice_%s_%d_%02d.%s", number, inv.PeriodYear, inv.PeriodMonth, format)
+}
+
+func invoicePeriod(inv *invoice) string {
+	return fmt.Sprintf("%s %d", time.Month(inv.PeriodMonth), inv.PeriodYear)
+}
+
+// formatRupiah formats whole rupiah with dots as thousand separators.
+func formatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(math.Abs(math.Round(amount))), 10)
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	if amount < 0 {
+		return "-Rp " + b.String()
+	}
+
+	return "Rp " + b.String()
+}
+
+func renderInvoicePDF(inv *invoice) ([]byte, error) {
+	const (
+		left       = 40.0
+		right      = pdf.PageWidth - 40
+		colQty     = 330.0
+		colUnit    = 435.0
+		lineHeight = 16.0
+		bottom     = pdf.PageHeight - 80
+	)
+
+	doc := pdf.New()
+	doc.AddPage()
+
+	doc.Text(left, 60, pdf.HelveticaBold, 20, "INVOICE")
+	doc.TextRight(right, 52, pdf.HelveticaBold, 10, inv.Number)
+	doc.TextRight(right, 66, pdf.Helvetica, 9, "Issued "+inv.IssuedAt.Format("02 January 2006"))
+
+	doc.Text(left, 100, pdf.HelveticaBold, 10, "Billed to")
+	doc.Text(left, 114, pdf.Helvetica, 10, inv.CompanyName)
+	y := 128.0
+	if inv.CompanyAddress != "" {
+		doc.Text(left, y, pdf.Helvetica, 9, inv.CompanyAddress)
+		y += 14
+	}
+	doc.Text(left, y, pdf.Helvetica, 9, "Period: "+invoicePeriod(inv))
+	if inv.PaymentScheme != "" {
+		doc.Text(left, y+14, pdf.Helvetica, 9, "Payment scheme: "+inv.PaymentScheme)
+	}
+
+	y = 190
+	header := func() {
+		doc.Text(left, y, pdf.HelveticaBold, 9, "Product")
+		doc.TextRight(colQty, y, pdf.HelveticaBold, 9, "Qty")
+		doc.TextRight(colUnit, y, pdf.HelveticaBold, 9, "Unit price")
+		doc.TextRight(right, y, pdf.HelveticaBold, 9, "Amount")
+		doc.Line(left, y+5, right, y+5)
+		y += lineHeight + 4
+	}
+	header()
+
+	for _, line := range inv.Lines {
+		if y > bottom {
+			doc.AddPage()
+			y = 60
+			header()
+		}
+
+		doc.Text(left, y, pdf.Helvetica, 9, line.Description)
+		doc.TextRight(colQty, y, pdf.Courier, 9, strconv.Itoa(line.Quantity))
+		doc.TextRight(colUnit, y, pdf.Courier, 9, formatRupiah(line.UnitPrice))
+		doc.TextRight(right, y, pdf.Courier, 9, formatRupiah(line.Amount))
+		y += lineHeight
+	}
+
+	if y > bottom-5*lineHeight {
+		doc.AddPage()
+		y = 60
+	}
+
+	doc.Line(left, y-10, right, y-10)
+	y += 4
+
+	total := func(label, value string, font pdf.Font) {
+		doc.TextRight(colUnit, y, font, 9, label)
+		doc.TextRight(right, y, pdf.Courier, 9, value)
+		y += lineHeight
+	}
+
+	total("Subtotal", formatRupiah(inv.Subtotal), pdf.Helvetica)
+	if inv.CommitmentAdjustment > 0 {
+		total("Minimum commitment adjustment", formatRupiah(inv.CommitmentAdjustment), pdf.Helvetica)
+	}
+	total(fmt.Sprintf("PPN %g%%", inv.VATRate*100), formatRupiah(inv.VAT), pdf.Helvetica)
+	total("Total", formatRupiah(inv.Total), pdf.HelveticaBold)
+
+	return doc.Bytes()
+}
+
+func renderInvoiceXlsx(inv *invoice) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Invoice"
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return nil, err
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	money, err := f.NewStyle(&excelize.Style{CustomNumFmt: strPtr(`"Rp "#,##0`)})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]interface{}{
+		{"Invoice", inv.Number},
+		{"Issued", inv.IssuedAt.Format("2006-01-02")},
+		{"Company", inv.CompanyName},
+		{"Period", invoicePeriod(inv)},
+		{},
+		{"Product", "Qty", "Unit price", "Amount"},
+	}
+	headerRow := len(rows)
+
+	for _, line := range inv.Lines {
+		rows = append(rows, []interface{}{line.Description, line.Quantity, line.UnitPrice, line.Amount})
+	}
+	firstMoneyRow := headerRow + 1
+
+	rows = append(rows, []interface{}{})
+	rows = append(rows, []interface{}{"", "", "Subtotal", inv.Subtotal})
+	if inv.CommitmentAdjustment > 0 {
+		rows = append(rows, []interface{}{"", "", "Minimum commitment adjustment", inv.CommitmentAdjustment})
+	}
+	rows = append(rows, []interface{}{"", "", fmt.Sprintf("PPN %g%%", inv.VATRate*100), inv.VAT})
+	rows = append(rows, []interface{}{"", "", "Total", inv.Total})
+
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return nil, err
+		}
+	}
+
+	lastRow := len(rows)
+	if err := f.SetCellStyle(sheet, fmt.Sprintf("A%d", headerRow), fmt.Sprintf("D%d", headerRow), bold); err != nil {
+		return nil, err
+	}
+	if err := f.SetCellStyle(sheet, fmt.Sprintf("C%d", firstMoneyRow), fmt.Sprintf("D%d", lastRow), money); err != nil {
+		return nil, err
+	}
+	if err := f.SetCellStyle(sheet, fmt.Sprintf("C%d", lastRow), fmt.Sprintf("C%d", lastRow), bold); err != nil {
+		return nil, err
+	}
+	if err := f.SetColWidth(sheet, "A", "A", 36); err != nil {
+		return nil, err
+	}
+	if err := f.SetColWidth(sheet, "B", "D", 18); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
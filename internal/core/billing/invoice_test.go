@@ -0,0 +1,115 @@
+package billing
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestTieredAmount(t *testing.T) {
+	tiers := []priceTier{
+		{UpTo: 0, UnitPrice: 500},
+		{UpTo: 100, UnitPrice: 1000},
+		{UpTo: 1000, UnitPrice: 800},
+	}
+
+	assert.Equal(t, 50_000.0, tieredAmount(50, tiers))
+	assert.Equal(t, 100_000.0+80_000, tieredAmount(200, tiers))
+	assert.Equal(t, 100_000.0+720_000+500*500, tieredAmount(1500, tiers))
+
+	// without an unbounded tier the last price keeps applying
+	assert.Equal(t, 2*1000.0+3*800, tieredAmount(5, []priceTier{{UpTo: 2, UnitPrice: 1000}, {UpTo: 4, UnitPrice: 800}}))
+}
+
+func TestPriceInvoice(t *testing.T) {
+	plan := &pricingPlan{
+		Products: []productPrice{
+			{ProductSlug: "loan-record-checker", UnitPrice: 2000},
+			{ProductSlug: "phone-live-status", Tiers: []priceTier{{UpTo: 10, UnitPrice: 1000}, {UnitPrice: 500}}},
+		},
+	}
+	usage := []usagePerProduct{
+		{ProductSlug: "loan-record-checker", ProductName: "Loan Record Checker", TotalPay: 10},
+		{ProductSlug: "phone-live-status", ProductName: "Phone Live Status", TotalPay: 20},
+		{ProductSlug: "npwp-verification", ProductName: "NPWP Verification", TotalPay: 3},
+		{ProductSlug: "tax-score", ProductName: "Tax Score", TotalPay: 0},
+	}
+
+	t.Run("prices each product", func(t *testing.T) {
+		draft := priceInvoice(plan, 1500, usage)
+
+		require.Len(t, draft.Lines, 3)
+		assert.Equal(t, 20_000.0, draft.Lines[0].Amount)
+		assert.Equal(t, 15_000.0, draft.Lines[1].Amount)
+		assert.Equal(t, 750.0, draft.Lines[1].UnitPrice)
+		assert.Equal(t, 4_500.0, draft.Lines[2].Amount)
+
+		assert.Equal(t, 39_500.0, draft.Subtotal)
+		assert.Zero(t, draft.CommitmentAdjustment)
+		assert.Equal(t, 4_345.0, draft.VAT)
+		assert.Equal(t, 43_845.0, draft.Total)
+	})
+
+	t.Run("tops up to the minimum commitment", func(t *testing.T) {
+		committed := *plan
+		committed.MinimumCommitment = 100_000
+
+		draft := priceInvoice(&committed, 1500, usage)
+
+		assert.Equal(t, 60_500.0, draft.CommitmentAdjustment)
+		assert.Equal(t, 100_000.0, draft.TaxBase)
+		assert.Equal(t, 11_000.0, draft.VAT)
+		assert.Equal(t, 111_000.0, draft.Total)
+	})
+}
+
+func TestFormatRupiah(t *testing.T) {
+	assert.Equal(t, "Rp 0", formatRupiah(0))
+	assert.Equal(t, "Rp 999", formatRupiah(999))
+	assert.Equal(t, "Rp 1.000", formatRupiah(1000))
+	assert.Equal(t, "Rp 12.345.678", formatRupiah(12_345_678))
+	assert.Equal(t, "-Rp 1.500", formatRupiah(-1500))
+}
+
+func TestRenderInvoice(t *testing.T) {
+	inv := &invoice{
+		Id:       1,
+		Number:   "INV/2025/03/0001",
+		IssuedAt: time.Date(2025, time.April, 3, 9, 0, 0, 0, time.UTC),
+		invoiceDraft: priceInvoice(&pricingPlan{}, 1000, []usagePerProduct{
+			{ProductSlug: "loan-record-checker", ProductName: "Loan Record Checker", TotalPay: 10},
+		}),
+	}
+	inv.CompanyName = "PT Example"
+	inv.PeriodYear = 2025
+	inv.PeriodMonth = 3
+
+	t.Run("pdf", func(t *testing.T) {
+		file, err := renderInvoice(inv, invoiceFormatPDF)
+
+		require.NoError(t, err)
+		assert.Equal(t, "invoice_INV-2025-03-0001_2025_03.pdf", file.Filename)
+		assert.True(t, bytes.HasPrefix(file.Data, []byte("%PDF-")))
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		file, err := renderInvoice(inv, invoiceFormatXlsx)
+		require.NoError(t, err)
+
+		f, err := excelize.OpenReader(bytes.NewReader(file.Data))
+		require.NoError(t, err)
+		defer f.Close()
+
+		number, err := f.GetCellValue("Invoice", "B1")
+		require.NoError(t, err)
+		assert.Equal(t, inv.Number, number)
+
+		rows, err := f.GetRows("Invoice")
+		require.NoError(t, err)
+		assert.Equal(t, "Total", rows[len(rows)-1][2])
+	})
+}
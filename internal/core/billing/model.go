@@ -1,8 +1,10 @@
 package billing
 
 import (
+	"encoding/json"
 	"front-office/internal/core/log/transaction"
 	"front-office/pkg/common/constant"
+	"time"
 )
 
 type downloadUsageXlsxRequest struct {
@@ -11,22 +13,21 @@ type downloadUsageXlsxRequest struct {
 	Month           int
 	Groups          []string // opsional, example: "procat,scoreezy"
 	PricingStrategy string   // opsional, default: "PAY"
-	Password        string
+	RequestedBy     uint
 }
 
 type downloadUsageXlsxInput struct {
-	CompanyId       uint
-	Year            int
-	Month           int
+	Query           usageQuery
 	Groups          []string
 	PricingStrategy string // kosong = default constant.PaidStatus
-	Password        string
+	RequestedBy     uint
 }
 
 type downloadUsageXlsxResult struct {
 	Filename    string
 	ContentType string
 	Data        []byte
+	PasswordId  string
 }
 
 const (
@@ -34,12 +35,58 @@ const (
 	groupKeyScorezy = "scoreezy"
 )
 
+const (
+	usageGroupByDay     = "day"
+	usageGroupByWeek    = "week"
+	usageGroupByMonth   = "month"
+	usageGroupByMember  = "member"
+	usageGroupByProduct = "product"
+
+	maxUsageRangeDays = 366
+)
+
+// usageQuery selects the usage of a company between StartDate and EndDate,
+// both inclusive. Empty ProductSlugs or MemberIds select everything.
+type usageQuery struct {
+	CompanyId    uint
+	StartDate    time.Time
+	EndDate      time.Time
+	ProductSlugs []string
+	MemberIds    []uint
+	GroupBy      string
+}
+
+// usageBucket counts the hits of one group, PayRequest and FreeRequest are
+// the hits billed with the PAY and FREE pricing strategy.
+type usageBucket struct {
+	Key          string `json:"key"`
+	Label        string `json:"label"`
+	PayRequest   int    `json:"pay_request"`
+	FreeRequest  int    `json:"free_request"`
+	TotalRequest int    `json:"total_request"`
+}
+
+type usageBreakdown struct {
+	CompanyId        uint              `json:"company_id"`
+	CompanyName      string            `json:"company_name"`
+	StartDate        string            `json:"start_date"`
+	EndDate          string            `json:"end_date"`
+	GroupBy          string            `json:"group_by"`
+	ProductSlugs     []string          `json:"product_slugs,omitempty"`
+	MemberIds        []uint            `json:"member_ids,omitempty"`
+	Buckets          []usageBucket     `json:"buckets"`
+	Total            usageBucket       `json:"total"`
+	ProcatProducts   []usagePerProduct `json:"procat_products"`
+	ScoreezyProducts []usagePerProduct `json:"scoreezy_products"`
+}
+
 type usagePerProduct struct {
 	ProductId    uint   `json:"product_id"`
 	ProductSlug  string `json:"product_slug"`
 	ProductName  string `json:"product_name"`
 	TotalRequest int    `json:"total_request"`
 	TotalPay     int    `json:"total_pay"`
+	FreeHits     int    `json:"free_hits,omitempty"`
 }
 
 type usageSummary struct {
@@ -54,6 +101,205 @@ type usageSummary struct {
 	// GrandTotalPay     int64             `json:"grand_total_pay"`
 }
 
+// billingPolicy decides how a company's usage is counted. Dedup collapses
+// repeated hits on the same input within DedupWindowDays, limited to
+// DedupProducts when set. FreeHits are deducted from the paid hits of a
+// product every period.
+type billingPolicy struct {
+	CompanyId       uint          `json:"company_id"`
+	Dedup           bool          `json:"dedup"`
+	DedupWindowDays int           `json:"dedup_window_days"`
+	DedupProducts   []string      `json:"dedup_products"`
+	FreeHits        []freeHitRule `json:"free_hits"`
+	UpdatedAt       *time.Time    `json:"updated_at,omitempty"`
+}
+
+type freeHitRule struct {
+	ProductSlug string `json:"product_slug"`
+	Count       int    `json:"count"`
+}
+
+const (
+	defaultReportDeliveryDay  = 3
+	defaultReportDeliveryTime = "09:00"
+	maxReportDeliveryDay      = 28
+	reportDeliveryTimeLayout  = "15:04"
+	reportPeriodLayout        = "2006-01"
+)
+
+// reportSchedule controls who receives the monthly usage report of a company
+// and when. Companies without a stored schedule use defaultReportSchedule.
+type reportSchedule struct {
+	CompanyId           uint     `json:"company_id"`
+	To                  []string `json:"to"`
+	CC                  []string `json:"cc"`
+	BCC                 []string `json:"bcc"`
+	IncludeAdmins       bool     `json:"include_admins"`
+	IncludeInternalTeam bool     `json:"include_internal_team"`
+	DeliveryDay         int      `json:"delivery_day"`
+	DeliveryTime        string   `json:"delivery_time"`
+	OptOut              bool     `json:"opt_out"`
+	// LastSentPeriod is the month, as "2006-01", of the last report sent
+	LastSentPeriod string `json:"last_sent_period,omitempty"`
+}
+
+type updateReportScheduleRequest struct {
+	To                  []string `json:"to"`
+	CC                  []string `json:"cc"`
+	BCC                 []string `json:"bcc"`
+	IncludeAdmins       bool     `json:"include_admins"`
+	IncludeInternalTeam bool     `json:"include_internal_team"`
+	DeliveryDay         int      `json:"delivery_day" validate:"required~Field Delivery Day is required"`
+	DeliveryTime        string   `json:"delivery_time" validate:"required~Field Delivery Time is required"`
+	OptOut              bool     `json:"opt_out"`
+}
+
+type reportRecipients struct {
+	To  []string
+	CC  []string
+	BCC []string
+}
+
+const (
+	invoiceFormatPDF  = "pdf"
+	invoiceFormatXlsx = "xlsx"
+
+	// PPN
+	vatRate = 0.11
+)
+
+// priceTier prices the units up to and including UpTo, counted from the end
+// of the previous tier. UpTo 0 marks the last, unbounded tier.
+type priceTier struct {
+	UpTo      int     `json:"up_to"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+type productPrice struct {
+	ProductSlug string      `json:"product_slug"`
+	UnitPrice   float64     `json:"unit_price"`
+	Tiers       []priceTier `json:"tiers"`
+}
+
+// pricingPlan is the negotiated pricing of a company. Products without an
+// entry are charged at the company base pricing.
+type pricingPlan struct {
+	CompanyId         uint           `json:"company_id"`
+	MinimumCommitment float64        `json:"minimum_commitment"`
+	Products          []productPrice `json:"products"`
+}
+
+type invoiceLine struct {
+	ProductSlug string  `json:"product_slug"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+type invoiceDraft struct {
+	CompanyId            uint          `json:"company_id"`
+	CompanyName          string        `json:"company_name"`
+	CompanyAddress       string        `json:"company_address"`
+	PaymentScheme        string        `json:"payment_scheme"`
+	PeriodYear           int           `json:"period_year"`
+	PeriodMonth          int           `json:"period_month"`
+	Lines                []invoiceLine `json:"lines"`
+	Subtotal             float64       `json:"subtotal"`
+	CommitmentAdjustment float64       `json:"commitment_adjustment"`
+	TaxBase              float64       `json:"tax_base"`
+	VATRate              float64       `json:"vat_rate"`
+	VAT                  float64       `json:"vat"`
+	Total                float64       `json:"total"`
+}
+
+// invoice is an issued invoice, the number is assigned by the core service.
+type invoice struct {
+	Id       uint      `json:"id"`
+	Number   string    `json:"number"`
+	IssuedAt time.Time `json:"issued_at"`
+	invoiceDraft
+}
+
+type invoiceFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+const (
+	quotaTypeNone       = 0
+	quotaTypeTotal      = 1
+	quotaTypePerProduct = 2
+
+	defaultLowQuotaThreshold = 100
+)
+
+// quotaAlertConfig sets when the admins of a company are warned about low
+// quota. An alert goes out at most once a day, tracked by LastAlertedAt.
+type quotaAlertConfig struct {
+	CompanyId     uint       `json:"company_id"`
+	Enabled       bool       `json:"enabled"`
+	Threshold     int        `json:"threshold"`
+	Recipients    []string   `json:"recipients"`
+	LastAlertedAt *time.Time `json:"last_alerted_at"`
+}
+
+type updateQuotaAlertConfigRequest struct {
+	Enabled    bool     `json:"enabled"`
+	Threshold  int      `json:"threshold" validate:"required~Field Threshold is required"`
+	Recipients []string `json:"recipients"`
+}
+
+type productQuotaUsage struct {
+	ProductId           uint    `json:"product_id"`
+	ProductSlug         string  `json:"product_slug"`
+	ProductName         string  `json:"product_name"`
+	MonthToDateRequests int     `json:"month_to_date_requests"`
+	MonthToDatePaid     int     `json:"month_to_date_paid"`
+	ProjectedPaid       int     `json:"projected_paid"`
+	MonthToDateSpend    float64 `json:"month_to_date_spend"`
+	ProjectedSpend      float64 `json:"projected_spend"`
+}
+
+type productQuota struct {
+	ProductSlug string `json:"product_slug"`
+	ProductName string `json:"product_name"`
+	Remaining   int    `json:"remaining"`
+	Low         bool   `json:"low"`
+}
+
+type memberQuota struct {
+	MemberId       uint           `json:"member_id"`
+	Name           string         `json:"name"`
+	Email          string         `json:"email"`
+	QuotaType      int8           `json:"quota_type"`
+	RemainingTotal *int           `json:"remaining_total,omitempty"`
+	Products       []productQuota `json:"products,omitempty"`
+	Low            bool           `json:"low"`
+}
+
+type quotaDashboard struct {
+	CompanyId        uint                `json:"company_id"`
+	CompanyName      string              `json:"company_name"`
+	PeriodYear       int                 `json:"period_year"`
+	PeriodMonth      int                 `json:"period_month"`
+	GeneratedAt      time.Time           `json:"generated_at"`
+	Threshold        int                 `json:"threshold"`
+	Products         []productQuotaUsage `json:"products"`
+	Members          []memberQuota       `json:"members"`
+	MonthToDateSpend float64             `json:"month_to_date_spend"`
+	ProjectedSpend   float64             `json:"projected_spend"`
+}
+
+type QuotaAlertTemplateData struct {
+	Subject     string
+	CompanyName string
+	Threshold   int
+	Members     []memberQuota
+	Year        int
+}
+
 type adminEmail struct {
 	MemberId  uint   `json:"member_id"`
 	Name      string `json:"name"`
@@ -86,7 +332,101 @@ type XlsxReportInput struct {
 	PeriodMonth     int
 	ProductGroups   []ProductGroup
 	PricingStrategy string
-	Password        string
+	// Breakdown adds a summary sheet with the grouped PAY/FREE totals
+	Breakdown *usageBreakdown
+}
+
+const (
+	reconStatusMatched  = "matched"
+	reconStatusMismatch = "mismatch"
+
+	reconReasonJobTotal     = "job_total_differs_from_logged"
+	reconReasonBilledPay    = "billed_pay_differs_from_logged"
+	reconReasonBilledFree   = "billed_free_differs_from_logged"
+	reconReasonJobNotFound  = "job_not_found"
+	reconReasonDuplicateTrx = "duplicate_transaction_id"
+	reconReasonUnknownPrice = "unknown_pricing_strategy"
+)
+
+type companyJob struct {
+	JobId        uint   `json:"job_id"`
+	ProductId    uint   `json:"product_id"`
+	MemberId     uint   `json:"member_id"`
+	CompanyId    uint   `json:"company_id"`
+	Total        int    `json:"total"`
+	SuccessCount int    `json:"success_count"`
+	Status       string `json:"status"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+}
+
+type companyJobList struct {
+	Jobs      []companyJob `json:"jobs"`
+	TotalData int64        `json:"total_data"`
+}
+
+// reconciliationReport compares, per product, the rows the jobs say they
+// processed, the transactions that were logged and the hits that were
+// billed, and lists the jobs and transactions behind every difference.
+type reconciliationReport struct {
+	CompanyId    uint                     `json:"company_id"`
+	CompanyName  string                   `json:"company_name"`
+	StartDate    string                   `json:"start_date"`
+	EndDate      string                   `json:"end_date"`
+	Status       string                   `json:"status"`
+	Products     []productReconciliation  `json:"products"`
+	Jobs         []jobDiscrepancy         `json:"jobs"`
+	Transactions []transactionDiscrepancy `json:"transactions"`
+}
+
+type productReconciliation struct {
+	ProductId   uint   `json:"product_id"`
+	ProductSlug string `json:"product_slug"`
+	ProductName string `json:"product_name"`
+	JobCount    int    `json:"job_count"`
+	JobTotal    int    `json:"job_total"`
+	JobSuccess  int    `json:"job_success"`
+	Logged      int    `json:"logged"`
+	LoggedPay   int    `json:"logged_pay"`
+	LoggedFree  int    `json:"logged_free"`
+	BilledPay   int    `json:"billed_pay"`
+	BilledFree  int    `json:"billed_free"`
+	// Deduplicated products may bill fewer hits than were logged
+	Deduplicated bool     `json:"deduplicated"`
+	Status       string   `json:"status"`
+	Reasons      []string `json:"reasons,omitempty"`
+}
+
+type jobDiscrepancy struct {
+	JobId        uint   `json:"job_id"`
+	ProductSlug  string `json:"product_slug"`
+	MemberId     uint   `json:"member_id"`
+	Status       string `json:"status"`
+	JobTotal     int    `json:"job_total"`
+	SuccessCount int    `json:"success_count"`
+	Logged       int    `json:"logged"`
+	Reason       string `json:"reason"`
+}
+
+type transactionDiscrepancy struct {
+	TransactionId   string `json:"transaction_id"`
+	JobId           uint   `json:"job_id"`
+	ProductSlug     string `json:"product_slug"`
+	PricingStrategy string `json:"pricing_strategy"`
+	Reason          string `json:"reason"`
+}
+
+// previewSheetRequest renders Definition against sample logs of its source.
+type previewSheetRequest struct {
+	Definition productSheetSpec  `json:"definition"`
+	Logs       []json.RawMessage `json:"logs"`
+}
+
+type sheetPreview struct {
+	SheetName string     `json:"sheet_name"`
+	Headers   []string   `json:"headers"`
+	Rows      [][]string `json:"rows"`
+	Workbook  []byte     `json:"-"`
 }
 
 type ColumnType int
@@ -131,6 +471,55 @@ type MonthlyUsageTemplateData struct {
 	Month    string
 	Year     int
 	Products []TemplateProduct
+	// the workbook password is sent in a separate email
+	PasswordSentSeparately bool
+}
+
+type TopupTemplateData struct {
+	Subject         string
+	CompanyName     string
+	Heading         string
+	Message         string
+	ReferenceNumber string
+	Amount          string
+	Status          string
+	Note            string
+	Year            int
+}
+
+type WorkbookPasswordTemplateData struct {
+	Subject   string
+	Name      string
+	Filename  string
+	Password  string
+	ExpiresAt string
+	Year      int
+}
+
+const (
+	workbookPasswordChannelEmail = "email"
+	workbookPasswordChannelInApp = "in_app"
+)
+
+// workbookPassword is a one-time password protecting a single generated
+// workbook. The core only returns Password when the password is claimed.
+type workbookPassword struct {
+	Id          string     `json:"id"`
+	CompanyId   uint       `json:"company_id"`
+	MemberId    uint       `json:"member_id"`
+	Recipient   string     `json:"recipient,omitempty"`
+	Filename    string     `json:"filename"`
+	Channel     string     `json:"channel"`
+	Password    string     `json:"password,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RetrievedAt *time.Time `json:"retrieved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type workbookPasswordResponse struct {
+	Filename  string    `json:"filename"`
+	Password  string    `json:"password"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type subscribedProduct struct {
@@ -138,11 +527,6 @@ type subscribedProduct struct {
 	ProductName string `json:"product_name"`
 }
 
-var dedupCompanyNames = []string{
-	"pt. bank raya indonesia",
-	"pt. bank raya indonesia (pinang maxima)",
-}
-
 var productRegistry = map[string]ProductSheetDef{
 	constant.SlugGenRetailV3:           productGenRetail,
 	constant.SlugLoanRecordChecker:     productLoanRecord,
@@ -326,3 +710,73 @@ var productTaxVerification = ProductSheetDef{
 		{Header: constant.CSVHeaderDateCreated, Type: ColTypeDateTime, Width: 22, ExtractFn: ProcatExtractCreatedAt},
 	},
 }
+
+const (
+	topupStatusPendingPayment  = "pending_payment"
+	topupStatusWaitingApproval = "waiting_approval"
+	topupStatusApproved        = "approved"
+	topupStatusRejected        = "rejected"
+
+	minTopupAmount     = 100000
+	topupPaymentWindow = 7 * 24 * time.Hour
+)
+
+type balance struct {
+	CompanyId uint       `json:"company_id"`
+	Amount    float64    `json:"amount"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// topup is a prepaid balance top-up. It waits for payment until the member
+// uploads a proof, then for an internal team member to approve or reject it.
+type topup struct {
+	Id              uint       `json:"id"`
+	CompanyId       uint       `json:"company_id"`
+	ReferenceNumber string     `json:"reference_number"`
+	Amount          float64    `json:"amount"`
+	Status          string     `json:"status"`
+	Note            string     `json:"note"`
+	RequestedBy     uint       `json:"requested_by"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	PaymentProof    string     `json:"payment_proof,omitempty"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	PaymentNote     string     `json:"payment_note,omitempty"`
+	SubmittedBy     uint       `json:"submitted_by,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+	ReviewedBy      uint       `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote      string     `json:"review_note,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type topupFilter struct {
+	CompanyId string
+	Status    string
+}
+
+type createTopupRequest struct {
+	Amount float64 `json:"amount" validate:"required~Field Amount is required"`
+	Note   string  `json:"note"`
+}
+
+type paymentConfirmationRequest struct {
+	PaidAt   string
+	Note     string
+	Filename string
+}
+
+type reviewTopupRequest struct {
+	Note string `json:"note"`
+}
+
+type topupReview struct {
+	ReviewedBy uint      `json:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+	ReviewNote string    `json:"review_note"`
+}
+
+// topupApproval is returned by the core once the balance has been credited.
+type topupApproval struct {
+	Topup   *topup   `json:"topup"`
+	Balance *balance `json:"balance"`
+}
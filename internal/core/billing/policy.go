@@ -0,0 +1,89 @@
+package billing
+
+import (
+	"errors"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+func (svc *service) GetBillingPolicy(companyId uint) (*billingPolicy, error) {
+	return svc.getBillingPolicy(companyId)
+}
+
+// getBillingPolicy returns the company's policy, companies without one are
+// billed for every hit.
+func (svc *service) getBillingPolicy(companyId uint) (*billingPolicy, error) {
+	policy, err := svc.repo.GetBillingPolicyAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchBillingPolicy)
+		}
+	}
+	if policy == nil || policy.CompanyId == 0 {
+		policy = &billingPolicy{CompanyId: companyId}
+	}
+
+	return policy, nil
+}
+
+// getUsageSummary fetches the company's usage of the period counted and
+// priced the way its billing policy says.
+func (svc *service) getUsageSummary(companyId uint, pricingStrategy string, month, year int) (*usageSummary, *billingPolicy, error) {
+	policy, err := svc.getBillingPolicy(companyId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := svc.repo.GetUsageReportByCompany(
+		strconv.FormatUint(uint64(companyId), 10),
+		pricingStrategy,
+		strconv.Itoa(month),
+		strconv.Itoa(year),
+		policy,
+	)
+	if err != nil {
+		return nil, nil, apperror.MapRepoError(err, "failed to get usage report")
+	}
+
+	applyFreeHits(summary, policy)
+
+	return summary, policy, nil
+}
+
+// dedupApplies reports whether hits of the product are deduplicated, an
+// empty product list covers every product.
+func (p *billingPolicy) dedupApplies(productSlug string) bool {
+	if p == nil || !p.Dedup {
+		return false
+	}
+
+	return len(p.DedupProducts) == 0 || slices.Contains(p.DedupProducts, productSlug)
+}
+
+// applyFreeHits deducts the free hits of each product from its paid hits.
+func applyFreeHits(summary *usageSummary, policy *billingPolicy) {
+	if summary == nil || policy == nil || len(policy.FreeHits) == 0 {
+		return
+	}
+
+	free := make(map[string]int, len(policy.FreeHits))
+	for _, rule := range policy.FreeHits {
+		free[rule.ProductSlug] += rule.Count
+	}
+
+	for _, products := range [][]usagePerProduct{summary.ProcatProducts, summary.ScoreezyProducts} {
+		for i := range products {
+			count := min(free[products[i].ProductSlug], products[i].TotalPay)
+			if count <= 0 {
+				continue
+			}
+
+			products[i].FreeHits = count
+			products[i].TotalPay -= count
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package billing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsageSummary(t *testing.T) {
+	t.Run("no policy bills every hit", func(t *testing.T) {
+		svc, client, _ := setupQuotaService(t, quotaRoutes(nil))
+
+		summary, policy, err := svc.getUsageSummary(1, "paid", 6, 2025)
+		require.NoError(t, err)
+
+		assert.Equal(t, &billingPolicy{CompanyId: 1}, policy)
+		assert.Equal(t, 100, summary.ProcatProducts[0].TotalPay)
+
+		last := client.requests[len(client.requests)-1]
+		assert.Empty(t, last.URL.Query().Get("apply_dedup"))
+	})
+
+	t.Run("policy is forwarded and free hits deducted", func(t *testing.T) {
+		routes := quotaRoutes(nil)
+		routes["GET /api/core/billing/policies/1"] = func(*http.Request) (int, any) {
+			return http.StatusOK, billingPolicy{
+				CompanyId:       1,
+				Dedup:           true,
+				DedupWindowDays: 30,
+				DedupProducts:   []string{"loan-record-checker", "phone-live-status"},
+				FreeHits:        []freeHitRule{{ProductSlug: "phone-live-status", Count: 20}},
+			}
+		}
+
+		svc, client, _ := setupQuotaService(t, routes)
+
+		summary, _, err := svc.getUsageSummary(1, "paid", 6, 2025)
+		require.NoError(t, err)
+
+		assert.Equal(t, 100, summary.ProcatProducts[0].TotalPay)
+		assert.Equal(t, 30, summary.ScoreezyProducts[0].TotalPay)
+		assert.Equal(t, 20, summary.ScoreezyProducts[0].FreeHits)
+
+		q := client.requests[len(client.requests)-1].URL.Query()
+		assert.Equal(t, "true", q.Get("apply_dedup"))
+		assert.Equal(t, "30", q.Get("dedup_window_days"))
+		assert.Equal(t, "loan-record-checker,phone-live-status", q.Get("dedup_products"))
+	})
+
+	t.Run("policy lookup fails", func(t *testing.T) {
+		routes := quotaRoutes(nil)
+		routes["GET /api/core/billing/policies/1"] = func(*http.Request) (int, any) {
+			return http.StatusInternalServerError, nil
+		}
+
+		svc, _, _ := setupQuotaService(t, routes)
+
+		_, _, err := svc.getUsageSummary(1, "paid", 6, 2025)
+		assert.Error(t, err)
+	})
+}
+
+func TestDedupApplies(t *testing.T) {
+	var none *billingPolicy
+	assert.False(t, none.dedupApplies("tax-score"))
+	assert.False(t, (&billingPolicy{}).dedupApplies("tax-score"))
+	assert.True(t, (&billingPolicy{Dedup: true}).dedupApplies("tax-score"))
+
+	policy := &billingPolicy{Dedup: true, DedupProducts: []string{"loan-record-checker"}}
+	assert.True(t, policy.dedupApplies("loan-record-checker"))
+	assert.False(t, policy.dedupApplies("tax-score"))
+}
+
+func TestApplyFreeHits(t *testing.T) {
+	summary := &usageSummary{
+		ProcatProducts: []usagePerProduct{
+			{ProductSlug: "tax-score", TotalRequest: 12, TotalPay: 10},
+			{ProductSlug: "loan-record-checker", TotalRequest: 40, TotalPay: 30},
+		},
+		ScoreezyProducts: []usagePerProduct{
+			{ProductSlug: "gen-retail-v3", TotalRequest: 5, TotalPay: 5},
+		},
+	}
+
+	applyFreeHits(summary, &billingPolicy{FreeHits: []freeHitRule{
+		{ProductSlug: "tax-score", Count: 25},
+		{ProductSlug: "loan-record-checker", Count: 5},
+	}})
+
+	assert.Equal(t, 0, summary.ProcatProducts[0].TotalPay)
+	assert.Equal(t, 10, summary.ProcatProducts[0].FreeHits)
+	assert.Equal(t, 25, summary.ProcatProducts[1].TotalPay)
+	assert.Equal(t, 5, summary.ProcatProducts[1].FreeHits)
+	assert.Equal(t, 5, summary.ScoreezyProducts[0].TotalPay)
+	assert.Zero(t, summary.ScoreezyProducts[0].FreeHits)
+	assert.Equal(t, 12, summary.ProcatProducts[0].TotalRequest)
+}
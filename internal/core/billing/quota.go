@@ -0,0 +1,379 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"math"
+	"net/http"
+	netmail "net/mail"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (svc *service) GetQuotaDashboard(companyId uint) (*quotaDashboard, error) {
+	alertCfg, err := svc.GetQuotaAlertConfig(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.buildQuotaDashboard(companyId, alertCfg.Threshold, time.Now())
+}
+
+func (svc *service) GetQuotaAlertConfig(companyId uint) (*quotaAlertConfig, error) {
+	alertCfg, err := svc.repo.GetQuotaAlertConfigAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return defaultQuotaAlertConfig(companyId), nil
+		}
+
+		return nil, apperror.MapRepoError(err, "failed to fetch quota alert config")
+	}
+	if alertCfg == nil || alertCfg.CompanyId == 0 {
+		return defaultQuotaAlertConfig(companyId), nil
+	}
+
+	return alertCfg, nil
+}
+
+func (svc *service) UpdateQuotaAlertConfig(authCtx *model.AuthContext, companyId uint, req *updateQuotaAlertConfigRequest) (*quotaAlertConfig, error) {
+	if req.Threshold < 1 {
+		return nil, apperror.BadRequest("threshold must be greater than 0")
+	}
+
+	for _, email := range req.Recipients {
+		if _, err := netmail.ParseAddress(email); err != nil {
+			return nil, apperror.BadRequest(fmt.Sprintf(constant.InvalidReportRecipient, email))
+		}
+	}
+
+	current, err := svc.GetQuotaAlertConfig(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := svc.repo.UpsertQuotaAlertConfigAPI(strconv.FormatUint(uint64(companyId), 10), &quotaAlertConfig{
+		CompanyId:     companyId,
+		Enabled:       req.Enabled,
+		Threshold:     req.Threshold,
+		Recipients:    req.Recipients,
+		LastAlertedAt: current.LastAlertedAt,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to update quota alert config")
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Action:    constant.EventUpdateQuotaAlert,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", constant.EventUpdateQuotaAlert).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+
+	return updated, nil
+}
+
+// CheckQuotaAlerts emails the companies with alerts enabled once a day while
+// any of their members is at or below the low quota threshold.
+func (svc *service) CheckQuotaAlerts(now time.Time) error {
+	configs, err := svc.repo.GetQuotaAlertConfigsAPI()
+	if err != nil {
+		return apperror.MapRepoError(err, "failed to fetch quota alert configs")
+	}
+
+	for _, alertCfg := range configs {
+		if !alertCfg.Enabled || alertedToday(alertCfg, now) {
+			continue
+		}
+
+		if err := svc.alertLowQuota(alertCfg, now); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", alertCfg.CompanyId).
+				Msg("failed to check low quota")
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) alertLowQuota(alertCfg *quotaAlertConfig, now time.Time) error {
+	dashboard, err := svc.buildQuotaDashboard(alertCfg.CompanyId, alertCfg.Threshold, now)
+	if err != nil {
+		return err
+	}
+
+	var low []memberQuota
+	for _, m := range dashboard.Members {
+		if m.Low {
+			low = append(low, m)
+		}
+	}
+
+	if len(low) == 0 {
+		return nil
+	}
+
+	recipients := alertCfg.Recipients
+	if len(recipients) == 0 {
+		admins, err := svc.repo.GetAdminsData(alertCfg.CompanyId)
+		if err != nil {
+			return err
+		}
+
+		for _, admin := range admins {
+			recipients = append(recipients, admin.Email)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("no recipients for low quota alert")
+	}
+
+	subject := fmt.Sprintf("Low Quota Alert for %s", dashboard.CompanyName)
+	if err := svc.mailSvc.SendWithTemplateToList(
+		recipients,
+		nil,
+		nil,
+		subject,
+		"low_quota_alert.html",
+		QuotaAlertTemplateData{
+			Subject:     subject,
+			CompanyName: dashboard.CompanyName,
+			Threshold:   alertCfg.Threshold,
+			Members:     low,
+			Year:        now.Year(),
+		},
+	); err != nil {
+		return err
+	}
+
+	alertCfg.LastAlertedAt = &now
+	if _, err := svc.repo.UpsertQuotaAlertConfigAPI(strconv.FormatUint(uint64(alertCfg.CompanyId), 10), alertCfg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (svc *service) buildQuotaDashboard(companyId uint, threshold int, now time.Time) (*quotaDashboard, error) {
+	companyIdStr := strconv.FormatUint(uint64(companyId), 10)
+
+	summary, _, err := svc.getUsageSummary(companyId, constant.PaidStatus, int(now.Month()), now.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	companyData, err := svc.companyRepo.GetCompanyAPI(companyIdStr)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch company")
+	}
+	if companyData == nil {
+		return nil, apperror.NotFound("company not found")
+	}
+
+	plan, err := svc.getPricingPlan(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := append(append([]usagePerProduct{}, summary.ProcatProducts...), summary.ScoreezyProducts...)
+	projected := make([]usagePerProduct, len(usage))
+	for i, p := range usage {
+		projected[i] = p
+		projected[i].TotalPay = projectMonthEnd(p.TotalPay, now)
+	}
+
+	spend := priceInvoice(plan, companyData.BasePricing, usage)
+	projectedSpend := priceInvoice(plan, companyData.BasePricing, projected)
+
+	dashboard := &quotaDashboard{
+		CompanyId:        companyId,
+		CompanyName:      companyData.CompanyName,
+		PeriodYear:       now.Year(),
+		PeriodMonth:      int(now.Month()),
+		GeneratedAt:      now,
+		Threshold:        threshold,
+		MonthToDateSpend: spend.Subtotal,
+		ProjectedSpend:   projectedSpend.Subtotal,
+	}
+
+	for i, p := range usage {
+		dashboard.Products = append(dashboard.Products, productQuotaUsage{
+			ProductId:           p.ProductId,
+			ProductSlug:         p.ProductSlug,
+			ProductName:         p.ProductName,
+			MonthToDateRequests: p.TotalRequest,
+			MonthToDatePaid:     p.TotalPay,
+			ProjectedPaid:       projected[i].TotalPay,
+			MonthToDateSpend:    lineAmount(spend.Lines, p.ProductSlug),
+			ProjectedSpend:      lineAmount(projectedSpend.Lines, p.ProductSlug),
+		})
+	}
+
+	members, _, err := svc.memberRepo.GetMemberListAPI(&member.MemberParams{
+		CompanyId: companyIdStr,
+		Page:      "1",
+		Limit:     constant.SizeUnlimited,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+
+	subscribedIds := svc.subscribedIds(companyIdStr, usage)
+
+	for _, m := range members {
+		quota, err := svc.memberQuota(m, companyIdStr, usage, subscribedIds, threshold)
+		if err != nil {
+			return nil, err
+		}
+
+		dashboard.Members = append(dashboard.Members, *quota)
+	}
+
+	return dashboard, nil
+}
+
+// memberQuota reads the remaining quota of a member, either a single total
+// (quota type 1) or one per subscribed product (quota type 2).
+func (svc *service) memberQuota(m *member.MstMember, companyId string, usage []usagePerProduct, subscribedIds map[string]string, threshold int) (*memberQuota, error) {
+	result := &memberQuota{
+		MemberId:  m.MemberId,
+		Name:      m.Name,
+		Email:     m.Email,
+		QuotaType: m.QuotaType,
+	}
+
+	params := &member.QuotaParams{
+		MemberId:  strconv.FormatUint(uint64(m.MemberId), 10),
+		CompanyId: companyId,
+		QuotaType: strconv.Itoa(int(m.QuotaType)),
+	}
+
+	switch m.QuotaType {
+	case quotaTypeTotal:
+		for _, p := range usage {
+			if id, ok := subscribedIds[p.ProductSlug]; ok {
+				params.SubscribedId = id
+				break
+			}
+		}
+
+		quotaResp, err := svc.memberRepo.GetQuotaAPI(params)
+		if err != nil {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchQuota)
+		}
+
+		remaining := quotaResp.Data.Quota
+		result.RemainingTotal = &remaining
+		result.Low = remaining <= threshold
+
+	case quotaTypePerProduct:
+		for _, p := range usage {
+			id, ok := subscribedIds[p.ProductSlug]
+			if !ok {
+				continue
+			}
+
+			params.SubscribedId = id
+			quotaResp, err := svc.memberRepo.GetQuotaAPI(params)
+			if err != nil {
+				return nil, apperror.MapRepoError(err, constant.FailedFetchQuota)
+			}
+
+			low := quotaResp.Data.Quota <= threshold
+			result.Products = append(result.Products, productQuota{
+				ProductSlug: p.ProductSlug,
+				ProductName: p.ProductName,
+				Remaining:   quotaResp.Data.Quota,
+				Low:         low,
+			})
+			result.Low = result.Low || low
+		}
+	}
+
+	return result, nil
+}
+
+func (svc *service) subscribedIds(companyId string, usage []usagePerProduct) map[string]string {
+	ids := make(map[string]string, len(usage))
+	for _, p := range usage {
+		subscribedResp, err := svc.memberRepo.GetSubscribedProducts(companyId, p.ProductSlug)
+		if err != nil || subscribedResp.Data == nil {
+			log.Warn().
+				Err(err).
+				Str("company_id", companyId).
+				Str("product_slug", p.ProductSlug).
+				Msg(constant.ErrFetchSubscribedProduct)
+			continue
+		}
+
+		ids[p.ProductSlug] = strconv.FormatUint(uint64(subscribedResp.Data.SubsribedProductID), 10)
+	}
+
+	return ids
+}
+
+func (svc *service) getPricingPlan(companyId uint) (*pricingPlan, error) {
+	plan, err := svc.repo.GetPricingPlanAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, apperror.MapRepoError(err, "failed to fetch pricing plan")
+		}
+	}
+	if plan == nil {
+		plan = &pricingPlan{CompanyId: companyId}
+	}
+
+	return plan, nil
+}
+
+func defaultQuotaAlertConfig(companyId uint) *quotaAlertConfig {
+	return &quotaAlertConfig{
+		CompanyId: companyId,
+		Threshold: defaultLowQuotaThreshold,
+	}
+}
+
+func alertedToday(alertCfg *quotaAlertConfig, now time.Time) bool {
+	if alertCfg.LastAlertedAt == nil {
+		return false
+	}
+
+	last := alertCfg.LastAlertedAt.In(now.Location())
+
+	return last.Year() == now.Year() && last.YearDay() == now.YearDay()
+}
+
+// projectMonthEnd extrapolates month-to-date usage linearly, counting at
+// least one elapsed day so early hours of the month do not blow it up.
+func projectMonthEnd(value int, now time.Time) int {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	elapsedDays := math.Max(now.Sub(start).Hours()/24, 1)
+	days := float64(lastDayOfMonth(now))
+
+	return int(math.Round(float64(value) / elapsedDays * days))
+}
+
+func lineAmount(lines []invoiceLine, productSlug string) float64 {
+	for _, line := range lines {
+		if line.ProductSlug == productSlug {
+			return line.Amount
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,201 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/company"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/mail"
+	"front-office/pkg/common/constant"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coreStub answers core service calls by path, so the real repositories can
+// be used in service tests.
+type coreStub struct {
+	mu       sync.Mutex
+	routes   map[string]func(*http.Request) (int, any)
+	requests []*http.Request
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[req.Method+" "+req.URL.Path]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type stubMailSender struct {
+	sent []mail.Mail
+}
+
+func (s *stubMailSender) Send(m mail.Mail) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func setupQuotaService(t *testing.T, routes map[string]func(*http.Request) (int, any)) (*service, *coreStub, *stubMailSender) {
+	t.Helper()
+
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{routes: routes}
+
+	renderer, err := mail.NewTemplateRenderer("../../mail/template")
+	require.NoError(t, err)
+
+	sender := &stubMailSender{}
+	mailSvc := mail.NewMailService(sender, renderer, nil, "3")
+
+	svc := NewService(
+		cfg,
+		NewRepository(cfg, client, nil),
+		nil,
+		nil,
+		company.NewRepository(cfg, client, nil),
+		member.NewRepository(cfg, client, nil),
+		operation.NewRepository(cfg, client, nil),
+		mailSvc,
+		nil,
+	).(*service)
+
+	return svc, client, sender
+}
+
+func quotaRoutes(remaining map[string]int) map[string]func(*http.Request) (int, any) {
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	return map[string]func(*http.Request) (int, any){
+		"GET /api/core/billing/summary": ok(usageSummary{
+			CompanyId: 1,
+			ProcatProducts: []usagePerProduct{
+				{ProductId: 10, ProductSlug: "loan-record-checker", ProductName: "Loan Record Checker", TotalRequest: 120, TotalPay: 100},
+			},
+			ScoreezyProducts: []usagePerProduct{
+				{ProductId: 20, ProductSlug: "phone-live-status", ProductName: "Phone Live Status", TotalRequest: 60, TotalPay: 50},
+			},
+		}),
+		"GET /api/core/company/1": ok(company.MstCompany{CompanyId: 1, CompanyName: "PT Example", BasePricing: 1000}),
+		"GET /api/core/member/listbycompany/1": ok([]member.MstMember{
+			{MemberId: 1, Name: "Unlimited", QuotaType: quotaTypeNone},
+			{MemberId: 2, Name: "Total", Email: "total@example.com", QuotaType: quotaTypeTotal},
+			{MemberId: 3, Name: "Per Product", Email: "product@example.com", QuotaType: quotaTypePerProduct},
+		}),
+		"GET /api/core/member/subscribed-product/loan-record-checker": ok(map[string]uint{"subscribed_product_id": 100}),
+		"GET /api/core/member/subscribed-product/phone-live-status":   ok(map[string]uint{"subscribed_product_id": 200}),
+		"GET /api/core/member/quota": func(req *http.Request) (int, any) {
+			q := req.URL.Query()
+			return http.StatusOK, map[string]int{"quota": remaining[q.Get("member_id")+"/"+q.Get("subscribed_id")]}
+		},
+		"GET /api/core/billing/admins/1": ok([]adminEmail{{Email: "admin@example.com"}}),
+		"PUT /api/core/billing/quota-alerts/1": func(req *http.Request) (int, any) {
+			var payload quotaAlertConfig
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			return http.StatusOK, payload
+		},
+	}
+}
+
+func TestBuildQuotaDashboard(t *testing.T) {
+	routes := quotaRoutes(map[string]int{"2/100": 500, "3/100": 1000, "3/200": 20})
+	svc, _, _ := setupQuotaService(t, routes)
+
+	// ten days into a thirty day month
+	now := time.Date(2025, time.June, 11, 0, 0, 0, 0, time.UTC)
+
+	dashboard, err := svc.buildQuotaDashboard(1, 100, now)
+	require.NoError(t, err)
+
+	require.Len(t, dashboard.Products, 2)
+	assert.Equal(t, 100, dashboard.Products[0].MonthToDatePaid)
+	assert.Equal(t, 300, dashboard.Products[0].ProjectedPaid)
+	assert.Equal(t, 100_000.0, dashboard.Products[0].MonthToDateSpend)
+	assert.Equal(t, 150_000.0, dashboard.MonthToDateSpend)
+	assert.Equal(t, 450_000.0, dashboard.ProjectedSpend)
+
+	require.Len(t, dashboard.Members, 3)
+
+	assert.Nil(t, dashboard.Members[0].RemainingTotal)
+	assert.False(t, dashboard.Members[0].Low)
+
+	require.NotNil(t, dashboard.Members[1].RemainingTotal)
+	assert.Equal(t, 500, *dashboard.Members[1].RemainingTotal)
+	assert.False(t, dashboard.Members[1].Low)
+
+	require.Len(t, dashboard.Members[2].Products, 2)
+	assert.False(t, dashboard.Members[2].Products[0].Low)
+	assert.True(t, dashboard.Members[2].Products[1].Low)
+	assert.True(t, dashboard.Members[2].Low)
+}
+
+func TestCheckQuotaAlerts(t *testing.T) {
+	now := time.Date(2025, time.June, 11, 9, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+
+	t.Run("alerts once a day", func(t *testing.T) {
+		routes := quotaRoutes(map[string]int{"2/100": 10, "3/100": 1000, "3/200": 1000})
+		routes["GET /api/core/billing/quota-alerts"] = func(*http.Request) (int, any) {
+			return http.StatusOK, []quotaAlertConfig{
+				{CompanyId: 1, Enabled: true, Threshold: 100, LastAlertedAt: &yesterday},
+				{CompanyId: 2, Enabled: true, Threshold: 100, LastAlertedAt: &now},
+				{CompanyId: 3, Enabled: false, Threshold: 100},
+			}
+		}
+
+		svc, client, sender := setupQuotaService(t, routes)
+
+		require.NoError(t, svc.CheckQuotaAlerts(now))
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].ToList)
+		assert.Contains(t, sender.sent[0].Body, "total@example.com")
+		assert.NotContains(t, sender.sent[0].Body, "product@example.com")
+
+		last := client.requests[len(client.requests)-1]
+		assert.Equal(t, http.MethodPut, last.Method)
+		assert.Equal(t, "/api/core/billing/quota-alerts/1", last.URL.Path)
+	})
+
+	t.Run("no alert above threshold", func(t *testing.T) {
+		routes := quotaRoutes(map[string]int{"2/100": 500, "3/100": 1000, "3/200": 1000})
+		routes["GET /api/core/billing/quota-alerts"] = func(*http.Request) (int, any) {
+			return http.StatusOK, []quotaAlertConfig{{CompanyId: 1, Enabled: true, Threshold: 100}}
+		}
+
+		svc, _, sender := setupQuotaService(t, routes)
+
+		require.NoError(t, svc.CheckQuotaAlerts(now))
+		assert.Empty(t, sender.sent)
+	})
+}
+
+func TestProjectMonthEnd(t *testing.T) {
+	assert.Equal(t, 300, projectMonthEnd(100, time.Date(2025, time.June, 11, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 3000, projectMonthEnd(100, time.Date(2025, time.June, 1, 2, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, projectMonthEnd(0, time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,396 @@
+package billing
+
+import (
+	"fmt"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// loggedTransaction is a procat or scoreezy log reduced to what is
+// reconciled.
+type loggedTransaction struct {
+	TransactionId   string
+	JobId           uint
+	PricingStrategy string
+}
+
+func (svc *service) GetReconciliation(authCtx *model.AuthContext, query *usageQuery) (*reconciliationReport, error) {
+	return svc.reconcile(authCtx, query)
+}
+
+func (svc *service) ExportReconciliationXlsx(authCtx *model.AuthContext, query *usageQuery) (*downloadUsageXlsxResult, error) {
+	report, err := svc.reconcile(authCtx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := generateReconciliationXlsx(report)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("reconciliation_%s_%s_%s.xlsx", report.CompanyName, report.StartDate, report.EndDate)
+
+	protected, passwordId, err := svc.protectForMember(authCtx.UserId, query.CompanyId, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &downloadUsageXlsxResult{
+		Filename:    filename,
+		ContentType: constant.MimeXlsx,
+		Data:        protected,
+		PasswordId:  passwordId,
+	}, nil
+}
+
+// reconcile compares every product billed in the period. Transactions are
+// counted without dedup, products whose policy deduplicates hits only
+// mismatch when more hits were billed than logged.
+func (svc *service) reconcile(authCtx *model.AuthContext, query *usageQuery) (*reconciliationReport, error) {
+	query.GroupBy = usageGroupByProduct
+
+	breakdown, policy, err := svc.getUsageBreakdown(query)
+	if err != nil {
+		return nil, err
+	}
+
+	billed := make(map[string]usageBucket, len(breakdown.Buckets))
+	for _, bucket := range breakdown.Buckets {
+		billed[bucket.Key] = bucket
+	}
+
+	companyId := strconv.FormatUint(uint64(query.CompanyId), 10)
+	report := &reconciliationReport{
+		CompanyId:    query.CompanyId,
+		CompanyName:  breakdown.CompanyName,
+		StartDate:    breakdown.StartDate,
+		EndDate:      breakdown.EndDate,
+		Status:       reconStatusMatched,
+		Products:     []productReconciliation{},
+		Jobs:         []jobDiscrepancy{},
+		Transactions: []transactionDiscrepancy{},
+	}
+
+	add := func(product usagePerProduct, logs []loggedTransaction) error {
+		jobs, err := svc.repo.GetCompanyJobsAPI(authCtx, companyId, product.ProductSlug, report.StartDate, report.EndDate)
+		if err != nil {
+			return apperror.MapRepoError(err, constant.FailedFetchJobs)
+		}
+
+		rec, jobDiffs, trxDiffs := reconcileProduct(
+			product,
+			filterJobMembers(jobs, query.MemberIds),
+			logs,
+			billed[product.ProductSlug],
+			policy.dedupApplies(product.ProductSlug),
+		)
+
+		report.Products = append(report.Products, rec)
+		report.Jobs = append(report.Jobs, jobDiffs...)
+		report.Transactions = append(report.Transactions, trxDiffs...)
+		if rec.Status == reconStatusMismatch {
+			report.Status = reconStatusMismatch
+		}
+
+		return nil
+	}
+
+	for _, product := range breakdown.ProcatProducts {
+		rows, err := svc.transactionRepo.GetLogTransByCompanyRangeAPI(&transaction.CompanyLogFilter{
+			CompanyId:   companyId,
+			ProductId:   strconv.FormatUint(uint64(product.ProductId), 10),
+			ProductSlug: product.ProductSlug,
+			ApplyDedup:  strconv.FormatBool(false),
+			StartDate:   report.StartDate,
+			EndDate:     report.EndDate,
+			MemberIds:   idStrings(query.MemberIds),
+		})
+		if err != nil {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchLogs)
+		}
+
+		logs := make([]loggedTransaction, 0, len(rows))
+		for _, row := range rows {
+			var jobId uint
+			if row.JobID != nil {
+				jobId = *row.JobID
+			}
+			logs = append(logs, loggedTransaction{row.TransactionID, jobId, row.PricingStrategy})
+		}
+
+		if err := add(product, logs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(breakdown.ScoreezyProducts) > 0 {
+		rows, err := svc.transactionRepo.GetLogsScoreezyByDateRangeAPI(&transaction.LogFilter{
+			CompanyId: companyId,
+			StartDate: report.StartDate,
+			EndDate:   report.EndDate,
+			Size:      constant.SizeUnlimited,
+		})
+		if err != nil {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchLogs)
+		}
+		rows = filterScoreezyMembers(rows, query.MemberIds)
+
+		for _, product := range breakdown.ScoreezyProducts {
+			var logs []loggedTransaction
+			for _, row := range rows {
+				if row.ProductId == product.ProductId {
+					logs = append(logs, loggedTransaction{row.TrxId, row.JobId, row.Status})
+				}
+			}
+
+			if err := add(product, logs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// reconcileProduct compares the jobs, logged transactions and billed hits
+// of one product. Jobs started before the period can make their
+// transactions show up as job_not_found.
+func reconcileProduct(
+	product usagePerProduct,
+	jobs []companyJob,
+	logs []loggedTransaction,
+	billed usageBucket,
+	dedup bool,
+) (productReconciliation, []jobDiscrepancy, []transactionDiscrepancy) {
+	rec := productReconciliation{
+		ProductId:    product.ProductId,
+		ProductSlug:  product.ProductSlug,
+		ProductName:  product.ProductName,
+		JobCount:     len(jobs),
+		BilledPay:    billed.PayRequest,
+		BilledFree:   billed.FreeRequest,
+		Deduplicated: dedup,
+		Status:       reconStatusMatched,
+	}
+
+	known := make(map[uint]bool, len(jobs))
+	for _, job := range jobs {
+		rec.JobTotal += job.Total
+		rec.JobSuccess += job.SuccessCount
+		known[job.JobId] = true
+	}
+
+	var trxDiffs []transactionDiscrepancy
+	flag := func(log loggedTransaction, reason string) {
+		trxDiffs = append(trxDiffs, transactionDiscrepancy{
+			TransactionId:   log.TransactionId,
+			JobId:           log.JobId,
+			ProductSlug:     product.ProductSlug,
+			PricingStrategy: log.PricingStrategy,
+			Reason:          reason,
+		})
+	}
+
+	perJob := make(map[uint]int)
+	seen := make(map[string]bool, len(logs))
+	for _, log := range logs {
+		rec.Logged++
+
+		switch strings.ToUpper(log.PricingStrategy) {
+		case constant.PaidStatus:
+			rec.LoggedPay++
+		case constant.FreeStatus:
+			rec.LoggedFree++
+		default:
+			flag(log, reconReasonUnknownPrice)
+		}
+
+		if log.TransactionId != "" {
+			if seen[log.TransactionId] {
+				flag(log, reconReasonDuplicateTrx)
+			}
+			seen[log.TransactionId] = true
+		}
+
+		if log.JobId != 0 {
+			perJob[log.JobId]++
+			if !known[log.JobId] {
+				flag(log, reconReasonJobNotFound)
+			}
+		}
+	}
+
+	var jobDiffs []jobDiscrepancy
+	for _, job := range jobs {
+		if perJob[job.JobId] == job.Total {
+			continue
+		}
+
+		jobDiffs = append(jobDiffs, jobDiscrepancy{
+			JobId:        job.JobId,
+			ProductSlug:  product.ProductSlug,
+			MemberId:     job.MemberId,
+			Status:       job.Status,
+			JobTotal:     job.Total,
+			SuccessCount: job.SuccessCount,
+			Logged:       perJob[job.JobId],
+			Reason:       reconReasonJobTotal,
+		})
+	}
+
+	differs := func(billed, logged int) bool {
+		if dedup {
+			return billed > logged
+		}
+		return billed != logged
+	}
+
+	if len(jobDiffs) > 0 {
+		rec.Reasons = append(rec.Reasons, reconReasonJobTotal)
+	}
+	if differs(rec.BilledPay, rec.LoggedPay) {
+		rec.Reasons = append(rec.Reasons, reconReasonBilledPay)
+	}
+	if differs(rec.BilledFree, rec.LoggedFree) {
+		rec.Reasons = append(rec.Reasons, reconReasonBilledFree)
+	}
+	for _, diff := range trxDiffs {
+		if !slices.Contains(rec.Reasons, diff.Reason) {
+			rec.Reasons = append(rec.Reasons, diff.Reason)
+		}
+	}
+
+	if len(rec.Reasons) > 0 {
+		rec.Status = reconStatusMismatch
+	}
+
+	return rec, jobDiffs, trxDiffs
+}
+
+func filterJobMembers(jobs []companyJob, memberIds []uint) []companyJob {
+	if len(memberIds) == 0 {
+		return jobs
+	}
+
+	var out []companyJob
+	for _, job := range jobs {
+		if slices.Contains(memberIds, job.MemberId) {
+			out = append(out, job)
+		}
+	}
+
+	return out
+}
+
+// reconRow lets the reconciliation sheets reuse the product sheet writer.
+type reconRow struct {
+	item any
+}
+
+func (r reconRow) logRow() {}
+
+func wrapRecon[T any](items []T) []LogRow {
+	out := make([]LogRow, len(items))
+	for i, item := range items {
+		out[i] = reconRow{item}
+	}
+
+	return out
+}
+
+func fromRecon[T any](fn func(T) interface{}) RowExtractFn {
+	return func(row LogRow) interface{} {
+		r, ok := row.(reconRow)
+		if !ok {
+			return nil
+		}
+
+		item, ok := r.item.(T)
+		if !ok {
+			return nil
+		}
+
+		return fn(item)
+	}
+}
+
+var reconProductSheet = ProductSheetDef{
+	SheetName: "Products",
+	Columns: []ColumnDef{
+		{Header: "Product", Type: ColTypeText, Width: 30, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.ProductName })},
+		{Header: "Jobs", Type: ColTypeNumber, Width: 10, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.JobCount })},
+		{Header: "Job Rows", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.JobTotal })},
+		{Header: "Job Success", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.JobSuccess })},
+		{Header: "Logged", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.Logged })},
+		{Header: "Logged PAY", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.LoggedPay })},
+		{Header: "Logged FREE", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.LoggedFree })},
+		{Header: "Billed PAY", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.BilledPay })},
+		{Header: "Billed FREE", Type: ColTypeNumber, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.BilledFree })},
+		{Header: "Deduplicated", Type: ColTypeText, Width: 14, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.Deduplicated })},
+		{Header: "Status", Type: ColTypeText, Width: 12, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return p.Status })},
+		{Header: "Reasons", Type: ColTypeText, Width: 48, ExtractFn: fromRecon(func(p productReconciliation) interface{} { return strings.Join(p.Reasons, ", ") })},
+	},
+}
+
+var reconJobSheet = ProductSheetDef{
+	SheetName: "Jobs",
+	Columns: []ColumnDef{
+		{Header: "Job ID", Type: ColTypeNumber, Width: 12, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.JobId })},
+		{Header: "Product", Type: ColTypeText, Width: 32, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.ProductSlug })},
+		{Header: "Member ID", Type: ColTypeNumber, Width: 12, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.MemberId })},
+		{Header: "Status", Type: ColTypeText, Width: 14, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.Status })},
+		{Header: "Job Rows", Type: ColTypeNumber, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.JobTotal })},
+		{Header: "Job Success", Type: ColTypeNumber, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.SuccessCount })},
+		{Header: "Logged", Type: ColTypeNumber, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.Logged })},
+		{Header: "Reason", Type: ColTypeText, Width: 36, ExtractFn: fromRecon(func(j jobDiscrepancy) interface{} { return j.Reason })},
+	},
+}
+
+var reconTransactionSheet = ProductSheetDef{
+	SheetName: "Transactions",
+	Columns: []ColumnDef{
+		{Header: constant.CSVHeaderTransactionID, Type: ColTypeText, Width: 32, ExtractFn: fromRecon(func(t transactionDiscrepancy) interface{} { return t.TransactionId })},
+		{Header: "Job ID", Type: ColTypeNumber, Width: 12, ExtractFn: fromRecon(func(t transactionDiscrepancy) interface{} { return t.JobId })},
+		{Header: "Product", Type: ColTypeText, Width: 32, ExtractFn: fromRecon(func(t transactionDiscrepancy) interface{} { return t.ProductSlug })},
+		{Header: constant.CSVHeaderPricingStrategy, Type: ColTypeText, Width: 20, ExtractFn: fromRecon(func(t transactionDiscrepancy) interface{} { return t.PricingStrategy })},
+		{Header: "Reason", Type: ColTypeText, Width: 36, ExtractFn: fromRecon(func(t transactionDiscrepancy) interface{} { return t.Reason })},
+	},
+}
+
+func generateReconciliationXlsx(report *reconciliationReport) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	defaultSheet := f.GetSheetName(0)
+
+	sheets := []struct {
+		def  ProductSheetDef
+		rows []LogRow
+	}{
+		{reconProductSheet, wrapRecon(report.Products)},
+		{reconJobSheet, wrapRecon(report.Jobs)},
+		{reconTransactionSheet, wrapRecon(report.Transactions)},
+	}
+	for _, sheet := range sheets {
+		if _, err := f.NewSheet(sheet.def.SheetName); err != nil {
+			return nil, apperror.Internal(fmt.Sprintf("failed to create sheet '%s': %s", sheet.def.SheetName, err), err)
+		}
+
+		if err := writeProductSheet(f, sheet.def.SheetName, sheet.def, sheet.rows); err != nil {
+			return nil, apperror.Internal(fmt.Sprintf("failed to write sheet '%s': %s", sheet.def.SheetName, err), err)
+		}
+	}
+
+	f.DeleteSheet(defaultSheet)
+	f.SetActiveSheet(0)
+
+	return writeXlsx(f)
+}
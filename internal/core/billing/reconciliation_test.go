@@ -0,0 +1,191 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestReconcileProduct(t *testing.T) {
+	product := usagePerProduct{ProductId: 10, ProductSlug: constant.SlugLoanRecordChecker, ProductName: "Loan Record Checker"}
+	jobs := []companyJob{
+		{JobId: 1, MemberId: 3, Total: 2, SuccessCount: 2},
+		{JobId: 2, MemberId: 3, Total: 2, SuccessCount: 1},
+	}
+
+	t.Run("matched", func(t *testing.T) {
+		logs := []loggedTransaction{
+			{"trx-1", 1, constant.PaidStatus},
+			{"trx-2", 1, "free"},
+			{"trx-3", 2, constant.PaidStatus},
+			{"trx-4", 2, constant.PaidStatus},
+		}
+
+		rec, jobDiffs, trxDiffs := reconcileProduct(product, jobs, logs, usageBucket{PayRequest: 3, FreeRequest: 1}, false)
+		assert.Equal(t, reconStatusMatched, rec.Status)
+		assert.Empty(t, rec.Reasons)
+		assert.Equal(t, 4, rec.JobTotal)
+		assert.Equal(t, 3, rec.JobSuccess)
+		assert.Equal(t, 3, rec.LoggedPay)
+		assert.Equal(t, 1, rec.LoggedFree)
+		assert.Empty(t, jobDiffs)
+		assert.Empty(t, trxDiffs)
+	})
+
+	t.Run("mismatched", func(t *testing.T) {
+		logs := []loggedTransaction{
+			{"trx-1", 1, constant.PaidStatus},
+			{"trx-1", 1, constant.PaidStatus},
+			{"trx-3", 2, "TRIAL"},
+			{"trx-9", 7, constant.PaidStatus},
+		}
+
+		rec, jobDiffs, trxDiffs := reconcileProduct(product, jobs, logs, usageBucket{PayRequest: 3}, false)
+		assert.Equal(t, reconStatusMismatch, rec.Status)
+		assert.Equal(t, []string{reconReasonJobTotal, reconReasonDuplicateTrx, reconReasonUnknownPrice, reconReasonJobNotFound}, rec.Reasons)
+
+		require.Len(t, jobDiffs, 1)
+		assert.Equal(t, uint(2), jobDiffs[0].JobId)
+		assert.Equal(t, 1, jobDiffs[0].Logged)
+
+		require.Len(t, trxDiffs, 3)
+		assert.Equal(t, transactionDiscrepancy{TransactionId: "trx-1", JobId: 1, ProductSlug: product.ProductSlug, PricingStrategy: constant.PaidStatus, Reason: reconReasonDuplicateTrx}, trxDiffs[0])
+		assert.Equal(t, "trx-3", trxDiffs[1].TransactionId)
+		assert.Equal(t, reconReasonJobNotFound, trxDiffs[2].Reason)
+	})
+
+	t.Run("dedup only flags over-billing", func(t *testing.T) {
+		logs := []loggedTransaction{
+			{"trx-1", 1, constant.PaidStatus},
+			{"trx-2", 1, constant.PaidStatus},
+			{"trx-3", 2, constant.PaidStatus},
+			{"trx-4", 2, constant.PaidStatus},
+		}
+
+		rec, _, _ := reconcileProduct(product, jobs, logs, usageBucket{PayRequest: 2}, true)
+		assert.Equal(t, reconStatusMatched, rec.Status)
+
+		rec, _, _ = reconcileProduct(product, jobs, logs, usageBucket{PayRequest: 5}, true)
+		assert.Equal(t, []string{reconReasonBilledPay}, rec.Reasons)
+
+		rec, _, _ = reconcileProduct(product, jobs, logs, usageBucket{PayRequest: 2}, false)
+		assert.Equal(t, []string{reconReasonBilledPay}, rec.Reasons)
+	})
+}
+
+func reconciliationRoutes() map[string]func(*http.Request) (int, any) {
+	routes := quotaRoutes(nil)
+	routes["GET /api/core/billing/usage/breakdown"] = breakdownRoute(usageBreakdown{
+		CompanyId:   1,
+		CompanyName: "PT Example",
+		Buckets: []usageBucket{
+			{Key: constant.SlugLoanRecordChecker, PayRequest: 2},
+			{Key: "kyc-check", PayRequest: 1, FreeRequest: 1},
+		},
+		ProcatProducts:   []usagePerProduct{{ProductId: 10, ProductSlug: constant.SlugLoanRecordChecker, ProductName: "Loan Record Checker"}},
+		ScoreezyProducts: []usagePerProduct{{ProductId: 20, ProductSlug: "kyc-check", ProductName: "KYC Check"}},
+	})
+	routes["GET /api/core/product/"+constant.SlugLoanRecordChecker+"/jobs"] = func(*http.Request) (int, any) {
+		return http.StatusOK, companyJobList{Jobs: []companyJob{{JobId: 1, MemberId: 3, Total: 3}}}
+	}
+	routes["GET /api/core/product/kyc-check/jobs"] = func(*http.Request) (int, any) {
+		return http.StatusOK, companyJobList{Jobs: []companyJob{{JobId: 5, MemberId: 3, Total: 2}}}
+	}
+	routes["GET /api/core/logging/transaction/product-catalog/by-company/range"] = func(*http.Request) (int, any) {
+		return http.StatusOK, []*transaction.LogTransProductCatalog{
+			{TransactionID: "trx-1", JobID: uintPtr(1), PricingStrategy: constant.PaidStatus},
+			{TransactionID: "trx-2", JobID: uintPtr(1), PricingStrategy: constant.PaidStatus},
+		}
+	}
+	routes["GET /api/core/logging/transaction/scoreezy/range"] = func(*http.Request) (int, any) {
+		return http.StatusOK, []*transaction.LogTransScoreezy{
+			{TrxId: "s-1", JobId: 5, ProductId: 20, MemberId: 3, Status: "Pay"},
+			{TrxId: "s-2", JobId: 5, ProductId: 20, MemberId: 3, Status: "Free"},
+			{TrxId: "s-3", JobId: 6, ProductId: 30, MemberId: 3, Status: "Pay"},
+		}
+	}
+
+	return routes
+}
+
+func TestGetReconciliation(t *testing.T) {
+	svc, client, _ := setupQuotaService(t, reconciliationRoutes())
+	svc.transactionRepo = transaction.NewRepository(svc.cfg, client, nil)
+
+	report, err := svc.GetReconciliation(&model.AuthContext{UserId: 5, CompanyId: 1}, &usageQuery{
+		CompanyId: 1,
+		StartDate: date("2026-01-01"),
+		EndDate:   date("2026-01-31"),
+		GroupBy:   usageGroupByDay,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, reconStatusMismatch, report.Status)
+	require.Len(t, report.Products, 2)
+	assert.Equal(t, []string{reconReasonJobTotal}, report.Products[0].Reasons)
+	assert.Equal(t, reconStatusMatched, report.Products[1].Status)
+	require.Len(t, report.Jobs, 1)
+	assert.Equal(t, jobDiscrepancy{JobId: 1, ProductSlug: constant.SlugLoanRecordChecker, MemberId: 3, JobTotal: 3, Logged: 2, Reason: reconReasonJobTotal}, report.Jobs[0])
+	assert.Empty(t, report.Transactions)
+
+	for _, req := range client.requests {
+		switch req.URL.Path {
+		case "/api/core/billing/usage/breakdown":
+			assert.Equal(t, usageGroupByProduct, req.URL.Query().Get("group_by"))
+		case "/api/core/logging/transaction/product-catalog/by-company/range":
+			assert.Equal(t, "false", req.URL.Query().Get("apply_dedup"))
+			assert.Empty(t, req.URL.Query().Get("pricing_strategy"))
+		case "/api/core/product/kyc-check/jobs":
+			assert.Equal(t, "1", req.Header.Get(constant.XCompanyId))
+			assert.Equal(t, "2026-01-01", req.URL.Query().Get(constant.StartDate))
+		}
+	}
+}
+
+func TestExportReconciliationXlsx(t *testing.T) {
+	routes := reconciliationRoutes()
+	var password string
+	routes["POST /api/core/billing/workbook-passwords"] = func(req *http.Request) (int, any) {
+		var payload workbookPassword
+		_ = json.NewDecoder(req.Body).Decode(&payload)
+		password = payload.Password
+		payload.Id = "wp-3"
+		return http.StatusOK, payload
+	}
+	routes["POST /api/core/logging/operation"] = func(*http.Request) (int, any) { return http.StatusOK, nil }
+
+	svc, client, _ := setupQuotaService(t, routes)
+	svc.transactionRepo = transaction.NewRepository(svc.cfg, client, nil)
+
+	result, err := svc.ExportReconciliationXlsx(&model.AuthContext{UserId: 5, CompanyId: 1}, &usageQuery{
+		CompanyId: 1,
+		StartDate: date("2026-01-01"),
+		EndDate:   date("2026-01-31"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "reconciliation_PT Example_2026-01-01_2026-01-31.xlsx", result.Filename)
+	assert.Equal(t, "wp-3", result.PasswordId)
+
+	f, err := excelize.OpenReader(bytes.NewReader(result.Data), excelize.Options{Password: password})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Products", "Jobs", "Transactions"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Jobs")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"1", constant.SlugLoanRecordChecker, "3", "", "3", "0", "2", reconReasonJobTotal}, rows[1])
+
+	rows, err = f.GetRows("Transactions")
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
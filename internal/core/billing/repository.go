@@ -1,15 +1,19 @@
 package billing
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"front-office/configs/application"
 	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
 	"front-office/pkg/httpclient"
 	"front-office/pkg/jsonutil"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
@@ -32,8 +36,31 @@ type repository struct {
 
 type Repository interface {
 	GetUsageReport() ([]usageSummary, error)
-	GetUsageReportByCompany(companyId, pricingStrategy, month, year string) (*usageSummary, error)
+	GetUsageReportByCompany(companyId, pricingStrategy, month, year string, policy *billingPolicy) (*usageSummary, error)
+	GetUsageBreakdownAPI(companyId string, query *usageQuery, policy *billingPolicy) (*usageBreakdown, error)
 	GetAdminsData(companyId uint) ([]adminEmail, error)
+	GetCompanyJobsAPI(authCtx *model.AuthContext, companyId, productSlug, startDate, endDate string) ([]companyJob, error)
+	GetReportSchedulesAPI() ([]*reportSchedule, error)
+	GetReportScheduleAPI(companyId string) (*reportSchedule, error)
+	UpsertReportScheduleAPI(companyId string, payload *reportSchedule) (*reportSchedule, error)
+	UpdateReportSentPeriodAPI(companyId, period string) error
+	GetPricingPlanAPI(companyId string) (*pricingPlan, error)
+	GetBillingPolicyAPI(companyId string) (*billingPolicy, error)
+	CreateWorkbookPasswordAPI(payload *workbookPassword) (*workbookPassword, error)
+	GetWorkbookPasswordAPI(id string) (*workbookPassword, error)
+	ClaimWorkbookPasswordAPI(id string) (*workbookPassword, error)
+	GetInvoicesAPI(companyId string) ([]*invoice, error)
+	GetInvoiceByPeriodAPI(companyId, year, month string) (*invoice, error)
+	CreateInvoiceAPI(payload *invoiceDraft) (*invoice, error)
+	GetQuotaAlertConfigsAPI() ([]*quotaAlertConfig, error)
+	GetQuotaAlertConfigAPI(companyId string) (*quotaAlertConfig, error)
+	UpsertQuotaAlertConfigAPI(companyId string, payload *quotaAlertConfig) (*quotaAlertConfig, error)
+	GetBalanceAPI(companyId string) (*balance, error)
+	CreateTopupAPI(payload *topup) (*topup, error)
+	GetTopupsAPI(filter *topupFilter) ([]*topup, error)
+	GetTopupAPI(id string) (*topup, error)
+	UpdateTopupAPI(id string, payload *topup) (*topup, error)
+	ApproveTopupAPI(id string, payload *topupReview) (*topupApproval, error)
 }
 
 func (repo *repository) GetUsageReport() ([]usageSummary, error) {
@@ -60,7 +87,9 @@ func (repo *repository) GetUsageReport() ([]usageSummary, error) {
 	return apiResp.Data, nil
 }
 
-func (repo *repository) GetUsageReportByCompany(companyId, pricingStrategy, month, year string) (*usageSummary, error) {
+// GetUsageReportByCompany counts the hits of the period, the dedup settings
+// of policy are forwarded so the counts match the billed transactions.
+func (repo *repository) GetUsageReportByCompany(companyId, pricingStrategy, month, year string, policy *billingPolicy) (*usageSummary, error) {
 	url := fmt.Sprintf(`%v/api/core/billing/summary`, repo.cfg.App.AifcoreHost)
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -75,6 +104,13 @@ func (repo *repository) GetUsageReportByCompany(companyId, pricingStrategy, mont
 	q.Add("pricing_strategy", pricingStrategy)
 	q.Add("month", month)
 	q.Add("year", year)
+	if policy != nil && policy.Dedup {
+		q.Add("apply_dedup", "true")
+		q.Add("dedup_window_days", strconv.Itoa(policy.DedupWindowDays))
+		if len(policy.DedupProducts) > 0 {
+			q.Add("dedup_products", strings.Join(policy.DedupProducts, ","))
+		}
+	}
 	req.URL.RawQuery = q.Encode()
 
 	resp, err := repo.client.Do(req)
@@ -91,6 +127,91 @@ func (repo *repository) GetUsageReportByCompany(companyId, pricingStrategy, mont
 	return apiResp.Data, nil
 }
 
+// GetUsageBreakdownAPI counts the hits of the query grouped by its
+// dimension, split by pricing strategy.
+func (repo *repository) GetUsageBreakdownAPI(companyId string, query *usageQuery, policy *billingPolicy) (*usageBreakdown, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/usage/breakdown`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", companyId)
+	q.Add(constant.StartDate, query.StartDate.Format(constant.FormatYYYYMMDD))
+	q.Add(constant.EndDate, query.EndDate.Format(constant.FormatYYYYMMDD))
+	q.Add("group_by", query.GroupBy)
+	if len(query.ProductSlugs) > 0 {
+		q.Add("product_slugs", strings.Join(query.ProductSlugs, ","))
+	}
+	if len(query.MemberIds) > 0 {
+		q.Add("member_ids", joinIds(query.MemberIds))
+	}
+	if policy != nil && policy.Dedup {
+		q.Add("apply_dedup", "true")
+		q.Add("dedup_window_days", strconv.Itoa(policy.DedupWindowDays))
+		if len(policy.DedupProducts) > 0 {
+			q.Add("dedup_products", strings.Join(policy.DedupProducts, ","))
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*usageBreakdown](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// GetCompanyJobsAPI lists every job of the product started in the range,
+// the admin's tier level lets the core return the jobs of all members.
+func (repo *repository) GetCompanyJobsAPI(authCtx *model.AuthContext, companyId, productSlug, startDate, endDate string) ([]companyJob, error) {
+	url := fmt.Sprintf(`%v/api/core/product/%s/jobs`, repo.cfg.App.AifcoreHost, productSlug)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XMemberId, authCtx.UserIdStr())
+	req.Header.Set(constant.XCompanyId, companyId)
+	req.Header.Set(constant.XTierLevel, authCtx.RoleIdStr())
+
+	q := req.URL.Query()
+	q.Add(constant.Page, "1")
+	q.Add(constant.Size, constant.SizeUnlimited)
+	q.Add(constant.StartDate, startDate)
+	q.Add(constant.EndDate, endDate)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*companyJobList](resp)
+	if err != nil {
+		return nil, err
+	}
+	if apiResp.Data == nil {
+		return nil, nil
+	}
+
+	return apiResp.Data.Jobs, nil
+}
+
 func (repo *repository) GetAdminsData(companyId uint) ([]adminEmail, error) {
 	url := fmt.Sprintf(`%v/api/core/billing/admins/%d`, repo.cfg.App.AifcoreHost, companyId)
 
@@ -114,3 +235,573 @@ func (repo *repository) GetAdminsData(companyId uint) ([]adminEmail, error) {
 
 	return apiResp.Data, nil
 }
+
+func (repo *repository) GetReportSchedulesAPI() ([]*reportSchedule, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/report-schedules`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*reportSchedule](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetReportScheduleAPI(companyId string) (*reportSchedule, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/report-schedules/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*reportSchedule](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpsertReportScheduleAPI(companyId string, payload *reportSchedule) (*reportSchedule, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/report-schedules/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*reportSchedule](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// UpdateReportSentPeriodAPI records the last period whose report went out,
+// the core creates the default schedule of a company that has none.
+func (repo *repository) UpdateReportSentPeriodAPI(companyId, period string) error {
+	url := fmt.Sprintf(`%v/api/core/billing/report-schedules/%s/last-sent-period`, repo.cfg.App.AifcoreHost, companyId)
+
+	bodyBytes, err := repo.marshalFn(map[string]string{"last_sent_period": period})
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+func (repo *repository) GetPricingPlanAPI(companyId string) (*pricingPlan, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/pricing/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*pricingPlan](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetBillingPolicyAPI(companyId string) (*billingPolicy, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/policies/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*billingPolicy](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetInvoicesAPI(companyId string) ([]*invoice, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/invoices`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", companyId)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*invoice](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetInvoiceByPeriodAPI(companyId, year, month string) (*invoice, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/invoices/period`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", companyId)
+	q.Add("year", year)
+	q.Add("month", month)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*invoice](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// CreateInvoiceAPI stores the invoice and returns it with the next number of
+// the invoice sequence.
+func (repo *repository) CreateInvoiceAPI(payload *invoiceDraft) (*invoice, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/invoices`, repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*invoice](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetQuotaAlertConfigsAPI() ([]*quotaAlertConfig, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/quota-alerts`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*quotaAlertConfig](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetQuotaAlertConfigAPI(companyId string) (*quotaAlertConfig, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/quota-alerts/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*quotaAlertConfig](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpsertQuotaAlertConfigAPI(companyId string, payload *quotaAlertConfig) (*quotaAlertConfig, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/quota-alerts/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*quotaAlertConfig](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) CreateWorkbookPasswordAPI(payload *workbookPassword) (*workbookPassword, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/workbook-passwords`, repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*workbookPassword](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// GetWorkbookPasswordAPI returns the password metadata without the password.
+func (repo *repository) GetWorkbookPasswordAPI(id string) (*workbookPassword, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/workbook-passwords/%s`, repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*workbookPassword](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// ClaimWorkbookPasswordAPI marks the password retrieved and returns it, the
+// core rejects passwords that were already claimed.
+func (repo *repository) ClaimWorkbookPasswordAPI(id string) (*workbookPassword, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/workbook-passwords/%s/claim`, repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XAPIKey, repo.cfg.App.CoreModuleKey)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*workbookPassword](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetBalanceAPI(companyId string) (*balance, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/balances/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*balance](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) CreateTopupAPI(payload *topup) (*topup, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/topups`, repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*topup](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetTopupsAPI(filter *topupFilter) ([]*topup, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/topups`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	if filter.CompanyId != "" {
+		q.Add("company_id", filter.CompanyId)
+	}
+	if filter.Status != "" {
+		q.Add("status", filter.Status)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*topup](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetTopupAPI(id string) (*topup, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/topups/%s`, repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*topup](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpdateTopupAPI(id string, payload *topup) (*topup, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/topups/%s`, repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*topup](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+// ApproveTopupAPI marks the top-up approved and credits the company balance
+// in one step, the core answers 409 when the top-up is no longer waiting.
+func (repo *repository) ApproveTopupAPI(id string, payload *topupReview) (*topupApproval, error) {
+	url := fmt.Sprintf(`%v/api/core/billing/topups/%s/approve`, repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*topupApproval](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
@@ -2,17 +2,24 @@ package billing
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"front-office/configs/application"
+	"front-office/internal/core/company"
 	"front-office/internal/core/internalteam"
+	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
 	"front-office/internal/mail"
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
+	"net/http"
+	netmail "net/mail"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -24,14 +31,27 @@ func NewService(
 	repo Repository,
 	transactionRepo transaction.Repository,
 	internalRepo internalteam.Repository,
+	companyRepo company.Repository,
+	memberRepo member.Repository,
+	operationRepo operation.Repository,
 	mailSvc *mail.SendMailService,
+	sheetDefs map[string]ProductSheetDef,
 ) Service {
+	if sheetDefs == nil {
+		sheetDefs = productRegistry
+	}
+
 	return &service{
 		cfg,
 		repo,
 		transactionRepo,
 		internalRepo,
+		companyRepo,
+		memberRepo,
+		operationRepo,
 		mailSvc,
+		sheetDefs,
+		&sync.Map{},
 	}
 }
 
@@ -40,20 +60,60 @@ type service struct {
 	repo            Repository
 	transactionRepo transaction.Repository
 	internalRepo    internalteam.Repository
+	companyRepo     company.Repository
+	memberRepo      member.Repository
+	operationRepo   operation.Repository
 	mailSvc         *mail.SendMailService
+	sheetDefs       map[string]ProductSheetDef
+	// sentReports holds the last report period sent to each company by this
+	// instance, it keeps a report from going out again on every check when
+	// the period could not be recorded in the core.
+	sentReports *sync.Map
 }
 
 type Service interface {
 	SendMonthlyUsageReport() error
+	SendScheduledUsageReports(now time.Time) error
+	GetReportSchedule(companyId uint) (*reportSchedule, error)
+	UpdateReportSchedule(authCtx *model.AuthContext, companyId uint, req *updateReportScheduleRequest) (*reportSchedule, error)
 	ExportUsageXlsx(input downloadUsageXlsxInput) (*downloadUsageXlsxResult, error)
 	GetUsageReport(companyId uint, pricingStrategy string, month, year int) (*usageSummary, error)
+	GetUsageBreakdown(query *usageQuery) (*usageBreakdown, error)
+	GetReconciliation(authCtx *model.AuthContext, query *usageQuery) (*reconciliationReport, error)
+	ExportReconciliationXlsx(authCtx *model.AuthContext, query *usageQuery) (*downloadUsageXlsxResult, error)
+	GetBillingPolicy(companyId uint) (*billingPolicy, error)
+	PreviewSheetDefinition(req *previewSheetRequest) (*sheetPreview, error)
+	RetrieveWorkbookPassword(authCtx *model.AuthContext, id string) (*workbookPasswordResponse, error)
+	GetInvoices(companyId uint) ([]*invoice, error)
+	DownloadInvoice(companyId uint, year, month int, format string) (*invoiceFile, error)
+	GetQuotaDashboard(companyId uint) (*quotaDashboard, error)
+	GetQuotaAlertConfig(companyId uint) (*quotaAlertConfig, error)
+	UpdateQuotaAlertConfig(authCtx *model.AuthContext, companyId uint, req *updateQuotaAlertConfigRequest) (*quotaAlertConfig, error)
+	CheckQuotaAlerts(now time.Time) error
+	GetBalance(companyId uint) (*balance, error)
+	GetTopups(companyId uint, status string) ([]*topup, error)
+	RequestTopup(authCtx *model.AuthContext, companyId uint, req *createTopupRequest) (*topup, error)
+	DownloadProforma(companyId uint, id string) (*invoiceFile, error)
+	CheckPaymentConfirmation(companyId uint, id, paidAt string) error
+	SubmitPaymentConfirmation(authCtx *model.AuthContext, companyId uint, id string, req *paymentConfirmationRequest) (*topup, error)
+	GetPaymentProof(companyId uint, id string) (string, error)
+	GetTopupsAwaitingApproval(authCtx *model.AuthContext) ([]*topup, error)
+	GetPaymentProofForReview(authCtx *model.AuthContext, id string) (string, error)
+	ApproveTopup(authCtx *model.AuthContext, id string, req *reviewTopupRequest) (*topupApproval, error)
+	RejectTopup(authCtx *model.AuthContext, id string, req *reviewTopupRequest) (*topup, error)
 	generateUsageXlsx(input XlsxReportInput) ([]byte, error)
 }
 
-func (svc *service) NewProcatFetchFn(pricingStrategy, applyDedup, month, year string) FetchFn {
+func (svc *service) NewProcatFetchFn(pricingStrategy string, policy *billingPolicy, month, year string) FetchFn {
 	return func(productId, companyId, productSlug string) ([]LogRow, error) {
+		applyDedup := policy.dedupApplies(productSlug)
+		dedupWindowDays := ""
+		if applyDedup {
+			dedupWindowDays = strconv.Itoa(policy.DedupWindowDays)
+		}
+
 		rows, err := svc.transactionRepo.GetLogTransByCompanyAPI(
-			"", productId, companyId, pricingStrategy, productSlug, applyDedup, month, year,
+			"", productId, companyId, pricingStrategy, productSlug, strconv.FormatBool(applyDedup), dedupWindowDays, month, year,
 		)
 		if err != nil {
 			return nil, err
@@ -63,7 +123,7 @@ func (svc *service) NewProcatFetchFn(pricingStrategy, applyDedup, month, year st
 	}
 }
 
-func (svc *service) NewScoreezyFetchFn(startDate, endDate string) FetchFn {
+func (svc *service) NewScoreezyFetchFn(startDate, endDate string, memberIds []uint) FetchFn {
 	return func(productId, companyId, productSlug string) ([]LogRow, error) {
 		rows, err := svc.transactionRepo.GetLogsScoreezyByDateRangeAPI(
 			&transaction.LogFilter{
@@ -77,7 +137,7 @@ func (svc *service) NewScoreezyFetchFn(startDate, endDate string) FetchFn {
 			return nil, err
 		}
 
-		return WrapScoreezy(rows), nil
+		return WrapScoreezy(filterScoreezyMembers(rows, memberIds)), nil
 	}
 }
 
@@ -90,129 +150,304 @@ func (svc *service) ExportUsageXlsx(input downloadUsageXlsxInput) (*downloadUsag
 		input.Groups = []string{"procat", "scoreezy"}
 	}
 
-	period := time.Date(input.Year, time.Month(input.Month), 1, 0, 0, 0, 0, time.UTC)
-	startDate := fmt.Sprintf("%d-%02d-01", input.Year, input.Month)
-	endDate := fmt.Sprintf("%d-%02d-%02d", input.Year, input.Month, lastDayOfMonth(period))
-
-	allGroups, companyName, err := svc.buildProductGroups(input.CompanyId, input.PricingStrategy, startDate, endDate, input.Month, input.Year)
+	breakdown, policy, err := svc.getUsageBreakdown(&input.Query)
 	if err != nil {
-		return nil, apperror.Internal("failed to build product groups", err)
+		return nil, err
 	}
 
-	filtered := filterGroups(allGroups, input.Groups)
+	filtered := filterGroups(svc.buildUsageGroups(&input.Query, input.PricingStrategy, breakdown, policy), input.Groups)
 	if len(filtered) == 0 {
 		return nil, apperror.BadRequest(fmt.Sprintf("no valid product groups found for: %v", input.Groups))
 	}
 
 	xlsxBytes, err := svc.generateUsageXlsx(XlsxReportInput{
-		CompanyId:       input.CompanyId,
-		CompanyName:     companyName,
-		PeriodYear:      input.Year,
-		PeriodMonth:     input.Month,
+		CompanyId:       input.Query.CompanyId,
+		CompanyName:     breakdown.CompanyName,
+		PeriodYear:      input.Query.StartDate.Year(),
+		PeriodMonth:     int(input.Query.StartDate.Month()),
 		PricingStrategy: input.PricingStrategy,
 		ProductGroups:   filtered,
-		Password:        input.Password,
+		Breakdown:       breakdown,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	filename := fmt.Sprintf(
-		"usage_report_%s_%02d_%d.xlsx",
-		companyName, input.Month, input.Year,
-	)
+	filename := usageFilename(breakdown.CompanyName, &input.Query)
+
+	protected, passwordId, err := svc.protectForMember(input.RequestedBy, input.Query.CompanyId, filename, xlsxBytes)
+	if err != nil {
+		return nil, err
+	}
 
 	return &downloadUsageXlsxResult{
 		Filename:    filename,
 		ContentType: constant.MimeXlsx,
-		Data:        xlsxBytes,
+		Data:        protected,
+		PasswordId:  passwordId,
 	}, nil
 }
 
 func (svc *service) SendMonthlyUsageReport() error {
+	return svc.sendUsageReports(time.Now(), func(*reportSchedule) bool { return true })
+}
+
+// SendScheduledUsageReports sends the monthly usage report to every company
+// whose delivery day and time have passed and that has not been sent the
+// report of last month yet. Reports sent by this instance are remembered, so
+// a company is not sent its report again when the period could not be
+// recorded in the core.
+func (svc *service) SendScheduledUsageReports(now time.Time) error {
+	period := reportPeriod(now).Format(reportPeriodLayout)
+
+	return svc.sendUsageReports(now, func(schedule *reportSchedule) bool {
+		return schedule.isDue(now) && !svc.reportSent(schedule.CompanyId, period)
+	})
+}
+
+func (svc *service) sendUsageReports(now time.Time, isDue func(*reportSchedule) bool) error {
+	stored, err := svc.repo.GetReportSchedulesAPI()
+	if err != nil {
+		return apperror.MapRepoError(err, "failed to fetch usage report schedules")
+	}
+
+	schedules := make(map[uint]*reportSchedule, len(stored))
+	anyDue := isDue(defaultReportSchedule(0))
+	for _, schedule := range stored {
+		schedules[schedule.CompanyId] = schedule
+		anyDue = anyDue || (!schedule.OptOut && isDue(schedule))
+	}
+
+	if !anyDue {
+		return nil
+	}
+
 	summaries, err := svc.repo.GetUsageReport()
 	if err != nil {
 		return apperror.MapRepoError(err, "failed to get monthly usage report")
 	}
 
-	now := time.Now()
-	firstOfThisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-	lastMonth := firstOfThisMonth.AddDate(0, -1, 0)
+	lastMonth := reportPeriod(now)
 
 	year := lastMonth.Year()
 	month := lastMonth.Month()
 	startDate := fmt.Sprintf("%d-%02d-01", year, int(month))
 	endDate := fmt.Sprintf("%d-%02d-%02d", year, int(month), lastDayOfMonth(lastMonth))
 
-	// internalTeam, err := svc.internalRepo.GetMemberAPI()
-	// if err != nil {
-	// 	log.Warn().
-	// 		Err(err).
-	// 		Msg("failed to get internal team emails")
-	// }
-
-	// ccEmails := svc.buildCCEmails(internalTeam)
-
-	ccEmails := []string{
-		"diki@aiforesee.com",
-		"chris@aiforesee.com",
-		"finance@aiforesee.com",
-		"johanes@aiforesee.com",
-		"arief.nugraha@bankraya.co.id",
+	var internalCC []string
+	internalTeam, err := svc.internalRepo.GetMemberAPI()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("failed to get internal team emails")
+	} else {
+		internalCC = svc.buildCCEmails(internalTeam)
 	}
 
 	for _, summary := range summaries {
-		svc.processSummaryReport(summary, ccEmails, startDate, endDate, month, year)
+		schedule, ok := schedules[summary.CompanyId]
+		if !ok {
+			schedule = defaultReportSchedule(summary.CompanyId)
+		}
+
+		if schedule.OptOut || !isDue(schedule) {
+			continue
+		}
+
+		if !svc.processSummaryReport(summary, schedule, internalCC, startDate, endDate, month, year) {
+			continue
+		}
+
+		period := lastMonth.Format(reportPeriodLayout)
+		svc.sentReports.Store(summary.CompanyId, period)
+
+		companyIdStr := strconv.FormatUint(uint64(summary.CompanyId), 10)
+		if err := svc.repo.UpdateReportSentPeriodAPI(companyIdStr, period); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", summary.CompanyId).
+				Msg("failed to record usage report period")
+		}
 	}
 
 	return nil
 }
 
-func (svc *service) GetUsageReport(companyId uint, pricingStrategy string, month, year int) (*usageSummary, error) {
+func (svc *service) reportSent(companyId uint, period string) bool {
+	sent, ok := svc.sentReports.Load(companyId)
+	return ok && sent == period
+}
+
+func (svc *service) GetReportSchedule(companyId uint) (*reportSchedule, error) {
 	companyIdStr := strconv.FormatUint(uint64(companyId), 10)
-	monthStr := strconv.FormatUint(uint64(month), 10)
-	yearStr := strconv.FormatUint(uint64(year), 10)
 
-	summary, err := svc.repo.GetUsageReportByCompany(companyIdStr, pricingStrategy, monthStr, yearStr)
+	schedule, err := svc.repo.GetReportScheduleAPI(companyIdStr)
 	if err != nil {
-		return nil, apperror.MapRepoError(err, "failed to get usage report")
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return defaultReportSchedule(companyId), nil
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchReportSchedule)
+	}
+	if schedule == nil || schedule.CompanyId == 0 {
+		return defaultReportSchedule(companyId), nil
+	}
+
+	return schedule, nil
+}
+
+func (svc *service) UpdateReportSchedule(authCtx *model.AuthContext, companyId uint, req *updateReportScheduleRequest) (*reportSchedule, error) {
+	schedule := &reportSchedule{
+		CompanyId:           companyId,
+		To:                  req.To,
+		CC:                  req.CC,
+		BCC:                 req.BCC,
+		IncludeAdmins:       req.IncludeAdmins,
+		IncludeInternalTeam: req.IncludeInternalTeam,
+		DeliveryDay:         req.DeliveryDay,
+		DeliveryTime:        req.DeliveryTime,
+		OptOut:              req.OptOut,
+	}
+
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+
+	updated, err := svc.repo.UpsertReportScheduleAPI(strconv.FormatUint(uint64(companyId), 10), schedule)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to update usage report schedule")
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Action:    constant.EventUpdateReportSchedule,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", constant.EventUpdateReportSchedule).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+
+	return updated, nil
+}
+
+func (svc *service) GetUsageReport(companyId uint, pricingStrategy string, month, year int) (*usageSummary, error) {
+	summary, _, err := svc.getUsageSummary(companyId, pricingStrategy, month, year)
+	if err != nil {
+		return nil, err
 	}
 
 	return summary, nil
 }
 
-func (svc *service) buildProductGroups(
-	companyId uint,
-	pricingStrategy, startDate, endDate string,
-	month, year int,
-) ([]ProductGroup, string, error) {
+func (svc *service) GetInvoices(companyId uint) ([]*invoice, error) {
+	invoices, err := svc.repo.GetInvoicesAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch invoices")
+	}
+
+	return invoices, nil
+}
+
+func (svc *service) DownloadInvoice(companyId uint, year, month int, format string) (*invoiceFile, error) {
+	if format == "" {
+		format = invoiceFormatPDF
+	}
+	if format != invoiceFormatPDF && format != invoiceFormatXlsx {
+		return nil, apperror.BadRequest(constant.InvalidInvoiceFormat)
+	}
+
+	now := time.Now()
+	if year > now.Year() || (year == now.Year() && month >= int(now.Month())) {
+		return nil, apperror.BadRequest(constant.InvoicePeriodNotClosed)
+	}
+
+	summary, _, err := svc.getUsageSummary(companyId, constant.PaidStatus, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := svc.issueInvoice(companyId, year, month, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderInvoice(inv, format)
+}
+
+// issueInvoice returns the invoice of the period, creating and numbering it
+// from the usage summary the first time it is requested.
+func (svc *service) issueInvoice(companyId uint, year, month int, summary *usageSummary) (*invoice, error) {
 	companyIdStr := strconv.FormatUint(uint64(companyId), 10)
-	monthStr := strconv.FormatUint(uint64(month), 10)
-	yearStr := strconv.FormatUint(uint64(year), 10)
 
-	summary, err := svc.repo.GetUsageReportByCompany(companyIdStr, pricingStrategy, monthStr, yearStr)
+	existing, err := svc.repo.GetInvoiceByPeriodAPI(companyIdStr, strconv.Itoa(year), strconv.Itoa(month))
 	if err != nil {
-		return nil, "", apperror.MapRepoError(err, "failed to get usage report")
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchInvoice)
+		}
+	}
+	if existing != nil && existing.Id != 0 {
+		return existing, nil
 	}
 
-	applyDedup := strconv.FormatBool(isDedupCompany(summary.CompanyName))
+	companyData, err := svc.companyRepo.GetCompanyAPI(companyIdStr)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch company")
+	}
+	if companyData == nil {
+		return nil, apperror.NotFound("company not found")
+	}
 
-	return []ProductGroup{
-			{
-				GroupName: "Procat",
-				Key:       groupKeyProcat,
-				Products:  toXlsxProducts(summary.ProcatProducts),
-				FetchFn:   svc.NewProcatFetchFn(pricingStrategy, applyDedup, monthStr, yearStr),
-			},
-			{
-				GroupName: "Scoreezy",
-				Key:       groupKeyScorezy,
-				Products:  toXlsxProducts(summary.ScoreezyProducts),
-				FetchFn:   svc.NewScoreezyFetchFn(startDate, endDate),
-			},
-		},
-		summary.CompanyName,
-		nil
+	plan, err := svc.getPricingPlan(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := append(append([]usagePerProduct{}, summary.ProcatProducts...), summary.ScoreezyProducts...)
+
+	draft := priceInvoice(plan, companyData.BasePricing, usage)
+	draft.CompanyId = companyId
+	draft.CompanyName = companyData.CompanyName
+	draft.CompanyAddress = companyData.CompanyAddress
+	draft.PaymentScheme = companyData.PaymentScheme
+	draft.PeriodYear = year
+	draft.PeriodMonth = month
+
+	inv, err := svc.repo.CreateInvoiceAPI(&draft)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to create invoice")
+	}
+
+	return inv, nil
+}
+
+func renderInvoice(inv *invoice, format string) (*invoiceFile, error) {
+	if format == invoiceFormatXlsx {
+		data, err := renderInvoiceXlsx(inv)
+		if err != nil {
+			return nil, apperror.Internal("failed to render invoice", err)
+		}
+
+		return &invoiceFile{
+			Filename:    invoiceFilename(inv, invoiceFormatXlsx),
+			ContentType: constant.MimeXlsx,
+			Data:        data,
+		}, nil
+	}
+
+	data, err := renderInvoicePDF(inv)
+	if err != nil {
+		return nil, apperror.Internal("failed to render invoice", err)
+	}
+
+	return &invoiceFile{
+		Filename:    invoiceFilename(inv, invoiceFormatPDF),
+		ContentType: constant.MimePdf,
+		Data:        data,
+	}, nil
 }
 
 func (svc *service) buildCCEmails(internalTeam *model.AifcoreAPIResponse[[]internalteam.MstInternalTeam]) []string {
@@ -224,13 +459,17 @@ func (svc *service) buildCCEmails(internalTeam *model.AifcoreAPIResponse[[]inter
 	return ccEmails
 }
 
+// processSummaryReport reports whether the company is done for the period,
+// either sent or with nothing to send. It is false when a lookup failed so
+// the next run tries again.
 func (svc *service) processSummaryReport(
 	summary usageSummary,
-	ccEmails []string,
+	schedule *reportSchedule,
+	internalCC []string,
 	startDate, endDate string,
 	month time.Month,
 	year int,
-) {
+) bool {
 	companyId := summary.CompanyId
 	yearStr := strconv.Itoa(year)
 	monthStr := strconv.Itoa(int(month))
@@ -239,7 +478,7 @@ func (svc *service) processSummaryReport(
 		log.Warn().
 			Uint("company_id", companyId).
 			Msg("no subscribed products, skipping send monthly report usage")
-		return
+		return true
 	}
 
 	admins, err := svc.repo.GetAdminsData(companyId)
@@ -248,27 +487,58 @@ func (svc *service) processSummaryReport(
 			Err(err).
 			Uint("company_id", companyId).
 			Msg("failed to get admin emails")
-		return
+		return false
 	}
 
 	if len(admins) == 0 {
 		log.Warn().
 			Uint("company_id", companyId).
 			Msg("no admin emails found, skipping send monthly report usage")
-		return
+		return true
 	}
 
-	applyDedup := strconv.FormatBool(isDedupCompany(summary.CompanyName))
+	recipients := resolveRecipients(schedule, admins, internalCC)
+	if len(recipients.To) == 0 {
+		log.Warn().
+			Uint("company_id", companyId).
+			Msg("no report recipients configured, skipping send monthly report usage")
+		return true
+	}
+
+	// the summaries of all companies are counted without dedup
+	policy, err := svc.getBillingPolicy(companyId)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", companyId).
+			Msg("failed to get billing policy, skipping send monthly report usage")
+		return false
+	}
+	if policy.Dedup {
+		deduped, err := svc.repo.GetUsageReportByCompany(
+			strconv.FormatUint(uint64(companyId), 10), constant.PaidStatus, monthStr, yearStr, policy,
+		)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", companyId).
+				Msg("failed to get deduplicated usage, skipping send monthly report usage")
+			return false
+		}
+		summary = *deduped
+	}
+	applyFreeHits(&summary, policy)
+
 	groups := []ProductGroup{
 		{
 			GroupName: "Procat",
 			Products:  toXlsxProducts(summary.ProcatProducts),
-			FetchFn:   svc.NewProcatFetchFn(constant.PaidStatus, applyDedup, monthStr, yearStr),
+			FetchFn:   svc.NewProcatFetchFn(constant.PaidStatus, policy, monthStr, yearStr),
 		},
 		{
 			GroupName: "Scoreezy",
 			Products:  toXlsxProducts(summary.ScoreezyProducts),
-			FetchFn:   svc.NewScoreezyFetchFn(startDate, endDate),
+			FetchFn:   svc.NewScoreezyFetchFn(startDate, endDate, nil),
 		},
 	}
 
@@ -281,10 +551,6 @@ func (svc *service) processSummaryReport(
 		summary,
 	)
 
-	listReceiver := []string{
-		"DOP.reconciliation@bankraya.co.id",
-	}
-
 	xlsxBytes, xlsxErr := svc.generateUsageXlsx(XlsxReportInput{
 		CompanyId:       companyId,
 		CompanyName:     summary.CompanyName,
@@ -292,7 +558,6 @@ func (svc *service) processSummaryReport(
 		PeriodMonth:     int(month),
 		ProductGroups:   groups,
 		PricingStrategy: constant.PaidStatus,
-		Password:        admins[0].Key,
 	})
 	if xlsxErr != nil {
 		log.Warn().
@@ -302,73 +567,184 @@ func (svc *service) processSummaryReport(
 	}
 
 	var attachments []mail.MailAttachment
+	invoiceAttachment, err := svc.invoiceAttachment(summary, month, year)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", companyId).
+			Msg("sending email without invoice")
+	} else {
+		attachments = append(attachments, *invoiceAttachment)
+	}
+
 	if xlsxBytes != nil {
-		attachments = append(attachments, mail.MailAttachment{
+		workbook := mail.MailAttachment{
 			FileName: fmt.Sprintf("Monthly Usage Report for %s - %s %d.xlsx", summary.CompanyName, month, year),
 			Content:  xlsxBytes,
 			MimeType: constant.MimeXlsx,
-		})
+		}
+
+		svc.sendProtectedWorkbook(recipients, admins, companyId, summary.CompanyName, subject, templateData, workbook, attachments)
+		return true
 	}
 
-	for _, email := range listReceiver {
-		if err := svc.mailSvc.SendWithTemplate(
-			email,
-			ccEmails,
-			subject,
-			"monthly_usage_report.html",
-			templateData,
-			attachments...,
-		); err != nil {
-			log.Warn().
-				Err(err).
-				Uint("company_id", companyId).
-				Msg("failed to send monthly usage report")
-		}
-	}
-
-	// todo: uncomment this section
-	// for _, admin := range admins {
-	// 	xlsxPassword := admin.Key
-
-	// 	xlsxBytes, xlsxErr := svc.generateUsageXlsx(XlsxReportInput{
-	// 		CompanyId:       companyId,
-	// 		CompanyName:     summary.CompanyName,
-	// 		PeriodYear:      year,
-	// 		PeriodMonth:     int(month),
-	// 		ProductGroups:   groups,
-	// 		PricingStrategy: constant.PaidStatus,
-	// 		Password:        xlsxPassword,
-	// 	})
-	// 	if xlsxErr != nil {
-	// 		log.Warn().
-	// 			Err(xlsxErr).
-	// 			Uint("company_id", companyId).
-	// 			Msg("sending email without attachment")
-	// 	}
-
-	// var attachments []mail.MailAttachment
-	// 	if xlsxBytes != nil {
-	// 		attachments = append(attachments, mail.MailAttachment{
-	// 			FileName: fmt.Sprintf("Monthly Usage Report for %s - %s %d.xlsx", summary.CompanyName, month, year),
-	// 			Content:  xlsxBytes,
-	// 			MimeType: constant.MimeXlsx,
-	// 		})
-	// 	}
-
-	// 	if err := svc.mailSvc.SendWithTemplate(
-	// 		admin.Email,
-	// 		ccEmails,
-	// 		subject,
-	// 		"monthly_usage_report.html",
-	// 		templateData,
-	// 		attachments...,
-	// 	); err != nil {
-	// 		log.Warn().
-	// 			Err(err).
-	// 			Uint("company_id", companyId).
-	// 			Msg("failed to send monthly usage report")
-	// 	}
-	// }
+	if err := svc.mailSvc.SendWithTemplateToList(
+		recipients.To,
+		recipients.CC,
+		recipients.BCC,
+		subject,
+		"monthly_usage_report.html",
+		templateData,
+		attachments...,
+	); err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", companyId).
+			Msg("failed to send monthly usage report")
+		return false
+	}
+
+	return true
+}
+
+func (svc *service) invoiceAttachment(summary usageSummary, month time.Month, year int) (*mail.MailAttachment, error) {
+	inv, err := svc.issueInvoice(summary.CompanyId, year, int(month), &summary)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := renderInvoice(inv, invoiceFormatPDF)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mail.MailAttachment{
+		FileName: file.Filename,
+		Content:  file.Data,
+		MimeType: file.ContentType,
+	}, nil
+}
+
+func defaultReportSchedule(companyId uint) *reportSchedule {
+	return &reportSchedule{
+		CompanyId:           companyId,
+		IncludeAdmins:       true,
+		IncludeInternalTeam: true,
+		DeliveryDay:         defaultReportDeliveryDay,
+		DeliveryTime:        defaultReportDeliveryTime,
+	}
+}
+
+func (s *reportSchedule) validate() error {
+	if s.DeliveryDay < 1 || s.DeliveryDay > maxReportDeliveryDay {
+		return apperror.BadRequest(fmt.Sprintf(constant.InvalidReportDeliveryDay, maxReportDeliveryDay))
+	}
+
+	if _, err := time.Parse(reportDeliveryTimeLayout, s.DeliveryTime); err != nil {
+		return apperror.BadRequest(constant.InvalidReportDeliveryTime)
+	}
+
+	for _, list := range [][]string{s.To, s.CC, s.BCC} {
+		for _, email := range list {
+			if _, err := netmail.ParseAddress(email); err != nil {
+				return apperror.BadRequest(fmt.Sprintf(constant.InvalidReportRecipient, email))
+			}
+		}
+	}
+
+	if !s.OptOut && len(s.To) == 0 && !s.IncludeAdmins {
+		return apperror.BadRequest("at least one recipient is required unless the company opts out")
+	}
+
+	return nil
+}
+
+// isDue reports whether the delivery time of this month has passed and the
+// report of last month has not been sent. The delivery day moves to the next
+// business day, or back to the previous Friday when that would cross into
+// the next month.
+func (s *reportSchedule) isDue(now time.Time) bool {
+	deliveryTime, err := time.Parse(reportDeliveryTimeLayout, s.DeliveryTime)
+	if err != nil {
+		return false
+	}
+
+	due := time.Date(now.Year(), now.Month(), targetRunDay(now, s.DeliveryDay),
+		deliveryTime.Hour(), deliveryTime.Minute(), 0, 0, now.Location())
+
+	return !now.Before(due) && s.LastSentPeriod < reportPeriod(now).Format(reportPeriodLayout)
+}
+
+// reportPeriod returns the first day of the month reported in the month of
+// now, the month before it.
+func reportPeriod(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0)
+}
+
+func targetRunDay(now time.Time, day int) int {
+	target := time.Date(now.Year(), now.Month(), day, 0, 0, 0, 0, now.Location())
+
+	shift := 0
+	switch target.Weekday() {
+	case time.Saturday:
+		shift = 2
+	case time.Sunday:
+		shift = 1
+	}
+
+	if shift == 0 {
+		return day
+	}
+
+	adjusted := target.AddDate(0, 0, shift)
+	if adjusted.Month() != target.Month() {
+		adjusted = target.AddDate(0, 0, shift-3)
+	}
+
+	return adjusted.Day()
+}
+
+// resolveRecipients merges the stored lists with the company admins and the
+// internal team, dropping duplicates so nobody gets the report twice.
+func resolveRecipients(schedule *reportSchedule, admins []adminEmail, internalCC []string) reportRecipients {
+	seen := make(map[string]struct{})
+	collect := func(lists ...[]string) []string {
+		var out []string
+		for _, list := range lists {
+			for _, email := range list {
+				key := strings.ToLower(strings.TrimSpace(email))
+				if key == "" {
+					continue
+				}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+
+				seen[key] = struct{}{}
+				out = append(out, email)
+			}
+		}
+
+		return out
+	}
+
+	var adminEmails []string
+	if schedule.IncludeAdmins {
+		for _, admin := range admins {
+			adminEmails = append(adminEmails, admin.Email)
+		}
+	}
+
+	var internalEmails []string
+	if schedule.IncludeInternalTeam {
+		internalEmails = internalCC
+	}
+
+	return reportRecipients{
+		To:  collect(schedule.To, adminEmails),
+		CC:  collect(schedule.CC, internalEmails),
+		BCC: collect(schedule.BCC),
+	}
 }
 
 func filterGroups(groups []ProductGroup, allowedKeys []string) []ProductGroup {
@@ -402,6 +778,14 @@ func (svc *service) generateUsageXlsx(input XlsxReportInput) ([]byte, error) {
 	defaultSheet := f.GetSheetName(0)
 	builtAny := false
 
+	if input.Breakdown != nil && len(input.Breakdown.Buckets) > 0 {
+		if err := writeUsageSummarySheet(f, input.Breakdown); err != nil {
+			return nil, err
+		}
+
+		builtAny = true
+	}
+
 	for _, group := range input.ProductGroups {
 		for _, product := range group.Products {
 			created, err := svc.buildProductSheet(f, group, product, input, !builtAny, input.CompanyName)
@@ -421,7 +805,7 @@ func (svc *service) generateUsageXlsx(input XlsxReportInput) ([]byte, error) {
 
 	f.DeleteSheet(defaultSheet)
 
-	return encryptOrWriteXlsx(f, input.Password)
+	return writeXlsx(f)
 }
 
 func (svc *service) buildProductSheet(
@@ -439,7 +823,7 @@ func (svc *service) buildProductSheet(
 	case constant.SlugCustomMAW2:
 		def = productCustomMAW2(companyName)
 	default:
-		def, ok = productRegistry[product.ProductSlug]
+		def, ok = svc.sheetDefs[product.ProductSlug]
 		if !ok {
 			log.Warn().
 				Str("product_slug", product.ProductSlug).
@@ -488,22 +872,15 @@ func (svc *service) buildProductSheet(
 	return true, nil
 }
 
-func encryptOrWriteXlsx(f *excelize.File, password string) ([]byte, error) {
+// writeXlsx returns the plain workbook, it is encrypted per recipient
+// before it leaves the service.
+func writeXlsx(f *excelize.File) ([]byte, error) {
 	var buf bytes.Buffer
 	if err := f.Write(&buf); err != nil {
 		return nil, apperror.Internal(fmt.Sprintf("failed to write sheet: %s", err), err)
 	}
 
-	if password == "" {
-		return buf.Bytes(), nil
-	}
-
-	encrypted, err := excelize.Encrypt(buf.Bytes(), &excelize.Options{Password: password})
-	if err != nil {
-		return nil, apperror.Internal("failed to write encypted xlxs in buffer", err)
-	}
-
-	return encrypted, nil
+	return buf.Bytes(), nil
 }
 
 func buildMonthlyUsageTemplateData(
@@ -663,17 +1040,6 @@ func toXlsxProducts(products []usagePerProduct) []XlsxReportProduct {
 	return result
 }
 
-func isDedupCompany(companyName string) bool {
-	nameLower := strings.ToLower(strings.TrimSpace(companyName))
-	for _, name := range dedupCompanyNames {
-		if nameLower == name {
-			return true
-		}
-	}
-
-	return false
-}
-
 type LogRow interface {
 	logRow()
 }
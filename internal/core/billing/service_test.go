@@ -0,0 +1,147 @@
+package billing
+
+import (
+	"front-office/internal/core/internalteam"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetRunDay(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		day      int
+		expected int
+	}{
+		{"weekday", time.Date(2025, time.March, 1, 0, 0, 0, 0, jakarta), 3, 3},
+		{"saturday moves to monday", time.Date(2025, time.May, 1, 0, 0, 0, 0, jakarta), 3, 5},
+		{"sunday moves to monday", time.Date(2025, time.August, 1, 0, 0, 0, 0, jakarta), 3, 4},
+		{"end of month moves back to friday", time.Date(2026, time.February, 1, 0, 0, 0, 0, jakarta), 28, 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, targetRunDay(tt.now, tt.day))
+		})
+	}
+}
+
+func TestReportScheduleIsDue(t *testing.T) {
+	schedule := &reportSchedule{DeliveryDay: 3, DeliveryTime: "09:35"}
+
+	assert.True(t, schedule.isDue(time.Date(2025, time.March, 3, 9, 35, 20, 0, time.UTC)))
+	assert.False(t, schedule.isDue(time.Date(2025, time.March, 3, 9, 30, 0, 0, time.UTC)))
+	assert.False(t, schedule.isDue(time.Date(2025, time.March, 2, 9, 45, 0, 0, time.UTC)))
+	// the first check after the delivery time picks it up
+	assert.True(t, schedule.isDue(time.Date(2025, time.March, 3, 9, 45, 0, 0, time.UTC)))
+	assert.True(t, schedule.isDue(time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)))
+
+	// the february report has gone out
+	schedule.LastSentPeriod = "2025-02"
+	assert.False(t, schedule.isDue(time.Date(2025, time.March, 3, 9, 45, 0, 0, time.UTC)))
+	assert.True(t, schedule.isDue(time.Date(2025, time.April, 3, 9, 45, 0, 0, time.UTC)))
+
+	// the january report going out of schedule does not hold back february
+	schedule.LastSentPeriod = "2025-01"
+	assert.True(t, schedule.isDue(time.Date(2025, time.March, 31, 23, 45, 0, 0, time.UTC)))
+}
+
+func TestReportScheduleValidate(t *testing.T) {
+	valid := func() *reportSchedule {
+		return &reportSchedule{
+			To:           []string{"finance@example.com"},
+			CC:           []string{"ops@example.com"},
+			DeliveryDay:  3,
+			DeliveryTime: "09:00",
+		}
+	}
+
+	assert.NoError(t, valid().validate())
+
+	s := valid()
+	s.DeliveryDay = 29
+	assert.Error(t, s.validate())
+
+	s = valid()
+	s.DeliveryTime = "9am"
+	assert.Error(t, s.validate())
+
+	s = valid()
+	s.BCC = []string{"not-an-email"}
+	assert.Error(t, s.validate())
+
+	s = valid()
+	s.To = nil
+	assert.Error(t, s.validate())
+
+	s.OptOut = true
+	assert.NoError(t, s.validate())
+}
+
+func TestResolveRecipients(t *testing.T) {
+	schedule := &reportSchedule{
+		To:                  []string{"finance@example.com"},
+		CC:                  []string{"ops@example.com"},
+		BCC:                 []string{"audit@example.com"},
+		IncludeAdmins:       true,
+		IncludeInternalTeam: true,
+	}
+	admins := []adminEmail{{Email: "admin@example.com"}, {Email: "Finance@example.com"}}
+	internalCC := []string{"support@aiforesee.com", "admin@example.com"}
+
+	result := resolveRecipients(schedule, admins, internalCC)
+
+	assert.Equal(t, []string{"finance@example.com", "admin@example.com"}, result.To)
+	assert.Equal(t, []string{"ops@example.com", "support@aiforesee.com"}, result.CC)
+	assert.Equal(t, []string{"audit@example.com"}, result.BCC)
+
+	schedule.IncludeAdmins = false
+	schedule.IncludeInternalTeam = false
+
+	result = resolveRecipients(schedule, admins, internalCC)
+
+	assert.Equal(t, []string{"finance@example.com"}, result.To)
+	assert.Equal(t, []string{"ops@example.com"}, result.CC)
+}
+
+func TestSendScheduledUsageReportsOnce(t *testing.T) {
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	// company 1 has no stored schedule and its sent period cannot be recorded
+	var recorded int
+	svc, _, sender := setupQuotaService(t, map[string]func(*http.Request) (int, any){
+		"GET /api/core/billing/report-schedules": ok([]*reportSchedule{}),
+		"GET /api/core/billing/summaries": ok([]usageSummary{{
+			CompanyId:          1,
+			CompanyName:        "PT Example",
+			SubscribedProducts: []subscribedProduct{{ProductId: 10, ProductName: "Loan Record Checker"}},
+		}}),
+		"GET /api/core/billing/admins/1": ok([]adminEmail{{MemberId: 3, Email: "admin@example.com"}}),
+		"PUT /api/core/billing/report-schedules/1/last-sent-period": func(*http.Request) (int, any) {
+			recorded++
+			return http.StatusInternalServerError, nil
+		},
+	})
+	svc.internalRepo = internalteam.NewRepository(svc.cfg, &coreStub{}, nil)
+
+	due := time.Date(2025, time.March, 3, 9, 15, 0, 0, time.UTC)
+	require.NoError(t, svc.SendScheduledUsageReports(due))
+	require.NotEmpty(t, sender.sent)
+	sent := len(sender.sent)
+
+	require.NoError(t, svc.SendScheduledUsageReports(due.Add(15*time.Minute)))
+	assert.Len(t, sender.sent, sent)
+	assert.Equal(t, 1, recorded)
+
+	// the next period goes out again
+	require.NoError(t, svc.SendScheduledUsageReports(due.AddDate(0, 1, 0)))
+	assert.Greater(t, len(sender.sent), sent)
+}
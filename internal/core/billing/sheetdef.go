@@ -0,0 +1,485 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/helper"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+	"gorm.io/datatypes"
+)
+
+const (
+	sheetSourceProcat   = "procat"
+	sheetSourceScoreezy = "scoreezy"
+
+	maxSheetNameLength = 31
+	maxPreviewLogs     = 100
+)
+
+// sheetDefsFile is the YAML or JSON document that defines product sheets
+// without a deploy, entries replace the built-in sheet of the same slug.
+//
+//	products:
+//	  - slug: IDENTITY_phone_live_status
+//	    product_name: Phone Live Status
+//	    source: procat
+//	    columns:
+//	      - header: Phone Number
+//	        path: response_body.input.phone_number
+//	      - header: Device Status
+//	        path: data.live_status
+//	        transform: {name: split_index, sep: ",", index: 1}
+type sheetDefsFile struct {
+	Products []productSheetSpec `yaml:"products" json:"products"`
+}
+
+type productSheetSpec struct {
+	Slug        string       `yaml:"slug" json:"slug"`
+	ProductName string       `yaml:"product_name" json:"product_name"`
+	SheetName   string       `yaml:"sheet_name" json:"sheet_name"`
+	Source      string       `yaml:"source" json:"source"`
+	Columns     []columnSpec `yaml:"columns" json:"columns"`
+}
+
+// columnSpec reads Path from the log, its first segment is a field of the
+// log and the rest walks into the JSON of data, request_body or
+// response_body. Static columns print the same value on every row.
+type columnSpec struct {
+	Header    string         `yaml:"header" json:"header"`
+	Type      string         `yaml:"type" json:"type"`
+	Width     float64        `yaml:"width" json:"width"`
+	Path      string         `yaml:"path" json:"path"`
+	Static    string         `yaml:"static" json:"static"`
+	Transform *transformSpec `yaml:"transform" json:"transform"`
+}
+
+type transformSpec struct {
+	Name  string `yaml:"name" json:"name"`
+	Sep   string `yaml:"sep" json:"sep"`
+	Index int    `yaml:"index" json:"index"`
+}
+
+var columnTypes = map[string]ColumnType{
+	"":         ColTypeText,
+	"text":     ColTypeText,
+	"number":   ColTypeNumber,
+	"date":     ColTypeDate,
+	"datetime": ColTypeDateTime,
+}
+
+var procatFields = map[string]func(*transaction.LogTransProductCatalog) interface{}{
+	"transaction_id":   func(r *transaction.LogTransProductCatalog) interface{} { return r.TransactionID },
+	"member_id":        func(r *transaction.LogTransProductCatalog) interface{} { return r.MemberID },
+	"loan_no":          func(r *transaction.LogTransProductCatalog) interface{} { return r.LoanNo },
+	"pricing_strategy": func(r *transaction.LogTransProductCatalog) interface{} { return r.PricingStrategy },
+	"success":          func(r *transaction.LogTransProductCatalog) interface{} { return r.Success },
+	"status":           func(r *transaction.LogTransProductCatalog) interface{} { return r.Status },
+	"message":          func(r *transaction.LogTransProductCatalog) interface{} { return r.Message },
+	"notes":            func(r *transaction.LogTransProductCatalog) interface{} { return r.Notes },
+	"request_time":     func(r *transaction.LogTransProductCatalog) interface{} { return r.RequestTime },
+	"response_time":    func(r *transaction.LogTransProductCatalog) interface{} { return r.ResponseTime },
+	"created_at":       func(r *transaction.LogTransProductCatalog) interface{} { return r.CreatedAt },
+}
+
+var procatJSONFields = map[string]func(*transaction.LogTransProductCatalog) datatypes.JSON{
+	"data":          func(r *transaction.LogTransProductCatalog) datatypes.JSON { return r.Data },
+	"request_body":  func(r *transaction.LogTransProductCatalog) datatypes.JSON { return r.RequestBody },
+	"response_body": func(r *transaction.LogTransProductCatalog) datatypes.JSON { return r.ResponseBody },
+}
+
+var scoreezyFields = map[string]func(*transaction.LogTransScoreezy) interface{}{
+	"trx_id":                 func(r *transaction.LogTransScoreezy) interface{} { return r.TrxId },
+	"member_id":              func(r *transaction.LogTransScoreezy) interface{} { return r.MemberId },
+	"loan_no":                func(r *transaction.LogTransScoreezy) interface{} { return r.LoanNo },
+	"status":                 func(r *transaction.LogTransScoreezy) interface{} { return r.Status },
+	"success":                func(r *transaction.LogTransScoreezy) interface{} { return r.Success },
+	"message":                func(r *transaction.LogTransScoreezy) interface{} { return r.Message },
+	"grade":                  func(r *transaction.LogTransScoreezy) interface{} { return r.Grade },
+	"probability_to_default": func(r *transaction.LogTransScoreezy) interface{} { return r.ProbabilityToDefault },
+	"behavior":               func(r *transaction.LogTransScoreezy) interface{} { return r.Behavior },
+	"identity":               func(r *transaction.LogTransScoreezy) interface{} { return r.Identity },
+	"created_at":             func(r *transaction.LogTransScoreezy) interface{} { return r.CreatedAt },
+}
+
+var scoreezyJSONFields = map[string]func(*transaction.LogTransScoreezy) datatypes.JSON{
+	"data": func(r *transaction.LogTransScoreezy) datatypes.JSON { return r.Data },
+}
+
+var transforms = map[string]func(spec *transformSpec) (func(string) string, error){
+	"split_index": func(spec *transformSpec) (func(string) string, error) {
+		if spec.Sep == "" {
+			return nil, errors.New("split_index needs a sep")
+		}
+
+		return splitIndex(spec.Sep, spec.Index), nil
+	},
+	"upper": func(*transformSpec) (func(string) string, error) { return strings.ToUpper, nil },
+	"lower": func(*transformSpec) (func(string) string, error) { return strings.ToLower, nil },
+	"trim":  func(*transformSpec) (func(string) string, error) { return strings.TrimSpace, nil },
+}
+
+// loadSheetDefs returns the built-in product sheets overridden by the
+// definitions in path, an empty path keeps the built-in sheets.
+func loadSheetDefs(path string) (map[string]ProductSheetDef, error) {
+	defs := maps.Clone(productRegistry)
+	if path == "" {
+		return defs, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sheet definitions: %w", err)
+	}
+
+	file, err := decodeSheetDefs(raw, filepath.Ext(path))
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := compileSheetDefs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	maps.Copy(defs, compiled)
+
+	if err := checkUniqueSheetNames(defs); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
+
+func decodeSheetDefs(raw []byte, ext string) (*sheetDefsFile, error) {
+	var file sheetDefsFile
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("decode sheet definitions: %w", err)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("decode sheet definitions: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("sheet definitions must be a .yaml, .yml or .json file, got %q", ext)
+	}
+
+	return &file, nil
+}
+
+// compileSheetDefs validates every product of the file and reports all
+// problems at once.
+func compileSheetDefs(file *sheetDefsFile) (map[string]ProductSheetDef, error) {
+	defs := make(map[string]ProductSheetDef, len(file.Products))
+
+	var errs []error
+	for i, spec := range file.Products {
+		def, err := compileProductSheet(&spec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("products[%d] %q: %w", i, spec.Slug, err))
+			continue
+		}
+
+		if _, ok := defs[spec.Slug]; ok {
+			errs = append(errs, fmt.Errorf("products[%d] %q: duplicate slug", i, spec.Slug))
+			continue
+		}
+
+		defs[spec.Slug] = def
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return defs, nil
+}
+
+func compileProductSheet(spec *productSheetSpec) (ProductSheetDef, error) {
+	var errs []error
+
+	if spec.Slug == "" {
+		errs = append(errs, errors.New("slug is required"))
+	}
+	if spec.ProductName == "" {
+		errs = append(errs, errors.New("product_name is required"))
+	}
+	if spec.Source != sheetSourceProcat && spec.Source != sheetSourceScoreezy {
+		errs = append(errs, fmt.Errorf("source must be %s or %s", sheetSourceProcat, sheetSourceScoreezy))
+	}
+	if len(spec.Columns) == 0 {
+		errs = append(errs, errors.New("at least one column is required"))
+	}
+
+	sheetName := spec.SheetName
+	if sheetName == "" {
+		sheetName = spec.ProductName
+	}
+	if err := checkSheetName(sheetName); err != nil {
+		errs = append(errs, err)
+	}
+
+	columns := make([]ColumnDef, 0, len(spec.Columns))
+	for i := range spec.Columns {
+		col, err := compileColumn(spec.Source, &spec.Columns[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("columns[%d] %q: %w", i, spec.Columns[i].Header, err))
+			continue
+		}
+
+		columns = append(columns, col)
+	}
+
+	if len(errs) > 0 {
+		return ProductSheetDef{}, errors.Join(errs...)
+	}
+
+	return ProductSheetDef{
+		ProductName: spec.ProductName,
+		SheetName:   spec.SheetName,
+		Columns:     columns,
+	}, nil
+}
+
+func compileColumn(source string, spec *columnSpec) (ColumnDef, error) {
+	if spec.Header == "" {
+		return ColumnDef{}, errors.New("header is required")
+	}
+
+	colType, ok := columnTypes[spec.Type]
+	if !ok {
+		return ColumnDef{}, fmt.Errorf("unknown type %q, use text, number, date or datetime", spec.Type)
+	}
+
+	if (spec.Path == "") == (spec.Static == "") {
+		return ColumnDef{}, errors.New("set exactly one of path or static")
+	}
+
+	if spec.Static != "" {
+		if spec.Transform != nil {
+			return ColumnDef{}, errors.New("static columns cannot have a transform")
+		}
+
+		return ColumnDef{Header: spec.Header, Type: colType, Width: spec.Width, ExtractFn: staticVal(spec.Static)}, nil
+	}
+
+	extract, err := compilePath(source, spec.Path)
+	if err != nil {
+		return ColumnDef{}, err
+	}
+
+	if spec.Transform != nil {
+		build, ok := transforms[spec.Transform.Name]
+		if !ok {
+			return ColumnDef{}, fmt.Errorf("unknown transform %q", spec.Transform.Name)
+		}
+
+		fn, err := build(spec.Transform)
+		if err != nil {
+			return ColumnDef{}, err
+		}
+
+		extract = transformed(extract, fn)
+	}
+
+	return ColumnDef{Header: spec.Header, Type: colType, Width: spec.Width, ExtractFn: extract}, nil
+}
+
+// compilePath resolves the path once so a bad path fails at startup rather
+// than as an empty column in a customer's report.
+func compilePath(source, path string) (RowExtractFn, error) {
+	segments := strings.Split(path, ".")
+	root, keys := segments[0], segments[1:]
+	for _, s := range segments {
+		if s == "" {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+
+	switch source {
+	case sheetSourceProcat:
+		if field, ok := procatJSONFields[root]; ok {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("path %q needs a key inside %s", path, root)
+			}
+
+			return fromProcat(func(r *transaction.LogTransProductCatalog) interface{} {
+				return nestedStr(field(r), keys)
+			}), nil
+		}
+		if field, ok := procatFields[root]; ok && len(keys) == 0 {
+			return fromProcat(field), nil
+		}
+	case sheetSourceScoreezy:
+		if field, ok := scoreezyJSONFields[root]; ok {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("path %q needs a key inside %s", path, root)
+			}
+
+			return fromScoreezy(func(r *transaction.LogTransScoreezy) interface{} {
+				return nestedStr(field(r), keys)
+			}), nil
+		}
+		if field, ok := scoreezyFields[root]; ok && len(keys) == 0 {
+			return fromScoreezy(field), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown %s field %q", source, path)
+}
+
+func nestedStr(raw []byte, keys []string) interface{} {
+	v := helper.ExtractNestedField(raw, keys...)
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func transformed(extract RowExtractFn, fn func(string) string) RowExtractFn {
+	return func(row LogRow) interface{} {
+		v := extract(row)
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprintf("%v", v)
+		}
+
+		return fn(s)
+	}
+}
+
+// checkSheetName applies the sheet name rules of Excel.
+func checkSheetName(name string) error {
+	if name == "" {
+		return errors.New("sheet name is required")
+	}
+	if utf8.RuneCountInString(name) > maxSheetNameLength {
+		return fmt.Errorf("sheet name %q is longer than %d characters", name, maxSheetNameLength)
+	}
+	if strings.ContainsAny(name, `:\/?*[]`) || strings.HasPrefix(name, "'") || strings.HasSuffix(name, "'") {
+		return fmt.Errorf("sheet name %q contains characters Excel does not allow", name)
+	}
+
+	return nil
+}
+
+// checkUniqueSheetNames rejects two products that would write the same sheet
+// of one workbook.
+func checkUniqueSheetNames(defs map[string]ProductSheetDef) error {
+	seen := make(map[string]string, len(defs))
+
+	var errs []error
+	for slug, def := range defs {
+		name := def.SheetName
+		if name == "" {
+			name = def.ProductName
+		}
+
+		key := strings.ToLower(name)
+		if other, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("products %q and %q both use sheet %q", other, slug, name))
+			continue
+		}
+		seen[key] = slug
+	}
+
+	return errors.Join(errs...)
+}
+
+// PreviewSheetDefinition renders the definition against sample logs the way
+// the usage workbook would.
+func (svc *service) PreviewSheetDefinition(req *previewSheetRequest) (*sheetPreview, error) {
+	def, err := compileProductSheet(&req.Definition)
+	if err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+
+	if len(req.Logs) > maxPreviewLogs {
+		return nil, apperror.BadRequest(fmt.Sprintf("at most %d sample logs can be previewed", maxPreviewLogs))
+	}
+
+	rows, err := decodeSampleLogs(req.Definition.Source, req.Logs)
+	if err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+
+	sheetName := def.SheetName
+	if sheetName == "" {
+		sheetName = def.ProductName
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	defaultSheet := f.GetSheetName(0)
+	if _, err := f.NewSheet(sheetName); err != nil {
+		return nil, apperror.Internal(fmt.Sprintf("failed to create sheet '%s': %s", sheetName, err), err)
+	}
+	f.DeleteSheet(defaultSheet)
+
+	if err := writeProductSheet(f, sheetName, def, rows); err != nil {
+		return nil, apperror.Internal(fmt.Sprintf("failed to write sheet '%s': %s", sheetName, err), err)
+	}
+
+	cells, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, apperror.Internal("failed to read preview sheet", err)
+	}
+
+	data, err := writeXlsx(f)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &sheetPreview{SheetName: sheetName, Workbook: data}
+	if len(cells) > 0 {
+		preview.Headers = cells[0]
+		preview.Rows = cells[1:]
+	}
+
+	return preview, nil
+}
+
+func decodeSampleLogs(source string, logs []json.RawMessage) ([]LogRow, error) {
+	rows := make([]LogRow, 0, len(logs))
+
+	for i, raw := range logs {
+		switch source {
+		case sheetSourceProcat:
+			var r transaction.LogTransProductCatalog
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return nil, fmt.Errorf("logs[%d]: %w", i, err)
+			}
+			rows = append(rows, ProcatRow{&r})
+		case sheetSourceScoreezy:
+			var r transaction.LogTransScoreezy
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return nil, fmt.Errorf("logs[%d]: %w", i, err)
+			}
+			rows = append(rows, ScoreezyRow{&r})
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,170 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+const phoneLiveYAML = `
+products:
+  - slug: IDENTITY_phone_live_status
+    product_name: Phone Live Status
+    source: procat
+    columns:
+      - {header: Transaction ID, width: 32, path: transaction_id}
+      - {header: Product Name, static: Phone Live Status}
+      - {header: Phone Number, path: response_body.input.phone_number}
+      - header: Subscriber Status
+        path: data.live_status
+        transform: {name: split_index, sep: ",", index: 0}
+      - header: Device Status
+        path: data.live_status
+        transform: {name: split_index, sep: ",", index: 1}
+      - {header: Operator, path: data.operator}
+      - {header: Phone Type, path: data.phone_type}
+      - {header: Date Created, type: datetime, path: created_at}
+  - slug: kyc-check
+    product_name: KYC Check
+    source: scoreezy
+    columns:
+      - {header: Trx ID, path: trx_id}
+      - {header: Grade, path: data.result.grade, transform: {name: upper}}
+`
+
+func writeDefs(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func samplePhoneLog() *transaction.LogTransProductCatalog {
+	return &transaction.LogTransProductCatalog{
+		TransactionID: "trx-1",
+		ResponseBody:  []byte(`{"input":{"phone_number":"0812"}}`),
+		Data:          []byte(`{"live_status":"active, reachable","operator":"Telkomsel","phone_type":"prepaid"}`),
+		CreatedAt:     time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestLoadSheetDefs(t *testing.T) {
+	t.Run("built-in sheets without a file", func(t *testing.T) {
+		defs, err := loadSheetDefs("")
+		require.NoError(t, err)
+		assert.Len(t, defs, len(productRegistry))
+		assert.NoError(t, checkUniqueSheetNames(productRegistry))
+	})
+
+	t.Run("file matches the built-in sheet", func(t *testing.T) {
+		defs, err := loadSheetDefs(writeDefs(t, "sheets.yaml", phoneLiveYAML))
+		require.NoError(t, err)
+		assert.Len(t, defs, len(productRegistry)+1)
+
+		row := ProcatRow{samplePhoneLog()}
+		builtIn := productRegistry[constant.SlugPhoneLiveStatus]
+		loaded := defs[constant.SlugPhoneLiveStatus]
+		require.Len(t, loaded.Columns, len(builtIn.Columns))
+		for i := range builtIn.Columns {
+			assert.Equal(t, builtIn.Columns[i].ExtractFn(row), loaded.Columns[i].ExtractFn(row), builtIn.Columns[i].Header)
+			assert.Equal(t, builtIn.Columns[i].Type, loaded.Columns[i].Type)
+		}
+
+		scoreezy := ScoreezyRow{&transaction.LogTransScoreezy{TrxId: "s-1", Data: []byte(`{"result":{"grade":"a"}}`)}}
+		assert.Equal(t, "A", defs["kyc-check"].Columns[1].ExtractFn(scoreezy))
+	})
+
+	t.Run("json file", func(t *testing.T) {
+		defs, err := loadSheetDefs(writeDefs(t, "sheets.json", `{"products":[{"slug":"INCOMETAX_tax_score","product_name":"Tax Score v2","source":"procat","columns":[{"header":"Score","type":"number","path":"data.score"}]}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, "Tax Score v2", defs[constant.SlugTaxScore].ProductName)
+	})
+
+	invalid := []struct {
+		name    string
+		file    string
+		content string
+		msg     string
+	}{
+		{"unknown extension", "sheets.toml", "", "must be a .yaml"},
+		{"unknown key", "sheets.yaml", "products:\n  - slug: a\n    colums: []\n", "colums"},
+		{"unknown field", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, path: trx_id}]}\n", `unknown procat field "trx_id"`},
+		{"json root without key", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, path: data}]}\n", "needs a key inside data"},
+		{"unknown type", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, type: money, path: status}]}\n", `unknown type "money"`},
+		{"path and static", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, path: status, static: Y}]}\n", "exactly one of path or static"},
+		{"unknown transform", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, path: data.x, transform: {name: reverse}}]}\n", `unknown transform "reverse"`},
+		{"split without sep", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, path: data.x, transform: {name: split_index}}]}\n", "needs a sep"},
+		{"unknown source", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: partner, columns: [{header: X, static: Y}]}\n", "source must be"},
+		{"long sheet name", "sheets.yaml", "products:\n  - {slug: a, product_name: A Product Name That Is Far Too Long, source: procat, columns: [{header: X, static: Y}]}\n", "longer than 31"},
+		{"duplicate slug", "sheets.yaml", "products:\n  - {slug: a, product_name: A, source: procat, columns: [{header: X, static: Y}]}\n  - {slug: a, product_name: B, source: procat, columns: [{header: X, static: Y}]}\n", "duplicate slug"},
+		{"sheet clash with built-in", "sheets.yaml", "products:\n  - {slug: a, product_name: Tax Score, source: procat, columns: [{header: X, static: Y}]}\n", `both use sheet "Tax Score"`},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadSheetDefs(writeDefs(t, tc.file, tc.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
+
+func TestPreviewSheetDefinition(t *testing.T) {
+	svc, _, _ := setupQuotaService(t, map[string]func(*http.Request) (int, any){})
+
+	file, err := decodeSheetDefs([]byte(phoneLiveYAML), ".yaml")
+	require.NoError(t, err)
+
+	sample, err := json.Marshal(samplePhoneLog())
+	require.NoError(t, err)
+
+	preview, err := svc.PreviewSheetDefinition(&previewSheetRequest{
+		Definition: file.Products[0],
+		Logs:       []json.RawMessage{sample},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, constant.PhoneLiveStatus, preview.SheetName)
+	assert.Equal(t, "Subscriber Status", preview.Headers[3])
+	require.Len(t, preview.Rows, 1)
+	assert.Equal(t, []string{"trx-1", "Phone Live Status", "0812", "active", "reachable", "Telkomsel", "prepaid", "15/01/2026 09:00:00"}, preview.Rows[0])
+
+	f, err := excelize.OpenReader(bytes.NewReader(preview.Workbook))
+	require.NoError(t, err)
+	assert.Equal(t, []string{constant.PhoneLiveStatus}, f.GetSheetList())
+
+	t.Run("invalid definition", func(t *testing.T) {
+		_, err := svc.PreviewSheetDefinition(&previewSheetRequest{
+			Definition: productSheetSpec{Slug: "a", ProductName: "A", Source: sheetSourceProcat},
+			Logs:       []json.RawMessage{sample},
+		})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Contains(t, appErr.Message, "at least one column")
+	})
+
+	t.Run("malformed log", func(t *testing.T) {
+		_, err := svc.PreviewSheetDefinition(&previewSheetRequest{
+			Definition: file.Products[0],
+			Logs:       []json.RawMessage{json.RawMessage(`{"transaction_id":1}`)},
+		})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Contains(t, appErr.Message, "logs[0]")
+	})
+}
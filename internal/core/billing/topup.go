@@ -0,0 +1,587 @@
+package billing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"front-office/internal/core/company"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/mail"
+	"front-office/internal/middleware"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/pdf"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	topupReferenceLength = 6
+	// uppercase letters and digits that cannot be mistaken for each other
+	// when the reference is typed into a bank transfer
+	topupReferenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+func (svc *service) GetBalance(companyId uint) (*balance, error) {
+	result, err := svc.repo.GetBalanceAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return &balance{CompanyId: companyId}, nil
+		}
+
+		return nil, apperror.MapRepoError(err, "failed to fetch balance")
+	}
+	if result == nil || result.CompanyId == 0 {
+		return &balance{CompanyId: companyId}, nil
+	}
+
+	return result, nil
+}
+
+func (svc *service) GetTopups(companyId uint, status string) ([]*topup, error) {
+	topups, err := svc.repo.GetTopupsAPI(&topupFilter{
+		CompanyId: strconv.FormatUint(uint64(companyId), 10),
+		Status:    status,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch top-ups")
+	}
+
+	return topups, nil
+}
+
+// RequestTopup opens a top-up with a reference number for the bank transfer
+// and mails the proforma to the company admins.
+func (svc *service) RequestTopup(authCtx *model.AuthContext, companyId uint, req *createTopupRequest) (*topup, error) {
+	if req.Amount < minTopupAmount {
+		return nil, apperror.BadRequest(fmt.Sprintf(constant.InvalidTopupAmount, formatRupiah(minTopupAmount)))
+	}
+
+	companyData, err := svc.getCompany(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reference, err := generateTopupReference(now)
+	if err != nil {
+		return nil, apperror.Internal("failed to generate top-up reference number", err)
+	}
+
+	created, err := svc.repo.CreateTopupAPI(&topup{
+		CompanyId:       companyId,
+		ReferenceNumber: reference,
+		Amount:          req.Amount,
+		Status:          topupStatusPendingPayment,
+		Note:            req.Note,
+		RequestedBy:     authCtx.UserId,
+		ExpiresAt:       now.Add(topupPaymentWindow),
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to create top-up")
+	}
+
+	proforma, err := renderProformaPDF(created, companyData)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("reference_number", created.ReferenceNumber).
+			Msg("failed to render top-up proforma")
+	}
+
+	var attachments []mail.MailAttachment
+	if proforma != nil {
+		attachments = append(attachments, mail.MailAttachment{
+			FileName: proformaFilename(created),
+			Content:  proforma,
+			MimeType: constant.MimePdf,
+		})
+	}
+
+	svc.notifyTopup(created, companyData.CompanyName, "Top-up Requested", fmt.Sprintf(
+		"A balance top-up has been requested. Please transfer the amount before %s and include the reference number in the transfer description, the proforma is attached.",
+		created.ExpiresAt.Format(constant.FormatDateAndTime),
+	), false, attachments...)
+
+	svc.logTopup(authCtx.UserId, companyId, constant.EventTopupBalance, created, "requested")
+
+	return created, nil
+}
+
+func (svc *service) DownloadProforma(companyId uint, id string) (*invoiceFile, error) {
+	t, err := svc.getCompanyTopup(companyId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	companyData, err := svc.getCompany(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := renderProformaPDF(t, companyData)
+	if err != nil {
+		return nil, apperror.Internal("failed to render proforma", err)
+	}
+
+	return &invoiceFile{
+		Filename:    proformaFilename(t),
+		ContentType: constant.MimePdf,
+		Data:        data,
+	}, nil
+}
+
+// CheckPaymentConfirmation runs the checks of SubmitPaymentConfirmation
+// before the proof is uploaded, so a rejected confirmation stores no file.
+func (svc *service) CheckPaymentConfirmation(companyId uint, id, paidAt string) error {
+	_, _, err := svc.awaitingPayment(companyId, id, paidAt, time.Now())
+	return err
+}
+
+// SubmitPaymentConfirmation attaches the uploaded proof to an unpaid or
+// rejected top-up and hands it to the internal team for approval.
+func (svc *service) SubmitPaymentConfirmation(authCtx *model.AuthContext, companyId uint, id string, req *paymentConfirmationRequest) (*topup, error) {
+	now := time.Now()
+	t, paidAt, err := svc.awaitingPayment(companyId, id, req.PaidAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	previousProof := t.PaymentProof
+
+	t.Status = topupStatusWaitingApproval
+	t.PaymentProof = req.Filename
+	t.PaidAt = &paidAt
+	t.PaymentNote = req.Note
+	t.SubmittedBy = authCtx.UserId
+	t.SubmittedAt = &now
+	t.ReviewedBy = 0
+	t.ReviewedAt = nil
+	t.ReviewNote = ""
+
+	updated, err := svc.repo.UpdateTopupAPI(id, t)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to submit payment confirmation")
+	}
+
+	if previousProof != req.Filename {
+		discardPaymentProof(previousProof)
+	}
+
+	companyName := svc.companyName(companyId)
+	svc.notifyTopup(updated, companyName, "Payment Confirmation Submitted", fmt.Sprintf(
+		"%s has confirmed the payment of a balance top-up. Please check the payment proof and approve or reject the top-up.",
+		companyName,
+	), true)
+
+	svc.logTopup(authCtx.UserId, companyId, constant.EventSubmitPaymentConfirmation, updated, "")
+
+	return updated, nil
+}
+
+// awaitingPayment returns the top-up when it still takes a payment
+// confirmation, with the payment date of the confirmation.
+func (svc *service) awaitingPayment(companyId uint, id, paidAtStr string, now time.Time) (*topup, time.Time, error) {
+	t, err := svc.getCompanyTopup(companyId, id)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if t.Status != topupStatusPendingPayment && t.Status != topupStatusRejected {
+		return nil, time.Time{}, apperror.Conflict(constant.TopupNotAwaitingPayment)
+	}
+
+	if now.After(t.ExpiresAt) {
+		return nil, time.Time{}, apperror.Conflict(constant.TopupExpired)
+	}
+
+	paidAt := now
+	if paidAtStr != "" {
+		paidAt, err = time.Parse(constant.FormatYYYYMMDD, paidAtStr)
+		if err != nil || paidAt.After(now) {
+			return nil, time.Time{}, apperror.BadRequest(constant.InvalidPaidAtDate)
+		}
+	}
+
+	return t, paidAt, nil
+}
+
+func (svc *service) GetPaymentProof(companyId uint, id string) (string, error) {
+	t, err := svc.getCompanyTopup(companyId, id)
+	if err != nil {
+		return "", err
+	}
+
+	return paymentProofPath(t)
+}
+
+func (svc *service) GetTopupsAwaitingApproval(authCtx *model.AuthContext) ([]*topup, error) {
+	if err := svc.ensureInternalTeam(authCtx.UserId); err != nil {
+		return nil, err
+	}
+
+	topups, err := svc.repo.GetTopupsAPI(&topupFilter{Status: topupStatusWaitingApproval})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch top-ups")
+	}
+
+	return topups, nil
+}
+
+func (svc *service) GetPaymentProofForReview(authCtx *model.AuthContext, id string) (string, error) {
+	if err := svc.ensureInternalTeam(authCtx.UserId); err != nil {
+		return "", err
+	}
+
+	t, err := svc.getTopup(id)
+	if err != nil {
+		return "", err
+	}
+
+	return paymentProofPath(t)
+}
+
+// ApproveTopup credits the company balance, the core approves and credits
+// in one step so a top-up is never credited twice.
+func (svc *service) ApproveTopup(authCtx *model.AuthContext, id string, req *reviewTopupRequest) (*topupApproval, error) {
+	if err := svc.ensureInternalTeam(authCtx.UserId); err != nil {
+		return nil, err
+	}
+
+	t, err := svc.getTopup(id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != topupStatusWaitingApproval {
+		return nil, apperror.Conflict(constant.TopupNotAwaitingApproval)
+	}
+
+	approval, err := svc.repo.ApproveTopupAPI(id, &topupReview{
+		ReviewedBy: authCtx.UserId,
+		ReviewedAt: time.Now(),
+		ReviewNote: req.Note,
+	})
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return nil, apperror.Conflict(constant.TopupNotAwaitingApproval)
+		}
+
+		return nil, apperror.MapRepoError(err, "failed to approve top-up")
+	}
+	if approval == nil || approval.Topup == nil {
+		return nil, apperror.Internal("failed to approve top-up", errors.New("empty approval response"))
+	}
+
+	message := "The balance top-up has been approved and credited to the company balance."
+	if approval.Balance != nil {
+		message = fmt.Sprintf(
+			"The balance top-up has been approved and credited, the company balance is now %s.",
+			formatRupiah(approval.Balance.Amount),
+		)
+	}
+	svc.notifyTopup(approval.Topup, svc.companyName(t.CompanyId), "Top-up Approved", message, false)
+
+	svc.logTopup(authCtx.UserId, t.CompanyId, constant.EventTopupBalance, approval.Topup, "approved")
+
+	return approval, nil
+}
+
+func (svc *service) RejectTopup(authCtx *model.AuthContext, id string, req *reviewTopupRequest) (*topup, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, apperror.BadRequest(constant.TopupRejectReasonRequired)
+	}
+
+	if err := svc.ensureInternalTeam(authCtx.UserId); err != nil {
+		return nil, err
+	}
+
+	t, err := svc.getTopup(id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != topupStatusWaitingApproval {
+		return nil, apperror.Conflict(constant.TopupNotAwaitingApproval)
+	}
+
+	now := time.Now()
+	t.Status = topupStatusRejected
+	t.ReviewedBy = authCtx.UserId
+	t.ReviewedAt = &now
+	t.ReviewNote = req.Note
+
+	updated, err := svc.repo.UpdateTopupAPI(id, t)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to reject top-up")
+	}
+
+	svc.notifyTopup(updated, svc.companyName(t.CompanyId), "Top-up Rejected",
+		"The payment confirmation of the balance top-up was rejected. Please check the note below and submit the payment proof again.",
+		false,
+	)
+
+	svc.logTopup(authCtx.UserId, t.CompanyId, constant.EventTopupBalance, updated, "rejected")
+
+	return updated, nil
+}
+
+func (svc *service) getTopup(id string) (*topup, error) {
+	t, err := svc.repo.GetTopupAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.NotFound(constant.TopupNotFound)
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchTopup)
+	}
+	if t == nil || t.Id == 0 {
+		return nil, apperror.NotFound(constant.TopupNotFound)
+	}
+
+	return t, nil
+}
+
+// getCompanyTopup hides the top-ups of other companies as not found.
+func (svc *service) getCompanyTopup(companyId uint, id string) (*topup, error) {
+	t, err := svc.getTopup(id)
+	if err != nil {
+		return nil, err
+	}
+	if t.CompanyId != companyId {
+		return nil, apperror.NotFound(constant.TopupNotFound)
+	}
+
+	return t, nil
+}
+
+func (svc *service) getCompany(companyId uint) (*company.MstCompany, error) {
+	companyData, err := svc.companyRepo.GetCompanyAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch company")
+	}
+	if companyData == nil {
+		return nil, apperror.NotFound("company not found")
+	}
+
+	return companyData, nil
+}
+
+// companyName is only used in notifications, a failed lookup falls back to
+// the company id.
+func (svc *service) companyName(companyId uint) string {
+	companyData, err := svc.getCompany(companyId)
+	if err != nil {
+		return fmt.Sprintf("Company %d", companyId)
+	}
+
+	return companyData.CompanyName
+}
+
+func (svc *service) ensureInternalTeam(memberId uint) error {
+	resp, err := svc.internalRepo.GetMemberAPI()
+	if err != nil {
+		return apperror.MapRepoError(err, "failed to fetch internal team")
+	}
+
+	for _, staff := range resp.Data {
+		if staff.MemberID == memberId {
+			return nil
+		}
+	}
+
+	return apperror.Forbidden(constant.NotInternalTeam)
+}
+
+// notifyTopup mails a step of the top-up to the company admins with the
+// internal team in cc, or the other way round when the internal team has to
+// act. A failed email does not undo the step.
+func (svc *service) notifyTopup(t *topup, companyName, heading, message string, toInternal bool, attachments ...mail.MailAttachment) {
+	var admins []string
+	adminData, err := svc.repo.GetAdminsData(t.CompanyId)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", t.CompanyId).
+			Msg("failed to fetch admins for top-up notification")
+	}
+	for _, admin := range adminData {
+		admins = append(admins, admin.Email)
+	}
+
+	var internal []string
+	internalTeam, err := svc.internalRepo.GetMemberAPI()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("failed to fetch internal team for top-up notification")
+	} else {
+		internal = svc.buildCCEmails(internalTeam)
+	}
+
+	to, cc := admins, internal
+	if toInternal {
+		to, cc = internal, admins
+	}
+	if len(to) == 0 {
+		to, cc = cc, nil
+	}
+	if len(to) == 0 {
+		log.Warn().
+			Uint("company_id", t.CompanyId).
+			Str("reference_number", t.ReferenceNumber).
+			Msg("no recipients for top-up notification")
+		return
+	}
+
+	note := t.ReviewNote
+	if note == "" && t.Status == topupStatusWaitingApproval {
+		note = t.PaymentNote
+	}
+
+	subject := fmt.Sprintf("%s: %s", heading, t.ReferenceNumber)
+	if err := svc.mailSvc.SendWithTemplateToList(
+		to,
+		cc,
+		nil,
+		subject,
+		"topup_notification.html",
+		TopupTemplateData{
+			Subject:         subject,
+			CompanyName:     companyName,
+			Heading:         heading,
+			Message:         message,
+			ReferenceNumber: t.ReferenceNumber,
+			Amount:          formatRupiah(t.Amount),
+			Status:          strings.ReplaceAll(t.Status, "_", " "),
+			Note:            note,
+			Year:            time.Now().Year(),
+		},
+		attachments...,
+	); err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", t.CompanyId).
+			Str("reference_number", t.ReferenceNumber).
+			Msg("failed to send top-up notification")
+	}
+}
+
+func (svc *service) logTopup(memberId, companyId uint, action string, t *topup, step string) {
+	detail := fmt.Sprintf("reference=%s amount=%s", t.ReferenceNumber, strconv.FormatFloat(t.Amount, 'f', -1, 64))
+	if step != "" {
+		detail = step + " " + detail
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  memberId,
+		CompanyId: companyId,
+		Action:    action,
+		Detail:    detail,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", action).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+func generateTopupReference(now time.Time) (string, error) {
+	alphabetSize := big.NewInt(int64(len(topupReferenceAlphabet)))
+
+	code := make([]byte, topupReferenceLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = topupReferenceAlphabet[n.Int64()]
+	}
+
+	return fmt.Sprintf("TU-%s-%s", now.Format("20060102"), code), nil
+}
+
+// paymentProofPath returns the stored proof of the top-up, the filename
+// comes from the upload middleware and never leaves its directory.
+func paymentProofPath(t *topup) (string, error) {
+	if t.PaymentProof == "" || t.PaymentProof != filepath.Base(t.PaymentProof) {
+		return "", apperror.NotFound(constant.PaymentProofNotFound)
+	}
+
+	path := filepath.Join(middleware.PaymentProofUpload.Dir, t.PaymentProof)
+	if _, err := os.Stat(path); err != nil {
+		return "", apperror.NotFound(constant.PaymentProofNotFound)
+	}
+
+	return path, nil
+}
+
+// discardPaymentProof removes an uploaded proof that was not attached to a
+// top-up.
+func discardPaymentProof(filename string) {
+	if filename == "" || filename != filepath.Base(filename) {
+		return
+	}
+
+	if err := os.Remove(filepath.Join(middleware.PaymentProofUpload.Dir, filename)); err != nil && !os.IsNotExist(err) {
+		log.Warn().
+			Err(err).
+			Str("filename", filename).
+			Msg("failed to remove payment proof")
+	}
+}
+
+func proformaFilename(t *topup) string {
+	return fmt.Sprintf("proforma_%s.pdf", t.ReferenceNumber)
+}
+
+func renderProformaPDF(t *topup, companyData *company.MstCompany) ([]byte, error) {
+	const (
+		left  = 40.0
+		right = pdf.PageWidth - 40
+	)
+
+	doc := pdf.New()
+	doc.AddPage()
+
+	doc.Text(left, 60, pdf.HelveticaBold, 20, "PROFORMA INVOICE")
+	doc.TextRight(right, 52, pdf.HelveticaBold, 10, t.ReferenceNumber)
+	doc.TextRight(right, 66, pdf.Helvetica, 9, "Issued "+t.CreatedAt.Format("02 January 2006"))
+	doc.TextRight(right, 80, pdf.Helvetica, 9, "Pay before "+t.ExpiresAt.Format("02 January 2006 15:04"))
+
+	doc.Text(left, 100, pdf.HelveticaBold, 10, "Billed to")
+	doc.Text(left, 114, pdf.Helvetica, 10, companyData.CompanyName)
+	if companyData.CompanyAddress != "" {
+		doc.Text(left, 128, pdf.Helvetica, 9, companyData.CompanyAddress)
+	}
+
+	y := 190.0
+	doc.Text(left, y, pdf.HelveticaBold, 9, "Description")
+	doc.TextRight(right, y, pdf.HelveticaBold, 9, "Amount")
+	doc.Line(left, y+5, right, y+5)
+	y += 20
+
+	doc.Text(left, y, pdf.Helvetica, 9, "Prepaid balance top-up")
+	doc.TextRight(right, y, pdf.Courier, 9, formatRupiah(t.Amount))
+	y += 16
+
+	doc.Line(left, y-10, right, y-10)
+	y += 4
+	doc.TextRight(right-120, y, pdf.HelveticaBold, 9, "Total")
+	doc.TextRight(right, y, pdf.Courier, 9, formatRupiah(t.Amount))
+
+	y += 40
+	doc.Text(left, y, pdf.Helvetica, 9, "Include the reference number "+t.ReferenceNumber+" in the transfer description.")
+	doc.Text(left, y+14, pdf.Helvetica, 9, "The balance is credited once the payment proof has been approved.")
+
+	return doc.Bytes()
+}
@@ -0,0 +1,273 @@
+package billing
+
+import (
+	"encoding/json"
+	"front-office/internal/core/internalteam"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/middleware"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func topupRoutes(existing *topup) (map[string]func(*http.Request) (int, any), *[]operation.AddLogRequest) {
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	var logs []operation.AddLogRequest
+	routes := map[string]func(*http.Request) (int, any){
+		"GET /api/core/company/1":             ok(map[string]any{"company_id": 1, "company_name": "PT Example"}),
+		"GET /api/core/billing/admins/1":      ok([]adminEmail{{MemberId: 3, Email: "admin@example.com"}}),
+		"GET /api/core/aifuser/internal/list": ok([]internalteam.MstInternalTeam{{MemberID: 99, Email: "finance@aif.example"}}),
+		"POST /api/core/billing/topups": func(req *http.Request) (int, any) {
+			var payload topup
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			payload.Id = 7
+			payload.CreatedAt = time.Now()
+			return http.StatusOK, payload
+		},
+		"PUT /api/core/billing/topups/7": func(req *http.Request) (int, any) {
+			var payload topup
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			return http.StatusOK, payload
+		},
+		"POST /api/core/logging/operation": func(req *http.Request) (int, any) {
+			var payload operation.AddLogRequest
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			logs = append(logs, payload)
+			return http.StatusOK, nil
+		},
+	}
+	if existing != nil {
+		routes["GET /api/core/billing/topups/7"] = ok(existing)
+	}
+
+	return routes, &logs
+}
+
+func setupTopupService(t *testing.T, existing *topup) (*service, *coreStub, *stubMailSender, *[]operation.AddLogRequest) {
+	t.Helper()
+
+	routes, logs := topupRoutes(existing)
+	svc, client, sender := setupQuotaService(t, routes)
+	svc.internalRepo = internalteam.NewRepository(svc.cfg, client, nil)
+
+	return svc, client, sender, logs
+}
+
+func TestRequestTopup(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 3, CompanyId: 1, RoleId: 1}
+
+	t.Run("amount below minimum", func(t *testing.T) {
+		svc, _, _, _ := setupTopupService(t, nil)
+
+		_, err := svc.RequestTopup(authCtx, 1, &createTopupRequest{Amount: 5000})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Equal(t, "amount must be at least Rp 100.000", appErr.Message)
+	})
+
+	t.Run("creates the top-up and mails the proforma", func(t *testing.T) {
+		svc, _, sender, logs := setupTopupService(t, nil)
+
+		result, err := svc.RequestTopup(authCtx, 1, &createTopupRequest{Amount: 2500000, Note: "Q4"})
+		require.NoError(t, err)
+
+		assert.Regexp(t, regexp.MustCompile(`^TU-\d{8}-[A-Z2-9]{6}$`), result.ReferenceNumber)
+		assert.Equal(t, topupStatusPendingPayment, result.Status)
+		assert.Equal(t, uint(3), result.RequestedBy)
+		assert.WithinDuration(t, time.Now().Add(topupPaymentWindow), result.ExpiresAt, time.Minute)
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].ToList)
+		assert.Equal(t, []string{"finance@aif.example"}, sender.sent[0].CC)
+		assert.Contains(t, sender.sent[0].Body, "Rp 2.500.000")
+		require.Len(t, sender.sent[0].Attachments, 1)
+		assert.Equal(t, "proforma_"+result.ReferenceNumber+".pdf", sender.sent[0].Attachments[0].FileName)
+
+		require.Len(t, *logs, 1)
+		assert.Equal(t, constant.EventTopupBalance, (*logs)[0].Action)
+		assert.Contains(t, (*logs)[0].Detail, "requested reference="+result.ReferenceNumber)
+	})
+}
+
+func TestSubmitPaymentConfirmation(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 3, CompanyId: 1, RoleId: 1}
+	pending := func() *topup {
+		return &topup{Id: 7, CompanyId: 1, ReferenceNumber: "TU-20261019-ABCDEF", Amount: 500000, Status: topupStatusPendingPayment, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name      string
+		companyId uint
+		modify    func(*topup)
+		paidAt    string
+		status    int
+		msg       string
+	}{
+		{"other company", 2, nil, "", http.StatusNotFound, constant.TopupNotFound},
+		{"already submitted", 1, func(t *topup) { t.Status = topupStatusWaitingApproval }, "", http.StatusConflict, constant.TopupNotAwaitingPayment},
+		{"expired", 1, func(t *topup) { t.ExpiresAt = time.Now().Add(-time.Minute) }, "", http.StatusConflict, constant.TopupExpired},
+		{"paid in the future", 1, nil, time.Now().AddDate(0, 0, 2).Format(constant.FormatYYYYMMDD), http.StatusBadRequest, constant.InvalidPaidAtDate},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := pending()
+			if tc.modify != nil {
+				tc.modify(existing)
+			}
+			svc, _, _, _ := setupTopupService(t, existing)
+
+			_, err := svc.SubmitPaymentConfirmation(authCtx, tc.companyId, "7", &paymentConfirmationRequest{PaidAt: tc.paidAt, Filename: "3_proof.pdf"})
+
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.status, appErr.StatusCode)
+			assert.Equal(t, tc.msg, appErr.Message)
+
+			err = svc.CheckPaymentConfirmation(tc.companyId, "7", tc.paidAt)
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.msg, appErr.Message)
+		})
+	}
+
+	t.Run("check before the upload", func(t *testing.T) {
+		svc, _, _, _ := setupTopupService(t, pending())
+
+		assert.NoError(t, svc.CheckPaymentConfirmation(1, "7", "2026-01-05"))
+	})
+
+	t.Run("hands a rejected top-up back for approval", func(t *testing.T) {
+		existing := pending()
+		existing.Status = topupStatusRejected
+		existing.ReviewNote = "amount differs"
+		svc, _, sender, logs := setupTopupService(t, existing)
+
+		result, err := svc.SubmitPaymentConfirmation(authCtx, 1, "7", &paymentConfirmationRequest{PaidAt: "2026-01-05", Note: "BCA transfer", Filename: "3_proof.pdf"})
+		require.NoError(t, err)
+
+		assert.Equal(t, topupStatusWaitingApproval, result.Status)
+		assert.Equal(t, "3_proof.pdf", result.PaymentProof)
+		assert.Equal(t, "2026-01-05", result.PaidAt.Format(constant.FormatYYYYMMDD))
+		assert.Empty(t, result.ReviewNote)
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, []string{"finance@aif.example"}, sender.sent[0].ToList)
+		assert.Contains(t, sender.sent[0].Body, "BCA transfer")
+
+		require.Len(t, *logs, 1)
+		assert.Equal(t, constant.EventSubmitPaymentConfirmation, (*logs)[0].Action)
+	})
+
+	t.Run("removes the rejected proof", func(t *testing.T) {
+		existing := pending()
+		existing.Status = topupStatusRejected
+		existing.PaymentProof = "3_rejected.pdf"
+		svc, _, _, _ := setupTopupService(t, existing)
+
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.MkdirAll(middleware.PaymentProofUpload.Dir, 0o750))
+		rejectedProof := filepath.Join(middleware.PaymentProofUpload.Dir, existing.PaymentProof)
+		require.NoError(t, os.WriteFile(rejectedProof, []byte("%PDF"), 0o600))
+
+		_, err := svc.SubmitPaymentConfirmation(authCtx, 1, "7", &paymentConfirmationRequest{Filename: "3_proof.pdf"})
+		require.NoError(t, err)
+
+		assert.NoFileExists(t, rejectedProof)
+	})
+}
+
+func TestReviewTopup(t *testing.T) {
+	waiting := &topup{Id: 7, CompanyId: 1, ReferenceNumber: "TU-20261019-ABCDEF", Amount: 500000, Status: topupStatusWaitingApproval}
+	staff := &model.AuthContext{UserId: 99, CompanyId: 5}
+
+	t.Run("only the internal team", func(t *testing.T) {
+		svc, _, _, _ := setupTopupService(t, waiting)
+
+		_, err := svc.ApproveTopup(&model.AuthContext{UserId: 3, CompanyId: 1}, "7", &reviewTopupRequest{})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("approve credits the balance", func(t *testing.T) {
+		svc, client, sender, logs := setupTopupService(t, waiting)
+		client.routes["POST /api/core/billing/topups/7/approve"] = func(req *http.Request) (int, any) {
+			var payload topupReview
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			assert.Equal(t, uint(99), payload.ReviewedBy)
+
+			approved := *waiting
+			approved.Status = topupStatusApproved
+			return http.StatusOK, topupApproval{Topup: &approved, Balance: &balance{CompanyId: 1, Amount: 1500000}}
+		}
+
+		result, err := svc.ApproveTopup(staff, "7", &reviewTopupRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, topupStatusApproved, result.Topup.Status)
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, []string{"admin@example.com"}, sender.sent[0].ToList)
+		assert.Contains(t, sender.sent[0].Body, "Rp 1.500.000")
+
+		require.Len(t, *logs, 1)
+		assert.Equal(t, uint(1), (*logs)[0].CompanyId)
+		assert.Contains(t, (*logs)[0].Detail, "approved")
+	})
+
+	t.Run("approve races with another reviewer", func(t *testing.T) {
+		svc, client, _, _ := setupTopupService(t, waiting)
+		client.routes["POST /api/core/billing/topups/7/approve"] = func(*http.Request) (int, any) {
+			return http.StatusConflict, nil
+		}
+
+		_, err := svc.ApproveTopup(staff, "7", &reviewTopupRequest{})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	})
+
+	t.Run("reject needs a note", func(t *testing.T) {
+		svc, _, _, _ := setupTopupService(t, waiting)
+
+		_, err := svc.RejectTopup(staff, "7", &reviewTopupRequest{Note: " "})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, constant.TopupRejectReasonRequired, appErr.Message)
+
+		result, err := svc.RejectTopup(staff, "7", &reviewTopupRequest{Note: "amount differs"})
+		require.NoError(t, err)
+		assert.Equal(t, topupStatusRejected, result.Status)
+		assert.Equal(t, uint(99), result.ReviewedBy)
+	})
+}
+
+func TestGetBalanceDefaultsToZero(t *testing.T) {
+	svc, _, _, _ := setupTopupService(t, nil)
+
+	result, err := svc.GetBalance(1)
+	require.NoError(t, err)
+	assert.Equal(t, &balance{CompanyId: 1}, result)
+}
+
+func TestPaymentProofPath(t *testing.T) {
+	for _, name := range []string{"", "../secret.pdf", "nested/3_proof.pdf", "3_missing.pdf"} {
+		_, err := paymentProofPath(&topup{PaymentProof: name})
+		assert.Error(t, err, name)
+	}
+}
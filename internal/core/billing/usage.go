@@ -0,0 +1,259 @@
+package billing
+
+import (
+	"fmt"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const usageSummarySheet = "Summary"
+
+var usageGroupHeaders = map[string]string{
+	usageGroupByDay:     "Date",
+	usageGroupByWeek:    "Week",
+	usageGroupByMonth:   "Month",
+	usageGroupByMember:  "Member",
+	usageGroupByProduct: "Product",
+}
+
+func (q *usageQuery) validate() error {
+	if q.GroupBy == "" {
+		q.GroupBy = usageGroupByProduct
+	}
+	if _, ok := usageGroupHeaders[q.GroupBy]; !ok {
+		return apperror.BadRequest(constant.InvalidUsageGroupBy)
+	}
+
+	if q.EndDate.Before(q.StartDate) {
+		return apperror.BadRequest(constant.InvalidUsageRange)
+	}
+	if days := int(q.EndDate.Sub(q.StartDate).Hours()/24) + 1; days > maxUsageRangeDays {
+		return apperror.BadRequest(fmt.Sprintf(constant.UsageRangeTooLong, maxUsageRangeDays))
+	}
+
+	return nil
+}
+
+// isCalendarMonth reports whether the query covers exactly one month.
+func (q *usageQuery) isCalendarMonth() bool {
+	return q.StartDate.Day() == 1 &&
+		q.EndDate.Year() == q.StartDate.Year() &&
+		q.EndDate.Month() == q.StartDate.Month() &&
+		q.EndDate.Day() == lastDayOfMonth(q.StartDate)
+}
+
+func (svc *service) GetUsageBreakdown(query *usageQuery) (*usageBreakdown, error) {
+	breakdown, _, err := svc.getUsageBreakdown(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// getUsageBreakdown counts the usage of the query with the dedup settings of
+// the company's policy. Free hits are a monthly allowance and only deducted
+// from the monthly summary.
+func (svc *service) getUsageBreakdown(query *usageQuery) (*usageBreakdown, *billingPolicy, error) {
+	if err := query.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	policy, err := svc.getBillingPolicy(query.CompanyId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	breakdown, err := svc.repo.GetUsageBreakdownAPI(strconv.FormatUint(uint64(query.CompanyId), 10), query, policy)
+	if err != nil {
+		return nil, nil, apperror.MapRepoError(err, constant.FailedFetchUsageBreakdown)
+	}
+	if breakdown == nil {
+		breakdown = &usageBreakdown{CompanyId: query.CompanyId}
+	}
+
+	breakdown.StartDate = query.StartDate.Format(constant.FormatYYYYMMDD)
+	breakdown.EndDate = query.EndDate.Format(constant.FormatYYYYMMDD)
+	breakdown.GroupBy = query.GroupBy
+	breakdown.ProductSlugs = query.ProductSlugs
+	breakdown.MemberIds = query.MemberIds
+	breakdown.Total = sumBuckets(breakdown.Buckets)
+
+	return breakdown, policy, nil
+}
+
+// sumBuckets fills the total of every bucket and returns the grand total.
+func sumBuckets(buckets []usageBucket) usageBucket {
+	total := usageBucket{Key: "total", Label: "Total"}
+	for i := range buckets {
+		buckets[i].TotalRequest = buckets[i].PayRequest + buckets[i].FreeRequest
+
+		total.PayRequest += buckets[i].PayRequest
+		total.FreeRequest += buckets[i].FreeRequest
+		total.TotalRequest += buckets[i].TotalRequest
+	}
+
+	return total
+}
+
+// buildUsageGroups lists the products of the breakdown with fetchers that
+// honour the range and member filters of the query.
+func (svc *service) buildUsageGroups(
+	query *usageQuery,
+	pricingStrategy string,
+	breakdown *usageBreakdown,
+	policy *billingPolicy,
+) []ProductGroup {
+	startDate := query.StartDate.Format(constant.FormatYYYYMMDD)
+	endDate := query.EndDate.Format(constant.FormatYYYYMMDD)
+
+	return []ProductGroup{
+		{
+			GroupName: "Procat",
+			Key:       groupKeyProcat,
+			Products:  toXlsxProducts(breakdown.ProcatProducts),
+			FetchFn:   svc.NewProcatRangeFetchFn(pricingStrategy, policy, startDate, endDate, query.MemberIds),
+		},
+		{
+			GroupName: "Scoreezy",
+			Key:       groupKeyScorezy,
+			Products:  toXlsxProducts(breakdown.ScoreezyProducts),
+			FetchFn:   svc.NewScoreezyFetchFn(startDate, endDate, query.MemberIds),
+		},
+	}
+}
+
+func (svc *service) NewProcatRangeFetchFn(pricingStrategy string, policy *billingPolicy, startDate, endDate string, memberIds []uint) FetchFn {
+	return func(productId, companyId, productSlug string) ([]LogRow, error) {
+		applyDedup := policy.dedupApplies(productSlug)
+		dedupWindowDays := ""
+		if applyDedup {
+			dedupWindowDays = strconv.Itoa(policy.DedupWindowDays)
+		}
+
+		rows, err := svc.transactionRepo.GetLogTransByCompanyRangeAPI(&transaction.CompanyLogFilter{
+			CompanyId:       companyId,
+			ProductId:       productId,
+			ProductSlug:     productSlug,
+			PricingStrategy: pricingStrategy,
+			ApplyDedup:      strconv.FormatBool(applyDedup),
+			DedupWindowDays: dedupWindowDays,
+			StartDate:       startDate,
+			EndDate:         endDate,
+			MemberIds:       idStrings(memberIds),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return WrapProcat(rows), nil
+	}
+}
+
+// filterScoreezyMembers keeps the rows of the given members, the scoreezy
+// log endpoint cannot filter by member itself.
+func filterScoreezyMembers(rows []*transaction.LogTransScoreezy, memberIds []uint) []*transaction.LogTransScoreezy {
+	if len(memberIds) == 0 {
+		return rows
+	}
+
+	var out []*transaction.LogTransScoreezy
+	for _, row := range rows {
+		if slices.Contains(memberIds, row.MemberId) {
+			out = append(out, row)
+		}
+	}
+
+	return out
+}
+
+// usageBucketRow lets the summary sheet reuse the product sheet writer.
+type usageBucketRow struct {
+	usageBucket
+}
+
+func (r usageBucketRow) logRow() {}
+
+func fromBucket(fn func(usageBucket) interface{}) RowExtractFn {
+	return func(row LogRow) interface{} {
+		if r, ok := row.(usageBucketRow); ok {
+			return fn(r.usageBucket)
+		}
+
+		return nil
+	}
+}
+
+func usageSummarySheetDef(groupBy string) ProductSheetDef {
+	return ProductSheetDef{
+		SheetName: usageSummarySheet,
+		Columns: []ColumnDef{
+			{Header: usageGroupHeaders[groupBy], Type: ColTypeText, Width: 30, ExtractFn: fromBucket(func(b usageBucket) interface{} {
+				if b.Label != "" {
+					return b.Label
+				}
+				return b.Key
+			})},
+			{Header: constant.PaidStatus, Type: ColTypeNumber, ExtractFn: fromBucket(func(b usageBucket) interface{} { return b.PayRequest })},
+			{Header: constant.FreeStatus, Type: ColTypeNumber, ExtractFn: fromBucket(func(b usageBucket) interface{} { return b.FreeRequest })},
+			{Header: "Total", Type: ColTypeNumber, ExtractFn: fromBucket(func(b usageBucket) interface{} { return b.TotalRequest })},
+		},
+	}
+}
+
+// writeUsageSummarySheet lists every bucket of the breakdown followed by the
+// grand total.
+func writeUsageSummarySheet(f *excelize.File, breakdown *usageBreakdown) error {
+	idx, err := f.NewSheet(usageSummarySheet)
+	if err != nil {
+		return apperror.Internal(fmt.Sprintf("failed to create sheet '%s': %s", usageSummarySheet, err), err)
+	}
+	f.SetActiveSheet(idx)
+
+	rows := make([]LogRow, 0, len(breakdown.Buckets)+1)
+	for _, bucket := range breakdown.Buckets {
+		rows = append(rows, usageBucketRow{bucket})
+	}
+	rows = append(rows, usageBucketRow{breakdown.Total})
+
+	if err := writeProductSheet(f, usageSummarySheet, usageSummarySheetDef(breakdown.GroupBy), rows); err != nil {
+		return apperror.Internal(fmt.Sprintf("failed to write sheet '%s': %s", usageSummarySheet, err), err)
+	}
+
+	return nil
+}
+
+func usageFilename(companyName string, query *usageQuery) string {
+	if query.isCalendarMonth() {
+		return fmt.Sprintf(
+			"usage_report_%s_%02d_%d.xlsx",
+			companyName, int(query.StartDate.Month()), query.StartDate.Year(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"usage_report_%s_%s_%s.xlsx",
+		companyName,
+		query.StartDate.Format(constant.FormatYYYYMMDD),
+		query.EndDate.Format(constant.FormatYYYYMMDD),
+	)
+}
+
+func idStrings(ids []uint) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, strconv.FormatUint(uint64(id), 10))
+	}
+
+	return out
+}
+
+func joinIds(ids []uint) string {
+	return strings.Join(idStrings(ids), ",")
+}
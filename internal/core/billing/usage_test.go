@@ -0,0 +1,173 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(constant.FormatYYYYMMDD, s)
+	return t
+}
+
+func breakdownRoute(breakdown usageBreakdown) func(*http.Request) (int, any) {
+	return func(*http.Request) (int, any) { return http.StatusOK, breakdown }
+}
+
+func TestUsageQueryValidate(t *testing.T) {
+	query := &usageQuery{StartDate: date("2026-01-01"), EndDate: date("2026-01-31")}
+	require.NoError(t, query.validate())
+	assert.Equal(t, usageGroupByProduct, query.GroupBy)
+
+	tests := []struct {
+		name  string
+		query usageQuery
+		msg   string
+	}{
+		{"unknown group", usageQuery{StartDate: date("2026-01-01"), EndDate: date("2026-01-31"), GroupBy: "year"}, constant.InvalidUsageGroupBy},
+		{"end before start", usageQuery{StartDate: date("2026-02-01"), EndDate: date("2026-01-31")}, constant.InvalidUsageRange},
+		{"range too long", usageQuery{StartDate: date("2025-01-01"), EndDate: date("2026-01-02")}, "date range must not exceed 366 days"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var appErr *apperror.AppError
+			require.ErrorAs(t, tc.query.validate(), &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Equal(t, tc.msg, appErr.Message)
+		})
+	}
+
+	leapYear := &usageQuery{StartDate: date("2024-01-01"), EndDate: date("2024-12-31"), GroupBy: usageGroupByWeek}
+	assert.NoError(t, leapYear.validate())
+}
+
+func TestGetUsageBreakdown(t *testing.T) {
+	routes := quotaRoutes(nil)
+	routes["GET /api/core/billing/policies/1"] = func(*http.Request) (int, any) {
+		return http.StatusOK, billingPolicy{CompanyId: 1, Dedup: true, DedupWindowDays: 7}
+	}
+	routes["GET /api/core/billing/usage/breakdown"] = breakdownRoute(usageBreakdown{
+		CompanyId: 1,
+		Buckets: []usageBucket{
+			{Key: "3", Label: "Alice", PayRequest: 4, FreeRequest: 1},
+			{Key: "4", Label: "Bob", PayRequest: 2},
+		},
+	})
+
+	svc, client, _ := setupQuotaService(t, routes)
+
+	result, err := svc.GetUsageBreakdown(&usageQuery{
+		CompanyId:    1,
+		StartDate:    date("2026-01-15"),
+		EndDate:      date("2026-02-14"),
+		ProductSlugs: []string{"loan-record-checker", "tax-score"},
+		MemberIds:    []uint{3, 4},
+		GroupBy:      usageGroupByMember,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, result.Buckets[0].TotalRequest)
+	assert.Equal(t, usageBucket{Key: "total", Label: "Total", PayRequest: 6, FreeRequest: 1, TotalRequest: 7}, result.Total)
+	assert.Equal(t, "2026-01-15", result.StartDate)
+	assert.Equal(t, "2026-02-14", result.EndDate)
+
+	q := client.requests[len(client.requests)-1].URL.Query()
+	assert.Equal(t, "2026-01-15", q.Get(constant.StartDate))
+	assert.Equal(t, "2026-02-14", q.Get(constant.EndDate))
+	assert.Equal(t, usageGroupByMember, q.Get("group_by"))
+	assert.Equal(t, "loan-record-checker,tax-score", q.Get("product_slugs"))
+	assert.Equal(t, "3,4", q.Get("member_ids"))
+	assert.Equal(t, "true", q.Get("apply_dedup"))
+	assert.Equal(t, "7", q.Get("dedup_window_days"))
+}
+
+func TestExportUsageXlsxForRange(t *testing.T) {
+	routes := quotaRoutes(nil)
+	routes["GET /api/core/billing/usage/breakdown"] = breakdownRoute(usageBreakdown{
+		CompanyId:   1,
+		CompanyName: "PT Example",
+		Buckets: []usageBucket{
+			{Key: "2026-01-15", PayRequest: 3, FreeRequest: 1},
+			{Key: "2026-01-16", PayRequest: 2},
+		},
+		ProcatProducts: []usagePerProduct{
+			{ProductId: 10, ProductSlug: constant.SlugLoanRecordChecker, ProductName: "Loan Record Checker", TotalRequest: 6, TotalPay: 5},
+		},
+	})
+	routes["GET /api/core/logging/transaction/product-catalog/by-company/range"] = func(*http.Request) (int, any) {
+		return http.StatusOK, []*transaction.LogTransProductCatalog{{MemberID: 3}, {MemberID: 3}}
+	}
+	var password string
+	routes["POST /api/core/billing/workbook-passwords"] = func(req *http.Request) (int, any) {
+		var payload workbookPassword
+		_ = json.NewDecoder(req.Body).Decode(&payload)
+		password = payload.Password
+		payload.Id = "wp-9"
+		return http.StatusOK, payload
+	}
+	routes["POST /api/core/logging/operation"] = func(*http.Request) (int, any) { return http.StatusOK, nil }
+
+	svc, client, _ := setupQuotaService(t, routes)
+	svc.transactionRepo = transaction.NewRepository(svc.cfg, client, nil)
+
+	result, err := svc.ExportUsageXlsx(downloadUsageXlsxInput{
+		Query: usageQuery{
+			CompanyId: 1,
+			StartDate: date("2026-01-15"),
+			EndDate:   date("2026-01-16"),
+			MemberIds: []uint{3},
+			GroupBy:   usageGroupByDay,
+		},
+		Groups:      []string{groupKeyProcat},
+		RequestedBy: 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "usage_report_PT Example_2026-01-15_2026-01-16.xlsx", result.Filename)
+	assert.Equal(t, "wp-9", result.PasswordId)
+
+	for _, req := range client.requests {
+		if req.URL.Path == "/api/core/logging/transaction/product-catalog/by-company/range" {
+			q := req.URL.Query()
+			assert.Equal(t, "2026-01-15", q.Get(constant.StartDate))
+			assert.Equal(t, "2026-01-16", q.Get(constant.EndDate))
+			assert.Equal(t, "3", q.Get("member_ids"))
+			assert.Equal(t, constant.PaidStatus, q.Get("pricing_strategy"))
+		}
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(result.Data), excelize.Options{Password: password})
+	require.NoError(t, err)
+	assert.Equal(t, []string{usageSummarySheet, constant.LoanRecordChecker}, f.GetSheetList())
+
+	rows, err := f.GetRows(usageSummarySheet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Date", constant.PaidStatus, constant.FreeStatus, "Total"}, rows[0])
+	assert.Equal(t, []string{"2026-01-15", "3", "1", "4"}, rows[1])
+	assert.Equal(t, []string{"Total", "5", "1", "6"}, rows[3])
+}
+
+func TestUsageFilename(t *testing.T) {
+	month := &usageQuery{StartDate: date("2026-02-01"), EndDate: date("2026-02-28")}
+	assert.Equal(t, "usage_report_PT Example_02_2026.xlsx", usageFilename("PT Example", month))
+
+	partial := &usageQuery{StartDate: date("2026-02-01"), EndDate: date("2026-02-27")}
+	assert.Equal(t, "usage_report_PT Example_2026-02-01_2026-02-27.xlsx", usageFilename("PT Example", partial))
+}
+
+func TestFilterScoreezyMembers(t *testing.T) {
+	rows := []*transaction.LogTransScoreezy{{MemberId: 3}, {MemberId: 4}, {MemberId: 3}}
+
+	assert.Len(t, filterScoreezyMembers(rows, nil), 3)
+	assert.Len(t, filterScoreezyMembers(rows, []uint{3}), 2)
+	assert.Empty(t, filterScoreezyMembers(rows, []uint{9}))
+}
@@ -0,0 +1,279 @@
+package billing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/mail"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	workbookPasswordLength = 16
+	// letters and digits that cannot be mistaken for each other when the
+	// password is typed from an email
+	workbookPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+	workbookPasswordTTL      = 7 * 24 * time.Hour
+)
+
+// workbookPasswordPolicy is recorded with every issued password.
+var workbookPasswordPolicy = fmt.Sprintf(
+	"length=%d charset=alphanumeric one_time=true expires_in=%s",
+	workbookPasswordLength, workbookPasswordTTL,
+)
+
+func (svc *service) RetrieveWorkbookPassword(authCtx *model.AuthContext, id string) (*workbookPasswordResponse, error) {
+	meta, err := svc.repo.GetWorkbookPasswordAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.NotFound(constant.WorkbookPasswordNotFound)
+		}
+
+		return nil, apperror.MapRepoError(err, "failed to fetch workbook password")
+	}
+
+	// passwords are only handed to the member that generated the workbook
+	if meta == nil || meta.Channel != workbookPasswordChannelInApp ||
+		meta.CompanyId != authCtx.CompanyId || meta.MemberId != authCtx.UserId {
+		return nil, apperror.NotFound(constant.WorkbookPasswordNotFound)
+	}
+	if meta.RetrievedAt != nil {
+		return nil, apperror.Conflict(constant.WorkbookPasswordRetrieved)
+	}
+	if time.Now().After(meta.ExpiresAt) {
+		return nil, apperror.Conflict(constant.WorkbookPasswordExpired)
+	}
+
+	claimed, err := svc.repo.ClaimWorkbookPasswordAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return nil, apperror.Conflict(constant.WorkbookPasswordRetrieved)
+		}
+
+		return nil, apperror.MapRepoError(err, "failed to retrieve workbook password")
+	}
+
+	svc.logWorkbookPassword(authCtx.UserId, authCtx.CompanyId, constant.EventRetrieveWorkbookPassword, meta.Filename, "")
+
+	return &workbookPasswordResponse{
+		Filename:  meta.Filename,
+		Password:  claimed.Password,
+		ExpiresAt: meta.ExpiresAt,
+	}, nil
+}
+
+// protectForMember encrypts the workbook with a new one-time password and
+// stores it for in-app retrieval by the member, the returned id is what the
+// member exchanges for the password.
+func (svc *service) protectForMember(memberId, companyId uint, filename string, data []byte) ([]byte, string, error) {
+	password, err := generateWorkbookPassword()
+	if err != nil {
+		return nil, "", apperror.Internal("failed to generate workbook password", err)
+	}
+
+	encrypted, err := encryptXlsx(data, password)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stored, err := svc.repo.CreateWorkbookPasswordAPI(&workbookPassword{
+		CompanyId: companyId,
+		MemberId:  memberId,
+		Filename:  filename,
+		Channel:   workbookPasswordChannelInApp,
+		Password:  password,
+		ExpiresAt: time.Now().Add(workbookPasswordTTL),
+	})
+	if err != nil {
+		return nil, "", apperror.MapRepoError(err, "failed to store workbook password")
+	}
+
+	svc.logWorkbookPassword(memberId, companyId, constant.EventIssueWorkbookPassword, filename, workbookPasswordChannelInApp)
+
+	return encrypted, stored.Id, nil
+}
+
+// sendProtectedWorkbook mails every recipient its own copy of the workbook,
+// encrypted with a one-time password that follows in a second email.
+func (svc *service) sendProtectedWorkbook(
+	recipients reportRecipients,
+	admins []adminEmail,
+	companyId uint,
+	companyName string,
+	subject string,
+	templateData MonthlyUsageTemplateData,
+	workbook mail.MailAttachment,
+	attachments []mail.MailAttachment,
+) {
+	templateData.PasswordSentSeparately = true
+	expiresAt := time.Now().Add(workbookPasswordTTL)
+
+	for _, recipient := range recipients.all() {
+		password, err := generateWorkbookPassword()
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", companyId).
+				Msg("failed to generate workbook password")
+			return
+		}
+
+		encrypted, err := encryptXlsx(workbook.Content, password)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", companyId).
+				Msg("failed to encrypt workbook")
+			return
+		}
+
+		protected := workbook
+		protected.Content = encrypted
+
+		if err := svc.mailSvc.SendWithTemplateToList(
+			[]string{recipient},
+			nil,
+			nil,
+			subject,
+			"monthly_usage_report.html",
+			templateData,
+			append([]mail.MailAttachment{protected}, attachments...)...,
+		); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", companyId).
+				Str("recipient", recipient).
+				Msg("failed to send monthly usage report")
+			continue
+		}
+
+		passwordSubject := fmt.Sprintf("Password for %s", workbook.FileName)
+		if err := svc.mailSvc.SendWithTemplateToList(
+			[]string{recipient},
+			nil,
+			nil,
+			passwordSubject,
+			"workbook_password.html",
+			WorkbookPasswordTemplateData{
+				Subject:   passwordSubject,
+				Name:      companyName,
+				Filename:  workbook.FileName,
+				Password:  password,
+				ExpiresAt: expiresAt.Format(constant.FormatDateAndTime),
+				Year:      time.Now().Year(),
+			},
+		); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("company_id", companyId).
+				Str("recipient", recipient).
+				Msg("failed to send workbook password")
+			continue
+		}
+
+		svc.logWorkbookPassword(
+			memberIdOf(admins, recipient),
+			companyId,
+			constant.EventSendWorkbookPassword,
+			workbook.FileName,
+			workbookPasswordChannelEmail+" recipient="+recipient,
+		)
+	}
+}
+
+func (svc *service) logWorkbookPassword(memberId, companyId uint, action, filename, channel string) {
+	detail := fmt.Sprintf("file=%s %s", filename, workbookPasswordPolicy)
+	if channel != "" {
+		detail += " channel=" + channel
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  memberId,
+		CompanyId: companyId,
+		Action:    action,
+		Detail:    detail,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", action).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+// all returns every recipient once, whichever field they are in.
+func (r reportRecipients) all() []string {
+	var all []string
+	for _, list := range [][]string{r.To, r.CC, r.BCC} {
+		all = append(all, list...)
+	}
+
+	return all
+}
+
+// memberIdOf attributes a delivery to the admin that received it, other
+// recipients are not members and are logged under the company only.
+func memberIdOf(admins []adminEmail, email string) uint {
+	for _, admin := range admins {
+		if strings.EqualFold(admin.Email, email) {
+			return admin.MemberId
+		}
+	}
+
+	return 0
+}
+
+func generateWorkbookPassword() (string, error) {
+	alphabetSize := big.NewInt(int64(len(workbookPasswordAlphabet)))
+
+	for {
+		password := make([]byte, workbookPasswordLength)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return "", err
+			}
+			password[i] = workbookPasswordAlphabet[n.Int64()]
+		}
+
+		if hasEveryCharClass(string(password)) {
+			return string(password), nil
+		}
+	}
+}
+
+func hasEveryCharClass(password string) bool {
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+
+	return upper && lower && digit
+}
+
+func encryptXlsx(data []byte, password string) ([]byte, error) {
+	encrypted, err := excelize.Encrypt(data, &excelize.Options{Password: password})
+	if err != nil {
+		return nil, apperror.Internal("failed to write encypted xlxs in buffer", err)
+	}
+
+	return encrypted, nil
+}
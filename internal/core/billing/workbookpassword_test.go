@@ -0,0 +1,176 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/mail"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var passwordPattern = regexp.MustCompile(`>\s*([` + workbookPasswordAlphabet + `]{16})\s*<`)
+
+func TestGenerateWorkbookPassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		password, err := generateWorkbookPassword()
+		require.NoError(t, err)
+
+		assert.Len(t, password, workbookPasswordLength)
+		assert.True(t, hasEveryCharClass(password))
+		for _, r := range password {
+			assert.True(t, strings.ContainsRune(workbookPasswordAlphabet, r))
+		}
+
+		assert.False(t, seen[password])
+		seen[password] = true
+	}
+}
+
+func TestRetrieveWorkbookPassword(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 5, CompanyId: 1}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	meta := func(p workbookPassword) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, p }
+	}
+	owned := workbookPassword{Id: "wp-1", CompanyId: 1, MemberId: 5, Filename: "usage.xlsx", Channel: workbookPasswordChannelInApp, ExpiresAt: future}
+
+	t.Run("claims once for the owner", func(t *testing.T) {
+		routes := quotaRoutes(nil)
+		routes["GET /api/core/billing/workbook-passwords/wp-1"] = meta(owned)
+		routes["POST /api/core/billing/workbook-passwords/wp-1/claim"] = func(*http.Request) (int, any) {
+			claimed := owned
+			claimed.Password = "Secret23abcdEFGH"
+			return http.StatusOK, claimed
+		}
+		routes["POST /api/core/logging/operation"] = func(*http.Request) (int, any) { return http.StatusOK, nil }
+
+		svc, client, _ := setupQuotaService(t, routes)
+
+		result, err := svc.RetrieveWorkbookPassword(authCtx, "wp-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Secret23abcdEFGH", result.Password)
+		assert.Equal(t, "usage.xlsx", result.Filename)
+
+		var logged operation.AddLogRequest
+		last := client.requests[len(client.requests)-1]
+		require.NoError(t, json.NewDecoder(last.Body).Decode(&logged))
+		assert.Equal(t, constant.EventRetrieveWorkbookPassword, logged.Action)
+		assert.Equal(t, uint(5), logged.MemberId)
+		assert.Contains(t, logged.Detail, "one_time=true")
+	})
+
+	rejected := []struct {
+		name   string
+		meta   workbookPassword
+		status int
+	}{
+		{"other member", workbookPassword{CompanyId: 1, MemberId: 6, Channel: workbookPasswordChannelInApp, ExpiresAt: future}, http.StatusNotFound},
+		{"other company", workbookPassword{CompanyId: 2, MemberId: 5, Channel: workbookPasswordChannelInApp, ExpiresAt: future}, http.StatusNotFound},
+		{"emailed password", workbookPassword{CompanyId: 1, MemberId: 5, Channel: workbookPasswordChannelEmail, ExpiresAt: future}, http.StatusNotFound},
+		{"already retrieved", workbookPassword{CompanyId: 1, MemberId: 5, Channel: workbookPasswordChannelInApp, ExpiresAt: future, RetrievedAt: &past}, http.StatusConflict},
+		{"expired", workbookPassword{CompanyId: 1, MemberId: 5, Channel: workbookPasswordChannelInApp, ExpiresAt: past}, http.StatusConflict},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			routes := quotaRoutes(nil)
+			routes["GET /api/core/billing/workbook-passwords/wp-1"] = meta(tc.meta)
+
+			svc, client, _ := setupQuotaService(t, routes)
+
+			_, err := svc.RetrieveWorkbookPassword(authCtx, "wp-1")
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.status, appErr.StatusCode)
+
+			for _, req := range client.requests {
+				assert.NotEqual(t, http.MethodPost, req.Method, "password must not be claimed")
+			}
+		})
+	}
+}
+
+func TestSendProtectedWorkbook(t *testing.T) {
+	routes := quotaRoutes(nil)
+	routes["POST /api/core/logging/operation"] = func(*http.Request) (int, any) { return http.StatusOK, nil }
+	svc, client, sender := setupQuotaService(t, routes)
+
+	f := excelize.NewFile()
+	require.NoError(t, f.SetCellValue("Sheet1", "A1", "usage"))
+	plain, err := writeXlsx(f)
+	require.NoError(t, err)
+
+	invoicePDF := mail.MailAttachment{FileName: "invoice.pdf", Content: []byte("%PDF"), MimeType: constant.MimePdf}
+
+	svc.sendProtectedWorkbook(
+		reportRecipients{To: []string{"admin@example.com"}, CC: []string{"finance@example.com"}},
+		[]adminEmail{{MemberId: 9, Email: "admin@example.com"}},
+		1,
+		"PT Example",
+		"Monthly Usage Report",
+		MonthlyUsageTemplateData{Subject: "Monthly Usage Report"},
+		mail.MailAttachment{FileName: "usage.xlsx", Content: plain, MimeType: constant.MimeXlsx},
+		[]mail.MailAttachment{invoicePDF},
+	)
+
+	require.Len(t, sender.sent, 4)
+
+	var passwords []string
+	for i, recipient := range []string{"admin@example.com", "finance@example.com"} {
+		workbookMail, passwordMail := sender.sent[2*i], sender.sent[2*i+1]
+
+		assert.Equal(t, []string{recipient}, workbookMail.ToList)
+		assert.Empty(t, workbookMail.CC)
+		require.Len(t, workbookMail.Attachments, 2)
+		assert.Equal(t, "invoice.pdf", workbookMail.Attachments[1].FileName)
+		assert.Contains(t, workbookMail.Body, "separate email")
+
+		assert.Equal(t, []string{recipient}, passwordMail.ToList)
+		assert.Empty(t, passwordMail.Attachments)
+
+		match := passwordPattern.FindStringSubmatch(passwordMail.Body)
+		require.Len(t, match, 2)
+		password := match[1]
+		passwords = append(passwords, password)
+
+		opened, err := excelize.OpenReader(bytes.NewReader(workbookMail.Attachments[0].Content), excelize.Options{Password: password})
+		require.NoError(t, err)
+		value, err := opened.GetCellValue("Sheet1", "A1")
+		require.NoError(t, err)
+		assert.Equal(t, "usage", value)
+	}
+	assert.NotEqual(t, passwords[0], passwords[1])
+
+	var logged []operation.AddLogRequest
+	for _, req := range client.requests {
+		if req.Method != http.MethodPost {
+			continue
+		}
+		var entry operation.AddLogRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&entry))
+		logged = append(logged, entry)
+	}
+	require.Len(t, logged, 2)
+	assert.Equal(t, constant.EventSendWorkbookPassword, logged[0].Action)
+	assert.Equal(t, uint(9), logged[0].MemberId)
+	assert.Equal(t, uint(0), logged[1].MemberId)
+	assert.Contains(t, logged[1].Detail, "recipient=finance@example.com")
+	for _, entry := range logged {
+		for _, password := range passwords {
+			assert.NotContains(t, entry.Detail, password)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package decision
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(svc Service) Controller {
+	return &controller{svc}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	GetRules(c *fiber.Ctx) error
+	SaveRules(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) GetRules(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	rules, err := ctrl.svc.GetRules(authCtx.CompanyId)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		rules,
+	))
+}
+
+func (ctrl *controller) SaveRules(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*saveRulesRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	rules, err := ctrl.svc.SaveRules(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		rules,
+	))
+}
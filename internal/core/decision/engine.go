@@ -0,0 +1,194 @@
+package decision
+
+import (
+	"encoding/json"
+	"fmt"
+	"front-office/pkg/common/model"
+	"strconv"
+	"strings"
+)
+
+// evaluate applies the rules to facts. Only rules whose products are all in
+// facts apply, nil is returned when none do so a product without rules gets
+// no decision.
+func evaluate(rules *ruleSet, facts Facts) *model.Decision {
+	normalized := make(map[string]any, len(facts))
+	for product, data := range facts {
+		normalized[product] = normalize(data)
+	}
+
+	applied := false
+	result := &model.Decision{FiredRules: []string{}}
+	for _, r := range rules.Rules {
+		if !appliesTo(r, normalized) {
+			continue
+		}
+		applied = true
+
+		if !fires(r, normalized) {
+			continue
+		}
+
+		result.FiredRules = append(result.FiredRules, r.Name)
+		if severity[r.Decision] > severity[result.Decision] {
+			result.Decision = r.Decision
+		}
+	}
+
+	if !applied {
+		return nil
+	}
+
+	if result.Decision == "" {
+		result.Decision = rules.DefaultDecision
+		if result.Decision == "" {
+			result.Decision = decisionReview
+		}
+	}
+
+	return result
+}
+
+func appliesTo(r rule, facts map[string]any) bool {
+	for _, cond := range r.Conditions {
+		if _, ok := facts[cond.Product]; !ok {
+			return false
+		}
+	}
+
+	return len(r.Conditions) > 0
+}
+
+func fires(r rule, facts map[string]any) bool {
+	for _, cond := range r.Conditions {
+		value, ok := lookup(facts[cond.Product], cond.Field)
+		if !ok || !matches(value, cond.Operator, cond.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalize turns the product data into the generic json shape, so fields
+// are found by their json names whatever the product type is.
+func normalize(data any) any {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+
+	return value
+}
+
+func lookup(data any, field string) (any, bool) {
+	value := data
+	for _, key := range strings.Split(field, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = object[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func matches(actual any, operator string, expected any) bool {
+	switch operator {
+	case operatorEq:
+		return equal(actual, expected)
+	case operatorNeq:
+		return !equal(actual, expected)
+	case operatorGt, operatorGte, operatorLt, operatorLte:
+		a, okA := number(actual)
+		e, okE := number(expected)
+		if !okA || !okE {
+			return false
+		}
+
+		switch operator {
+		case operatorGt:
+			return a > e
+		case operatorGte:
+			return a >= e
+		case operatorLt:
+			return a < e
+		default:
+			return a <= e
+		}
+	case operatorIn:
+		values, ok := expected.([]any)
+		if !ok {
+			return false
+		}
+
+		for _, v := range values {
+			if equal(actual, v) {
+				return true
+			}
+		}
+
+		return false
+	case operatorContains:
+		if values, ok := actual.([]any); ok {
+			for _, v := range values {
+				if equal(v, expected) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		return strings.Contains(strings.ToLower(text(actual)), strings.ToLower(text(expected)))
+	}
+
+	return false
+}
+
+// equal compares numbers by value and everything else as case insensitive
+// text, "ACTIVE" equals "active" and "3" equals 3.
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x == y
+		}
+	}
+
+	return strings.EqualFold(text(a), text(b))
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func text(v any) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,153 @@
+package decision
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multipleLoanData struct {
+	QueryCount uint `json:"query_count"`
+}
+
+type phoneLiveStatusData struct {
+	LiveStatus string `json:"live_status"`
+	Operator   string `json:"operator"`
+}
+
+// testRules round trips the rules through json like the core would, so the
+// condition values have their decoded types.
+func testRules(t *testing.T, raw string) *ruleSet {
+	t.Helper()
+
+	var rules ruleSet
+	require.NoError(t, json.Unmarshal([]byte(raw), &rules))
+
+	return &rules
+}
+
+const underwritingRules = `{
+	"default_decision": "approve",
+	"rules": [
+		{
+			"name": "inactive phone with many loans",
+			"decision": "reject",
+			"conditions": [
+				{"product": "phone-live-status", "field": "live_status", "operator": "neq", "value": "active"},
+				{"product": "30d-multiple-loan", "field": "query_count", "operator": "gt", "value": 3}
+			]
+		},
+		{
+			"name": "some loans",
+			"decision": "review",
+			"conditions": [
+				{"product": "30d-multiple-loan", "field": "query_count", "operator": "gte", "value": 2}
+			]
+		},
+		{
+			"name": "low grade",
+			"decision": "reject",
+			"conditions": [
+				{"product": "gen-retail", "field": "grade", "operator": "in", "value": ["D", "E"]}
+			]
+		}
+	]
+}`
+
+func TestEvaluate(t *testing.T) {
+	rules := testRules(t, underwritingRules)
+
+	t.Run("most severe fired rule wins", func(t *testing.T) {
+		result := evaluate(rules, Facts{
+			"phone-live-status": &phoneLiveStatusData{LiveStatus: "INACTIVE"},
+			"30d-multiple-loan": multipleLoanData{QueryCount: 5},
+		})
+
+		require.NotNil(t, result)
+		assert.Equal(t, decisionReject, result.Decision)
+		assert.Equal(t, []string{"inactive phone with many loans", "some loans"}, result.FiredRules)
+	})
+
+	t.Run("rule needs every product", func(t *testing.T) {
+		result := evaluate(rules, Facts{"30d-multiple-loan": multipleLoanData{QueryCount: 5}})
+
+		require.NotNil(t, result)
+		assert.Equal(t, decisionReview, result.Decision)
+		assert.Equal(t, []string{"some loans"}, result.FiredRules)
+	})
+
+	t.Run("default decision when nothing fired", func(t *testing.T) {
+		result := evaluate(rules, Facts{
+			"phone-live-status": &phoneLiveStatusData{LiveStatus: "active"},
+			"30d-multiple-loan": multipleLoanData{QueryCount: 1},
+		})
+
+		require.NotNil(t, result)
+		assert.Equal(t, decisionApprove, result.Decision)
+		assert.Empty(t, result.FiredRules)
+	})
+
+	t.Run("review without a default", func(t *testing.T) {
+		noDefault := testRules(t, underwritingRules)
+		noDefault.DefaultDecision = ""
+
+		result := evaluate(noDefault, Facts{"gen-retail": map[string]any{"grade": "A"}})
+
+		require.NotNil(t, result)
+		assert.Equal(t, decisionReview, result.Decision)
+	})
+
+	t.Run("no rule covers the products", func(t *testing.T) {
+		assert.Nil(t, evaluate(rules, Facts{"tax-score": map[string]any{"score": "A"}}))
+	})
+
+	t.Run("missing field does not fire", func(t *testing.T) {
+		result := evaluate(rules, Facts{"gen-retail": map[string]any{"probability_to_default": 0.4}})
+
+		require.NotNil(t, result)
+		assert.Empty(t, result.FiredRules)
+	})
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   any
+		operator string
+		expected any
+		want     bool
+	}{
+		{"eq ignores case", "Active", operatorEq, "ACTIVE", true},
+		{"eq compares numbers", "3", operatorEq, float64(3), true},
+		{"neq", "active", operatorNeq, "inactive", true},
+		{"gt", float64(4), operatorGt, float64(3), true},
+		{"gt equal", float64(3), operatorGt, float64(3), false},
+		{"gte", float64(3), operatorGte, float64(3), true},
+		{"lt numeric string", "0.12", operatorLt, float64(0.2), true},
+		{"lte text", "high", operatorLte, float64(1), false},
+		{"in", "D", operatorIn, []any{"D", "E"}, true},
+		{"in missing", "A", operatorIn, []any{"D", "E"}, false},
+		{"contains text", "Telkomsel Halo", operatorContains, "halo", true},
+		{"contains list", []any{"fraud", "default"}, operatorContains, "fraud", true},
+		{"unknown operator", "a", "like", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matches(tt.actual, tt.operator, tt.expected))
+		})
+	}
+}
+
+func TestLookupNestedField(t *testing.T) {
+	data := normalize(map[string]any{"errors": map[string]any{"code": 12}})
+
+	value, ok := lookup(data, "errors.code")
+	require.True(t, ok)
+	assert.Equal(t, float64(12), value)
+
+	_, ok = lookup(data, "errors.description")
+	assert.False(t, ok)
+}
@@ -0,0 +1,25 @@
+package decision
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupInit serves the rule settings to company admins and returns the
+// evaluator the product requests decide with.
+func SetupInit(decisionAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient) Evaluator {
+	repo := NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+
+	service := NewService(repo, operationRepo)
+	controller := NewController(service)
+
+	decisionAPI.Get("/", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetRules)
+	decisionAPI.Put("/", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(saveRulesRequest{}), controller.SaveRules)
+
+	return service
+}
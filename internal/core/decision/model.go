@@ -0,0 +1,67 @@
+package decision
+
+const (
+	decisionApprove = "approve"
+	decisionReview  = "review"
+	decisionReject  = "reject"
+
+	operatorEq       = "eq"
+	operatorNeq      = "neq"
+	operatorGt       = "gt"
+	operatorGte      = "gte"
+	operatorLt       = "lt"
+	operatorLte      = "lte"
+	operatorIn       = "in"
+	operatorContains = "contains"
+)
+
+// severity orders the decisions, the most severe fired rule wins.
+var severity = map[string]int{
+	decisionApprove: 1,
+	decisionReview:  2,
+	decisionReject:  3,
+}
+
+var operators = map[string]bool{
+	operatorEq:       true,
+	operatorNeq:      true,
+	operatorGt:       true,
+	operatorGte:      true,
+	operatorLt:       true,
+	operatorLte:      true,
+	operatorIn:       true,
+	operatorContains: true,
+}
+
+// Facts holds the data of each product result keyed by the product route,
+// e.g. "30d-multiple-loan" or "gen-retail".
+type Facts map[string]any
+
+type ruleSet struct {
+	CompanyId uint `json:"company_id"`
+	// DefaultDecision is used when rules apply to the request but none fired,
+	// review when empty
+	DefaultDecision string `json:"default_decision"`
+	Rules           []rule `json:"rules"`
+}
+
+type rule struct {
+	Name     string `json:"name"`
+	Decision string `json:"decision"`
+	// Conditions must all hold for the rule to fire
+	Conditions []condition `json:"conditions"`
+}
+
+type condition struct {
+	Product string `json:"product"`
+	// Field is the json name in the product data, nested fields are
+	// separated by dots
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+}
+
+type saveRulesRequest struct {
+	DefaultDecision string `json:"default_decision"`
+	Rules           []rule `json:"rules"`
+}
@@ -0,0 +1,90 @@
+package decision
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	GetRulesAPI(companyId string) (*ruleSet, error)
+	SaveRulesAPI(companyId string, payload *ruleSet) (*ruleSet, error)
+}
+
+func (repo *repository) GetRulesAPI(companyId string) (*ruleSet, error) {
+	url := fmt.Sprintf(`%v/api/core/decision-rules/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*ruleSet](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) SaveRulesAPI(companyId string, payload *ruleSet) (*ruleSet, error) {
+	url := fmt.Sprintf(`%v/api/core/decision-rules/%s`, repo.cfg.App.AifcoreHost, companyId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*ruleSet](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
@@ -0,0 +1,134 @@
+package decision
+
+import (
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(repo Repository, operationRepo operation.Repository) Service {
+	return &service{
+		repo,
+		operationRepo,
+	}
+}
+
+type service struct {
+	repo          Repository
+	operationRepo operation.Repository
+}
+
+type Service interface {
+	Evaluator
+	GetRules(companyId uint) (*ruleSet, error)
+	SaveRules(authCtx *model.AuthContext, req *saveRulesRequest) (*ruleSet, error)
+}
+
+// Evaluator decides approve, review or reject from the product results of a
+// request with the rules of the company.
+type Evaluator interface {
+	// Evaluate returns nil when none of the company rules cover the products
+	// in facts.
+	Evaluate(companyId uint, facts Facts) (*model.Decision, error)
+}
+
+func (svc *service) GetRules(companyId uint) (*ruleSet, error) {
+	rules, err := svc.repo.GetRulesAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchDecisionRules)
+		}
+	}
+	if rules == nil {
+		rules = &ruleSet{}
+	}
+	if rules.CompanyId == 0 {
+		rules.CompanyId = companyId
+	}
+	if rules.Rules == nil {
+		rules.Rules = []rule{}
+	}
+
+	return rules, nil
+}
+
+func (svc *service) SaveRules(authCtx *model.AuthContext, req *saveRulesRequest) (*ruleSet, error) {
+	if err := validateRules(req); err != nil {
+		return nil, err
+	}
+
+	saved, err := svc.repo.SaveRulesAPI(authCtx.CompanyIdStr(), &ruleSet{
+		CompanyId:       authCtx.CompanyId,
+		DefaultDecision: req.DefaultDecision,
+		Rules:           req.Rules,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedSaveDecisionRules)
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      constant.EventUpdateDecisionRules,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", constant.EventUpdateDecisionRules).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+
+	return saved, nil
+}
+
+func (svc *service) Evaluate(companyId uint, facts Facts) (*model.Decision, error) {
+	rules, err := svc.GetRules(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluate(rules, facts), nil
+}
+
+func validateRules(req *saveRulesRequest) error {
+	if req.DefaultDecision != "" && severity[req.DefaultDecision] == 0 {
+		return apperror.BadRequest(constant.InvalidDecision)
+	}
+
+	names := make(map[string]bool, len(req.Rules))
+	for _, r := range req.Rules {
+		if r.Name == "" {
+			return apperror.BadRequest(constant.DecisionRuleNameRequired)
+		}
+		if names[r.Name] {
+			return apperror.BadRequest(fmt.Sprintf(constant.DuplicateDecisionRule, r.Name))
+		}
+		names[r.Name] = true
+
+		if severity[r.Decision] == 0 {
+			return apperror.BadRequest(constant.InvalidDecision)
+		}
+		if len(r.Conditions) == 0 {
+			return apperror.BadRequest(fmt.Sprintf(constant.DecisionRuleNoConditions, r.Name))
+		}
+
+		for _, cond := range r.Conditions {
+			if cond.Product == "" || cond.Field == "" {
+				return apperror.BadRequest(fmt.Sprintf(constant.InvalidDecisionCondition, r.Name))
+			}
+			if !operators[cond.Operator] {
+				return apperror.BadRequest(constant.InvalidDecisionOperator)
+			}
+		}
+	}
+
+	return nil
+}
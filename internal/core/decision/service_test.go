@@ -0,0 +1,157 @@
+package decision
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coreStub answers the core calls by "METHOD path".
+type coreStub struct {
+	routes map[string]func(*http.Request) (int, any)
+	bodies map[string][][]byte
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+	}
+	s.bodies[key] = append(s.bodies[key], reqBody)
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func setupService(routes map[string]func(*http.Request) (int, any)) (Service, *coreStub) {
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{routes: routes, bodies: map[string][][]byte{}}
+
+	return NewService(NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil)), client
+}
+
+func TestServiceEvaluate(t *testing.T) {
+	t.Run("company rules", func(t *testing.T) {
+		svc, _ := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/decision-rules/9": func(*http.Request) (int, any) {
+				var rules map[string]any
+				_ = json.Unmarshal([]byte(underwritingRules), &rules)
+				rules["company_id"] = 9
+
+				return http.StatusOK, rules
+			},
+		})
+
+		result, err := svc.Evaluate(9, Facts{"gen-retail": map[string]any{"grade": "E"}})
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, decisionReject, result.Decision)
+		assert.Equal(t, []string{"low grade"}, result.FiredRules)
+	})
+
+	t.Run("company without rules", func(t *testing.T) {
+		svc, _ := setupService(nil)
+
+		result, err := svc.Evaluate(9, Facts{"gen-retail": map[string]any{"grade": "E"}})
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("core error", func(t *testing.T) {
+		svc, _ := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/decision-rules/9": func(*http.Request) (int, any) {
+				return http.StatusInternalServerError, nil
+			},
+		})
+
+		_, err := svc.Evaluate(9, Facts{})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusInternalServerError, appErr.StatusCode)
+	})
+}
+
+func TestSaveRules(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+	validCondition := condition{Product: "30d-multiple-loan", Field: "query_count", Operator: operatorGt, Value: 3}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"PUT /api/core/decision-rules/9": func(req *http.Request) (int, any) {
+				return http.StatusOK, map[string]any{"company_id": 9}
+			},
+			"POST /api/core/logging/operation": func(*http.Request) (int, any) {
+				return http.StatusOK, nil
+			},
+		})
+
+		_, err := svc.SaveRules(authCtx, &saveRulesRequest{
+			DefaultDecision: decisionApprove,
+			Rules:           []rule{{Name: "many loans", Decision: decisionReject, Conditions: []condition{validCondition}}},
+		})
+		require.NoError(t, err)
+
+		var saved ruleSet
+		require.NoError(t, json.Unmarshal(client.bodies["PUT /api/core/decision-rules/9"][0], &saved))
+		assert.Equal(t, uint(9), saved.CompanyId)
+		assert.Equal(t, "many loans", saved.Rules[0].Name)
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	invalid := []struct {
+		name string
+		req  *saveRulesRequest
+		msg  string
+	}{
+		{"default decision", &saveRulesRequest{DefaultDecision: "maybe"}, constant.InvalidDecision},
+		{"rule decision", &saveRulesRequest{Rules: []rule{{Name: "a", Decision: "deny", Conditions: []condition{validCondition}}}}, constant.InvalidDecision},
+		{"missing name", &saveRulesRequest{Rules: []rule{{Decision: decisionReject, Conditions: []condition{validCondition}}}}, constant.DecisionRuleNameRequired},
+		{"duplicate name", &saveRulesRequest{Rules: []rule{
+			{Name: "a", Decision: decisionReject, Conditions: []condition{validCondition}},
+			{Name: "a", Decision: decisionReview, Conditions: []condition{validCondition}},
+		}}, "duplicate rule: a"},
+		{"no conditions", &saveRulesRequest{Rules: []rule{{Name: "a", Decision: decisionReject}}}, "rule a has no conditions"},
+		{"missing field", &saveRulesRequest{Rules: []rule{{Name: "a", Decision: decisionReject, Conditions: []condition{{Product: "tax-score", Operator: operatorEq}}}}}, "rule a has a condition without product or field"},
+		{"operator", &saveRulesRequest{Rules: []rule{{Name: "a", Decision: decisionReject, Conditions: []condition{{Product: "tax-score", Field: "score", Operator: "like"}}}}}, constant.InvalidDecisionOperator},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, client := setupService(nil)
+
+			_, err := svc.SaveRules(authCtx, tt.req)
+
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Equal(t, tt.msg, appErr.Message)
+			assert.Empty(t, client.bodies)
+		})
+	}
+}
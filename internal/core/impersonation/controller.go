@@ -0,0 +1,108 @@
+package impersonation
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(service Service) Controller {
+	return &controller{svc: service}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	StartImpersonation(c *fiber.Ctx) error
+	EndImpersonation(c *fiber.Ctx) error
+	GetSessions(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) StartImpersonation(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*startImpersonationRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.StartImpersonation(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	// replaces the staff session, the staff refresh token is kept so the
+	// frontend can restore it once impersonation ends or expires
+	c.Cookie(&fiber.Cookie{
+		Name:     "aif_token",
+		Value:    result.AccessToken,
+		Expires:  result.Session.ExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Lax",
+		Path:     "/",
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(helper.SuccessResponse(
+		"succeed to start impersonation",
+		result.Session,
+	))
+}
+
+func (ctrl *controller) EndImpersonation(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	sessionId, _ := c.Locals(sessionIdLocal).(string)
+
+	if err := ctrl.svc.EndImpersonation(authCtx, sessionId); err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "aif_token",
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Lax",
+		Path:     "/",
+	})
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+		"succeed to end impersonation",
+		nil,
+	))
+}
+
+func (ctrl *controller) GetSessions(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.GetSessions(authCtx, &sessionFilter{
+		CompanyId:      c.Query("company_id"),
+		ImpersonatorId: c.Query("impersonator_id"),
+		Page:           c.Query(constant.Page, "1"),
+		Size:           c.Query(constant.Size, "10"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get impersonation sessions",
+		result,
+	))
+}
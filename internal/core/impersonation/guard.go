@@ -0,0 +1,171 @@
+package impersonation
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const sessionIdLocal = "impersonationSessionId"
+
+// Guard runs in front of every route. Requests made with an impersonation
+// token are limited to reads that are not sensitive, flagged in the
+// response and recorded in the operation log of the impersonated company.
+// Tokens of ended sessions are rejected. Other requests pass untouched.
+func Guard(cfg *application.Config, operationRepo operation.Repository, revocations RevocationStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Cookies("aif_token")
+		if token == "" {
+			return c.Next()
+		}
+
+		// invalid or expired tokens are rejected by the route auth middleware
+		claims, err := helper.ExtractClaimsFromJWT(token, cfg.App.JwtSecretKey)
+		if err != nil {
+			return c.Next()
+		}
+
+		impersonatorId, ok := helper.ExtractImpersonatorIdFromClaims(claims)
+		if !ok {
+			return c.Next()
+		}
+
+		rawSessionId, ok := (*claims)["impersonation_session_id"].(float64)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(helper.ErrorResponse(constant.ImpersonationEnded))
+		}
+		sessionId := strconv.FormatUint(uint64(rawSessionId), 10)
+
+		// fails closed, a session that cannot be checked may have been ended
+		revoked, err := revocations.IsRevoked(c.Context(), sessionId)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("impersonation_session_id", sessionId).
+				Msg("failed to check impersonation session")
+
+			return c.Status(fiber.StatusServiceUnavailable).JSON(helper.ErrorResponse(constant.ErrUpstreamUnavailable))
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(helper.ErrorResponse(constant.ImpersonationEnded))
+		}
+
+		isEnd := strings.HasSuffix(c.Path(), endImpersonationPath)
+		if !isReadOnlyMethod(c.Method()) && !isEnd {
+			return c.Status(fiber.StatusForbidden).JSON(helper.ErrorResponse(constant.ImpersonationReadOnly))
+		}
+		if isSensitiveRead(c.Path()) {
+			return c.Status(fiber.StatusForbidden).JSON(helper.ErrorResponse(constant.ImpersonationNoExport))
+		}
+
+		memberId, _ := helper.ExtractUserIdFromClaims(claims)
+		companyId, _ := helper.ExtractCompanyIdFromClaims(claims)
+		expiresAt := claimTime(claims, "exp")
+
+		c.Locals(sessionIdLocal, sessionId)
+
+		c.Set(constant.XImpersonatedBy, strconv.FormatUint(uint64(impersonatorId), 10))
+		c.Set(constant.XImpersonationExpiresAt, expiresAt.Format(time.RFC3339))
+
+		audit := &operation.AddLogRequest{
+			MemberId:       memberId,
+			CompanyId:      companyId,
+			Action:         constant.EventImpersonatedRequest,
+			ImpersonatorId: impersonatorId,
+			Detail:         c.Method() + " " + c.OriginalURL(),
+		}
+
+		err = c.Next()
+		if err == nil {
+			flagResponse(c, &flag{
+				Active:         true,
+				ImpersonatorId: impersonatorId,
+				ReadOnly:       true,
+				ExpiresAt:      expiresAt,
+			})
+		}
+
+		// ending the session is logged by the service
+		if !isEnd {
+			go func() {
+				if err := operationRepo.AddLogOperation(audit); err != nil {
+					log.Warn().
+						Err(err).
+						Str("action", audit.Action).
+						Msg(constant.MsgFailedAddOperationLog)
+				}
+			}()
+		}
+
+		return err
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
+
+func isSensitiveRead(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	last := segments[len(segments)-1]
+	for _, ending := range sensitiveReadEndings {
+		if last == ending || strings.HasSuffix(last, "-"+ending) {
+			return true
+		}
+	}
+
+	for _, segment := range segments {
+		if slices.Contains(sensitiveReadGroups, segment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flagResponse adds an "impersonation" field to JSON object responses so the
+// frontend can show who is viewing and until when.
+func flagResponse(c *fiber.Ctx, f *flag) {
+	contentType := string(c.Response().Header.ContentType())
+	if !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return
+	}
+
+	body := bytes.TrimSpace(c.Response().Body())
+	if len(body) < 2 || body[0] != '{' {
+		return
+	}
+
+	flagged, err := json.Marshal(map[string]*flag{"impersonation": f})
+	if err != nil {
+		return
+	}
+
+	rest := bytes.TrimSpace(body[1:])
+	if len(rest) > 1 {
+		flagged = append(flagged[:len(flagged)-1], ',')
+		flagged = append(flagged, rest...)
+	}
+
+	c.Response().SetBody(flagged)
+}
+
+func claimTime(claims *jwt.MapClaims, key string) time.Time {
+	if v, ok := (*claims)[key].(float64); ok {
+		return time.Unix(int64(v), 0)
+	}
+
+	return time.Time{}
+}
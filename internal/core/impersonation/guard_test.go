@@ -0,0 +1,172 @@
+package impersonation
+
+import (
+	"context"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOperationRepo struct {
+	operation.Repository
+	logs chan *operation.AddLogRequest
+}
+
+func (r *stubOperationRepo) AddLogOperation(req *operation.AddLogRequest) error {
+	r.logs <- req
+	return nil
+}
+
+type stubRevocations struct {
+	revoked map[string]bool
+}
+
+func (s *stubRevocations) Revoke(_ context.Context, sessionId string, _ time.Duration) error {
+	s.revoked[sessionId] = true
+	return nil
+}
+
+func (s *stubRevocations) IsRevoked(_ context.Context, sessionId string) (bool, error) {
+	return s.revoked[sessionId], nil
+}
+
+func setupGuardApp(t *testing.T, revoked ...string) (*fiber.App, *stubOperationRepo, string) {
+	t.Helper()
+
+	cfg := &application.Config{App: &application.Environment{JwtSecretKey: "access-secret"}}
+	operationRepo := &stubOperationRepo{logs: make(chan *operation.AddLogRequest, 4)}
+
+	token, err := helper.GenerateTokenWithClaims(cfg.App.JwtSecretKey, 30, constant.TokenTypeAccess, map[string]any{
+		"user_id":                  1,
+		"company_id":               2,
+		"impersonator_id":          9,
+		"impersonation_session_id": 5,
+	})
+	require.NoError(t, err)
+
+	revocations := &stubRevocations{revoked: map[string]bool{}}
+	for _, sessionId := range revoked {
+		revocations.revoked[sessionId] = true
+	}
+
+	app := fiber.New()
+	app.Use(Guard(cfg, operationRepo, revocations))
+	app.Get("/users/profile", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"message": "ok"})
+	})
+	app.Get("/users/me/data-export", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"message": "exported"})
+	})
+	app.Put("/users/profile", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"message": "updated"})
+	})
+	app.Post(endImpersonationPath, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"session": c.Locals(sessionIdLocal)})
+	})
+
+	return app, operationRepo, token
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, token string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: "aif_token", Value: token})
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	body := map[string]any{}
+	require.NoError(t, json.Unmarshal(raw, &body))
+
+	return resp, body
+}
+
+func TestGuard(t *testing.T) {
+	t.Run("flags read requests and logs them", func(t *testing.T) {
+		app, operationRepo, token := setupGuardApp(t)
+
+		resp, body := doRequest(t, app, fiber.MethodGet, "/users/profile", token)
+
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ok", body["message"])
+		assert.Equal(t, "9", resp.Header.Get(constant.XImpersonatedBy))
+
+		flag, ok := body["impersonation"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, true, flag["read_only"])
+		assert.Equal(t, float64(9), flag["impersonator_id"])
+
+		select {
+		case audit := <-operationRepo.logs:
+			assert.Equal(t, constant.EventImpersonatedRequest, audit.Action)
+			assert.Equal(t, uint(9), audit.ImpersonatorId)
+			assert.Equal(t, uint(2), audit.CompanyId)
+			assert.Equal(t, "GET /users/profile", audit.Detail)
+		case <-time.After(time.Second):
+			t.Fatal("impersonated request was not logged")
+		}
+	})
+
+	t.Run("blocks writes", func(t *testing.T) {
+		app, _, token := setupGuardApp(t)
+
+		resp, body := doRequest(t, app, fiber.MethodPut, "/users/profile", token)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, constant.ImpersonationReadOnly, body["message"])
+	})
+
+	t.Run("blocks sensitive reads", func(t *testing.T) {
+		app, _, token := setupGuardApp(t)
+
+		resp, body := doRequest(t, app, fiber.MethodGet, "/users/me/data-export", token)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, constant.ImpersonationNoExport, body["message"])
+	})
+
+	t.Run("rejects ended sessions", func(t *testing.T) {
+		app, _, token := setupGuardApp(t, "5")
+
+		resp, body := doRequest(t, app, fiber.MethodGet, "/users/profile", token)
+
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, constant.ImpersonationEnded, body["message"])
+	})
+
+	t.Run("allows ending the session", func(t *testing.T) {
+		app, _, token := setupGuardApp(t)
+
+		resp, body := doRequest(t, app, fiber.MethodPost, endImpersonationPath, token)
+
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "5", body["session"])
+	})
+
+	t.Run("ignores regular sessions", func(t *testing.T) {
+		app, _, _ := setupGuardApp(t)
+
+		resp, body := doRequest(t, app, fiber.MethodPut, "/users/profile", "")
+
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.NotContains(t, body, "impersonation")
+		assert.Empty(t, resp.Header.Get(constant.XImpersonatedBy))
+	})
+}
@@ -0,0 +1,44 @@
+package impersonation
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/internalteam"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	redisinfra "front-office/internal/infra/redis"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// SetupRevocationStore is shared by Guard and SetupInit, ended sessions are
+// written by one and read by the other.
+func SetupRevocationStore(cfg *application.Config) RevocationStore {
+	redisClient, err := redisinfra.NewRedisClient(
+		cfg.App.AppEnv,
+		cfg.App.RedisAddr,
+	)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("failed to create redis connection")
+	}
+
+	return NewRedisRevocationStore(redisClient)
+}
+
+func SetupInit(userAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient, revocations RevocationStore) {
+	repo := NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	internalTeamRepo := internalteam.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	service := NewService(cfg, repo, memberRepo, internalTeamRepo, operationRepo, revocations)
+	controller := NewController(service)
+
+	userAPI.Post("/impersonation", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(startImpersonationRequest{}), controller.StartImpersonation)
+	userAPI.Post("/impersonation/end", middleware.GetJWTPayloadFromCookie(cfg), controller.EndImpersonation)
+	userAPI.Get("/impersonation/sessions", middleware.GetJWTPayloadFromCookie(cfg), controller.GetSessions)
+}
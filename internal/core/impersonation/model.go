@@ -0,0 +1,71 @@
+package impersonation
+
+import "time"
+
+const (
+	defaultDurationMinutes = 30
+	maxDurationMinutes     = 60
+
+	// the only mutating request allowed while impersonating
+	endImpersonationPath = "/users/impersonation/end"
+)
+
+// Reads that hand out customer or billing data as files stay closed while
+// impersonating. They are matched by rule so new exports are covered too: a
+// path ending in one of sensitiveReadEndings, e.g. ".../jobs/:id/export" or
+// "/users/me/data-export", or a path in one of sensitiveReadGroups.
+var (
+	sensitiveReadEndings = []string{"export", "download", "proforma", "payment-proof"}
+	sensitiveReadGroups  = []string{"subject-history"}
+)
+
+type Session struct {
+	Id             uint       `json:"id"`
+	ImpersonatorId uint       `json:"impersonator_id"`
+	MemberId       uint       `json:"member_id"`
+	CompanyId      uint       `json:"company_id"`
+	Reason         string     `json:"reason"`
+	StartedAt      time.Time  `json:"started_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+}
+
+type startImpersonationRequest struct {
+	MemberId        uint   `json:"member_id" validate:"required~Field Member ID is required"`
+	Reason          string `json:"reason" validate:"required~Field Reason is required"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+type createSessionPayload struct {
+	ImpersonatorId uint      `json:"impersonator_id"`
+	MemberId       uint      `json:"member_id"`
+	CompanyId      uint      `json:"company_id"`
+	Reason         string    `json:"reason"`
+	StartedAt      time.Time `json:"started_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type sessionFilter struct {
+	CompanyId      string
+	ImpersonatorId string
+	Page           string
+	Size           string
+}
+
+type sessionListResponse struct {
+	Sessions  []*Session `json:"sessions"`
+	TotalData int64      `json:"total_data"`
+}
+
+type startImpersonationResult struct {
+	Session     *Session
+	AccessToken string
+}
+
+// flag is added to every JSON response served with an impersonation token.
+type flag struct {
+	Active         bool      `json:"active"`
+	ImpersonatorId uint      `json:"impersonator_id"`
+	ReadOnly       bool      `json:"read_only"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
@@ -0,0 +1,124 @@
+package impersonation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	CreateSessionAPI(payload *createSessionPayload) (*Session, error)
+	EndSessionAPI(id string, payload map[string]interface{}) error
+	GetSessionsAPI(filter *sessionFilter) (*sessionListResponse, error)
+}
+
+func (repo *repository) CreateSessionAPI(payload *createSessionPayload) (*Session, error) {
+	url := fmt.Sprintf("%s/api/core/impersonation-sessions", repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*Session](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) EndSessionAPI(id string, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/core/impersonation-sessions/%s", repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+func (repo *repository) GetSessionsAPI(filter *sessionFilter) (*sessionListResponse, error) {
+	url := fmt.Sprintf("%s/api/core/impersonation-sessions", repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", filter.CompanyId)
+	q.Add("impersonator_id", filter.ImpersonatorId)
+	q.Add(constant.Page, filter.Page)
+	q.Add(constant.Size, filter.Size)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*sessionListResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
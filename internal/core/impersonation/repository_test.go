@@ -0,0 +1,227 @@
+package impersonation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockClient struct {
+	mock.Mock
+}
+
+func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+	t.Helper()
+
+	mockClient := new(MockClient)
+	mockClient.On("Do", mock.Anything).Return(response, err)
+
+	repo := NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockHost},
+	}, mockClient, nil)
+
+	return repo, mockClient
+}
+
+func newInvalidHostRepo(marshalFn func(v any) ([]byte, error)) Repository {
+	return NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+	}, new(MockClient), marshalFn)
+}
+
+func jsonResponse(t *testing.T, data any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func invalidJSONResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+	}
+}
+
+func TestCreateSessionAPI(t *testing.T) {
+	payload := &createSessionPayload{ImpersonatorId: 9, MemberId: 1, CompanyId: 1, Reason: "ticket 42"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*Session]{
+			Success: true,
+			Data:    &Session{Id: 1, ImpersonatorId: 9, MemberId: 1},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.CreateSessionAPI(payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), result.Id)
+		assert.Equal(t, uint(9), result.ImpersonatorId)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		result, err := repo.CreateSessionAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).CreateSessionAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.CreateSessionAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.CreateSessionAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestEndSessionAPI(t *testing.T) {
+	payload := map[string]interface{}{"ended_at": "2026-01-01T00:00:00Z"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[any]{Success: true})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.EndSessionAPI(constant.DummyId, payload)
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		err := repo.EndSessionAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		err := newInvalidHostRepo(nil).EndSessionAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		err := repo.EndSessionAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		err := repo.EndSessionAPI(constant.DummyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetSessionsAPI(t *testing.T) {
+	filter := &sessionFilter{CompanyId: constant.DummyCompanyId, Page: "1", Size: "10"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*sessionListResponse]{
+			Success: true,
+			Data: &sessionListResponse{
+				Sessions:  []*Session{{Id: 1}, {Id: 2}},
+				TotalData: 2,
+			},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetSessionsAPI(filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Sessions, 2)
+		assert.Equal(t, int64(2), result.TotalData)
+
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		assert.Equal(t, constant.DummyCompanyId, req.URL.Query().Get("company_id"))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetSessionsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetSessionsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetSessionsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,193 @@
+package impersonation
+
+import (
+	"context"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/internal/core/internalteam"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(
+	cfg *application.Config,
+	repo Repository,
+	memberRepo member.Repository,
+	internalTeamRepo internalteam.Repository,
+	operationRepo operation.Repository,
+	revocations RevocationStore,
+) Service {
+	return &service{
+		cfg,
+		repo,
+		memberRepo,
+		internalTeamRepo,
+		operationRepo,
+		revocations,
+	}
+}
+
+type service struct {
+	cfg              *application.Config
+	repo             Repository
+	memberRepo       member.Repository
+	internalTeamRepo internalteam.Repository
+	operationRepo    operation.Repository
+	revocations      RevocationStore
+}
+
+type Service interface {
+	StartImpersonation(authCtx *model.AuthContext, req *startImpersonationRequest) (*startImpersonationResult, error)
+	EndImpersonation(authCtx *model.AuthContext, sessionId string) error
+	GetSessions(authCtx *model.AuthContext, filter *sessionFilter) (*sessionListResponse, error)
+}
+
+func (svc *service) StartImpersonation(authCtx *model.AuthContext, req *startImpersonationRequest) (*startImpersonationResult, error) {
+	if authCtx.ImpersonatorId != 0 {
+		return nil, apperror.Forbidden(constant.AlreadyImpersonating)
+	}
+
+	if err := svc.ensureInternalStaff(authCtx.UserId); err != nil {
+		return nil, err
+	}
+
+	duration := req.DurationMinutes
+	if duration == 0 {
+		duration = defaultDurationMinutes
+	}
+	if duration < 0 || duration > maxDurationMinutes {
+		return nil, apperror.BadRequest(fmt.Sprintf(constant.InvalidImpersonationTime, maxDurationMinutes))
+	}
+
+	target, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{Id: helper.ConvertUintToString(req.MemberId)})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+	if target == nil || target.MemberId == 0 {
+		return nil, apperror.NotFound(constant.UserNotFound)
+	}
+	if !target.Active {
+		return nil, apperror.BadRequest(constant.CannotImpersonateInactive)
+	}
+
+	now := time.Now()
+	session, err := svc.repo.CreateSessionAPI(&createSessionPayload{
+		ImpersonatorId: authCtx.UserId,
+		MemberId:       target.MemberId,
+		CompanyId:      target.CompanyId,
+		Reason:         req.Reason,
+		StartedAt:      now,
+		ExpiresAt:      now.Add(time.Duration(duration) * time.Minute),
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to create impersonation session")
+	}
+
+	// the token carries the target member identity, the impersonator claims
+	// make it read-only and tag everything done with it
+	accessToken, err := helper.GenerateTokenWithClaims(svc.cfg.App.JwtSecretKey, duration, constant.TokenTypeAccess, map[string]any{
+		"user_id":                  target.MemberId,
+		"company_id":               target.CompanyId,
+		"role_id":                  target.RoleId,
+		"quota_type":               uint(target.QuotaType),
+		"api_key":                  target.Key,
+		"impersonator_id":          authCtx.UserId,
+		"impersonation_session_id": session.Id,
+	})
+	if err != nil {
+		return nil, apperror.Internal("failed to generate impersonation token", err)
+	}
+
+	detail := fmt.Sprintf("member %d, reason: %s", target.MemberId, req.Reason)
+
+	// recorded on both sides so the customer audit trail shows the access too
+	svc.addLogOperation(&operation.AddLogRequest{
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Action:    constant.EventStartImpersonation,
+		Detail:    detail,
+	})
+	svc.addLogOperation(&operation.AddLogRequest{
+		MemberId:       target.MemberId,
+		CompanyId:      target.CompanyId,
+		Action:         constant.EventStartImpersonation,
+		ImpersonatorId: authCtx.UserId,
+		Detail:         detail,
+	})
+
+	return &startImpersonationResult{
+		Session:     session,
+		AccessToken: accessToken,
+	}, nil
+}
+
+func (svc *service) EndImpersonation(authCtx *model.AuthContext, sessionId string) error {
+	if authCtx.ImpersonatorId == 0 || sessionId == "" {
+		return apperror.BadRequest(constant.NotImpersonating)
+	}
+
+	// the token stays valid until it expires, the guard rejects it once the
+	// session is revoked
+	if err := svc.revocations.Revoke(context.Background(), sessionId, maxDurationMinutes*time.Minute); err != nil {
+		return apperror.Internal("failed to revoke impersonation session", err)
+	}
+
+	if err := svc.repo.EndSessionAPI(sessionId, map[string]interface{}{
+		"ended_at": time.Now(),
+	}); err != nil {
+		return apperror.MapRepoError(err, "failed to end impersonation session")
+	}
+
+	svc.addLogOperation(&operation.AddLogRequest{
+		MemberId:       authCtx.UserId,
+		CompanyId:      authCtx.CompanyId,
+		Action:         constant.EventEndImpersonation,
+		ImpersonatorId: authCtx.ImpersonatorId,
+	})
+
+	return nil
+}
+
+func (svc *service) GetSessions(authCtx *model.AuthContext, filter *sessionFilter) (*sessionListResponse, error) {
+	if err := svc.ensureInternalStaff(authCtx.UserId); err != nil {
+		return nil, err
+	}
+
+	result, err := svc.repo.GetSessionsAPI(filter)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch impersonation sessions")
+	}
+
+	return result, nil
+}
+
+func (svc *service) ensureInternalStaff(memberId uint) error {
+	resp, err := svc.internalTeamRepo.GetMemberAPI()
+	if err != nil {
+		return apperror.MapRepoError(err, "failed to fetch internal team")
+	}
+
+	for _, staff := range resp.Data {
+		if staff.MemberID == memberId {
+			return nil
+		}
+	}
+
+	return apperror.Forbidden(constant.NotInternalStaff)
+}
+
+func (svc *service) addLogOperation(req *operation.AddLogRequest) {
+	if err := svc.operationRepo.AddLogOperation(req); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", req.Action).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
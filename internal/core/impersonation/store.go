@@ -0,0 +1,51 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationStore remembers the sessions that were ended before their token
+// expired, so the token can no longer be used.
+type RevocationStore interface {
+	// Revoke marks the session as ended, the mark is dropped after ttl when
+	// the token has expired anyway.
+	Revoke(ctx context.Context, sessionId string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, sessionId string) (bool, error)
+}
+
+var errNoRedisClient = errors.New("redis client is not configured")
+
+const revokedKeyPrefix = "impersonation:revoked:"
+
+func NewRedisRevocationStore(client *redis.Client) RevocationStore {
+	return &redisRevocationStore{client: client}
+}
+
+type redisRevocationStore struct {
+	client *redis.Client
+}
+
+func (s *redisRevocationStore) Revoke(ctx context.Context, sessionId string, ttl time.Duration) error {
+	if s.client == nil {
+		return errNoRedisClient
+	}
+
+	return s.client.Set(ctx, revokedKeyPrefix+sessionId, 1, ttl).Err()
+}
+
+func (s *redisRevocationStore) IsRevoked(ctx context.Context, sessionId string) (bool, error) {
+	if s.client == nil {
+		return false, errNoRedisClient
+	}
+
+	n, err := s.client.Exists(ctx, revokedKeyPrefix+sessionId).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
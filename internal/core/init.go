@@ -2,16 +2,23 @@ package core
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/apiclient"
 	"front-office/internal/core/auth"
 	"front-office/internal/core/billing"
+	"front-office/internal/core/decision"
 	"front-office/internal/core/grade"
+	"front-office/internal/core/impersonation"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
 	"front-office/internal/core/member"
+	"front-office/internal/core/passwordpolicy"
+	"front-office/internal/core/privacy"
+	"front-office/internal/core/quota"
 	"front-office/internal/core/role"
 	"front-office/internal/core/template"
 	"front-office/internal/datahub"
 	"front-office/internal/mail"
+	"front-office/internal/middleware"
 	"front-office/internal/scoreezy"
 	"front-office/pkg/httpclient"
 
@@ -23,8 +30,16 @@ import (
 func SetupInit(routeGroup fiber.Router, cfg *application.Config, mailModule *mail.MailModule) {
 	client := httpclient.NewDefaultClient(10 * time.Second)
 
+	// must run before every route to keep impersonation sessions read-only
+	revocations := impersonation.SetupRevocationStore(cfg)
+	routeGroup.Use(impersonation.Guard(cfg, operation.NewRepository(cfg, client, nil), revocations))
+
 	userGroup := routeGroup.Group("users")
 	auth.SetupInit(userGroup, cfg, client, mailModule.SendMail)
+	// registered before member so static paths are not captured by /:id
+	privacy.SetupInit(userGroup, cfg, client, mailModule.SendMail)
+	passwordpolicy.SetupInit(userGroup, cfg, client)
+	impersonation.SetupInit(userGroup, cfg, client, revocations)
 	member.SetupInit(userGroup, cfg, client, mailModule.SendMail)
 
 	roleGroup := routeGroup.Group("roles")
@@ -33,13 +48,22 @@ func SetupInit(routeGroup fiber.Router, cfg *application.Config, mailModule *mai
 	gradeGroup := routeGroup.Group("grades")
 	grade.SetupInit(gradeGroup, cfg, client)
 
+	decisionGroup := routeGroup.Group("decision-rules")
+	evaluator := decision.SetupInit(decisionGroup, cfg, client)
+
 	logGroup := routeGroup.Group("logs")
 	transaction.SetupInit(logGroup, cfg, client)
 	operation.SetupInit(logGroup, cfg, client)
 
+	apiClientGroup := routeGroup.Group("api-clients")
+	apiClientSvc := apiclient.SetupInit(apiClientGroup, cfg, client)
+
 	productGroup := routeGroup.Group("products")
-	datahub.SetupInit(productGroup, cfg)
-	scoreezy.SetupInit(productGroup, cfg, client)
+	productGroup.Use(middleware.APIClientAuth(apiClientSvc))
+	// shared so every product draws from the same reservations
+	quotaReserver := quota.SetupInit(cfg, client)
+	datahub.SetupInit(productGroup, cfg, quotaReserver, evaluator, mailModule.SendMail)
+	scoreezy.SetupInit(productGroup, cfg, client, quotaReserver, evaluator)
 
 	billingGroup := routeGroup.Group("billing")
 	billing.SetupInit(billingGroup, cfg, client, mailModule.SendMail)
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/impersonation"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/mail"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOperationRepo struct {
+	operation.Repository
+}
+
+func (r *stubOperationRepo) AddLogOperation(*operation.AddLogRequest) error {
+	return nil
+}
+
+type stubRevocations struct{}
+
+func (stubRevocations) Revoke(context.Context, string, time.Duration) error {
+	return nil
+}
+
+func (stubRevocations) IsRevoked(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+var routeParam = regexp.MustCompile(`:[a-z_]+\??`)
+
+// TestImpersonationGuardRoutes walks every GET route the gateway registers
+// and checks that the reads handing out customer or billing data as files
+// stay closed while impersonating, the others stay open.
+func TestImpersonationGuardRoutes(t *testing.T) {
+	cfg := &application.Config{App: &application.Environment{
+		AifcoreHost:  constant.MockHost,
+		JwtSecretKey: "access-secret",
+	}}
+
+	gateway := fiber.New()
+	SetupInit(gateway.Group("/api/fo"), cfg, &mail.MailModule{SendMail: mail.NewMailService(nil, nil, nil, "3")})
+
+	// the routes are replayed behind the guard alone, so nothing reaches
+	// a handler that would call the core
+	app := fiber.New()
+	app.Use(impersonation.Guard(cfg, &stubOperationRepo{}, stubRevocations{}))
+
+	paths := map[string]bool{}
+	for _, route := range gateway.GetRoutes(true) {
+		if route.Method != fiber.MethodGet || paths[route.Path] {
+			continue
+		}
+
+		paths[route.Path] = true
+		app.Get(route.Path, func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"message": "ok"})
+		})
+	}
+
+	// the exports named in the review, the walk has to reach them
+	for _, path := range []string{
+		"/api/fo/users/me/data-export",
+		"/api/fo/products/compliance/:product_slug/jobs/:job_id/export",
+		"/api/fo/products/compliance/:product_slug/jobs-summary/export",
+		"/api/fo/products/identity/phone-live-status/jobs/:id/details/export",
+		"/api/fo/products/scoreezy/gen-retail/logs/export",
+		"/api/fo/products/applicant-check/:id/export",
+		"/api/fo/products/incometax/tax-report/:id/export",
+		"/api/fo/billing/topups/:id/proforma",
+		"/api/fo/billing/topups/:id/payment-proof",
+		"/api/fo/billing/topup-reviews/:id/payment-proof",
+		"/api/fo/billing/invoices/download",
+		"/api/fo/products/subject-history/",
+	} {
+		assert.Contains(t, paths, path)
+	}
+
+	token, err := helper.GenerateTokenWithClaims(cfg.App.JwtSecretKey, 30, constant.TokenTypeAccess, map[string]any{
+		"user_id":                  1,
+		"company_id":               2,
+		"impersonator_id":          9,
+		"impersonation_session_id": 5,
+	})
+	require.NoError(t, err)
+
+	for path := range paths {
+		sensitive := strings.Contains(path, "subject-history")
+		for _, ending := range []string{"export", "download", "proforma", "payment-proof"} {
+			sensitive = sensitive || strings.HasSuffix(strings.TrimSuffix(path, "/"), ending)
+		}
+
+		req := httptest.NewRequest(fiber.MethodGet, routeParam.ReplaceAllString(path, "1"), nil)
+		req.AddCookie(&http.Cookie{Name: "aif_token", Value: token})
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(raw, &body), path)
+
+		if sensitive {
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, path)
+			assert.Equal(t, constant.ImpersonationNoExport, body["message"], path)
+		} else {
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+		}
+	}
+}
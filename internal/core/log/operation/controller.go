@@ -92,8 +92,9 @@ func (ctrl *controller) GetListByRange(c *fiber.Ctx) error {
 func mapEventKeyword(input string) (string, bool) {
 	eventMap := map[string]string{
 		// auth
-		"sign-in":  constant.EventSignIn,
-		"sign-out": constant.EventSignOut,
+		"sign-in":     constant.EventSignIn,
+		"sign-in-sso": constant.EventSignInSSO,
+		"sign-out":    constant.EventSignOut,
 
 		// user
 		"change-password":        constant.EventChangePassword,
@@ -104,11 +105,33 @@ func mapEventKeyword(input string) (string, bool) {
 		"update-user-data":       constant.EventUpdateUserData,
 		"activate-user":          constant.EventActivateUser,
 		"inactivate-user":        constant.EventInactivateUser,
+		"update-password-policy": constant.EventUpdatePasswordPolicy,
+
+		// personal data
+		"request-data-export":      constant.EventRequestDataExport,
+		"request-account-deletion": constant.EventRequestAccountDeletion,
+		"approve-account-deletion": constant.EventApproveAccountDeletion,
+		"reject-account-deletion":  constant.EventRejectAccountDeletion,
+
+		// api client
+		"create-api-client":        constant.EventCreateAPIClient,
+		"rotate-api-client-secret": constant.EventRotateAPIClientSecret,
+		"revoke-api-client":        constant.EventRevokeAPIClient,
+
+		// impersonation
+		"start-impersonation":  constant.EventStartImpersonation,
+		"end-impersonation":    constant.EventEndImpersonation,
+		"impersonated-request": constant.EventImpersonatedRequest,
 
 		// balance
 		"update-billing-information":  constant.EventChangeBillingInformation,
 		"topup-balance":               constant.EventTopupBalance,
 		"submit-payment-confirmation": constant.EventSubmitPaymentConfirmation,
+		"update-report-schedule":      constant.EventUpdateReportSchedule,
+		"update-quota-alert":          constant.EventUpdateQuotaAlert,
+		"issue-workbook-password":     constant.EventIssueWorkbookPassword,
+		"send-workbook-password":      constant.EventSendWorkbookPassword,
+		"retrieve-workbook-password":  constant.EventRetrieveWorkbookPassword,
 
 		// scoreezy
 		"scoreezy-single-request":          constant.EventScoreezySingleReq,
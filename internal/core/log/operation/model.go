@@ -14,6 +14,10 @@ type LogOperation struct {
 	Action    string    `json:"action"`
 	ClientIP  string    `json:"ip_address"`
 	CreatedAt time.Time `json:"created_at"`
+
+	APIClientId    string `json:"api_client_id,omitempty"`
+	ImpersonatorId uint   `json:"impersonator_id,omitempty"`
+	Detail         string `json:"detail,omitempty"`
 }
 
 type mstMember struct {
@@ -55,6 +59,10 @@ type AddLogRequest struct {
 	MemberId  uint   `json:"member_id" validate:"required~Field Member ID is required"`
 	CompanyId uint   `json:"company_id" validate:"required~Field Company ID is required"`
 	Action    string `json:"action" validate:"required~Field Action is required"`
+
+	APIClientId    string `json:"api_client_id,omitempty"`
+	ImpersonatorId uint   `json:"impersonator_id,omitempty"`
+	Detail         string `json:"detail,omitempty"`
 }
 
 type logOperationAPIResponse struct {
@@ -108,3 +108,17 @@ type LogFilter struct {
 	StartDate string
 	EndDate   string
 }
+
+// CompanyLogFilter selects the product catalog transactions of a company
+// within a date range, MemberIds narrows them to the given members.
+type CompanyLogFilter struct {
+	CompanyId       string
+	ProductId       string
+	ProductSlug     string
+	PricingStrategy string
+	ApplyDedup      string
+	DedupWindowDays string
+	StartDate       string
+	EndDate         string
+	MemberIds       []string
+}
@@ -32,10 +32,13 @@ type Repository interface {
 	GetLogsScoreezyByDateAPI(companyId, date string) ([]*LogTransScoreezy, error)
 	GetLogsScoreezyByDateRangeAPI(filter *LogFilter) ([]*LogTransScoreezy, error)
 	GetLogsScoreezyByMonthAPI(companyId, month string) ([]*LogTransScoreezy, error)
+	UpdateLogScoreezyAPI(trxId string, req map[string]interface{}) error
 
 	// product catalog
 	CreateLogTransAPI(req *LogTransProCatRequest) error
-	GetLogTransByCompanyAPI(jobId, productId, companyId, pricingStrategy, productSlug, applyDedup, month, year string) ([]*LogTransProductCatalog, error)
+	GetLogTransByCompanyAPI(jobId, productId, companyId, pricingStrategy, productSlug, applyDedup, dedupWindowDays, month, year string) ([]*LogTransProductCatalog, error)
+	GetLogTransByCompanyRangeAPI(filter *CompanyLogFilter) ([]*LogTransProductCatalog, error)
+	GetLogTransAPI(companyId, transId string) (*LogTransProductCatalog, error)
 	ProcessedLogCountAPI(jobId string) (*getProcessedCountResp, error)
 	UpdateLogTransAPI(transId string, req map[string]interface{}) error
 }
@@ -8,6 +8,7 @@ import (
 	"front-office/pkg/common/constant"
 	"front-office/pkg/helper"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -67,7 +68,7 @@ func (repo *repository) ProcessedLogCountAPI(jobId string) (*getProcessedCountRe
 	return apiResp.Data, nil
 }
 
-func (repo *repository) GetLogTransByCompanyAPI(jobId, productId, companyId, pricingStrategy, productSlug, applyDedup, month, year string) ([]*LogTransProductCatalog, error) {
+func (repo *repository) GetLogTransByCompanyAPI(jobId, productId, companyId, pricingStrategy, productSlug, applyDedup, dedupWindowDays, month, year string) ([]*LogTransProductCatalog, error) {
 	url := fmt.Sprintf("%s/api/core/logging/transaction/product-catalog/by-company", repo.cfg.App.AifcoreHost)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -87,6 +88,7 @@ func (repo *repository) GetLogTransByCompanyAPI(jobId, productId, companyId, pri
 	q.Add("pricing_strategy", pricingStrategy)
 	q.Add("product_slug", productSlug)
 	q.Add("apply_dedup", applyDedup)
+	q.Add("dedup_window_days", dedupWindowDays)
 	q.Add("month", month)
 	q.Add("year", year)
 	req.URL.RawQuery = q.Encode()
@@ -105,6 +107,75 @@ func (repo *repository) GetLogTransByCompanyAPI(jobId, productId, companyId, pri
 	return apiResp.Data, nil
 }
 
+func (repo *repository) GetLogTransByCompanyRangeAPI(filter *CompanyLogFilter) ([]*LogTransProductCatalog, error) {
+	url := fmt.Sprintf("%s/api/core/logging/transaction/product-catalog/by-company/range", repo.cfg.App.AifcoreHost)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, filter.CompanyId)
+
+	q := req.URL.Query()
+	q.Add("product_id", filter.ProductId)
+	q.Add("pricing_strategy", filter.PricingStrategy)
+	q.Add("product_slug", filter.ProductSlug)
+	q.Add("apply_dedup", filter.ApplyDedup)
+	q.Add("dedup_window_days", filter.DedupWindowDays)
+	q.Add(constant.StartDate, filter.StartDate)
+	q.Add(constant.EndDate, filter.EndDate)
+	if len(filter.MemberIds) > 0 {
+		q.Add("member_ids", strings.Join(filter.MemberIds, ","))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*LogTransProductCatalog](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetLogTransAPI(companyId, transId string) (*LogTransProductCatalog, error) {
+	url := fmt.Sprintf("%s/api/core/logging/transaction/product-catalog/%s", repo.cfg.App.AifcoreHost, transId)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, companyId)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*LogTransProductCatalog](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
 func (repo *repository) UpdateLogTransAPI(transId string, payload map[string]interface{}) error {
 	url := fmt.Sprintf("%s/api/core/logging/transaction/product-catalog/%s", repo.cfg.App.AifcoreHost, transId)
 
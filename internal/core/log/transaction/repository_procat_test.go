@@ -32,7 +32,7 @@ func TestGetLogTransByCompanyAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "1", "2026")
+		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "30", "1", "2026")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -45,7 +45,7 @@ func TestGetLogTransByCompanyAPI(t *testing.T) {
 			App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
 		}, mockClient, nil)
 
-		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "1", "2026")
+		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "30", "1", "2026")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -56,7 +56,7 @@ func TestGetLogTransByCompanyAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, nil, expectedErr)
 
-		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "1", "2026")
+		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "30", "1", "2026")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -72,7 +72,7 @@ func TestGetLogTransByCompanyAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "1", "2026")
+		result, err := repo.GetLogTransByCompanyAPI(constant.DummyJobId, constant.DummyProduct, constant.DummyCompanyId, constant.DummyPricingStrategy, constant.SlugLoanRecordChecker, constant.DummyWithDedup, "30", "1", "2026")
 
 		assert.Nil(t, result)
 		assert.Error(t, err)
@@ -80,6 +80,57 @@ func TestGetLogTransByCompanyAPI(t *testing.T) {
 	})
 }
 
+func TestGetLogTransByCompanyRangeAPI(t *testing.T) {
+	filter := &CompanyLogFilter{
+		CompanyId:       constant.DummyCompanyId,
+		ProductId:       constant.DummyProduct,
+		ProductSlug:     constant.SlugLoanRecordChecker,
+		PricingStrategy: constant.DummyPricingStrategy,
+		ApplyDedup:      "false",
+		StartDate:       "2026-01-15",
+		EndDate:         "2026-02-14",
+		MemberIds:       []string{"3", "4"},
+	}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		body, err := json.Marshal(model.AifcoreAPIResponse[[]*LogTransProductCatalog]{
+			Success: true,
+			Data:    []*LogTransProductCatalog{{MemberID: 3}},
+		})
+		require.NoError(t, err)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetLogTransByCompanyRangeAPI(filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		q := req.URL.Query()
+		assert.Equal(t, "2026-01-15", q.Get(constant.StartDate))
+		assert.Equal(t, "2026-02-14", q.Get(constant.EndDate))
+		assert.Equal(t, "3,4", q.Get("member_ids"))
+		assert.Empty(t, q.Get("month"))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetLogTransByCompanyRangeAPI(filter)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+}
+
 func TestProcessedLogCountAPI(t *testing.T) {
 	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
 		mockData := model.AifcoreAPIResponse[*getProcessedCountResp]{
@@ -147,6 +198,74 @@ func TestProcessedLogCountAPI(t *testing.T) {
 	})
 }
 
+func TestGetLogTransAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		mockData := model.AifcoreAPIResponse[*LogTransProductCatalog]{
+			Success: true,
+			Data:    &LogTransProductCatalog{TransactionID: "CNR-1"},
+		}
+		body, err := json.Marshal(mockData)
+		require.NoError(t, err)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetLogTransAPI(constant.DummyCompanyId, "CNR-1")
+
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, "CNR-1", result.TransactionID)
+
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		assert.Equal(t, constant.DummyCompanyId, req.Header.Get(constant.XCompanyId))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		mockClient := new(MockClient)
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+		}, mockClient, nil)
+
+		result, err := repo.GetLogTransAPI(constant.DummyCompanyId, "CNR-1")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
+
+		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+
+		result, err := repo.GetLogTransAPI(constant.DummyCompanyId, "CNR-1")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetLogTransAPI(constant.DummyCompanyId, "CNR-1")
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
 func TestCreateLogTransAPI(t *testing.T) {
 	addLogReq := &LogTransProCatRequest{}
 
@@ -152,3 +152,32 @@ func (repo *repository) GetLogsScoreezyByMonthAPI(companyId, month string) ([]*L
 
 	return apiResp.Data, nil
 }
+
+func (repo *repository) UpdateLogScoreezyAPI(trxId string, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/core/logging/transaction/scoreezy/%s", repo.cfg.App.AifcoreHost, trxId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -271,3 +271,71 @@ func TestGetLogsScoreezyAPI(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestUpdateLogScoreezyAPI(t *testing.T) {
+	updateLogReq := map[string]interface{}{"decision": "reject"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		mockData := model.AifcoreAPIResponse[any]{
+			Success: true,
+		}
+		body, err := json.Marshal(mockData)
+		require.NoError(t, err)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err = repo.UpdateLogScoreezyAPI(constant.DummyTransactionId, updateLogReq)
+
+		assert.NoError(t, err)
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "/api/core/logging/transaction/scoreezy/"+constant.DummyTransactionId, req.URL.Path)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		fakeMarshal := func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		}
+
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockHost},
+		}, &MockClient{}, fakeMarshal)
+
+		err := repo.UpdateLogScoreezyAPI(constant.DummyTransactionId, updateLogReq)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
+
+		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+
+		err := repo.UpdateLogScoreezyAPI(constant.DummyTransactionId, updateLogReq)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.UpdateLogScoreezyAPI(constant.DummyTransactionId, updateLogReq)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,61 @@
+package passwordpolicy
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(service Service) Controller {
+	return &controller{svc: service}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	GetPolicy(c *fiber.Ctx) error
+	UpdatePolicy(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) GetPolicy(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	policy, err := ctrl.svc.GetPolicy(authCtx.CompanyIdStr())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get password policy",
+		policy,
+	))
+}
+
+func (ctrl *controller) UpdatePolicy(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*updatePolicyRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	policy, err := ctrl.svc.UpdatePolicy(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to update password policy",
+		policy,
+	))
+}
@@ -0,0 +1,20 @@
+package passwordpolicy
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupInit(userAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+	repo := NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	service := NewService(cfg, repo, operationRepo)
+	controller := NewController(service)
+
+	userAPI.Get("/password-policy", middleware.GetJWTPayloadFromCookie(cfg), controller.GetPolicy)
+	userAPI.Put("/password-policy", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(updatePolicyRequest{}), controller.UpdatePolicy)
+}
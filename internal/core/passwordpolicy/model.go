@@ -0,0 +1,147 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultMinLength         = 8
+	maxMinLength             = 128
+	maxHistoryDepth          = 24
+	defaultExpiryWarningDays = 7
+)
+
+type PasswordPolicy struct {
+	CompanyId         uint `json:"company_id"`
+	MinLength         int  `json:"min_length"`
+	RequireUppercase  bool `json:"require_uppercase"`
+	RequireLowercase  bool `json:"require_lowercase"`
+	RequireNumber     bool `json:"require_number"`
+	RequireSymbol     bool `json:"require_symbol"`
+	MaxAgeDays        int  `json:"max_age_days"`
+	HistoryDepth      int  `json:"history_depth"`
+	ExpiryWarningDays int  `json:"expiry_warning_days"`
+}
+
+type updatePolicyRequest struct {
+	MinLength         *int  `json:"min_length"`
+	RequireUppercase  *bool `json:"require_uppercase"`
+	RequireLowercase  *bool `json:"require_lowercase"`
+	RequireNumber     *bool `json:"require_number"`
+	RequireSymbol     *bool `json:"require_symbol"`
+	MaxAgeDays        *int  `json:"max_age_days"`
+	HistoryDepth      *int  `json:"history_depth"`
+	ExpiryWarningDays *int  `json:"expiry_warning_days"`
+}
+
+type passwordHistory struct {
+	PasswordHash string    `json:"password_hash"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type addPasswordHistoryRequest struct {
+	PasswordHash string `json:"password_hash"`
+	Depth        int    `json:"depth"`
+}
+
+type PasswordExpiry struct {
+	ExpiresAt *time.Time
+	DaysLeft  int
+	Expired   bool
+	Warning   bool
+}
+
+// Validate checks the password against the character rules of the policy.
+// Whitespace and control characters are never accepted.
+func (p *PasswordPolicy) Validate(password string) error {
+	var upp, low, num, sym bool
+
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			low = true
+		case unicode.IsUpper(char):
+			upp = true
+		case unicode.IsNumber(char):
+			num = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			sym = true
+		default:
+			return fmt.Errorf("password contains characters that are not allowed")
+		}
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("password must have at least %d characters", p.MinLength)
+	}
+
+	if (p.RequireUppercase && !upp) || (p.RequireLowercase && !low) ||
+		(p.RequireNumber && !num) || (p.RequireSymbol && !sym) {
+		return fmt.Errorf("password must contain at least one of each: %s", p.describeClasses())
+	}
+
+	return nil
+}
+
+// IsReused reports whether password matches any of the given bcrypt hashes,
+// only the newest HistoryDepth entries are considered.
+func (p *PasswordPolicy) IsReused(password string, hashes []string) bool {
+	if p.HistoryDepth <= 0 {
+		return false
+	}
+
+	for i, hash := range hashes {
+		if i >= p.HistoryDepth {
+			break
+		}
+
+		if hash == "" {
+			continue
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *PasswordPolicy) Expiry(changedAt *time.Time, now time.Time) *PasswordExpiry {
+	if p.MaxAgeDays <= 0 || changedAt == nil || changedAt.IsZero() {
+		return &PasswordExpiry{}
+	}
+
+	expiresAt := changedAt.AddDate(0, 0, p.MaxAgeDays)
+	daysLeft := int(expiresAt.Sub(now).Hours() / 24)
+
+	return &PasswordExpiry{
+		ExpiresAt: &expiresAt,
+		DaysLeft:  daysLeft,
+		Expired:   !now.Before(expiresAt),
+		Warning:   now.Before(expiresAt) && daysLeft < p.ExpiryWarningDays,
+	}
+}
+
+func (p *PasswordPolicy) describeClasses() string {
+	var classes []string
+	if p.RequireUppercase {
+		classes = append(classes, "uppercase")
+	}
+	if p.RequireLowercase {
+		classes = append(classes, "lowercase")
+	}
+	if p.RequireNumber {
+		classes = append(classes, "number")
+	}
+	if p.RequireSymbol {
+		classes = append(classes, "symbol")
+	}
+
+	return strings.Join(classes, ", ")
+}
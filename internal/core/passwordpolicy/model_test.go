@@ -0,0 +1,77 @@
+package passwordpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func strictPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:         10,
+		RequireUppercase:  true,
+		RequireLowercase:  true,
+		RequireNumber:     true,
+		RequireSymbol:     true,
+		MaxAgeDays:        90,
+		HistoryDepth:      2,
+		ExpiryWarningDays: 7,
+	}
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := strictPolicy()
+
+	assert.NoError(t, policy.Validate("Str0ng!Pass"))
+	assert.Error(t, policy.Validate("Sh0rt!"))
+	assert.Error(t, policy.Validate("nouppercase1!"))
+	assert.Error(t, policy.Validate("Has Space1!x"))
+
+	policy.RequireSymbol = false
+	assert.NoError(t, policy.Validate("NoSymbol123"))
+}
+
+func TestPasswordPolicyIsReused(t *testing.T) {
+	policy := strictPolicy()
+
+	hash := func(p string) string {
+		h, _ := bcrypt.GenerateFromPassword([]byte(p), bcrypt.MinCost)
+		return string(h)
+	}
+	hashes := []string{hash("Current1!pass"), hash("Previous1!pass"), hash("Oldest1!pass")}
+
+	assert.True(t, policy.IsReused("Current1!pass", hashes))
+	assert.True(t, policy.IsReused("Previous1!pass", hashes))
+	assert.False(t, policy.IsReused("Oldest1!pass", hashes))
+	assert.False(t, policy.IsReused("Brand1!New", hashes))
+
+	policy.HistoryDepth = 0
+	assert.False(t, policy.IsReused("Current1!pass", hashes))
+}
+
+func TestPasswordPolicyExpiry(t *testing.T) {
+	policy := strictPolicy()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	fresh := now.AddDate(0, 0, -10)
+	expiry := policy.Expiry(&fresh, now)
+	assert.False(t, expiry.Expired)
+	assert.False(t, expiry.Warning)
+
+	nearlyExpired := now.AddDate(0, 0, -85)
+	expiry = policy.Expiry(&nearlyExpired, now)
+	assert.False(t, expiry.Expired)
+	assert.True(t, expiry.Warning)
+	assert.Equal(t, 5, expiry.DaysLeft)
+
+	expired := now.AddDate(0, 0, -91)
+	expiry = policy.Expiry(&expired, now)
+	assert.True(t, expiry.Expired)
+
+	policy.MaxAgeDays = 0
+	expiry = policy.Expiry(&expired, now)
+	assert.False(t, expiry.Expired)
+	assert.Nil(t, expiry.ExpiresAt)
+}
@@ -0,0 +1,150 @@
+package passwordpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+	"strconv"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	GetPolicyAPI(companyId string) (*PasswordPolicy, error)
+	UpdatePolicyAPI(companyId string, payload map[string]interface{}) error
+	GetPasswordHistoryAPI(memberId string, depth int) ([]passwordHistory, error)
+	AddPasswordHistoryAPI(memberId string, payload *addPasswordHistoryRequest) error
+}
+
+func (repo *repository) GetPolicyAPI(companyId string) (*PasswordPolicy, error) {
+	url := fmt.Sprintf("%s/api/core/company/%s/password-policy", repo.cfg.App.AifcoreHost, companyId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*PasswordPolicy](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpdatePolicyAPI(companyId string, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/core/company/%s/password-policy", repo.cfg.App.AifcoreHost, companyId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *repository) GetPasswordHistoryAPI(memberId string, depth int) ([]passwordHistory, error) {
+	url := fmt.Sprintf("%s/api/core/member/%s/password-history", repo.cfg.App.AifcoreHost, memberId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add(constant.Size, strconv.Itoa(depth))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]passwordHistory](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) AddPasswordHistoryAPI(memberId string, payload *addPasswordHistoryRequest) error {
+	url := fmt.Sprintf("%s/api/core/member/%s/password-history", repo.cfg.App.AifcoreHost, memberId)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package passwordpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockClient struct {
+	mock.Mock
+}
+
+func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+	t.Helper()
+
+	mockClient := new(MockClient)
+	mockClient.On("Do", mock.Anything).Return(response, err)
+
+	repo := NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockHost},
+	}, mockClient, nil)
+
+	return repo, mockClient
+}
+
+func jsonResponse(t *testing.T, data any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func TestGetPolicyAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*PasswordPolicy]{
+			Success: true,
+			Data:    &PasswordPolicy{CompanyId: 1, MinLength: 12},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetPolicyAPI(constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, result.MinLength)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+		}, new(MockClient), nil)
+
+		result, err := repo.GetPolicyAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetPolicyAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetPolicyAPI(constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestUpdatePolicyAPI(t *testing.T) {
+	payload := map[string]interface{}{"min_length": 10}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[any]{Success: true})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.UpdatePolicyAPI(constant.DummyCompanyId, payload)
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockHost},
+		}, new(MockClient), func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		err := repo.UpdatePolicyAPI(constant.DummyCompanyId, payload)
+
+		assert.Error(t, err)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		err := repo.UpdatePolicyAPI(constant.DummyCompanyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetPasswordHistoryAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[[]passwordHistory]{
+			Success: true,
+			Data:    []passwordHistory{{PasswordHash: "hash-1"}, {PasswordHash: "hash-2"}},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetPasswordHistoryAPI(constant.DummyMemberId, 5)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetPasswordHistoryAPI(constant.DummyMemberId, 5)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestAddPasswordHistoryAPI(t *testing.T) {
+	payload := &addPasswordHistoryRequest{PasswordHash: "hash", Depth: 5}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[any]{Success: true})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.AddPasswordHistoryAPI(constant.DummyMemberId, payload)
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		err := repo.AddPasswordHistoryAPI(constant.DummyMemberId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,231 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func NewService(
+	cfg *application.Config,
+	repo Repository,
+	operationRepo operation.Repository,
+) Service {
+	return &service{
+		cfg,
+		repo,
+		operationRepo,
+	}
+}
+
+type service struct {
+	cfg           *application.Config
+	repo          Repository
+	operationRepo operation.Repository
+}
+
+type Service interface {
+	GetPolicy(companyId string) (*PasswordPolicy, error)
+	ResolvePolicy(companyId string) *PasswordPolicy
+	UpdatePolicy(authCtx *model.AuthContext, req *updatePolicyRequest) (*PasswordPolicy, error)
+	ValidateNewPassword(companyId, memberId, currentHash, password string) error
+	RecordPassword(companyId, memberId, password string)
+	CheckExpiry(companyId string, changedAt *time.Time) *PasswordExpiry
+}
+
+func (svc *service) GetPolicy(companyId string) (*PasswordPolicy, error) {
+	policy, err := svc.repo.GetPolicyAPI(companyId)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch password policy")
+	}
+
+	if policy == nil || policy.MinLength == 0 {
+		return svc.defaultPolicy(companyId), nil
+	}
+
+	return policy, nil
+}
+
+// ResolvePolicy never fails, it falls back to the environment defaults when
+// the company has no policy or the core service cannot be reached.
+func (svc *service) ResolvePolicy(companyId string) *PasswordPolicy {
+	policy, err := svc.GetPolicy(companyId)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("company_id", companyId).
+			Msg("failed to fetch password policy, using default policy")
+
+		return svc.defaultPolicy(companyId)
+	}
+
+	return policy
+}
+
+func (svc *service) UpdatePolicy(authCtx *model.AuthContext, req *updatePolicyRequest) (*PasswordPolicy, error) {
+	policy, err := svc.GetPolicy(authCtx.CompanyIdStr())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MinLength != nil {
+		if *req.MinLength < defaultMinLength || *req.MinLength > maxMinLength {
+			return nil, apperror.BadRequest(fmt.Sprintf("min_length must be between %d and %d", defaultMinLength, maxMinLength))
+		}
+		policy.MinLength = *req.MinLength
+	}
+	if req.RequireUppercase != nil {
+		policy.RequireUppercase = *req.RequireUppercase
+	}
+	if req.RequireLowercase != nil {
+		policy.RequireLowercase = *req.RequireLowercase
+	}
+	if req.RequireNumber != nil {
+		policy.RequireNumber = *req.RequireNumber
+	}
+	if req.RequireSymbol != nil {
+		policy.RequireSymbol = *req.RequireSymbol
+	}
+	if req.MaxAgeDays != nil {
+		if *req.MaxAgeDays < 0 {
+			return nil, apperror.BadRequest("max_age_days must not be negative")
+		}
+		policy.MaxAgeDays = *req.MaxAgeDays
+	}
+	if req.HistoryDepth != nil {
+		if *req.HistoryDepth < 0 || *req.HistoryDepth > maxHistoryDepth {
+			return nil, apperror.BadRequest(fmt.Sprintf("history_depth must be between 0 and %d", maxHistoryDepth))
+		}
+		policy.HistoryDepth = *req.HistoryDepth
+	}
+	if req.ExpiryWarningDays != nil {
+		if *req.ExpiryWarningDays < 0 {
+			return nil, apperror.BadRequest("expiry_warning_days must not be negative")
+		}
+		policy.ExpiryWarningDays = *req.ExpiryWarningDays
+	}
+
+	updateFields := map[string]interface{}{
+		"min_length":          policy.MinLength,
+		"require_uppercase":   policy.RequireUppercase,
+		"require_lowercase":   policy.RequireLowercase,
+		"require_number":      policy.RequireNumber,
+		"require_symbol":      policy.RequireSymbol,
+		"max_age_days":        policy.MaxAgeDays,
+		"history_depth":       policy.HistoryDepth,
+		"expiry_warning_days": policy.ExpiryWarningDays,
+		"updated_at":          time.Now(),
+	}
+
+	if err := svc.repo.UpdatePolicyAPI(authCtx.CompanyIdStr(), updateFields); err != nil {
+		return nil, apperror.MapRepoError(err, "failed to update password policy")
+	}
+
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Action:    constant.EventUpdatePasswordPolicy,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", constant.EventUpdatePasswordPolicy).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+
+	return policy, nil
+}
+
+func (svc *service) ValidateNewPassword(companyId, memberId, currentHash, password string) error {
+	policy := svc.ResolvePolicy(companyId)
+
+	if err := policy.Validate(password); err != nil {
+		return apperror.BadRequest(err.Error())
+	}
+
+	if policy.HistoryDepth <= 0 {
+		return nil
+	}
+
+	history, err := svc.repo.GetPasswordHistoryAPI(memberId, policy.HistoryDepth)
+	if err != nil {
+		return apperror.MapRepoError(err, "failed to fetch password history")
+	}
+
+	hashes := make([]string, 0, len(history)+1)
+	if currentHash != "" {
+		hashes = append(hashes, currentHash)
+	}
+	for _, entry := range history {
+		hashes = append(hashes, entry.PasswordHash)
+	}
+
+	if policy.IsReused(password, hashes) {
+		return apperror.BadRequest(fmt.Sprintf(constant.PasswordRecentlyUsed, policy.HistoryDepth))
+	}
+
+	return nil
+}
+
+func (svc *service) RecordPassword(companyId, memberId, password string) {
+	policy := svc.ResolvePolicy(companyId)
+	if policy.HistoryDepth <= 0 {
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("member_id", memberId).
+			Msg("failed to hash password for history")
+
+		return
+	}
+
+	if err := svc.repo.AddPasswordHistoryAPI(memberId, &addPasswordHistoryRequest{
+		PasswordHash: string(hash),
+		Depth:        policy.HistoryDepth,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("member_id", memberId).
+			Msg("failed to record password history")
+	}
+}
+
+func (svc *service) CheckExpiry(companyId string, changedAt *time.Time) *PasswordExpiry {
+	return svc.ResolvePolicy(companyId).Expiry(changedAt, time.Now())
+}
+
+func (svc *service) defaultPolicy(companyId string) *PasswordPolicy {
+	id, _ := strconv.ParseUint(companyId, 10, 64)
+
+	return &PasswordPolicy{
+		CompanyId:         uint(id),
+		MinLength:         envInt(svc.cfg.App.PasswordMinLength, defaultMinLength),
+		RequireUppercase:  true,
+		RequireLowercase:  true,
+		RequireNumber:     true,
+		RequireSymbol:     true,
+		MaxAgeDays:        envInt(svc.cfg.App.PasswordMaxAgeDays, 0),
+		HistoryDepth:      envInt(svc.cfg.App.PasswordHistoryDepth, 0),
+		ExpiryWarningDays: envInt(svc.cfg.App.PasswordExpiryWarningDays, defaultExpiryWarningDays),
+	}
+}
+
+func envInt(value string, fallback int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fallback
+	}
+
+	return n
+}
@@ -0,0 +1,163 @@
+package privacy
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(service Service) Controller {
+	return &controller{svc: service}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	ExportMyData(c *fiber.Ctx) error
+	RequestDeletion(c *fiber.Ctx) error
+	GetMyDeletionRequests(c *fiber.Ctx) error
+	GetDeletionRequests(c *fiber.Ctx) error
+	ApproveDeletion(c *fiber.Ctx) error
+	RejectDeletion(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) ExportMyData(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.ExportMemberData(authCtx)
+	if err != nil {
+		return err
+	}
+
+	c.Set(constant.HeaderContentType, result.ContentType)
+	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
+	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+
+	return c.Send(result.Data)
+}
+
+func (ctrl *controller) RequestDeletion(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*createDeletionRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.RequestDeletion(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(helper.SuccessResponse(
+		"succeed to submit deletion request",
+		result,
+	))
+}
+
+func (ctrl *controller) GetMyDeletionRequests(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.GetMyDeletionRequests(authCtx)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get deletion requests",
+		result,
+	))
+}
+
+func (ctrl *controller) GetDeletionRequests(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	status := c.Query(constant.Status)
+	if status != "" && status != deletionStatusPending && status != deletionStatusApproved && status != deletionStatusRejected {
+		return apperror.BadRequest(constant.InvalidStatusValue)
+	}
+
+	result, err := ctrl.svc.GetDeletionRequests(&deletionRequestFilter{
+		CompanyId: authCtx.CompanyIdStr(),
+		Status:    status,
+		Page:      c.Query(constant.Page, "1"),
+		Size:      c.Query(constant.Size, "10"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		"succeed to get deletion requests",
+		result,
+	))
+}
+
+func (ctrl *controller) ApproveDeletion(c *fiber.Ctx) error {
+	reqBody, authCtx, id, err := parseReviewRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.svc.ApproveDeletion(authCtx, id, reqBody); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+		"succeed to approve deletion request",
+		nil,
+	))
+}
+
+func (ctrl *controller) RejectDeletion(c *fiber.Ctx) error {
+	reqBody, authCtx, id, err := parseReviewRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.svc.RejectDeletion(authCtx, id, reqBody); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+		"succeed to reject deletion request",
+		nil,
+	))
+}
+
+func parseReviewRequest(c *fiber.Ctx) (*reviewDeletionRequest, *model.AuthContext, string, error) {
+	reqBody, ok := c.Locals(constant.Request).(*reviewDeletionRequest)
+	if !ok {
+		return nil, nil, "", apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return nil, nil, "", apperror.Unauthorized(err.Error())
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return nil, nil, "", apperror.BadRequest("missing deletion request id")
+	}
+
+	return reqBody, authCtx, id, nil
+}
@@ -0,0 +1,27 @@
+package privacy
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/mail"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupInit(userAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient, mailSvc *mail.SendMailService) {
+	repo := NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	service := NewService(repo, memberRepo, operationRepo, mailSvc)
+	controller := NewController(service)
+
+	userAPI.Get("/me/data-export", middleware.GetJWTPayloadFromCookie(cfg), controller.ExportMyData)
+	userAPI.Get("/me/deletion-requests", middleware.GetJWTPayloadFromCookie(cfg), controller.GetMyDeletionRequests)
+	userAPI.Post("/me/deletion-requests", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(createDeletionRequest{}), controller.RequestDeletion)
+	userAPI.Get("/deletion-requests", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetDeletionRequests)
+	userAPI.Put("/deletion-requests/:id/approve", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(reviewDeletionRequest{}), controller.ApproveDeletion)
+	userAPI.Put("/deletion-requests/:id/reject", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(reviewDeletionRequest{}), controller.RejectDeletion)
+}
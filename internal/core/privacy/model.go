@@ -0,0 +1,97 @@
+package privacy
+
+import "time"
+
+const (
+	deletionStatusPending  = "pending"
+	deletionStatusApproved = "approved"
+	deletionStatusRejected = "rejected"
+)
+
+type DeletionRequest struct {
+	Id         uint       `json:"id"`
+	MemberId   uint       `json:"member_id"`
+	CompanyId  uint       `json:"company_id"`
+	MemberName string     `json:"member_name"`
+	Email      string     `json:"email"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	ReviewedBy uint       `json:"reviewed_by"`
+	ReviewNote string     `json:"review_note"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+type deletionRequestListResponse struct {
+	Requests  []*DeletionRequest `json:"requests"`
+	TotalData int64              `json:"total_data"`
+}
+
+type createDeletionRequest struct {
+	Reason string `json:"reason" validate:"required~Field Reason is required"`
+}
+
+type reviewDeletionRequest struct {
+	Note string `json:"note"`
+}
+
+type createDeletionPayload struct {
+	MemberId   uint   `json:"member_id"`
+	CompanyId  uint   `json:"company_id"`
+	MemberName string `json:"member_name"`
+	Email      string `json:"email"`
+	Reason     string `json:"reason"`
+	Status     string `json:"status"`
+}
+
+type deletionRequestFilter struct {
+	CompanyId string
+	MemberId  string
+	Status    string
+	Page      string
+	Size      string
+}
+
+type memberJob struct {
+	JobId        uint   `json:"job_id"`
+	ProductId    uint   `json:"product_id"`
+	ProductSlug  string `json:"product_slug"`
+	Total        int    `json:"total"`
+	SuccessCount int    `json:"success_count"`
+	Status       string `json:"status"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+}
+
+type memberProfile struct {
+	MemberId    uint      `json:"member_id"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	CompanyId   uint      `json:"company_id"`
+	CompanyName string    `json:"company_name"`
+	Active      bool      `json:"active"`
+	MailStatus  string    `json:"mail_status"`
+	IsVerified  bool      `json:"is_verified"`
+	Image       string    `json:"image"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type memberRole struct {
+	RoleId uint   `json:"role_id"`
+	Name   string `json:"name"`
+}
+
+type dataExportResult struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type dataExportManifest struct {
+	MemberId   uint      `json:"member_id"`
+	CompanyId  uint      `json:"company_id"`
+	ExportedAt time.Time `json:"exported_at"`
+	Files      []string  `json:"files"`
+}
@@ -0,0 +1,218 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	GetMemberOperationLogsAPI(memberId, companyId string) ([]operation.LogOperation, error)
+	GetMemberJobsAPI(memberId, companyId string) ([]memberJob, error)
+	CreateDeletionRequestAPI(payload *createDeletionPayload) (*DeletionRequest, error)
+	GetDeletionRequestsAPI(filter *deletionRequestFilter) (*deletionRequestListResponse, error)
+	GetDeletionRequestAPI(id, companyId string) (*DeletionRequest, error)
+	UpdateDeletionRequestAPI(id, companyId string, payload map[string]interface{}) error
+}
+
+func (repo *repository) GetMemberOperationLogsAPI(memberId, companyId string) ([]operation.LogOperation, error) {
+	url := fmt.Sprintf("%s/api/core/logging/operation/member/%s", repo.cfg.App.AifcoreHost, memberId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, companyId)
+
+	q := req.URL.Query()
+	q.Add(constant.Size, constant.SizeUnlimited)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]operation.LogOperation](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetMemberJobsAPI(memberId, companyId string) ([]memberJob, error) {
+	url := fmt.Sprintf("%s/api/core/product/jobs/member/%s", repo.cfg.App.AifcoreHost, memberId)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XMemberId, memberId)
+	req.Header.Set(constant.XCompanyId, companyId)
+
+	q := req.URL.Query()
+	q.Add(constant.Size, constant.SizeUnlimited)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]memberJob](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) CreateDeletionRequestAPI(payload *createDeletionPayload) (*DeletionRequest, error) {
+	url := fmt.Sprintf("%s/api/core/member/deletion-requests", repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*DeletionRequest](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetDeletionRequestsAPI(filter *deletionRequestFilter) (*deletionRequestListResponse, error) {
+	url := fmt.Sprintf("%s/api/core/member/deletion-requests", repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, filter.CompanyId)
+
+	q := req.URL.Query()
+	q.Add("company_id", filter.CompanyId)
+	q.Add("member_id", filter.MemberId)
+	q.Add(constant.Status, filter.Status)
+	q.Add(constant.Page, filter.Page)
+	q.Add(constant.Size, filter.Size)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*deletionRequestListResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetDeletionRequestAPI(id, companyId string) (*DeletionRequest, error) {
+	url := fmt.Sprintf("%s/api/core/member/deletion-requests/%s", repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, companyId)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*DeletionRequest](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpdateDeletionRequestAPI(id, companyId string, payload map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/core/member/deletion-requests/%s", repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XCompanyId, companyId)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
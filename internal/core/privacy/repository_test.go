@@ -0,0 +1,337 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockClient struct {
+	mock.Mock
+}
+
+func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+	t.Helper()
+
+	mockClient := new(MockClient)
+	mockClient.On("Do", mock.Anything).Return(response, err)
+
+	repo := NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockHost},
+	}, mockClient, nil)
+
+	return repo, mockClient
+}
+
+func newInvalidHostRepo(marshalFn func(v any) ([]byte, error)) Repository {
+	return NewRepository(&application.Config{
+		App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+	}, new(MockClient), marshalFn)
+}
+
+func jsonResponse(t *testing.T, data any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func invalidJSONResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+	}
+}
+
+func TestGetMemberOperationLogsAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[[]operation.LogOperation]{
+			Success: true,
+			Data:    []operation.LogOperation{{LogOpsID: 1, Action: constant.EventSignIn}},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetMemberOperationLogsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetMemberOperationLogsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetMemberOperationLogsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetMemberOperationLogsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetMemberJobsAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[[]memberJob]{
+			Success: true,
+			Data:    []memberJob{{JobId: 1, Status: constant.JobStatusDone}},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetMemberJobsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetMemberJobsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetMemberJobsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetMemberJobsAPI(constant.DummyMemberId, constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestCreateDeletionRequestAPI(t *testing.T) {
+	payload := &createDeletionPayload{MemberId: 1, CompanyId: 1, Status: deletionStatusPending}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*DeletionRequest]{
+			Success: true,
+			Data:    &DeletionRequest{Id: 1, Status: deletionStatusPending},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.CreateDeletionRequestAPI(payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), result.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		result, err := repo.CreateDeletionRequestAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).CreateDeletionRequestAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.CreateDeletionRequestAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.CreateDeletionRequestAPI(payload)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetDeletionRequestsAPI(t *testing.T) {
+	filter := &deletionRequestFilter{CompanyId: constant.DummyCompanyId, Status: deletionStatusPending}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*deletionRequestListResponse]{
+			Success: true,
+			Data: &deletionRequestListResponse{
+				Requests:  []*DeletionRequest{{Id: 1}},
+				TotalData: 1,
+			},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetDeletionRequestsAPI(filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.TotalData)
+
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		assert.Equal(t, constant.DummyCompanyId, req.Header.Get(constant.XCompanyId))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetDeletionRequestsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetDeletionRequestsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, invalidJSONResponse(), nil)
+
+		result, err := repo.GetDeletionRequestsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetDeletionRequestAPI(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[*DeletionRequest]{
+			Success: true,
+			Data:    &DeletionRequest{Id: 1},
+		})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetDeletionRequestAPI("1", constant.DummyCompanyId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), result.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		result, err := newInvalidHostRepo(nil).GetDeletionRequestAPI("1", constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		result, err := repo.GetDeletionRequestAPI("1", constant.DummyCompanyId)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestUpdateDeletionRequestAPI(t *testing.T) {
+	payload := map[string]interface{}{"status": deletionStatusApproved}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		resp := jsonResponse(t, model.AifcoreAPIResponse[any]{Success: true})
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		err := repo.UpdateDeletionRequestAPI("1", constant.DummyCompanyId, payload)
+
+		assert.NoError(t, err)
+
+		req := mockClient.Calls[0].Arguments.Get(0).(*http.Request)
+		assert.Equal(t, constant.DummyCompanyId, req.Header.Get(constant.XCompanyId))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseMarshalError, func(t *testing.T) {
+		repo := newInvalidHostRepo(func(v any) ([]byte, error) {
+			return nil, errors.New(constant.ErrInvalidRequestPayload)
+		})
+
+		err := repo.UpdateDeletionRequestAPI("1", constant.DummyCompanyId, payload)
+
+		assert.Error(t, err)
+		assert.Equal(t, constant.ErrInvalidRequestPayload, err.Error())
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		repo, mockClient := setupMockRepo(t, nil, errors.New(constant.ErrUpstreamUnavailable))
+
+		err := repo.UpdateDeletionRequestAPI("1", constant.DummyCompanyId, payload)
+
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,346 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/mail"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(
+	repo Repository,
+	memberRepo member.Repository,
+	operationRepo operation.Repository,
+	mailSvc *mail.SendMailService,
+) Service {
+	return &service{
+		repo,
+		memberRepo,
+		operationRepo,
+		mailSvc,
+	}
+}
+
+type service struct {
+	repo          Repository
+	memberRepo    member.Repository
+	operationRepo operation.Repository
+	mailSvc       *mail.SendMailService
+}
+
+type Service interface {
+	ExportMemberData(authCtx *model.AuthContext) (*dataExportResult, error)
+	RequestDeletion(authCtx *model.AuthContext, req *createDeletionRequest) (*DeletionRequest, error)
+	GetMyDeletionRequests(authCtx *model.AuthContext) (*deletionRequestListResponse, error)
+	GetDeletionRequests(filter *deletionRequestFilter) (*deletionRequestListResponse, error)
+	ApproveDeletion(authCtx *model.AuthContext, id string, req *reviewDeletionRequest) error
+	RejectDeletion(authCtx *model.AuthContext, id string, req *reviewDeletionRequest) error
+}
+
+func (svc *service) ExportMemberData(authCtx *model.AuthContext) (*dataExportResult, error) {
+	memberId, companyId := authCtx.IDs()
+
+	user, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{Id: memberId, CompanyId: companyId})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+	if user.MemberId == 0 {
+		return nil, apperror.NotFound(constant.UserNotFound)
+	}
+
+	logs, err := svc.repo.GetMemberOperationLogsAPI(memberId, companyId)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchLogs)
+	}
+
+	jobs, err := svc.repo.GetMemberJobsAPI(memberId, companyId)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to fetch member jobs")
+	}
+
+	exportedAt := time.Now()
+	files := map[string]any{
+		"profile.json": &memberProfile{
+			MemberId:    user.MemberId,
+			Name:        user.Name,
+			Username:    user.Username,
+			Email:       user.Email,
+			Phone:       user.Phone,
+			CompanyId:   user.CompanyId,
+			CompanyName: user.MstCompany.CompanyName,
+			Active:      user.Active,
+			MailStatus:  user.MailStatus,
+			IsVerified:  user.IsVerified,
+			Image:       user.Image,
+			CreatedAt:   user.CreatedAt,
+		},
+		"role.json": &memberRole{
+			RoleId: user.RoleId,
+			Name:   user.Role.Name,
+		},
+		"operation_logs.json": logs,
+		"jobs.json":           jobs,
+	}
+
+	data, err := buildExportArchive(files, &dataExportManifest{
+		MemberId:   user.MemberId,
+		CompanyId:  user.CompanyId,
+		ExportedAt: exportedAt,
+		Files:      exportFileOrder,
+	})
+	if err != nil {
+		return nil, apperror.Internal("failed to build data export archive", err)
+	}
+
+	svc.addLogOperation(authCtx.UserId, authCtx.CompanyId, constant.EventRequestDataExport)
+
+	return &dataExportResult{
+		Filename:    fmt.Sprintf("member_data_%d_%s.zip", user.MemberId, exportedAt.Format("20060102150405")),
+		ContentType: constant.MimeZip,
+		Data:        data,
+	}, nil
+}
+
+func (svc *service) RequestDeletion(authCtx *model.AuthContext, req *createDeletionRequest) (*DeletionRequest, error) {
+	memberId, companyId := authCtx.IDs()
+
+	user, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{Id: memberId, CompanyId: companyId})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMember)
+	}
+	if user.MemberId == 0 {
+		return nil, apperror.NotFound(constant.UserNotFound)
+	}
+
+	existing, err := svc.repo.GetDeletionRequestsAPI(&deletionRequestFilter{
+		CompanyId: companyId,
+		MemberId:  memberId,
+		Status:    deletionStatusPending,
+		Page:      "1",
+		Size:      "1",
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchDeletionRequest)
+	}
+	if existing != nil && existing.TotalData > 0 {
+		return nil, apperror.Conflict(constant.DeletionRequestAlreadyPending)
+	}
+
+	deletionReq, err := svc.repo.CreateDeletionRequestAPI(&createDeletionPayload{
+		MemberId:   user.MemberId,
+		CompanyId:  user.CompanyId,
+		MemberName: user.Name,
+		Email:      user.Email,
+		Reason:     req.Reason,
+		Status:     deletionStatusPending,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, "failed to create deletion request")
+	}
+
+	svc.addLogOperation(authCtx.UserId, authCtx.CompanyId, constant.EventRequestAccountDeletion)
+
+	return deletionReq, nil
+}
+
+func (svc *service) GetMyDeletionRequests(authCtx *model.AuthContext) (*deletionRequestListResponse, error) {
+	memberId, companyId := authCtx.IDs()
+
+	return svc.GetDeletionRequests(&deletionRequestFilter{
+		CompanyId: companyId,
+		MemberId:  memberId,
+		Page:      "1",
+		Size:      constant.SizeUnlimited,
+	})
+}
+
+func (svc *service) GetDeletionRequests(filter *deletionRequestFilter) (*deletionRequestListResponse, error) {
+	result, err := svc.repo.GetDeletionRequestsAPI(filter)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchDeletionRequest)
+	}
+
+	return result, nil
+}
+
+func (svc *service) ApproveDeletion(authCtx *model.AuthContext, id string, req *reviewDeletionRequest) error {
+	deletionReq, err := svc.getPendingDeletionRequest(authCtx, id)
+	if err != nil {
+		return err
+	}
+
+	if deletionReq.MemberId == authCtx.UserId {
+		return apperror.Forbidden("you are not allowed to approve your own deletion request")
+	}
+
+	// claimed before deleting so two reviewers cannot both act on it, the
+	// claim is released when the member cannot be deleted
+	if err := svc.markReviewed(authCtx, id, deletionStatusApproved, req.Note); err != nil {
+		return err
+	}
+
+	if err := svc.memberRepo.DeleteMemberAPI(strconv.FormatUint(uint64(deletionReq.MemberId), 10)); err != nil {
+		svc.releaseClaim(authCtx, id)
+		return apperror.MapRepoError(err, "failed to delete member")
+	}
+
+	svc.addLogOperation(authCtx.UserId, authCtx.CompanyId, constant.EventApproveAccountDeletion)
+	svc.sendReviewNotification(deletionReq, deletionStatusApproved, req.Note)
+
+	return nil
+}
+
+func (svc *service) RejectDeletion(authCtx *model.AuthContext, id string, req *reviewDeletionRequest) error {
+	deletionReq, err := svc.getPendingDeletionRequest(authCtx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.markReviewed(authCtx, id, deletionStatusRejected, req.Note); err != nil {
+		return err
+	}
+
+	svc.addLogOperation(authCtx.UserId, authCtx.CompanyId, constant.EventRejectAccountDeletion)
+	svc.sendReviewNotification(deletionReq, deletionStatusRejected, req.Note)
+
+	return nil
+}
+
+func (svc *service) getPendingDeletionRequest(authCtx *model.AuthContext, id string) (*DeletionRequest, error) {
+	deletionReq, err := svc.repo.GetDeletionRequestAPI(id, authCtx.CompanyIdStr())
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchDeletionRequest)
+	}
+	if deletionReq == nil || deletionReq.Id == 0 || deletionReq.CompanyId != authCtx.CompanyId {
+		return nil, apperror.NotFound(constant.DeletionRequestNotFound)
+	}
+	if deletionReq.Status != deletionStatusPending {
+		return nil, apperror.Conflict(constant.DeletionRequestAlreadyReviewed)
+	}
+
+	return deletionReq, nil
+}
+
+// markReviewed moves a pending request to status, the core answers 409 when
+// the request was reviewed in the meantime.
+func (svc *service) markReviewed(authCtx *model.AuthContext, id, status, note string) error {
+	if err := svc.repo.UpdateDeletionRequestAPI(id, authCtx.CompanyIdStr(), map[string]interface{}{
+		"status":          status,
+		"expected_status": deletionStatusPending,
+		"reviewed_by":     authCtx.UserId,
+		"review_note":     note,
+		"reviewed_at":     time.Now(),
+	}); err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return apperror.Conflict(constant.DeletionRequestAlreadyReviewed)
+		}
+
+		return apperror.MapRepoError(err, "failed to update deletion request")
+	}
+
+	return nil
+}
+
+// releaseClaim puts an approved request back to pending so it can be
+// reviewed again.
+func (svc *service) releaseClaim(authCtx *model.AuthContext, id string) {
+	if err := svc.repo.UpdateDeletionRequestAPI(id, authCtx.CompanyIdStr(), map[string]interface{}{
+		"status":          deletionStatusPending,
+		"expected_status": deletionStatusApproved,
+		"reviewed_by":     nil,
+		"review_note":     nil,
+		"reviewed_at":     nil,
+	}); err != nil {
+		log.Error().
+			Err(err).
+			Str("deletion_request_id", id).
+			Msg("failed to release deletion request claim")
+	}
+}
+
+func (svc *service) sendReviewNotification(deletionReq *DeletionRequest, status, note string) {
+	if deletionReq.Email == "" {
+		return
+	}
+
+	if err := svc.mailSvc.SendWithTemplate(
+		deletionReq.Email,
+		nil,
+		"AIForesee Account Deletion Request",
+		"account_deletion_reviewed.html",
+		map[string]any{
+			"Name":       deletionReq.MemberName,
+			"Approved":   status == deletionStatusApproved,
+			"Note":       note,
+			"ReviewedAt": helper.FormatWIB(time.Now()),
+			"Year":       time.Now().Year(),
+		},
+	); err != nil {
+		log.Warn().
+			Err(err).
+			Uint("member_id", deletionReq.MemberId).
+			Msg("failed to send deletion request notification")
+	}
+}
+
+func (svc *service) addLogOperation(memberId, companyId uint, action string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:  memberId,
+		CompanyId: companyId,
+		Action:    action,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", action).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+var exportFileOrder = []string{"profile.json", "role.json", "operation_logs.json", "jobs.json"}
+
+func buildExportArchive(files map[string]any, manifest *dataExportManifest) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	write := func(name string, content any) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(content)
+	}
+
+	if err := write("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+
+	for _, name := range exportFileOrder {
+		if err := write(name, files[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,210 @@
+package privacy
+
+import (
+	"errors"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/mail"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deletionRepoStub holds a single deletion request and records its updates.
+type deletionRepoStub struct {
+	Repository
+	request    *DeletionRequest
+	updateErrs []error
+	updates    []map[string]interface{}
+	companyIds []string
+}
+
+func (s *deletionRepoStub) GetDeletionRequestAPI(id, companyId string) (*DeletionRequest, error) {
+	return s.request, nil
+}
+
+func (s *deletionRepoStub) UpdateDeletionRequestAPI(id, companyId string, payload map[string]interface{}) error {
+	s.updates = append(s.updates, payload)
+	s.companyIds = append(s.companyIds, companyId)
+
+	if len(s.updateErrs) > 0 {
+		err := s.updateErrs[0]
+		s.updateErrs = s.updateErrs[1:]
+		return err
+	}
+
+	return nil
+}
+
+type memberStub struct {
+	member.Repository
+	deleteErr error
+	deleted   []string
+}
+
+func (s *memberStub) DeleteMemberAPI(id string) error {
+	s.deleted = append(s.deleted, id)
+	return s.deleteErr
+}
+
+type operationStub struct {
+	operation.Repository
+	actions []string
+}
+
+func (s *operationStub) AddLogOperation(req *operation.AddLogRequest) error {
+	s.actions = append(s.actions, req.Action)
+	return nil
+}
+
+type stubMailSender struct {
+	sent []mail.Mail
+}
+
+func (s *stubMailSender) Send(m mail.Mail) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func pendingDeletionRequest() *DeletionRequest {
+	return &DeletionRequest{
+		Id:         7,
+		MemberId:   5,
+		CompanyId:  2,
+		MemberName: "Budi",
+		Email:      "budi@example.com",
+		Status:     deletionStatusPending,
+	}
+}
+
+func setupDeletionService(t *testing.T, request *DeletionRequest) (*service, *deletionRepoStub, *memberStub, *operationStub, *stubMailSender) {
+	t.Helper()
+
+	renderer, err := mail.NewTemplateRenderer("../../mail/template")
+	require.NoError(t, err)
+
+	repo := &deletionRepoStub{request: request}
+	memberRepo := &memberStub{}
+	operationRepo := &operationStub{}
+	sender := &stubMailSender{}
+
+	svc := NewService(repo, memberRepo, operationRepo, mail.NewMailService(sender, renderer, nil, "3")).(*service)
+
+	return svc, repo, memberRepo, operationRepo, sender
+}
+
+func TestApproveDeletion(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 2}
+	req := &reviewDeletionRequest{Note: "confirmed by phone"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, repo, memberRepo, operationRepo, sender := setupDeletionService(t, pendingDeletionRequest())
+
+		err := svc.ApproveDeletion(authCtx, "7", req)
+		require.NoError(t, err)
+
+		require.Len(t, repo.updates, 1)
+		assert.Equal(t, deletionStatusApproved, repo.updates[0]["status"])
+		assert.Equal(t, deletionStatusPending, repo.updates[0]["expected_status"])
+		assert.Equal(t, []string{"2"}, repo.companyIds)
+		assert.Equal(t, []string{"5"}, memberRepo.deleted)
+		assert.Equal(t, []string{constant.EventApproveAccountDeletion}, operationRepo.actions)
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, "budi@example.com", sender.sent[0].To)
+	})
+
+	t.Run("own request", func(t *testing.T) {
+		own := pendingDeletionRequest()
+		own.MemberId = authCtx.UserId
+		svc, repo, memberRepo, _, _ := setupDeletionService(t, own)
+
+		err := svc.ApproveDeletion(authCtx, "7", req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+		assert.Empty(t, repo.updates)
+		assert.Empty(t, memberRepo.deleted)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		svc, repo, memberRepo, _, _ := setupDeletionService(t, pendingDeletionRequest())
+		repo.updateErrs = []error{&apperror.ExternalAPIError{StatusCode: http.StatusConflict}}
+
+		err := svc.ApproveDeletion(authCtx, "7", req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+		assert.Equal(t, constant.DeletionRequestAlreadyReviewed, appErr.Message)
+		assert.Empty(t, memberRepo.deleted)
+	})
+
+	t.Run("member cannot be deleted", func(t *testing.T) {
+		svc, repo, memberRepo, operationRepo, sender := setupDeletionService(t, pendingDeletionRequest())
+		memberRepo.deleteErr = errors.New(constant.ErrUpstreamUnavailable)
+
+		err := svc.ApproveDeletion(authCtx, "7", req)
+		require.Error(t, err)
+
+		// the claim is released so the request can be reviewed again
+		require.Len(t, repo.updates, 2)
+		assert.Equal(t, deletionStatusPending, repo.updates[1]["status"])
+		assert.Equal(t, deletionStatusApproved, repo.updates[1]["expected_status"])
+		assert.Nil(t, repo.updates[1]["reviewed_by"])
+		assert.Equal(t, []string{"2", "2"}, repo.companyIds)
+		assert.Empty(t, operationRepo.actions)
+		assert.Empty(t, sender.sent)
+	})
+}
+
+func TestRejectDeletion(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 2}
+	req := &reviewDeletionRequest{Note: "open invoices"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, repo, memberRepo, operationRepo, sender := setupDeletionService(t, pendingDeletionRequest())
+
+		err := svc.RejectDeletion(authCtx, "7", req)
+		require.NoError(t, err)
+
+		require.Len(t, repo.updates, 1)
+		assert.Equal(t, deletionStatusRejected, repo.updates[0]["status"])
+		assert.Equal(t, "open invoices", repo.updates[0]["review_note"])
+		assert.Empty(t, memberRepo.deleted)
+		assert.Equal(t, []string{constant.EventRejectAccountDeletion}, operationRepo.actions)
+		require.Len(t, sender.sent, 1)
+	})
+
+	t.Run("other company", func(t *testing.T) {
+		other := pendingDeletionRequest()
+		other.CompanyId = 3
+		svc, repo, _, _, _ := setupDeletionService(t, other)
+
+		err := svc.RejectDeletion(authCtx, "7", req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+		assert.Empty(t, repo.updates)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		reviewed := pendingDeletionRequest()
+		reviewed.Status = deletionStatusApproved
+		svc, repo, _, _, _ := setupDeletionService(t, reviewed)
+
+		err := svc.RejectDeletion(authCtx, "7", req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+		assert.Empty(t, repo.updates)
+	})
+}
@@ -0,0 +1,27 @@
+package quota
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/member"
+	"front-office/pkg/httpclient"
+
+	redisinfra "front-office/internal/infra/redis"
+
+	"github.com/rs/zerolog/log"
+)
+
+func SetupInit(cfg *application.Config, client httpclient.HTTPClient) Reserver {
+	redisClient, err := redisinfra.NewRedisClient(
+		cfg.App.AppEnv,
+		cfg.App.RedisAddr,
+	)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("failed to create redis connection")
+	}
+
+	memberRepo := member.NewRepository(cfg, client, nil)
+
+	return NewReserver(NewRedisStore(redisClient), memberRepo)
+}
@@ -0,0 +1,32 @@
+package quota
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	quotaTypeNone  = uint(0)
+	quotaTypeTotal = uint(1)
+
+	keyPrefix = "quota:reserved"
+
+	// each reservation is dropped after this period, so the units held by
+	// a crashed process do not block the member forever
+	reservationTTL = time.Hour
+	storeTimeout   = 3 * time.Second
+)
+
+// reservationKey scopes the reserved counter the same way the core scopes
+// the quota, per member for total quota and per subscription otherwise.
+func reservationKey(companyId, memberId, subscribedId string, quotaType uint) string {
+	if quotaType == quotaTypeTotal {
+		return fmt.Sprintf("%s:%s:%s:total", keyPrefix, companyId, memberId)
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%s", keyPrefix, companyId, memberId, subscribedId)
+}
+
+func expiryKey(key string) string {
+	return key + ":expiry"
+}
@@ -0,0 +1,154 @@
+package quota
+
+import (
+	"context"
+	"front-office/internal/core/member"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func NewReserver(store Store, memberRepo member.Repository) Reserver {
+	return &reserver{
+		store,
+		memberRepo,
+	}
+}
+
+type reserver struct {
+	store      Store
+	memberRepo member.Repository
+}
+
+type Reserver interface {
+	// Reserve holds amount units of the member quota for a job, it fails
+	// with ErrQuotaExceeded when the quota left by the core minus the units
+	// held by other running jobs does not cover amount.
+	Reserve(authCtx *model.AuthContext, subscribedId string, amount int) (*Reservation, error)
+}
+
+func (r *reserver) Reserve(authCtx *model.AuthContext, subscribedId string, amount int) (*Reservation, error) {
+	if authCtx.QuotaType == quotaTypeNone {
+		return nil, nil
+	}
+
+	quotaResp, err := r.memberRepo.GetQuotaAPI(&member.QuotaParams{
+		MemberId:     authCtx.UserIdStr(),
+		CompanyId:    authCtx.CompanyIdStr(),
+		SubscribedId: subscribedId,
+		QuotaType:    authCtx.QuotaTypeStr(),
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchQuota)
+	}
+
+	key := reservationKey(authCtx.CompanyIdStr(), authCtx.UserIdStr(), subscribedId, authCtx.QuotaType)
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	id := uuid.NewString()
+	ok, err := r.store.Reserve(ctx, key, id, quotaResp.Data.Quota, amount, reservationTTL)
+	if err != nil {
+		// keep serving requests when redis is down, only the plain check
+		// against the core quota is applied then
+		log.Warn().
+			Err(err).
+			Str("key", key).
+			Msg("failed to reserve quota, falling back to core quota check")
+
+		if quotaResp.Data.Quota < amount {
+			return nil, apperror.Forbidden(constant.ErrQuotaExceeded)
+		}
+
+		return nil, nil
+	}
+	if !ok {
+		return nil, apperror.Forbidden(constant.ErrQuotaExceeded)
+	}
+
+	return &Reservation{
+		store:     r.store,
+		key:       key,
+		id:        id,
+		remaining: amount,
+	}, nil
+}
+
+// Reservation is the part of the quota held by a single job. A nil
+// Reservation is valid and does nothing, it is returned for members
+// without quota.
+type Reservation struct {
+	store     Store
+	key       string
+	id        string
+	mu        sync.Mutex
+	remaining int
+}
+
+// Consume gives back n units once their rows are done, from then on the
+// core quota already reflects them.
+func (res *Reservation) Consume(n int) {
+	if res == nil {
+		return
+	}
+
+	res.mu.Lock()
+	if n > res.remaining {
+		n = res.remaining
+	}
+	res.remaining -= n
+	res.mu.Unlock()
+
+	res.free(n)
+}
+
+// Release gives back every unit not consumed yet, it is called when the
+// job ends whether it succeeded, failed or was cancelled.
+func (res *Reservation) Release() {
+	if res == nil {
+		return
+	}
+
+	res.mu.Lock()
+	n := res.remaining
+	res.remaining = 0
+	res.mu.Unlock()
+
+	res.free(n)
+}
+
+// Remaining returns the units still held by the reservation.
+func (res *Reservation) Remaining() int {
+	if res == nil {
+		return 0
+	}
+
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	return res.remaining
+}
+
+func (res *Reservation) free(n int) {
+	if n <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := res.store.Free(ctx, res.key, res.id, n); err != nil {
+		// the reservation expires on its own, the units are only held
+		// until then
+		log.Warn().
+			Err(err).
+			Str("key", res.key).
+			Int("units", n).
+			Msg("failed to free reserved quota")
+	}
+}
@@ -0,0 +1,302 @@
+package quota
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/internal/core/member"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore mirrors the redis scripts under a mutex.
+type memoryStore struct {
+	mu           sync.Mutex
+	reservations map[string]map[string]*heldUnits
+	err          error
+}
+
+type heldUnits struct {
+	units     int
+	expiresAt time.Time
+}
+
+func (s *memoryStore) Reserve(_ context.Context, key, id string, limit, amount int, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return false, s.err
+	}
+
+	now := time.Now()
+	for heldId, held := range s.reservations[key] {
+		if !held.expiresAt.After(now) {
+			delete(s.reservations[key], heldId)
+		}
+	}
+
+	if limit-s.held(key) < amount {
+		return false, nil
+	}
+
+	if s.reservations[key] == nil {
+		s.reservations[key] = map[string]*heldUnits{}
+	}
+	s.reservations[key][id] = &heldUnits{units: amount, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+func (s *memoryStore) Free(_ context.Context, key, id string, amount int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	held, ok := s.reservations[key][id]
+	if !ok {
+		return nil
+	}
+
+	held.units -= amount
+	if held.units <= 0 {
+		delete(s.reservations[key], id)
+	}
+
+	return nil
+}
+
+// held sums the units of the reservations of key, the caller holds mu.
+func (s *memoryStore) held(key string) int {
+	total := 0
+	for _, held := range s.reservations[key] {
+		total += held.units
+	}
+
+	return total
+}
+
+func (s *memoryStore) reserved(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.held(key)
+}
+
+// quotaClient answers the core quota lookup with a fixed quota.
+type quotaClient struct {
+	quota  int
+	status int
+}
+
+func (c *quotaClient) Do(req *http.Request) (*http.Response, error) {
+	status := c.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"success": status < 400,
+		"data":    map[string]int{"quota": c.quota},
+		"message": http.StatusText(status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func setupReserver(quota int) (Reserver, *memoryStore, *quotaClient) {
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &quotaClient{quota: quota}
+	store := &memoryStore{reservations: map[string]map[string]*heldUnits{}}
+
+	return NewReserver(store, member.NewRepository(cfg, client, nil)), store, client
+}
+
+func authContext(quotaType uint) *model.AuthContext {
+	return &model.AuthContext{UserId: 7, CompanyId: 3, QuotaType: quotaType}
+}
+
+func TestReserve(t *testing.T) {
+	key := reservationKey("3", "7", "11", 2)
+
+	t.Run("no quota", func(t *testing.T) {
+		r, store, _ := setupReserver(0)
+
+		res, err := r.Reserve(authContext(0), "11", 100)
+		require.NoError(t, err)
+		assert.Nil(t, res)
+		assert.Empty(t, store.reservations)
+
+		// nil reservations are safe to use
+		res.Consume(1)
+		res.Release()
+	})
+
+	t.Run("reserves within quota", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+
+		res, err := r.Reserve(authContext(2), "11", 4)
+		require.NoError(t, err)
+		assert.Equal(t, 4, res.Remaining())
+		assert.Equal(t, 4, store.reserved(key))
+	})
+
+	t.Run("held units count against the quota", func(t *testing.T) {
+		r, _, _ := setupReserver(10)
+
+		_, err := r.Reserve(authContext(2), "11", 6)
+		require.NoError(t, err)
+
+		_, err = r.Reserve(authContext(2), "11", 5)
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+		assert.Equal(t, constant.ErrQuotaExceeded, appErr.Message)
+
+		_, err = r.Reserve(authContext(2), "11", 4)
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent bulk uploads", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			granted int
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := r.Reserve(authContext(2), "11", 3); err == nil {
+					mu.Lock()
+					granted++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, granted)
+		assert.Equal(t, 9, store.reserved(key))
+	})
+
+	t.Run("total quota is shared across products", func(t *testing.T) {
+		r, _, _ := setupReserver(5)
+
+		_, err := r.Reserve(authContext(1), "11", 3)
+		require.NoError(t, err)
+
+		_, err = r.Reserve(authContext(1), "12", 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("quota lookup fails", func(t *testing.T) {
+		r, _, client := setupReserver(10)
+		client.status = http.StatusInternalServerError
+
+		_, err := r.Reserve(authContext(2), "11", 1)
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusInternalServerError, appErr.StatusCode)
+	})
+
+	t.Run("store down falls back to core quota", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+		store.err = errors.New("connection refused")
+
+		res, err := r.Reserve(authContext(2), "11", 10)
+		require.NoError(t, err)
+		assert.Nil(t, res)
+
+		_, err = r.Reserve(authContext(2), "11", 11)
+		assert.Error(t, err)
+	})
+}
+
+func TestReservation(t *testing.T) {
+	key := reservationKey("3", "7", "11", 2)
+
+	t.Run("consume and release", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+
+		res, err := r.Reserve(authContext(2), "11", 5)
+		require.NoError(t, err)
+
+		res.Consume(2)
+		assert.Equal(t, 3, res.Remaining())
+		assert.Equal(t, 3, store.reserved(key))
+
+		res.Release()
+		assert.Equal(t, 0, res.Remaining())
+		assert.Equal(t, 0, store.reserved(key))
+
+		// releasing twice must not free units of other jobs
+		_, err = r.Reserve(authContext(2), "11", 4)
+		require.NoError(t, err)
+		res.Release()
+		assert.Equal(t, 4, store.reserved(key))
+	})
+
+	t.Run("leaked units expire on their own", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+
+		leaked, err := r.Reserve(authContext(2), "11", 6)
+		require.NoError(t, err)
+
+		// later reservations do not keep the leaked one alive
+		_, err = r.Reserve(authContext(2), "11", 4)
+		require.NoError(t, err)
+		store.reservations[key][leaked.id].expiresAt = time.Now().Add(-time.Second)
+
+		_, err = r.Reserve(authContext(2), "11", 6)
+		require.NoError(t, err)
+		assert.Equal(t, 10, store.reserved(key))
+
+		// freeing an expired reservation leaves the others alone
+		leaked.Release()
+		assert.Equal(t, 10, store.reserved(key))
+	})
+
+	t.Run("consume is capped at the reservation", func(t *testing.T) {
+		r, store, _ := setupReserver(10)
+
+		other, err := r.Reserve(authContext(2), "11", 2)
+		require.NoError(t, err)
+
+		res, err := r.Reserve(authContext(2), "11", 3)
+		require.NoError(t, err)
+
+		res.Consume(5)
+		assert.Equal(t, 0, res.Remaining())
+		assert.Equal(t, 2, store.reserved(key))
+		assert.Equal(t, 2, other.Remaining())
+	})
+}
+
+func TestReservationKey(t *testing.T) {
+	assert.Equal(t, "quota:reserved:3:7:total", reservationKey("3", "7", "11", 1))
+	assert.Equal(t, "quota:reserved:3:7:11", reservationKey("3", "7", "11", 2))
+}
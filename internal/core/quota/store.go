@@ -0,0 +1,95 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store holds the units reserved by running jobs. Each reservation expires
+// on its own, so the units of a job that never frees them stop counting
+// after the ttl while other jobs keep reserving.
+type Store interface {
+	// Reserve holds amount units under id when limit minus the units of the
+	// unexpired reservations of the key covers it, the check and the
+	// reservation must be atomic.
+	Reserve(ctx context.Context, key, id string, limit, amount int, ttl time.Duration) (bool, error)
+	// Free removes up to amount units from the reservation id of the key.
+	Free(ctx context.Context, key, id string, amount int) error
+}
+
+var errNoRedisClient = errors.New("redis client is not configured")
+
+// The units of each reservation are kept in a hash under the key and their
+// expiry in a sorted set under expiryKey, the expired reservations are
+// dropped before the units held are summed.
+var reserveScript = redis.NewScript(`
+for _, id in ipairs(redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[4])) do
+	redis.call("HDEL", KEYS[1], id)
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", ARGV[4])
+
+local reserved = 0
+for _, units in ipairs(redis.call("HVALS", KEYS[1])) do
+	reserved = reserved + tonumber(units)
+end
+
+local limit = tonumber(ARGV[2])
+local amount = tonumber(ARGV[3])
+
+if limit - reserved < amount then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+redis.call("ZADD", KEYS[2], ARGV[5], ARGV[1])
+-- every reservation has the same ttl, the newest one expires last
+redis.call("PEXPIRE", KEYS[1], ARGV[6])
+redis.call("PEXPIRE", KEYS[2], ARGV[6])
+
+return 1
+`)
+
+var freeScript = redis.NewScript(`
+local left = redis.call("HINCRBY", KEYS[1], ARGV[1], -tonumber(ARGV[2]))
+
+if left <= 0 then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+	redis.call("ZREM", KEYS[2], ARGV[1])
+end
+
+return left
+`)
+
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+type redisStore struct {
+	client *redis.Client
+}
+
+func (s *redisStore) Reserve(ctx context.Context, key, id string, limit, amount int, ttl time.Duration) (bool, error) {
+	if s.client == nil {
+		return false, errNoRedisClient
+	}
+
+	now := time.Now()
+	keys := []string{key, expiryKey(key)}
+	ok, err := reserveScript.Run(ctx, s.client, keys, id, limit, amount, now.UnixMilli(), now.Add(ttl).UnixMilli(), ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return ok == 1, nil
+}
+
+func (s *redisStore) Free(ctx context.Context, key, id string, amount int) error {
+	if s.client == nil {
+		return errNoRedisClient
+	}
+
+	return freeScript.Run(ctx, s.client, []string{key, expiryKey(key)}, id, amount).Err()
+}
@@ -0,0 +1,85 @@
+package applicantcheck
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(svc Service) Controller {
+	return &controller{svc}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	Check(c *fiber.Ctx) error
+	GetApplicantCheck(c *fiber.Ctx) error
+	ExportApplicantCheck(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) Check(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*applicantCheckRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.Check(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
+func (ctrl *controller) GetApplicantCheck(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	result, err := ctrl.svc.GetApplicantCheck(authCtx, c.Params("id"), masked)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
+func (ctrl *controller) ExportApplicantCheck(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	result, err := ctrl.svc.ExportApplicantCheck(authCtx, c.Params("id"), c.Query("format"), masked)
+	if err != nil {
+		return err
+	}
+
+	c.Set(constant.HeaderContentType, result.ContentType)
+	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
+	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+
+	return c.Send(result.Data)
+}
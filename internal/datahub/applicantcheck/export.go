@@ -0,0 +1,207 @@
+package applicantcheck
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"front-office/pkg/pdf"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{
+	"Product", "Status", "Status Code", "Message", "Transaction Id", "Pricing Strategy",
+	"Name", "NIK", "Phone Number", "NPWP", "Loan No", "Data", "Decision", "Fired Rules",
+}
+
+func (a applicant) masked() applicant {
+	a.NIK = helper.MaskingHead(a.NIK, 10)
+	a.PhoneNumber = helper.MaskingMiddle(a.PhoneNumber)
+	a.NPWP = helper.MaskingHead(a.NPWP, 10)
+
+	return a
+}
+
+// renderCSV writes one row per product, the product data is kept as JSON
+// since every product returns a different shape.
+func renderCSV(check *applicantCheck) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	decision, firedRules := decisionColumns(check.Decision)
+
+	for _, result := range check.Products {
+		data := ""
+		if result.Data != nil {
+			raw, err := json.Marshal(result.Data)
+			if err != nil {
+				return nil, err
+			}
+			data = string(raw)
+		}
+
+		if err := w.Write([]string{
+			result.Product,
+			result.Status,
+			strconv.Itoa(result.StatusCode),
+			result.Message,
+			result.TransactionId,
+			result.PricingStrategy,
+			check.Applicant.Name,
+			check.Applicant.NIK,
+			check.Applicant.PhoneNumber,
+			check.Applicant.NPWP,
+			check.Applicant.LoanNo,
+			data,
+			decision,
+			firedRules,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func renderPDF(check *applicantCheck) ([]byte, error) {
+	const (
+		left   = 40.0
+		right  = pdf.PageWidth - 40
+		top    = 60.0
+		bottom = pdf.PageHeight - 60
+	)
+
+	doc := pdf.New()
+	doc.AddPage()
+
+	doc.Text(left, top, pdf.HelveticaBold, 20, "APPLICANT CHECK")
+	doc.TextRight(right, top-8, pdf.HelveticaBold, 10, fmt.Sprintf("#%d", check.Id))
+	if !check.CreatedAt.IsZero() {
+		doc.TextRight(right, top+6, pdf.Helvetica, 9, check.CreatedAt.Format("02 January 2006 15:04"))
+	}
+
+	decision, firedRules := decisionColumns(check.Decision)
+	summary := [][2]string{
+		{"Name", check.Applicant.Name},
+		{"NIK", check.Applicant.NIK},
+		{"Phone Number", check.Applicant.PhoneNumber},
+		{"NPWP", check.Applicant.NPWP},
+		{"Loan No", check.Applicant.LoanNo},
+		{"Status", check.Status},
+	}
+	if check.Decision != nil {
+		summary = append(summary, [2]string{"Decision", strings.ToUpper(decision)}, [2]string{"Fired Rules", truncate(firedRules, 80)})
+	}
+
+	y := top + 40
+	for _, field := range summary {
+		doc.Text(left, y, pdf.HelveticaBold, 9, field[0])
+		doc.Text(left+100, y, pdf.Helvetica, 9, field[1])
+		y += 14
+	}
+
+	for _, result := range check.Products {
+		fields := dataFields(result.Data)
+
+		// keep the product heading together with its first lines
+		if y+60 > bottom {
+			doc.AddPage()
+			y = top
+		}
+
+		y += 20
+		doc.Text(left, y, pdf.HelveticaBold, 11, result.Name)
+		doc.TextRight(right, y, pdf.HelveticaBold, 9, strings.ToUpper(result.Status))
+		doc.Line(left, y+5, right, y+5)
+		y += 20
+
+		lines := [][2]string{
+			{"Status Code", strconv.Itoa(result.StatusCode)},
+			{"Message", result.Message},
+		}
+		if result.TransactionId != "" {
+			lines = append(lines, [2]string{"Transaction Id", result.TransactionId})
+		}
+		lines = append(lines, fields...)
+
+		for _, line := range lines {
+			if y > bottom {
+				doc.AddPage()
+				y = top
+			}
+
+			doc.Text(left, y, pdf.Helvetica, 9, line[0])
+			doc.Text(left+140, y, pdf.Courier, 9, truncate(line[1], 60))
+			y += 14
+		}
+	}
+
+	return doc.Bytes()
+}
+
+func decisionColumns(d *model.Decision) (string, string) {
+	if d == nil {
+		return "", ""
+	}
+
+	return d.Decision, strings.Join(d.FiredRules, "; ")
+}
+
+// dataFields flattens the product data into sorted key value pairs, nested
+// values are shown as JSON.
+func dataFields(data any) [][2]string {
+	if data == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return [][2]string{{"Data", string(raw)}}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		value := ""
+		switch v := values[key].(type) {
+		case nil:
+		case string:
+			value = v
+		default:
+			encoded, _ := json.Marshal(v)
+			value = string(encoded)
+		}
+
+		fields = append(fields, [2]string{key, value})
+	}
+
+	return fields
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,26 @@
+package applicantcheck
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupInit must run after the products are registered, the check can only
+// run the products registered at that point.
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, evaluator decision.Evaluator) {
+	repository := NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+
+	service := NewService(repository, operationRepo, evaluator, pipeline.Runners())
+	controller := NewController(service)
+
+	apiGroup.Post("/", middleware.ValidateRequest(applicantCheckRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.Check)
+	apiGroup.Get("/:id", middleware.GetJWTPayloadFromCookie(cfg), controller.GetApplicantCheck)
+	apiGroup.Get("/:id/export", middleware.GetJWTPayloadFromCookie(cfg), controller.ExportApplicantCheck)
+}
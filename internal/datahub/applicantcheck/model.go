@@ -0,0 +1,95 @@
+package applicantcheck
+
+import (
+	"front-office/pkg/common/model"
+	"time"
+)
+
+const (
+	productStatusSuccess = "success"
+	productStatusFailed  = "failed"
+
+	formatPDF = "pdf"
+	formatCSV = "csv"
+)
+
+// applicant is sent to every product as its single request body, the json
+// names match the fields of the product requests.
+type applicant struct {
+	Name        string `json:"name"`
+	NIK         string `json:"nik"`
+	PhoneNumber string `json:"phone_number"`
+	NPWP        string `json:"npwp"`
+	LoanNo      string `json:"loan_no"`
+}
+
+type applicantCheckRequest struct {
+	Name        string `json:"name"`
+	NIK         string `json:"nik" normalize:"nik"`
+	PhoneNumber string `json:"phone_number" normalize:"phone"`
+	NPWP        string `json:"npwp" normalize:"npwp"`
+	LoanNo      string `json:"loan_no" validate:"required~Loan No cannot be empty."`
+	// Products are the product routes to run, e.g. "phone-live-status",
+	// "phone-nik", "loan-record-checker", "30d-multiple-loan", "tax-score"
+	Products []string `json:"products"`
+}
+
+func (req *applicantCheckRequest) applicant() applicant {
+	return applicant{
+		Name:        req.Name,
+		NIK:         req.NIK,
+		PhoneNumber: req.PhoneNumber,
+		NPWP:        req.NPWP,
+		LoanNo:      req.LoanNo,
+	}
+}
+
+type createApplicantCheckRequest struct {
+	MemberId    uint      `json:"member_id"`
+	CompanyId   uint      `json:"company_id"`
+	APIClientId string    `json:"api_client_id,omitempty"`
+	Applicant   applicant `json:"applicant"`
+	Products    []string  `json:"products"`
+	Status      string    `json:"status"`
+}
+
+type createApplicantCheckRespData struct {
+	ApplicantCheckId uint `json:"applicant_check_id"`
+}
+
+type updateApplicantCheckRequest struct {
+	Status   string          `json:"status"`
+	Products []productResult `json:"products"`
+	Decision *model.Decision `json:"decision,omitempty"`
+}
+
+type applicantCheck struct {
+	Id        uint            `json:"id"`
+	MemberId  uint            `json:"member_id"`
+	CompanyId uint            `json:"company_id"`
+	Status    string          `json:"status"`
+	Applicant applicant       `json:"applicant"`
+	Products  []productResult `json:"products"`
+	Decision  *model.Decision `json:"decision,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type productResult struct {
+	Product         string `json:"product"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	StatusCode      int    `json:"status_code"`
+	Message         string `json:"message"`
+	TransactionId   string `json:"transaction_id,omitempty"`
+	PricingStrategy string `json:"pricing_strategy,omitempty"`
+	Data            any    `json:"data,omitempty"`
+	// facts is the partner data the decision rules are evaluated on, it is
+	// kept when the product hides Data
+	facts any
+}
+
+type exportFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,302 @@
+package applicantcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(repo Repository, operationRepo operation.Repository, evaluator decision.Evaluator, runners map[string]pipeline.Runner) Service {
+	return &service{
+		repo,
+		operationRepo,
+		evaluator,
+		runners,
+	}
+}
+
+type service struct {
+	repo          Repository
+	operationRepo operation.Repository
+	evaluator     decision.Evaluator
+	runners       map[string]pipeline.Runner
+}
+
+type Service interface {
+	Check(authCtx *model.AuthContext, req *applicantCheckRequest) (*applicantCheck, error)
+	GetApplicantCheck(authCtx *model.AuthContext, id string, masked bool) (*applicantCheck, error)
+	ExportApplicantCheck(authCtx *model.AuthContext, id, format string, masked bool) (*exportFile, error)
+}
+
+// Check runs the chosen products concurrently for one applicant. Every
+// product runs as a job linked to the applicant check, a failing product is
+// reported in its result and does not stop the others. The quota of every
+// product is reserved before any of them runs.
+func (svc *service) Check(authCtx *model.AuthContext, req *applicantCheckRequest) (*applicantCheck, error) {
+	routes, err := svc.products(req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := pipeline.Reserve(authCtx, svc.runners, routes, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer reservations.Release()
+
+	applicantData := req.applicant()
+	body, err := json.Marshal(applicantData)
+	if err != nil {
+		return nil, apperror.Internal(constant.ErrInvalidRequestPayload, err)
+	}
+
+	created, err := svc.repo.CreateApplicantCheckAPI(&createApplicantCheckRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		APIClientId: authCtx.APIClientId,
+		Applicant:   applicantData,
+		Products:    routes,
+		Status:      constant.JobStatusInProgress,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedCreateApplicantCheck)
+	}
+
+	results := make([]productResult, len(routes))
+
+	var wg sync.WaitGroup
+	for i, route := range routes {
+		wg.Add(1)
+
+		go func(i int, route string) {
+			defer wg.Done()
+
+			results[i] = svc.runProduct(authCtx, &pipeline.Parent{
+				ApplicantCheckId: created.ApplicantCheckId,
+				Reservation:      reservations[route],
+			}, route, body)
+		}(i, route)
+	}
+	wg.Wait()
+
+	status := checkStatus(results)
+	idStr := helper.ConvertUintToString(created.ApplicantCheckId)
+	checkDecision := svc.decide(authCtx, idStr, results)
+	if err := svc.repo.UpdateApplicantCheckAPI(idStr, &updateApplicantCheckRequest{
+		Status:   status,
+		Products: results,
+		Decision: checkDecision,
+	}); err != nil {
+		// the products have run and are logged, so the result is still returned
+		log.Error().
+			Err(err).
+			Str("applicant_check_id", idStr).
+			Msg("failed to store applicant check result")
+	}
+
+	svc.addLogOperation(authCtx, constant.EventApplicantCheck)
+
+	return &applicantCheck{
+		Id:        created.ApplicantCheckId,
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Status:    status,
+		Applicant: applicantData,
+		Products:  results,
+		Decision:  checkDecision,
+	}, nil
+}
+
+func (svc *service) GetApplicantCheck(authCtx *model.AuthContext, id string, masked bool) (*applicantCheck, error) {
+	check, err := svc.getCompanyApplicantCheck(authCtx.CompanyId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if masked {
+		check.Applicant = check.Applicant.masked()
+	}
+
+	return check, nil
+}
+
+func (svc *service) ExportApplicantCheck(authCtx *model.AuthContext, id, format string, masked bool) (*exportFile, error) {
+	if format == "" {
+		format = formatPDF
+	}
+	if format != formatPDF && format != formatCSV {
+		return nil, apperror.BadRequest(constant.InvalidApplicantCheckFormat)
+	}
+
+	check, err := svc.GetApplicantCheck(authCtx, id, masked)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &exportFile{
+		Filename: fmt.Sprintf("applicant_check_%d.%s", check.Id, format),
+	}
+
+	if format == formatCSV {
+		file.ContentType = constant.TextOrCSVContentType
+		file.Data, err = renderCSV(check)
+	} else {
+		file.ContentType = constant.MimePdf
+		file.Data, err = renderPDF(check)
+	}
+	if err != nil {
+		return nil, apperror.Internal("failed to render applicant check", err)
+	}
+
+	svc.addLogOperation(authCtx, constant.EventApplicantCheckDownload)
+
+	return file, nil
+}
+
+// products checks the requested routes against the registered products,
+// duplicates are run once.
+func (svc *service) products(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, apperror.BadRequest(constant.ApplicantCheckProductsRequired)
+	}
+
+	seen := make(map[string]bool, len(requested))
+	routes := make([]string, 0, len(requested))
+	for _, route := range requested {
+		if _, ok := svc.runners[route]; !ok {
+			return nil, apperror.BadRequest(fmt.Sprintf(constant.UnknownApplicantCheckProduct, route))
+		}
+		if seen[route] {
+			continue
+		}
+
+		seen[route] = true
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (svc *service) runProduct(authCtx *model.AuthContext, parent *pipeline.Parent, route string, body []byte) productResult {
+	runner := svc.runners[route]
+	result := productResult{
+		Product: route,
+		Name:    runner.Name(),
+	}
+
+	runResult, err := runner.Run(authCtx, parent, body)
+	if err != nil {
+		result.Status = productStatusFailed
+		result.StatusCode = http.StatusInternalServerError
+		result.Message = err.Error()
+
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) {
+			result.StatusCode = appErr.StatusCode
+			result.Message = appErr.Message
+		}
+
+		return result
+	}
+
+	result.Status = productStatusSuccess
+	result.StatusCode = runResult.StatusCode
+	result.Message = runResult.Message
+	result.TransactionId = runResult.TransactionId
+	result.PricingStrategy = runResult.PricingStrategy
+	result.facts = runResult.Data
+	if !runResult.Hidden {
+		result.Data = runResult.Data
+	}
+
+	return result
+}
+
+// decide evaluates the company decision rules over the products that
+// succeeded, so rules can combine the results of several products.
+func (svc *service) decide(authCtx *model.AuthContext, idStr string, results []productResult) *model.Decision {
+	if svc.evaluator == nil {
+		return nil
+	}
+
+	facts := decision.Facts{}
+	for _, result := range results {
+		if result.Status == productStatusSuccess {
+			facts[result.Product] = result.facts
+		}
+	}
+
+	checkDecision, err := svc.evaluator.Evaluate(authCtx.CompanyId, facts)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("applicant_check_id", idStr).
+			Msg("failed to evaluate decision rules")
+
+		return nil
+	}
+
+	return checkDecision
+}
+
+func (svc *service) getCompanyApplicantCheck(companyId uint, id string) (*applicantCheck, error) {
+	check, err := svc.repo.GetApplicantCheckAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.NotFound(constant.ApplicantCheckNotFound)
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchApplicantCheck)
+	}
+	if check == nil || check.Id == 0 || check.CompanyId != companyId {
+		return nil, apperror.NotFound(constant.ApplicantCheckNotFound)
+	}
+
+	return check, nil
+}
+
+func (svc *service) addLogOperation(authCtx *model.AuthContext, event string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      event,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", event).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+// checkStatus is done when every product succeeded, failed when none did and
+// partial otherwise.
+func checkStatus(results []productResult) string {
+	succeeded := 0
+	for _, result := range results {
+		if result.Status == productStatusSuccess {
+			succeeded++
+		}
+	}
+
+	switch succeeded {
+	case len(results):
+		return constant.JobStatusDone
+	case 0:
+		return constant.JobStatusFailed
+	default:
+		return constant.JobStatusPartial
+	}
+}
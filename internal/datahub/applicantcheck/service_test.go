@@ -0,0 +1,363 @@
+package applicantcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coreStub answers the core calls by "METHOD path".
+type coreStub struct {
+	mu     sync.Mutex
+	routes map[string]func(*http.Request) (int, any)
+	bodies map[string][][]byte
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+	}
+
+	s.mu.Lock()
+	s.bodies[key] = append(s.bodies[key], reqBody)
+	s.mu.Unlock()
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type fakeRunner struct {
+	name       string
+	run        func(parent *pipeline.Parent, body []byte) (*pipeline.RunResult, error)
+	reserveErr error
+	reserved   []int
+}
+
+func (r *fakeRunner) Name() string {
+	return r.name
+}
+
+func (r *fakeRunner) Reserve(_ *model.AuthContext, amount int) (*quota.Reservation, error) {
+	r.reserved = append(r.reserved, amount)
+	return nil, r.reserveErr
+}
+
+func (r *fakeRunner) Run(_ *model.AuthContext, parent *pipeline.Parent, body []byte) (*pipeline.RunResult, error) {
+	return r.run(parent, body)
+}
+
+func succeeding(name string, data any) *fakeRunner {
+	return &fakeRunner{name: name, run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+		return &pipeline.RunResult{StatusCode: http.StatusOK, Message: constant.Success, TransactionId: "TRX-" + name, Data: data}, nil
+	}}
+}
+
+func failing(name string, err error) *fakeRunner {
+	return &fakeRunner{name: name, run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+		return nil, err
+	}}
+}
+
+var storedCheck = map[string]any{
+	"id":         4,
+	"member_id":  1,
+	"company_id": 9,
+	"status":     constant.JobStatusPartial,
+	"applicant":  map[string]any{"name": "Budi", "nik": "3201234567890001", "phone_number": "6281234567890", "npwp": "0123456789012345", "loan_no": "L1"},
+	"decision":   map[string]any{"decision": "review", "fired_rules": []string{"many loans"}},
+	"products": []map[string]any{
+		{"product": "tax-score", "name": "tax score", "status": productStatusSuccess, "status_code": 200, "message": "success", "transaction_id": "TXS1", "data": map[string]any{"score": "A", "nama": "BUDI"}},
+		{"product": "phone-nik", "name": "phone to nik matching", "status": productStatusFailed, "status_code": 502, "message": "failed to process phone to nik matching"},
+	},
+	"created_at": time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+}
+
+type stubEvaluator struct {
+	facts decision.Facts
+}
+
+// Evaluate rejects when the tax score is known, every product with data
+// counts as covered by a rule.
+func (e *stubEvaluator) Evaluate(companyId uint, facts decision.Facts) (*model.Decision, error) {
+	e.facts = facts
+	if _, ok := facts["tax-score"]; ok {
+		return &model.Decision{Decision: "reject", FiredRules: []string{"score"}}, nil
+	}
+
+	return nil, nil
+}
+
+func setupService(t *testing.T, runners map[string]pipeline.Runner) (Service, *coreStub) {
+	t.Helper()
+
+	return setupServiceWithEvaluator(t, runners, nil)
+}
+
+func setupServiceWithEvaluator(t *testing.T, runners map[string]pipeline.Runner, evaluator decision.Evaluator) (Service, *coreStub) {
+	t.Helper()
+
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{
+		routes: map[string]func(*http.Request) (int, any){
+			"POST /api/core/product/applicant-checks":  ok(map[string]any{"applicant_check_id": 4}),
+			"PUT /api/core/product/applicant-checks/4": ok(nil),
+			"GET /api/core/product/applicant-checks/4": ok(storedCheck),
+			"POST /api/core/logging/operation":         ok(nil),
+		},
+		bodies: map[string][][]byte{},
+	}
+
+	return NewService(NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil), evaluator, runners), client
+}
+
+func TestCheck(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+	req := &applicantCheckRequest{
+		Name:        "Budi",
+		NIK:         "3201234567890001",
+		PhoneNumber: "6281234567890",
+		NPWP:        "0123456789012345",
+		LoanNo:      "L1",
+		Products:    []string{"tax-score", "phone-nik", "tax-score"},
+	}
+
+	t.Run("partial failure", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			bodies []applicant
+			parent []uint
+		)
+		taxScore := succeeding("tax score", map[string]string{"score": "A"})
+		run := taxScore.run
+		taxScore.run = func(p *pipeline.Parent, body []byte) (*pipeline.RunResult, error) {
+			var a applicant
+			require.NoError(t, json.Unmarshal(body, &a))
+
+			mu.Lock()
+			bodies = append(bodies, a)
+			parent = append(parent, p.ApplicantCheckId)
+			mu.Unlock()
+
+			return run(p, body)
+		}
+		phoneNik := failing("phone to nik matching", apperror.BadGateway("failed to process phone to nik matching"))
+
+		svc, client := setupService(t, map[string]pipeline.Runner{
+			"tax-score": taxScore,
+			"phone-nik": phoneNik,
+		})
+
+		result, err := svc.Check(authCtx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(4), result.Id)
+		assert.Equal(t, constant.JobStatusPartial, result.Status)
+		require.Len(t, result.Products, 2)
+		assert.Equal(t, "tax-score", result.Products[0].Product)
+		assert.Equal(t, productStatusSuccess, result.Products[0].Status)
+		assert.Equal(t, "TRX-tax score", result.Products[0].TransactionId)
+		assert.Equal(t, "phone-nik", result.Products[1].Product)
+		assert.Equal(t, productStatusFailed, result.Products[1].Status)
+		assert.Equal(t, http.StatusBadGateway, result.Products[1].StatusCode)
+
+		require.Len(t, bodies, 1)
+		assert.Equal(t, req.applicant(), bodies[0])
+		assert.Equal(t, []uint{4}, parent)
+
+		// one unit of every product is reserved up front
+		assert.Equal(t, []int{1}, taxScore.reserved)
+		assert.Equal(t, []int{1}, phoneNik.reserved)
+
+		var created createApplicantCheckRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/product/applicant-checks"][0], &created))
+		assert.Equal(t, []string{"tax-score", "phone-nik"}, created.Products)
+		assert.Equal(t, constant.JobStatusInProgress, created.Status)
+
+		var updated updateApplicantCheckRequest
+		require.NoError(t, json.Unmarshal(client.bodies["PUT /api/core/product/applicant-checks/4"][0], &updated))
+		assert.Equal(t, constant.JobStatusPartial, updated.Status)
+		assert.Len(t, updated.Products, 2)
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	t.Run("all products failed", func(t *testing.T) {
+		svc, _ := setupService(t, map[string]pipeline.Runner{
+			"tax-score": failing("tax score", apperror.Forbidden(constant.ErrQuotaExceeded)),
+			"phone-nik": failing("phone to nik matching", assert.AnError),
+		})
+
+		result, err := svc.Check(authCtx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, constant.JobStatusFailed, result.Status)
+		assert.Equal(t, http.StatusForbidden, result.Products[0].StatusCode)
+		assert.Equal(t, http.StatusInternalServerError, result.Products[1].StatusCode)
+	})
+
+	t.Run("decision over succeeded products", func(t *testing.T) {
+		evaluator := &stubEvaluator{}
+		svc, client := setupServiceWithEvaluator(t, map[string]pipeline.Runner{
+			"tax-score": succeeding("tax score", map[string]string{"score": "A"}),
+			"phone-nik": failing("phone to nik matching", apperror.BadGateway("failed")),
+		}, evaluator)
+
+		result, err := svc.Check(authCtx, req)
+		require.NoError(t, err)
+
+		require.NotNil(t, result.Decision)
+		assert.Equal(t, "reject", result.Decision.Decision)
+		assert.Equal(t, decision.Facts{"tax-score": map[string]string{"score": "A"}}, evaluator.facts)
+
+		var updated updateApplicantCheckRequest
+		require.NoError(t, json.Unmarshal(client.bodies["PUT /api/core/product/applicant-checks/4"][0], &updated))
+		require.NotNil(t, updated.Decision)
+		assert.Equal(t, []string{"score"}, updated.Decision.FiredRules)
+	})
+
+	t.Run("decision over hidden results", func(t *testing.T) {
+		evaluator := &stubEvaluator{}
+		phone := &fakeRunner{name: "phone live status", run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+			return &pipeline.RunResult{StatusCode: http.StatusOK, Message: constant.Success, Data: map[string]string{"live_status": "not active"}, Hidden: true}, nil
+		}}
+		svc, client := setupServiceWithEvaluator(t, map[string]pipeline.Runner{"phone-live-status": phone}, evaluator)
+
+		result, err := svc.Check(authCtx, &applicantCheckRequest{PhoneNumber: "6281234567890", LoanNo: "L1", Products: []string{"phone-live-status"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, decision.Facts{"phone-live-status": map[string]string{"live_status": "not active"}}, evaluator.facts)
+		assert.Nil(t, result.Products[0].Data)
+
+		var updated updateApplicantCheckRequest
+		require.NoError(t, json.Unmarshal(client.bodies["PUT /api/core/product/applicant-checks/4"][0], &updated))
+		assert.Nil(t, updated.Products[0].Data)
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ran := false
+		taxScore := &fakeRunner{name: "tax score", run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+			ran = true
+			return &pipeline.RunResult{}, nil
+		}}
+		svc, client := setupService(t, map[string]pipeline.Runner{
+			"tax-score": taxScore,
+			"phone-nik": &fakeRunner{name: "phone to nik matching", reserveErr: apperror.Forbidden(constant.ErrQuotaExceeded)},
+		})
+
+		_, err := svc.Check(authCtx, req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+		assert.False(t, ran)
+		assert.Empty(t, client.bodies["POST /api/core/product/applicant-checks"])
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		svc, client := setupService(t, map[string]pipeline.Runner{"tax-score": succeeding("tax score", nil)})
+
+		_, err := svc.Check(authCtx, req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Empty(t, client.bodies["POST /api/core/product/applicant-checks"])
+	})
+
+	t.Run("no products", func(t *testing.T) {
+		svc, _ := setupService(t, map[string]pipeline.Runner{"tax-score": succeeding("tax score", nil)})
+
+		_, err := svc.Check(authCtx, &applicantCheckRequest{LoanNo: "L1"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, constant.ApplicantCheckProductsRequired, appErr.Message)
+	})
+}
+
+func TestExportApplicantCheck(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+
+	t.Run("csv", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		file, err := svc.ExportApplicantCheck(authCtx, "4", formatCSV, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, "applicant_check_4.csv", file.Filename)
+		lines := strings.Split(strings.TrimSpace(string(file.Data)), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, strings.Join(csvHeader, ","), lines[0])
+		assert.Contains(t, lines[1], "tax-score,success,200")
+		assert.Contains(t, lines[1], "******4567890001")
+		assert.NotContains(t, lines[1], "3201234567890001")
+		assert.Contains(t, lines[2], "phone-nik,failed,502")
+		assert.True(t, strings.HasSuffix(lines[1], ",review,many loans"))
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		file, err := svc.ExportApplicantCheck(authCtx, "4", "", false)
+		require.NoError(t, err)
+
+		assert.Equal(t, "applicant_check_4.pdf", file.Filename)
+		assert.Equal(t, constant.MimePdf, file.ContentType)
+		assert.True(t, bytes.HasPrefix(file.Data, []byte("%PDF")))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		_, err := svc.ExportApplicantCheck(authCtx, "4", "xlsx", false)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+
+	t.Run("other company", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		_, err := svc.ExportApplicantCheck(&model.AuthContext{UserId: 2, CompanyId: 10}, "4", formatCSV, false)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
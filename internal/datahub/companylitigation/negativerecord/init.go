@@ -2,29 +2,64 @@ package negativerecord
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/decision"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/companylitigation/review"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
+var product = pipeline.Product[negativeRecordRequest, dataNegativeRecord]{
+	Slug:       constant.SlugNegativeRecord,
+	Route:      "negative-record",
+	Name:       "negative record",
+	TrxPrefix:  constant.TrxIdNegativeRecord,
+	Path:       "/product/complit/negative-record",
+	CSVHeaders: constant.CSVTemplateHeaderNegativeRecord,
+	FromCSV: func(record []string) *negativeRecordRequest {
+		return &negativeRecordRequest{
+			CompanyName: record[0],
+			LoanNo:      record[1],
+		}
+	},
+	LoanNo: func(req *negativeRecordRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventNegativeRecordSingleReq,
+	BulkEvent:   constant.EventNegativeRecordBulkReq,
+}
+
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	reviewService := review.NewService(review.NewRepository(cfg, client, nil), transaction.NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil))
+
+	negativeRecord := product
+	negativeRecord.Refine = refine(reviewService)
+
+	productGroup := pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &negativeRecord)
+	review.Register(productGroup, cfg, reviewService)
+}
 
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, operationRepo, transactionRepo, jobService)
+// refine drops the hits below the company threshold, the hits left wait for
+// a review.
+func refine(lookup review.Lookup) func(companyId uint, data *dataNegativeRecord) {
+	return func(companyId uint, data *dataNegativeRecord) {
+		threshold := lookup.Threshold(companyId)
 
-	controller := NewController(service)
+		hits := make([]dataNegativeRecordAPI, 0, len(data.Result))
+		caseNumbers := make([]string, 0, len(data.Result))
+		for _, hit := range data.Result {
+			if review.AboveThreshold(hit.SimilarityScore, threshold) {
+				hits = append(hits, hit)
+				caseNumbers = append(caseNumbers, hit.CaseNumber)
+			}
+		}
 
-	negativeRecordGroup := apiGroup.Group("negative-record")
-	negativeRecordGroup.Post("/single-request", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(negativeRecordRequest{}), controller.SingleRequest)
-	negativeRecordGroup.Post("/bulk-request", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateCSVFile(), controller.BulkSearch)
+		data.Result = hits
+		data.ReviewStatus = review.Status(caseNumbers, nil)
+	}
 }
@@ -7,6 +7,9 @@ type negativeRecordRequest struct {
 
 type dataNegativeRecord struct {
 	Result []dataNegativeRecordAPI `json:"result"`
+	// ReviewStatus is pending until a reviewer confirmed or dismissed every
+	// hit, empty without hits
+	ReviewStatus string `json:"review_status,omitempty"`
 }
 
 type dataNegativeRecordAPI struct {
@@ -21,16 +24,3 @@ type dataNegativeRecordAPI struct {
 	ProcessDuration  string `json:"process_duration"`
 	LastUpdated      string `json:"last_updated"`
 }
-
-type negativeRecordContext struct {
-	APIKey         string                 `json:"api_key"`
-	JobIdStr       string                 `json:"job_id_str"`
-	MemberIdStr    string                 `json:"member_id_str"`
-	CompanyIdStr   string                 `json:"company_id_str"`
-	MemberId       uint                   `json:"member_id"`
-	CompanyId      uint                   `json:"company_id"`
-	ProductId      uint                   `json:"product_id"`
-	ProductGroupId uint                   `json:"product_group_id"`
-	JobId          uint                   `json:"job_id"`
-	Request        *negativeRecordRequest `json:"request"`
-}
@@ -0,0 +1,133 @@
+package negativerecord
+
+import (
+	"front-office/internal/datahub/companylitigation/review"
+	"front-office/internal/datahub/pipeline/pipelinetest"
+	"front-office/internal/simulator"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSimulator starts a simulator where "PT Artha Mulia" has a court case,
+// "PT Sengketa" has a close and a distant hit and "down" fails on the partner
+// side.
+func newSimulator(t *testing.T) *simulator.TestServer {
+	t.Helper()
+
+	ts, err := simulator.NewTestServer(&simulator.Scenario{Products: map[string]*simulator.Product{
+		constant.SlugNegativeRecord: {Fixtures: []simulator.Fixture{
+			{Match: map[string]string{"company_name": "down"}, Status: http.StatusServiceUnavailable, Message: "court registry unavailable"},
+			{Match: map[string]string{"company_name": "PT Sengketa"}, Data: map[string]any{"result": []any{
+				map[string]any{"company_name": "PT SENGKETA", "similarity_score": "100.0", "case_number": "12/Pdt.G/2025/PN BDG"},
+				map[string]any{"company_name": "PT SENGKETA RAYA", "similarity_score": "72.5", "case_number": "98/Pdt.G/2024/PN SBY"},
+			}}},
+		}},
+	}})
+	require.NoError(t, err)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// thresholdLookup is a review lookup with a fixed threshold.
+type thresholdLookup float64
+
+func (l thresholdLookup) Threshold(uint) float64 {
+	return float64(l)
+}
+
+func (l thresholdLookup) Reviews(uint, []string) ([]*review.Review, error) {
+	return nil, nil
+}
+
+func TestSingleRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		result, err := svc.SingleRequest(authCtx, &negativeRecordRequest{CompanyName: "PT Artha Mulia", LoanNo: "L1"})
+		require.NoError(t, err)
+		require.Len(t, result.Data.Result, 1)
+		assert.Equal(t, "411/Pdt.G/2025/PN JKT.SEL", result.Data.Result[0].CaseNumber)
+		assert.NotEmpty(t, result.TransactionId)
+		assert.Equal(t, []string{"PT Artha Mulia"}, pipelinetest.Answered(ts.Sim.Transactions(), "company_name"))
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		result, err := svc.SingleRequest(authCtx, &negativeRecordRequest{CompanyName: "PT Bersih", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Data.Result)
+	})
+
+	t.Run("similarity threshold", func(t *testing.T) {
+		ts := newSimulator(t)
+		negativeRecord := product
+		negativeRecord.Refine = refine(thresholdLookup(80))
+		svc := pipelinetest.NewService(ts.Config(), &negativeRecord)
+
+		result, err := svc.SingleRequest(authCtx, &negativeRecordRequest{CompanyName: "PT Sengketa", LoanNo: "L1"})
+		require.NoError(t, err)
+		require.Len(t, result.Data.Result, 1)
+		assert.Equal(t, "12/Pdt.G/2025/PN BDG", result.Data.Result[0].CaseNumber)
+		assert.Equal(t, review.StatusPending, result.Data.ReviewStatus)
+
+		result, err = svc.SingleRequest(authCtx, &negativeRecordRequest{CompanyName: "PT Bersih", LoanNo: "L2"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Data.ReviewStatus)
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		_, err := svc.SingleRequest(authCtx, &negativeRecordRequest{CompanyName: "down", LoanNo: "L1"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusServiceUnavailable, appErr.StatusCode)
+	})
+}
+
+func TestBulkRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+	header := strings.Join(constant.CSVTemplateHeaderNegativeRecord, ",")
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, header+"\nPT Artha Mulia,L1\n,L2\nPT Bersih,L3\n"))
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"PT Artha Mulia", "PT Bersih"}, pipelinetest.Answered(ts.Sim.Transactions(), "company_name"))
+
+		logs := pipelinetest.Failures(ts.Sim.Transactions())
+		require.Len(t, logs, 1)
+		assert.Equal(t, "L2", logs[0].LoanNo)
+		assert.Equal(t, http.StatusBadRequest, logs[0].Status)
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, header+"\ndown,L1\n"))
+		require.NoError(t, err)
+
+		logs := pipelinetest.Failures(ts.Sim.Transactions())
+		require.Len(t, logs, 1)
+		assert.Equal(t, http.StatusServiceUnavailable, logs[0].Status)
+	})
+}
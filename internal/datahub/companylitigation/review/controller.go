@@ -0,0 +1,102 @@
+package review
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(svc Service) Controller {
+	return &controller{svc}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	GetSettings(c *fiber.Ctx) error
+	SaveSettings(c *fiber.Ctx) error
+	GetReviews(c *fiber.Ctx) error
+	ReviewHit(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) GetSettings(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	settings, err := ctrl.svc.GetSettings(authCtx.CompanyId)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		settings,
+	))
+}
+
+func (ctrl *controller) SaveSettings(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*saveSettingsRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	settings, err := ctrl.svc.SaveSettings(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		settings,
+	))
+}
+
+func (ctrl *controller) GetReviews(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	reviews, err := ctrl.svc.GetReviews(authCtx.CompanyId, c.Params("trx_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		reviews,
+	))
+}
+
+func (ctrl *controller) ReviewHit(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*reviewHitRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	review, err := ctrl.svc.ReviewHit(authCtx, c.Params("trx_id"), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		review,
+	))
+}
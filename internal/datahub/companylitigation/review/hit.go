@@ -0,0 +1,47 @@
+package review
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AboveThreshold reports whether a hit with the similarity score is kept.
+// A score that is not a number is kept, a reviewer should see it.
+func AboveThreshold(score string, threshold float64) bool {
+	if threshold <= 0 {
+		return true
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(score), 64)
+	if err != nil {
+		return true
+	}
+
+	return value >= threshold
+}
+
+// Status sums up the reviews of the hits of a transaction, the hits are
+// given by their case numbers.
+func Status(caseNumbers []string, reviews []*Review) string {
+	if len(caseNumbers) == 0 {
+		return ""
+	}
+
+	decisions := make(map[string]string, len(reviews))
+	for _, r := range reviews {
+		decisions[r.CaseNumber] = r.Decision
+	}
+
+	status := DecisionDismissed
+	for _, caseNumber := range caseNumbers {
+		switch decisions[caseNumber] {
+		case DecisionConfirmed:
+			status = DecisionConfirmed
+		case DecisionDismissed:
+		default:
+			return StatusPending
+		}
+	}
+
+	return status
+}
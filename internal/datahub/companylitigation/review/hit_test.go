@@ -0,0 +1,51 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAboveThreshold(t *testing.T) {
+	tests := []struct {
+		score     string
+		threshold float64
+		want      bool
+	}{
+		{"90.5", 0, true},
+		{"90.5", 90, true},
+		{"90.0", 90, true},
+		{"89.9", 90, false},
+		{" 95 ", 90, true},
+		{"", 90, true},
+		{"n/a", 90, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, AboveThreshold(tt.score, tt.threshold), "score %q threshold %v", tt.score, tt.threshold)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	confirmed := &Review{CaseNumber: "1", Decision: DecisionConfirmed}
+	dismissed := &Review{CaseNumber: "2", Decision: DecisionDismissed}
+
+	tests := map[string]struct {
+		caseNumbers []string
+		reviews     []*Review
+		want        string
+	}{
+		"no hits":          {nil, []*Review{confirmed}, ""},
+		"not reviewed":     {[]string{"1", "2"}, nil, StatusPending},
+		"partly reviewed":  {[]string{"1", "2", "3"}, []*Review{confirmed, dismissed}, StatusPending},
+		"one confirmed":    {[]string{"1", "2"}, []*Review{confirmed, dismissed}, DecisionConfirmed},
+		"all dismissed":    {[]string{"2"}, []*Review{dismissed}, DecisionDismissed},
+		"review elsewhere": {[]string{"3"}, []*Review{confirmed}, StatusPending},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Status(tt.caseNumbers, tt.reviews))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package review
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Register serves the review settings and the hit reviews under the
+// negative record product group.
+func Register(productGroup fiber.Router, cfg *application.Config, service Service) {
+	controller := NewController(service)
+
+	productGroup.Get("/review-settings", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), controller.GetSettings)
+	productGroup.Put("/review-settings", middleware.GetJWTPayloadFromCookie(cfg), middleware.AdminAuth(), middleware.ValidateRequest(saveSettingsRequest{}), controller.SaveSettings)
+	productGroup.Get("/reviews/:trx_id", middleware.GetJWTPayloadFromCookie(cfg), controller.GetReviews)
+	productGroup.Put("/reviews/:trx_id", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(reviewHitRequest{}), controller.ReviewHit)
+}
@@ -0,0 +1,52 @@
+package review
+
+import "time"
+
+const (
+	// DecisionConfirmed marks a hit as a true positive, the case is about
+	// the requested company
+	DecisionConfirmed = "confirmed"
+	// DecisionDismissed marks a hit as a false positive
+	DecisionDismissed = "dismissed"
+
+	// StatusPending is the status of a transaction with unreviewed hits,
+	// otherwise it is confirmed when a hit was confirmed and dismissed when
+	// every hit was dismissed. A transaction without hits has no status.
+	StatusPending = "pending"
+)
+
+// Review is the decision of a reviewer on one hit, the hit is identified by
+// its case number within the transaction.
+type Review struct {
+	TransactionId string    `json:"transaction_id"`
+	CaseNumber    string    `json:"case_number"`
+	Decision      string    `json:"decision"`
+	Notes         string    `json:"notes"`
+	ReviewerId    uint      `json:"reviewer_id"`
+	ReviewerName  string    `json:"reviewer_name,omitempty"`
+	ReviewedAt    time.Time `json:"reviewed_at"`
+}
+
+// Settings are the review settings of a company.
+type Settings struct {
+	CompanyId uint `json:"company_id"`
+	// SimilarityThreshold drops the hits scoring below it, 0 keeps every hit
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+}
+
+type saveSettingsRequest struct {
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+}
+
+type reviewHitRequest struct {
+	CaseNumber string `json:"case_number" validate:"required~case number cannot be empty"`
+	Decision   string `json:"decision" validate:"required~decision cannot be empty"`
+	Notes      string `json:"notes"`
+}
+
+// loggedHits are the hits kept on a negative record transaction.
+type loggedHits struct {
+	Result []struct {
+		CaseNumber string `json:"case_number"`
+	} `json:"result"`
+}
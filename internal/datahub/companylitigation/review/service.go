@@ -0,0 +1,199 @@
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(repo Repository, transactionRepo transaction.Repository, operationRepo operation.Repository) Service {
+	return &service{
+		repo,
+		transactionRepo,
+		operationRepo,
+	}
+}
+
+type service struct {
+	repo            Repository
+	transactionRepo transaction.Repository
+	operationRepo   operation.Repository
+}
+
+type Service interface {
+	Lookup
+	GetSettings(companyId uint) (*Settings, error)
+	SaveSettings(authCtx *model.AuthContext, req *saveSettingsRequest) (*Settings, error)
+	GetReviews(companyId uint, trxId string) ([]*Review, error)
+	ReviewHit(authCtx *model.AuthContext, trxId string, req *reviewHitRequest) (*Review, error)
+}
+
+// Lookup gives the product requests, job details and exports the threshold
+// and the reviews of the negative record hits.
+type Lookup interface {
+	// Threshold returns 0 when the settings cannot be fetched, so a request
+	// keeps every hit rather than failing.
+	Threshold(companyId uint) float64
+	Reviews(companyId uint, trxIds []string) ([]*Review, error)
+}
+
+func (svc *service) GetSettings(companyId uint) (*Settings, error) {
+	settings, err := svc.repo.GetSettingsAPI(strconv.FormatUint(uint64(companyId), 10))
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, apperror.MapRepoError(err, constant.FailedFetchReviewSettings)
+		}
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if settings.CompanyId == 0 {
+		settings.CompanyId = companyId
+	}
+
+	return settings, nil
+}
+
+func (svc *service) SaveSettings(authCtx *model.AuthContext, req *saveSettingsRequest) (*Settings, error) {
+	if req.SimilarityThreshold < 0 || req.SimilarityThreshold > 100 {
+		return nil, apperror.BadRequest(constant.InvalidSimilarityThreshold)
+	}
+
+	saved, err := svc.repo.SaveSettingsAPI(authCtx.CompanyIdStr(), &Settings{
+		CompanyId:           authCtx.CompanyId,
+		SimilarityThreshold: req.SimilarityThreshold,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedSaveReviewSettings)
+	}
+
+	svc.addLogOperation(authCtx, constant.EventNegativeRecordThreshold)
+
+	return saved, nil
+}
+
+func (svc *service) GetReviews(companyId uint, trxId string) ([]*Review, error) {
+	reviews, err := svc.Reviews(companyId, []string{trxId})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchHitReviews)
+	}
+
+	return reviews, nil
+}
+
+func (svc *service) ReviewHit(authCtx *model.AuthContext, trxId string, req *reviewHitRequest) (*Review, error) {
+	decision := strings.ToLower(strings.TrimSpace(req.Decision))
+	if decision != DecisionConfirmed && decision != DecisionDismissed {
+		return nil, apperror.BadRequest(constant.InvalidHitReviewDecision)
+	}
+
+	if err := svc.checkHit(authCtx, trxId, req.CaseNumber); err != nil {
+		return nil, err
+	}
+
+	saved, err := svc.repo.SaveReviewAPI(authCtx.CompanyIdStr(), &Review{
+		TransactionId: trxId,
+		CaseNumber:    req.CaseNumber,
+		Decision:      decision,
+		Notes:         strings.TrimSpace(req.Notes),
+		ReviewerId:    authCtx.UserId,
+		ReviewedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedSaveHitReview)
+	}
+
+	svc.addLogOperation(authCtx, constant.EventNegativeRecordReviewHit)
+
+	return saved, nil
+}
+
+// checkHit makes sure the case number is a hit of a negative record
+// transaction of the company.
+func (svc *service) checkHit(authCtx *model.AuthContext, trxId, caseNumber string) error {
+	trx, err := svc.transactionRepo.GetLogTransAPI(authCtx.CompanyIdStr(), trxId)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return apperror.NotFound(constant.ReviewedTransactionNotFound)
+		}
+
+		return apperror.MapRepoError(err, constant.FailedFetchReviewedTransaction)
+	}
+	if trx == nil || trx.CompanyID != authCtx.CompanyId || !strings.HasPrefix(trx.TransactionID, constant.TrxIdNegativeRecord) {
+		return apperror.NotFound(constant.ReviewedTransactionNotFound)
+	}
+
+	var data loggedHits
+	if len(trx.Data) > 0 {
+		if err := json.Unmarshal(trx.Data, &data); err != nil {
+			return apperror.Internal(constant.FailedFetchReviewedTransaction, err)
+		}
+	}
+
+	for _, hit := range data.Result {
+		if hit.CaseNumber == caseNumber {
+			return nil
+		}
+	}
+
+	return apperror.BadRequest(constant.UnknownHitCaseNumber)
+}
+
+func (svc *service) Threshold(companyId uint) float64 {
+	settings, err := svc.GetSettings(companyId)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint("company_id", companyId).
+			Msg("failed to fetch negative record threshold")
+
+		return 0
+	}
+
+	return settings.SimilarityThreshold
+}
+
+func (svc *service) Reviews(companyId uint, trxIds []string) ([]*Review, error) {
+	if len(trxIds) == 0 {
+		return nil, nil
+	}
+
+	reviews, err := svc.repo.GetReviewsAPI(strconv.FormatUint(uint64(companyId), 10), trxIds)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, err
+		}
+	}
+	if reviews == nil {
+		reviews = []*Review{}
+	}
+
+	return reviews, nil
+}
+
+func (svc *service) addLogOperation(authCtx *model.AuthContext, event string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      event,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", event).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
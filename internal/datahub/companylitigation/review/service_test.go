@@ -0,0 +1,248 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coreStub answers the core calls by "METHOD path".
+type coreStub struct {
+	routes  map[string]func(*http.Request) (int, any)
+	bodies  map[string][][]byte
+	queries map[string][]string
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	s.bodies[key] = append(s.bodies[key], reqBody)
+	s.queries[key] = append(s.queries[key], req.URL.RawQuery)
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func setupService(routes map[string]func(*http.Request) (int, any)) (Service, *coreStub) {
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{routes: routes, bodies: map[string][][]byte{}, queries: map[string][]string{}}
+
+	return NewService(NewRepository(cfg, client, nil), transaction.NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil)), client
+}
+
+func respond(status int, data any) func(*http.Request) (int, any) {
+	return func(*http.Request) (int, any) {
+		return status, data
+	}
+}
+
+func TestServiceSettings(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+
+	t.Run("company settings", func(t *testing.T) {
+		svc, _ := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/negative-record/settings/9": respond(http.StatusOK, map[string]any{"company_id": 9, "similarity_threshold": 85}),
+		})
+
+		settings, err := svc.GetSettings(9)
+		require.NoError(t, err)
+		assert.Equal(t, 85.0, settings.SimilarityThreshold)
+		assert.Equal(t, 85.0, svc.Threshold(9))
+	})
+
+	t.Run("company without settings", func(t *testing.T) {
+		svc, _ := setupService(nil)
+
+		settings, err := svc.GetSettings(9)
+		require.NoError(t, err)
+		assert.Equal(t, &Settings{CompanyId: 9}, settings)
+	})
+
+	t.Run("core error", func(t *testing.T) {
+		svc, _ := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/negative-record/settings/9": respond(http.StatusInternalServerError, nil),
+		})
+
+		_, err := svc.GetSettings(9)
+		assert.Error(t, err)
+
+		// a request keeps every hit rather than failing
+		assert.Zero(t, svc.Threshold(9))
+	})
+
+	t.Run("save", func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"PUT /api/core/negative-record/settings/9": func(req *http.Request) (int, any) {
+				var settings map[string]any
+				_ = json.NewDecoder(req.Body).Decode(&settings)
+
+				return http.StatusOK, settings
+			},
+		})
+
+		settings, err := svc.SaveSettings(authCtx, &saveSettingsRequest{SimilarityThreshold: 90})
+		require.NoError(t, err)
+		assert.Equal(t, &Settings{CompanyId: 9, SimilarityThreshold: 90}, settings)
+		assert.JSONEq(t, `{"company_id": 9, "similarity_threshold": 90}`, string(client.bodies["PUT /api/core/negative-record/settings/9"][0]))
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		svc, client := setupService(nil)
+
+		_, err := svc.SaveSettings(authCtx, &saveSettingsRequest{SimilarityThreshold: 101})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Empty(t, client.bodies)
+	})
+}
+
+func TestServiceReviewHit(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 4, CompanyId: 9}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/logging/transaction/product-catalog/CNR-1": respond(http.StatusOK, negativeRecordTrx(9)),
+			"PUT /api/core/negative-record/reviews/9/CNR-1": func(req *http.Request) (int, any) {
+				var review map[string]any
+				_ = json.NewDecoder(req.Body).Decode(&review)
+
+				return http.StatusOK, review
+			},
+		})
+
+		review, err := svc.ReviewHit(authCtx, "CNR-1", &reviewHitRequest{CaseNumber: "411/Pdt.G/2025/PN JKT.SEL", Decision: " Dismissed ", Notes: " different company "})
+		require.NoError(t, err)
+		assert.Equal(t, "CNR-1", review.TransactionId)
+		assert.Equal(t, DecisionDismissed, review.Decision)
+		assert.Equal(t, "different company", review.Notes)
+		assert.Equal(t, uint(4), review.ReviewerId)
+		assert.False(t, review.ReviewedAt.IsZero())
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	t.Run("invalid decision", func(t *testing.T) {
+		svc, client := setupService(nil)
+
+		_, err := svc.ReviewHit(authCtx, "CNR-1", &reviewHitRequest{CaseNumber: "1", Decision: "maybe"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Empty(t, client.bodies)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		svc, client := setupService(nil)
+
+		_, err := svc.ReviewHit(authCtx, "CNR-X", &reviewHitRequest{CaseNumber: "1", Decision: DecisionConfirmed})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+		assert.Empty(t, client.bodies["PUT /api/core/negative-record/reviews/9/CNR-X"])
+	})
+
+	t.Run("transaction of another company", func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/logging/transaction/product-catalog/CNR-1": respond(http.StatusOK, negativeRecordTrx(7)),
+		})
+
+		_, err := svc.ReviewHit(authCtx, "CNR-1", &reviewHitRequest{CaseNumber: "411/Pdt.G/2025/PN JKT.SEL", Decision: DecisionConfirmed})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+		assert.Empty(t, client.bodies["PUT /api/core/negative-record/reviews/9/CNR-1"])
+	})
+
+	t.Run("case number not a hit", func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/logging/transaction/product-catalog/CNR-1": respond(http.StatusOK, negativeRecordTrx(9)),
+		})
+
+		_, err := svc.ReviewHit(authCtx, "CNR-1", &reviewHitRequest{CaseNumber: "98/Pdt.G/2024/PN JKT.PST", Decision: DecisionConfirmed})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Equal(t, constant.UnknownHitCaseNumber, appErr.Message)
+		assert.Empty(t, client.bodies["PUT /api/core/negative-record/reviews/9/CNR-1"])
+	})
+}
+
+// negativeRecordTrx is the logged negative record transaction CNR-1 of the
+// company with one hit kept.
+func negativeRecordTrx(companyId uint) map[string]any {
+	return map[string]any{
+		"transaction_id": "CNR-1",
+		"company_id":     companyId,
+		"data": map[string]any{"result": []map[string]any{
+			{"company_name": "PT SENGKETA", "similarity_score": "100.0", "case_number": "411/Pdt.G/2025/PN JKT.SEL"},
+		}},
+	}
+}
+
+func TestServiceReviews(t *testing.T) {
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client := setupService(map[string]func(*http.Request) (int, any){
+			"GET /api/core/negative-record/reviews/9": respond(http.StatusOK, []map[string]any{
+				{"transaction_id": "CNR-1", "case_number": "1", "decision": DecisionConfirmed},
+			}),
+		})
+
+		reviews, err := svc.Reviews(9, []string{"CNR-1", "CNR-2"})
+		require.NoError(t, err)
+		require.Len(t, reviews, 1)
+		assert.Equal(t, DecisionConfirmed, reviews[0].Decision)
+		assert.Equal(t, []string{"transaction_ids=CNR-1%2CCNR-2"}, client.queries["GET /api/core/negative-record/reviews/9"])
+	})
+
+	t.Run("no transactions", func(t *testing.T) {
+		svc, client := setupService(nil)
+
+		reviews, err := svc.Reviews(9, nil)
+		require.NoError(t, err)
+		assert.Empty(t, reviews)
+		assert.Empty(t, client.bodies)
+	})
+
+	t.Run("not reviewed yet", func(t *testing.T) {
+		svc, _ := setupService(nil)
+
+		reviews, err := svc.GetReviews(9, "CNR-1")
+		require.NoError(t, err)
+		assert.Equal(t, []*Review{}, reviews)
+	})
+}
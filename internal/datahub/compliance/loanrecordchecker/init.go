@@ -2,29 +2,40 @@ package loanrecordchecker
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, operationRepo, transactionRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[loanRecordCheckerRequest, dataLoanRecord]{
+	Slug:          constant.SlugLoanRecordChecker,
+	Route:         "loan-record-checker",
+	Name:          "loan record checker",
+	TrxPrefix:     constant.TrxIdLoanRecord,
+	Path:          "/product/compliance/loan-record-checker",
+	ForwardMember: true,
+	CSVHeaders:    constant.CSVTemplateHeaderLoanRecord,
+	FromCSV: func(record []string) *loanRecordCheckerRequest {
+		return &loanRecordCheckerRequest{
+			Name:   record[0],
+			Nik:    record[1],
+			Phone:  record[2],
+			LoanNo: record[3],
+		}
+	},
+	LoanNo: func(req *loanRecordCheckerRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent:      constant.EventLoanRecordSingleReq,
+	BulkEvent:        constant.EventLoanRecordBulkReq,
+	MapExternalError: apperror.MapLoanError,
+}
 
-	loanRecordCheckerGroup := apiGroup.Group("loan-record-checker")
-	loanRecordCheckerGroup.Post("/single-request", middleware.ValidateRequest(loanRecordCheckerRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	loanRecordCheckerGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"front-office/configs/application"
+	"front-office/internal/datahub/pipeline"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"io"
@@ -26,15 +27,15 @@ func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+func setupMockRepo(t *testing.T, response *http.Response, err error) (pipeline.Repository[loanRecordCheckerRequest, dataLoanRecord], *MockClient) {
 	t.Helper()
 
 	mockClient := new(MockClient)
 	mockClient.On("Do", mock.Anything).Return(response, err)
 
-	repo := NewRepository(&application.Config{
+	repo := pipeline.NewRepository(&application.Config{
 		App: &application.Environment{ProductCatalogHost: constant.MockHost},
-	}, mockClient, nil)
+	}, mockClient, nil, &product)
 
 	return repo, mockClient
 }
@@ -56,7 +57,7 @@ func TestCallLoanRecordCheckerAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.LoanRecordCheckerAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -71,11 +72,11 @@ func TestCallLoanRecordCheckerAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product)
 
-		result, err := repo.LoanRecordCheckerAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -83,11 +84,11 @@ func TestCallLoanRecordCheckerAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product)
 
-		_, err := repo.LoanRecordCheckerAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
 		assert.Error(t, err)
 	})
 
@@ -97,7 +98,7 @@ func TestCallLoanRecordCheckerAPI(t *testing.T) {
 		repo, mockClient := setupMockRepo(t, nil, expectedErr)
 
 		req := &loanRecordCheckerRequest{}
-		_, err := repo.LoanRecordCheckerAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -112,7 +113,7 @@ func TestCallLoanRecordCheckerAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.LoanRecordCheckerAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &loanRecordCheckerRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
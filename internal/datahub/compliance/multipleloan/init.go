@@ -2,28 +2,53 @@ package multipleloan
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
+type multipleLoanProduct = pipeline.Product[multipleLoanRequest, dataMultipleLoanResponse]
 
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
+var (
+	product7Days  = newMultipleLoanProduct("7d-multiple-loan", "7-days", constant.Slug7DaysMultipleLoan, constant.TrxId7DaysMultipleLoan, constant.Event7DMLSingleReq, constant.Event7DMLBulkReq)
+	product30Days = newMultipleLoanProduct("30d-multiple-loan", "30-days", constant.Slug30DaysMultipleLoan, constant.TrxId30DaysMultipleLoan, constant.Event30DMLSingleReq, constant.Event30DMLBulkReq)
+	product90Days = newMultipleLoanProduct("90d-multiple-loan", "90-days", constant.Slug90DaysMultipleLoan, constant.TrxId90DaysMultipleLoan, constant.Event90DMLSingleReq, constant.Event90DMLBulkReq)
+)
 
-	controller := NewController(service)
+// newMultipleLoanProduct declares one of the multiple loan periods, they
+// only differ in route, partner path, slug and events.
+func newMultipleLoanProduct(route, period, slug, trxPrefix, singleEvent, bulkEvent string) multipleLoanProduct {
+	return multipleLoanProduct{
+		Slug:          slug,
+		Route:         route,
+		Name:          "multiple loan checker",
+		TrxPrefix:     trxPrefix,
+		Path:          "/product/compliance/multiple-loan/" + period,
+		ForwardMember: true,
+		CSVHeaders:    constant.CSVTemplateHeaderMultipleLoan,
+		FromCSV: func(record []string) *multipleLoanRequest {
+			return &multipleLoanRequest{
+				Nik:    record[0],
+				Phone:  record[1],
+				LoanNo: record[2],
+			}
+		},
+		LoanNo: func(req *multipleLoanRequest) string {
+			return req.LoanNo
+		},
+		SingleEvent:      singleEvent,
+		BulkEvent:        bulkEvent,
+		MapExternalError: apperror.MapLoanError,
+	}
+}
 
-	apiGroup.Post("/:product_slug/single-request", middleware.ValidateRequest(multipleLoanRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.MultipleLoan)
-	apiGroup.Post("/:product_slug/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkMultipleLoan)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product7Days)
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product30Days)
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product90Days)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"front-office/configs/application"
+	"front-office/internal/datahub/pipeline"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"io"
@@ -26,15 +27,15 @@ func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+func setupMockRepo(t *testing.T, product *multipleLoanProduct, response *http.Response, err error) (pipeline.Repository[multipleLoanRequest, dataMultipleLoanResponse], *MockClient) {
 	t.Helper()
 
 	mockClient := new(MockClient)
 	mockClient.On("Do", mock.Anything).Return(response, err)
 
-	repo := NewRepository(&application.Config{
+	repo := pipeline.NewRepository(&application.Config{
 		App: &application.Environment{ProductCatalogHost: constant.MockHost},
-	}, mockClient, nil)
+	}, mockClient, nil, product)
 
 	return repo, mockClient
 }
@@ -54,9 +55,9 @@ func TestCallMultipleLoan7DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(bytes.NewReader(body)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product7Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan7Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -69,11 +70,11 @@ func TestCallMultipleLoan7DaysAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product7Days)
 
-		result, err := repo.CallMultipleLoan7Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -81,21 +82,21 @@ func TestCallMultipleLoan7DaysAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product7Days)
 
-		_, err := repo.CallMultipleLoan7Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Error(t, err)
 	})
 
 	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
 		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
 
-		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+		repo, mockClient := setupMockRepo(t, &product7Days, nil, expectedErr)
 
 		req := &multipleLoanRequest{}
-		_, err := repo.CallMultipleLoan7Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -108,9 +109,9 @@ func TestCallMultipleLoan7DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product7Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan7Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
@@ -132,9 +133,9 @@ func TestCallMultipleLoan30DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(bytes.NewReader(body)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product30Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan30Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -147,11 +148,11 @@ func TestCallMultipleLoan30DaysAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product30Days)
 
-		result, err := repo.CallMultipleLoan30Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -159,21 +160,21 @@ func TestCallMultipleLoan30DaysAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product30Days)
 
-		_, err := repo.CallMultipleLoan30Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Error(t, err)
 	})
 
 	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
 		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
 
-		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+		repo, mockClient := setupMockRepo(t, &product30Days, nil, expectedErr)
 
 		req := &multipleLoanRequest{}
-		_, err := repo.CallMultipleLoan30Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -186,9 +187,9 @@ func TestCallMultipleLoan30DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product30Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan30Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
@@ -210,9 +211,9 @@ func TestCallMultipleLoan90DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(bytes.NewReader(body)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product90Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan90Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -225,11 +226,11 @@ func TestCallMultipleLoan90DaysAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product90Days)
 
-		result, err := repo.CallMultipleLoan90Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -237,21 +238,21 @@ func TestCallMultipleLoan90DaysAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product90Days)
 
-		_, err := repo.CallMultipleLoan90Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Error(t, err)
 	})
 
 	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
 		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
 
-		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+		repo, mockClient := setupMockRepo(t, &product90Days, nil, expectedErr)
 
 		req := &multipleLoanRequest{}
-		_, err := repo.CallMultipleLoan90Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -264,9 +265,9 @@ func TestCallMultipleLoan90DaysAPI(t *testing.T) {
 			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
 		}
 
-		repo, mockClient := setupMockRepo(t, resp, nil)
+		repo, mockClient := setupMockRepo(t, &product90Days, resp, nil)
 
-		result, err := repo.CallMultipleLoan90Days(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &multipleLoanRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
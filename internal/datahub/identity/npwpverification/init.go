@@ -2,28 +2,36 @@ package npwpverification
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repository := NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repository, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
-	controller := NewController(service)
+var product = pipeline.Product[npwpVerificationRequest, npwpVerificationRespData]{
+	Slug:       constant.SlugNPWPVerification,
+	Route:      "npwp-verification",
+	Name:       "npwp verification",
+	TrxPrefix:  constant.TrxIdNPWPVerification,
+	Path:       "/product/identity/npwp-verification",
+	CSVHeaders: constant.CSVTemplateHeaderNPWPVerification,
+	FromCSV: func(record []string) *npwpVerificationRequest {
+		return &npwpVerificationRequest{
+			Npwp:   record[0],
+			LoanNo: record[1],
+		}
+	},
+	LoanNo: func(req *npwpVerificationRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventNPWPVerificationSingleReq,
+	BulkEvent:   constant.EventNPWPVerificationBulkReq,
+	HideResult:  true,
+}
 
-	npwpVerificationGroup := apiGroup.Group("npwp-verification")
-	npwpVerificationGroup.Post("/single-request", middleware.ValidateRequest(npwpVerificationRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	npwpVerificationGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
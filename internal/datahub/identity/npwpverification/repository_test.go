@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"front-office/configs/application"
+	"front-office/internal/datahub/pipeline"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"io"
@@ -26,15 +27,15 @@ func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+func setupMockRepo(t *testing.T, response *http.Response, err error) (pipeline.Repository[npwpVerificationRequest, npwpVerificationRespData], *MockClient) {
 	t.Helper()
 
 	mockClient := new(MockClient)
 	mockClient.On("Do", mock.Anything).Return(response, err)
 
-	repo := NewRepository(&application.Config{
+	repo := pipeline.NewRepository(&application.Config{
 		App: &application.Environment{ProductCatalogHost: constant.MockHost},
-	}, mockClient, nil)
+	}, mockClient, nil, &product)
 
 	return repo, mockClient
 }
@@ -56,7 +57,7 @@ func TestCallNPWPVerificationAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.NPWPVerificationAPI(constant.DummyAPIKey, constant.DummyJobId, &npwpVerificationRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &npwpVerificationRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -70,11 +71,11 @@ func TestCallNPWPVerificationAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product)
 
-		result, err := repo.NPWPVerificationAPI(constant.DummyAPIKey, constant.DummyJobId, &npwpVerificationRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &npwpVerificationRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -82,11 +83,11 @@ func TestCallNPWPVerificationAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product)
 
-		_, err := repo.NPWPVerificationAPI(constant.DummyAPIKey, constant.DummyJobId, &npwpVerificationRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &npwpVerificationRequest{})
 		assert.Error(t, err)
 	})
 
@@ -95,7 +96,7 @@ func TestCallNPWPVerificationAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, nil, expectedErr)
 
-		_, err := repo.NPWPVerificationAPI(constant.DummyAPIKey, constant.DummyJobId, &npwpVerificationRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &npwpVerificationRequest{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -110,7 +111,7 @@ func TestCallNPWPVerificationAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.NPWPVerificationAPI(constant.DummyAPIKey, constant.DummyJobId, &npwpVerificationRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &npwpVerificationRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
@@ -1,248 +0,0 @@
-package npwpverification
-
-import (
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/pkg/apperror"
-	"front-office/pkg/common/constant"
-	"front-office/pkg/common/model"
-	"front-office/pkg/helper"
-	"mime/multipart"
-	"net/http"
-	"strconv"
-	"sync"
-	"time"
-
-	"github.com/rs/zerolog/log"
-	"github.com/usepzaka/validator"
-)
-
-func NewService(
-	repo Repository,
-	memberRepo member.Repository,
-	jobRepo job.Repository,
-	transactionRepo transaction.Repository,
-	operationRepo operation.Repository,
-	jobService job.Service,
-) Service {
-	return &service{
-		repo,
-		memberRepo,
-		jobRepo,
-		transactionRepo,
-		operationRepo,
-		jobService,
-	}
-}
-
-type service struct {
-	repo            Repository
-	memberRepo      member.Repository
-	jobRepo         job.Repository
-	transactionRepo transaction.Repository
-	operationRepo   operation.Repository
-	jobService      job.Service
-}
-
-type Service interface {
-	NPWPVerification(authCtx *model.AuthContext, payload *npwpVerificationRequest) error
-	BulkNPWPVerification(authCtx *model.AuthContext, file *multipart.FileHeader) error
-}
-
-func (svc *service) NPWPVerification(authCtx *model.AuthContext, payload *npwpVerificationRequest) error {
-	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), constant.SlugNPWPVerification)
-	if err != nil {
-		return apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
-	}
-
-	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  authCtx.UserIdStr(),
-		CompanyId: authCtx.CompanyIdStr(),
-		Total:     1,
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedCreateJob)
-	}
-
-	jobIdStr := helper.ConvertUintToString(jobRes.JobId)
-	_, err = svc.repo.NPWPVerificationAPI(authCtx.APIKey, jobIdStr, payload)
-	if err != nil {
-		if err := svc.jobService.FinalizeFailedJob(jobIdStr); err != nil {
-			return err
-		}
-
-		return apperror.MapRepoError(err, "failed to process npwp verification")
-	}
-
-	if err := svc.jobService.FinalizeJob(jobIdStr); err != nil {
-		return err
-	}
-
-	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  authCtx.UserId,
-		CompanyId: authCtx.CompanyId,
-		Action:    constant.EventNPWPVerificationSingleReq,
-	}); err != nil {
-		log.Warn().
-			Err(err).
-			Str("action", constant.EventNPWPVerificationSingleReq).
-			Msg(constant.MsgFailedAddOperationLog)
-	}
-
-	return nil
-}
-
-func (svc *service) BulkNPWPVerification(authCtx *model.AuthContext, file *multipart.FileHeader) error {
-	records, err := helper.ParseCSVFile(file, constant.CSVTemplateHeaderNPWPVerification)
-	if err != nil {
-		return apperror.BadRequest(err.Error())
-	}
-
-	memberIdStr := authCtx.UserIdStr()
-	companyIdStr := authCtx.CompanyIdStr()
-	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(companyIdStr, constant.SlugNPWPVerification)
-	if err != nil {
-		return apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
-	}
-
-	subscribedIdStr := strconv.Itoa(int(subscribedResp.Data.SubsribedProductID))
-	quotaResp, err := svc.memberRepo.GetQuotaAPI(&member.QuotaParams{
-		MemberId:     memberIdStr,
-		CompanyId:    companyIdStr,
-		SubscribedId: subscribedIdStr,
-		QuotaType:    authCtx.QuotaTypeStr(),
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedFetchQuota)
-	}
-
-	totalRequest := len(records) - 1
-	if authCtx.QuotaType != 0 && quotaResp.Data.Quota < totalRequest {
-		return apperror.Forbidden(constant.ErrQuotaExceeded)
-	}
-
-	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  memberIdStr,
-		CompanyId: companyIdStr,
-		Total:     totalRequest,
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedCreateJob)
-	}
-
-	jobIdStr := strconv.Itoa(int(jobRes.JobId))
-	var npwpVerificationReqs []*npwpVerificationRequest
-	for i, rec := range records {
-		if i == 0 {
-			continue
-		}
-		npwpVerificationReqs = append(npwpVerificationReqs, &npwpVerificationRequest{
-			Npwp:   rec[0],
-			LoanNo: rec[1],
-		})
-	}
-
-	var (
-		wg         sync.WaitGroup
-		errChan    = make(chan error, len(npwpVerificationReqs))
-		batchCount = 0
-	)
-
-	for _, req := range npwpVerificationReqs {
-		wg.Add(1)
-
-		go func(npwpVerificationReq *npwpVerificationRequest) {
-			defer wg.Done()
-
-			if err := svc.processNPWPVerification(&npwpVerificationContext{
-				APIKey:         authCtx.APIKey,
-				JobIdStr:       jobIdStr,
-				MemberIdStr:    memberIdStr,
-				CompanyIdStr:   companyIdStr,
-				MemberId:       authCtx.UserId,
-				CompanyId:      authCtx.CompanyId,
-				ProductId:      subscribedResp.Data.ProductId,
-				ProductGroupId: subscribedResp.Data.Product.ProductGroupId,
-				JobId:          jobRes.JobId,
-				Request:        npwpVerificationReq,
-			}); err != nil {
-				errChan <- err
-			}
-		}(req)
-
-		batchCount++
-		if batchCount == 100 {
-			time.Sleep(time.Second)
-			batchCount = 0
-		}
-	}
-
-	wg.Wait()
-	close(errChan)
-
-	for err := range errChan {
-		log.Error().Err(err).Str("job_id", jobIdStr).Msg("error during bulk npwp verification processing")
-	}
-
-	if err := svc.jobService.FinalizeJob(jobIdStr); err != nil {
-		return err
-	}
-
-	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  authCtx.UserId,
-		CompanyId: authCtx.CompanyId,
-		Action:    constant.EventNPWPVerificationBulkReq,
-	}); err != nil {
-		log.Warn().
-			Err(err).
-			Str("action", constant.EventNPWPVerificationBulkReq).
-			Msg(constant.MsgFailedAddOperationLog)
-	}
-
-	return nil
-}
-
-func (svc *service) processNPWPVerification(params *npwpVerificationContext) error {
-	trxId := helper.GenerateTrx(constant.TrxIdNPWPVerification)
-	if err := validator.ValidateStruct(params.Request); err != nil {
-		_ = svc.logFailedTransaction(params, trxId, err.Error(), http.StatusBadRequest)
-
-		return apperror.BadRequest(err.Error())
-	}
-
-	_, err := svc.repo.NPWPVerificationAPI(params.APIKey, params.JobIdStr, params.Request)
-	if err != nil {
-		// _ = svc.logFailedTransaction(params, trxId, err.Error(), http.StatusBadGateway)
-		_ = svc.jobService.FinalizeFailedJob(params.JobIdStr)
-
-		return apperror.MapRepoError(err, "failed to process npwp verification")
-	}
-
-	return nil
-}
-
-func (svc *service) logFailedTransaction(params *npwpVerificationContext, trxId, msg string, status int) error {
-	return svc.transactionRepo.CreateLogTransAPI(&transaction.LogTransProCatRequest{
-		TransactionID:  trxId,
-		MemberID:       params.MemberId,
-		CompanyID:      params.CompanyId,
-		ProductID:      params.ProductId,
-		ProductGroupID: params.ProductGroupId,
-		JobID:          params.JobId,
-		Message:        msg,
-		Status:         status,
-		Success:        false,
-		LoanNo:         params.Request.LoanNo,
-		ResponseBody: &transaction.ResponseBody{
-			Input:    params.Request,
-			DateTime: time.Now().Format(constant.FormatDateAndTime),
-		},
-		RequestBody:  params.Request,
-		RequestTime:  time.Now(),
-		ResponseTime: time.Now(),
-	})
-}
@@ -6,7 +6,6 @@ import (
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/helper"
-	"mime/multipart"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,8 +22,6 @@ type controller struct {
 }
 
 type Controller interface {
-	SingleSearch(c *fiber.Ctx) error
-	BulkSearch(c *fiber.Ctx) error
 	GetJobs(c *fiber.Ctx) error
 	GetJobDetails(c *fiber.Ctx) error
 	ExportJobDetails(c *fiber.Ctx) error
@@ -32,48 +29,6 @@ type Controller interface {
 	ExportJobsSummary(c *fiber.Ctx) error
 }
 
-func (ctrl *controller) SingleSearch(c *fiber.Ctx) error {
-	reqBody, ok := c.Locals(constant.Request).(*phoneLiveStatusRequest)
-	if !ok {
-		return apperror.BadRequest(constant.InvalidRequestFormat)
-	}
-
-	authCtx, err := helper.GetAuthContext(c)
-	if err != nil {
-		return apperror.Unauthorized(err.Error())
-	}
-
-	if err := ctrl.svc.PhoneLiveStatus(authCtx, reqBody); err != nil {
-		return err
-	}
-
-	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
-		"success",
-		nil,
-	))
-}
-
-func (ctrl *controller) BulkSearch(c *fiber.Ctx) error {
-	file, ok := c.Locals(constant.ValidatedFile).(*multipart.FileHeader)
-	if !ok {
-		return apperror.BadRequest(constant.InvalidRequestFormat)
-	}
-
-	authCtx, err := helper.GetAuthContext(c)
-	if err != nil {
-		return apperror.Unauthorized(err.Error())
-	}
-
-	if err := ctrl.svc.BulkPhoneLiveStatus(authCtx, file); err != nil {
-		return err
-	}
-
-	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
-		"success",
-		nil,
-	))
-}
-
 func (ctrl *controller) GetJobs(c *fiber.Ctx) error {
 	authCtx, err := helper.GetAuthContext(c)
 	if err != nil {
@@ -155,7 +110,7 @@ func (ctrl *controller) ExportJobDetails(c *fiber.Ctx) error {
 
 	var buf bytes.Buffer
 
-	filename, err := ctrl.svc.ExportJobDetails(authCtx.UserId, authCtx.CompanyId, filter, &buf)
+	filename, err := ctrl.svc.ExportJobDetails(authCtx, filter, &buf)
 	if err != nil {
 		return err
 	}
@@ -220,7 +175,7 @@ func (ctrl *controller) ExportJobsSummary(c *fiber.Ctx) error {
 	}
 
 	var buf bytes.Buffer
-	filename, err := ctrl.svc.ExportJobsSummary(authCtx.UserId, authCtx.CompanyId, filter, &buf)
+	filename, err := ctrl.svc.ExportJobsSummary(authCtx, filter, &buf)
 	if err != nil {
 		return err
 	}
@@ -2,31 +2,47 @@ package phonelivestatus
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/decision"
 	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
 	"front-office/internal/middleware"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+var product = pipeline.Product[phoneLiveStatusRequest, phoneLiveStatusRespData]{
+	Slug:       constant.SlugPhoneLiveStatus,
+	Route:      "phone-live-status",
+	Name:       "phone live status",
+	TrxPrefix:  constant.TrxIdPhoneLiveStatus,
+	Path:       "/product/identity/phone-live-status",
+	CSVHeaders: constant.CSVTemplateHeaderPhoneLive,
+	FromCSV: func(record []string) *phoneLiveStatusRequest {
+		return &phoneLiveStatusRequest{
+			PhoneNumber: record[0],
+			LoanNo:      record[1],
+		}
+	},
+	LoanNo: func(req *phoneLiveStatusRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventPhoneLiveSingleReq,
+	BulkEvent:   constant.EventPhoneLiveBulkReq,
+	HideResult:  true,
+}
+
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
 	repository := NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
 	operationRepo := operation.NewRepository(cfg, client, nil)
 
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repository, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
+	service := NewService(repository, operationRepo)
 
 	controller := NewController(service)
 
-	phoneLiveStatusGroup := apiGroup.Group("phone-live-status")
-	phoneLiveStatusGroup.Post("/single-request", middleware.ValidateRequest(phoneLiveStatusRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	phoneLiveStatusGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+	phoneLiveStatusGroup := pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 	phoneLiveStatusGroup.Get("/jobs", middleware.GetJWTPayloadFromCookie(cfg), controller.GetJobs)
 	phoneLiveStatusGroup.Get("/jobs/:id/details", middleware.GetJWTPayloadFromCookie(cfg), controller.GetJobDetails)
 	phoneLiveStatusGroup.Get("/jobs/:id/details/export", middleware.GetJWTPayloadFromCookie(cfg), controller.ExportJobDetails)
@@ -55,7 +55,7 @@ type RefLogTrx struct {
 }
 
 type phoneLiveStatusRequest struct {
-	PhoneNumber string `json:"phone_number" validate:"required~phone number is required, min(10)~phone number must be at least 10 characters, indophone~invalid number"`
+	PhoneNumber string `json:"phone_number" normalize:"phone" validate:"required~phone number is required, min(10)~phone number must be at least 10 characters, indophone~invalid number"`
 	TrxId       string `json:"trx_id"`
 	LoanNo      string `json:"loan_no" validate:"required~Loan No cannot be empty."`
 }
@@ -171,14 +171,3 @@ type logTransInput struct {
 	PhoneNumber string `json:"phone_number,omitempty"`
 	LoanNo      string `json:"loan_no,omitempty"`
 }
-
-type phoneLiveStatusContext struct {
-	APIKey         string                  `json:"api_key"`
-	JobIdStr       string                  `json:"job_id_str"`
-	MemberId       uint                    `json:"member_id"`
-	CompanyId      uint                    `json:"company_id"`
-	ProductId      uint                    `json:"product_id"`
-	ProductGroupId uint                    `json:"product_group_id"`
-	JobId          uint                    `json:"job_id"`
-	Request        *phoneLiveStatusRequest `json:"request"`
-}
@@ -1,14 +1,12 @@
 package phonelivestatus
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"front-office/configs/application"
 	"front-office/pkg/common/constant"
-	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
 	"front-office/pkg/httpclient"
 	"front-office/pkg/jsonutil"
@@ -35,42 +33,12 @@ type repository struct {
 }
 
 type Repository interface {
-	PhoneLiveStatusAPI(apiKey, jobId string, payload *phoneLiveStatusRequest) (*model.ProCatAPIResponse[phoneLiveStatusRespData], error)
 	GetPhoneLiveStatusJobAPI(filter *phoneLiveStatusFilter) (*jobListRespData, error)
 	GetJobDetailsAPI(filter *phoneLiveStatusFilter) (*jobDetailRaw, error)
 	GetJobsSummaryAPI(filter *phoneLiveStatusFilter) (*jobDetailRaw, error)
 	GetJobMetricsAPI(filter *phoneLiveStatusFilter) (*jobMetrics, error)
 }
 
-func (repo *repository) PhoneLiveStatusAPI(apiKey, jobId string, payload *phoneLiveStatusRequest) (*model.ProCatAPIResponse[phoneLiveStatusRespData], error) {
-	url := fmt.Sprintf("%s/product/identity/phone-live-status", repo.cfg.App.ProductCatalogHost)
-
-	bodyBytes, err := repo.marshalFn(payload)
-	if err != nil {
-		return nil, errors.New(constant.ErrInvalidRequestPayload)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
-	}
-
-	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
-	req.Header.Set(constant.XAPIKey, apiKey)
-
-	q := req.URL.Query()
-	q.Add("job_id", jobId)
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := repo.client.Do(req)
-	if err != nil {
-		return nil, errors.New(constant.ErrUpstreamUnavailable)
-	}
-	defer resp.Body.Close()
-
-	return helper.ParseProCatAPIResponse[phoneLiveStatusRespData](resp)
-}
-
 func (repo *repository) GetPhoneLiveStatusJobAPI(filter *phoneLiveStatusFilter) (*jobListRespData, error) {
 	url := fmt.Sprintf("%s/api/core/product/%s/jobs", repo.cfg.App.AifcoreHost, filter.ProductSlug)
 
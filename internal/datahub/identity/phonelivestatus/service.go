@@ -5,206 +5,35 @@ import (
 	"encoding/csv"
 	"fmt"
 	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
-	"front-office/pkg/helper"
-	"mime/multipart"
-	"net/http"
-	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/rs/zerolog/log"
-	"github.com/usepzaka/validator"
 )
 
 func NewService(
 	repo Repository,
-	memberRepo member.Repository,
-	jobRepo job.Repository,
-	transactionRepo transaction.Repository,
 	operationRepo operation.Repository,
-	jobService job.Service,
 ) Service {
 	return &service{
 		repo,
-		memberRepo,
-		jobRepo,
-		transactionRepo,
 		operationRepo,
-		jobService,
 	}
 }
 
 type service struct {
-	repo            Repository
-	memberRepo      member.Repository
-	jobRepo         job.Repository
-	transactionRepo transaction.Repository
-	operationRepo   operation.Repository
-	jobService      job.Service
+	repo          Repository
+	operationRepo operation.Repository
 }
 
 type Service interface {
-	PhoneLiveStatus(authCtx *model.AuthContext, reqBody *phoneLiveStatusRequest) error
-	BulkPhoneLiveStatus(authCtx *model.AuthContext, fileHeader *multipart.FileHeader) error
 	GetJobs(filter *phoneLiveStatusFilter) (*jobListClientRespData, error)
 	GetJobDetails(filter *phoneLiveStatusFilter) (*jobDetailsDTO, error)
-	ExportJobDetails(memberId, companyId uint, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error)
+	ExportJobDetails(authCtx *model.AuthContext, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error)
 	GetJobsSummary(filter *phoneLiveStatusFilter) (*jobsSummaryDTO, error)
-	ExportJobsSummary(memberId, companyId uint, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error)
-}
-
-func (svc *service) PhoneLiveStatus(authCtx *model.AuthContext, reqBody *phoneLiveStatusRequest) error {
-	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), constant.SlugPhoneLiveStatus)
-	if err != nil {
-		return apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
-	}
-
-	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  authCtx.UserIdStr(),
-		CompanyId: authCtx.CompanyIdStr(),
-		Total:     1,
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedCreateJob)
-	}
-	jobIdStr := helper.ConvertUintToString(jobRes.JobId)
-
-	_, err = svc.repo.PhoneLiveStatusAPI(authCtx.APIKey, jobIdStr, reqBody)
-	if err != nil {
-		if err := svc.jobService.FinalizeFailedJob(jobIdStr); err != nil {
-			return err
-		}
-
-		return apperror.Internal("failed to process phone live status", err)
-	}
-
-	if err := svc.jobService.FinalizeJob(jobIdStr); err != nil {
-		return err
-	}
-
-	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  authCtx.UserId,
-		CompanyId: authCtx.CompanyId,
-		Action:    constant.EventPhoneLiveSingleReq,
-	}); err != nil {
-		log.Warn().
-			Err(err).
-			Str("action", constant.EventPhoneLiveSingleReq).
-			Msg(constant.MsgFailedAddOperationLog)
-	}
-
-	return nil
-}
-
-func (svc *service) BulkPhoneLiveStatus(authCtx *model.AuthContext, file *multipart.FileHeader) error {
-	records, err := helper.ParseCSVFile(file, constant.CSVTemplateHeaderPhoneLive)
-	if err != nil {
-		return apperror.BadRequest(err.Error())
-	}
-
-	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), constant.SlugPhoneLiveStatus)
-	if err != nil {
-		return apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
-	}
-
-	subscribedIdStr := strconv.Itoa(int(subscribedResp.Data.SubsribedProductID))
-	quotaResp, err := svc.memberRepo.GetQuotaAPI(&member.QuotaParams{
-		MemberId:     authCtx.UserIdStr(),
-		CompanyId:    authCtx.CompanyIdStr(),
-		SubscribedId: subscribedIdStr,
-		QuotaType:    authCtx.QuotaTypeStr(),
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedFetchQuota)
-	}
-
-	totalRequests := len(records) - 1
-	if authCtx.QuotaType != 0 && quotaResp.Data.Quota < totalRequests {
-		return apperror.Forbidden(constant.ErrQuotaExceeded)
-	}
-
-	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  authCtx.UserIdStr(),
-		CompanyId: authCtx.CompanyIdStr(),
-		Total:     totalRequests,
-	})
-	if err != nil {
-		return apperror.MapRepoError(err, constant.FailedCreateJob)
-	}
-	jobIdStr := helper.ConvertUintToString(jobRes.JobId)
-
-	var phoneReqs []*phoneLiveStatusRequest
-	for i := 1; i < len(records); i++ { // Skip header
-		phoneReqs = append(phoneReqs, &phoneLiveStatusRequest{
-			PhoneNumber: records[i][0],
-			LoanNo:      records[i][1],
-		})
-	}
-
-	var (
-		wg         sync.WaitGroup
-		errChan    = make(chan error, len(phoneReqs))
-		batchCount = 0
-	)
-
-	for _, req := range phoneReqs {
-		wg.Add(1)
-
-		go func(phoneLiveReq *phoneLiveStatusRequest) {
-			defer wg.Done()
-			if err := svc.processSingle(&phoneLiveStatusContext{
-				APIKey:         authCtx.APIKey,
-				JobIdStr:       jobIdStr,
-				MemberId:       jobRes.MemberId,
-				CompanyId:      jobRes.CompanyId,
-				ProductId:      subscribedResp.Data.ProductId,
-				ProductGroupId: subscribedResp.Data.Product.ProductGroupId,
-				JobId:          jobRes.JobId,
-				Request:        phoneLiveReq,
-			}); err != nil {
-				errChan <- err
-			}
-		}(req)
-
-		batchCount++
-		if batchCount == 100 {
-			time.Sleep(time.Second)
-			batchCount = 0
-		}
-	}
-
-	wg.Wait()
-	close(errChan)
-
-	for err := range errChan {
-		log.Error().Err(err).Str("job_id", jobIdStr).Msg("error during bulk phone live status processing")
-	}
-
-	if err := svc.jobService.FinalizeJob(jobIdStr); err != nil {
-		return err
-	}
-
-	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  authCtx.UserId,
-		CompanyId: authCtx.CompanyId,
-		Action:    constant.EventPhoneLiveBulkReq,
-	}); err != nil {
-		log.Warn().
-			Err(err).
-			Str("action", constant.EventPhoneLiveBulkReq).
-			Msg(constant.MsgFailedAddOperationLog)
-	}
-
-	return nil
+	ExportJobsSummary(authCtx *model.AuthContext, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error)
 }
 
 func (svc *service) GetJobs(filter *phoneLiveStatusFilter) (*jobListClientRespData, error) {
@@ -256,7 +85,7 @@ func (svc *service) GetJobDetails(filter *phoneLiveStatusFilter) (*jobDetailsDTO
 	return result, nil
 }
 
-func (svc *service) ExportJobDetails(memberId, companyId uint, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error) {
+func (svc *service) ExportJobDetails(authCtx *model.AuthContext, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error) {
 	data, err := svc.repo.GetJobDetailsAPI(filter)
 	if err != nil {
 		return "", apperror.MapRepoError(err, constant.ErrFetchPhoneLiveDetail)
@@ -279,9 +108,10 @@ func (svc *service) ExportJobDetails(memberId, companyId uint, filter *phoneLive
 	filename := formatCSVFileName("job_summary", filter.StartDate, filter.EndDate)
 
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  memberId,
-		CompanyId: companyId,
-		Action:    constant.EventPhoneLiveDownload,
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		APIClientId: authCtx.APIClientId,
+		Action:      constant.EventPhoneLiveDownload,
 	}); err != nil {
 		log.Warn().
 			Err(err).
@@ -320,7 +150,7 @@ func (svc *service) GetJobsSummary(filter *phoneLiveStatusFilter) (*jobsSummaryD
 	return result, nil
 }
 
-func (svc *service) ExportJobsSummary(memberId, companyId uint, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error) {
+func (svc *service) ExportJobsSummary(authCtx *model.AuthContext, filter *phoneLiveStatusFilter, buf *bytes.Buffer) (string, error) {
 	data, err := svc.repo.GetJobsSummaryAPI(filter)
 	if err != nil {
 		return "", apperror.MapRepoError(err, constant.ErrFetchPhoneLiveDetail)
@@ -344,9 +174,10 @@ func (svc *service) ExportJobsSummary(memberId, companyId uint, filter *phoneLiv
 	filename := formatCSVFileName("job_summary", filter.StartDate, filter.EndDate)
 
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  memberId,
-		CompanyId: companyId,
-		Action:    constant.EventPhoneLiveDownloadSummary,
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		APIClientId: authCtx.APIClientId,
+		Action:      constant.EventPhoneLiveDownloadSummary,
 	}); err != nil {
 		log.Warn().
 			Err(err).
@@ -357,30 +188,6 @@ func (svc *service) ExportJobsSummary(memberId, companyId uint, filter *phoneLiv
 	return filename, nil
 }
 
-func (svc *service) processSingle(params *phoneLiveStatusContext) error {
-	trxId := helper.GenerateTrx(constant.TrxIdPhoneLiveStatus)
-	if err := validator.ValidateStruct(params.Request); err != nil {
-		_ = svc.logFailedTransaction(params, trxId, err.Error(), http.StatusBadRequest)
-
-		return apperror.BadRequest(err.Error())
-	}
-
-	_, err := svc.repo.PhoneLiveStatusAPI(params.APIKey, params.JobIdStr, params.Request)
-	if err != nil {
-		_ = svc.logFailedTransaction(params, trxId, err.Error(), http.StatusBadGateway)
-		_ = svc.jobService.FinalizeFailedJob(params.JobIdStr)
-
-		// var apiErr *apperror.ExternalAPIError
-		// if errors.As(err, &apiErr) {
-		// 	return apperror.MapLoanError(apiErr)
-		// }
-
-		return apperror.Internal("failed to process phone live status", err)
-	}
-
-	return nil
-}
-
 func mapToJobDetail(masked bool, raw *logTransProductCatalog) (*mstPhoneLiveStatusJobDetail, error) {
 	var subscriberStatus, deviceStatus, phoneType, operator, phoneNumber string
 	if raw.Data != nil {
@@ -465,28 +272,6 @@ func formatCSVFileName(base, startDate, endDate string) string {
 	return fmt.Sprintf("%s_%s.csv", base, startDate)
 }
 
-func (svc *service) logFailedTransaction(params *phoneLiveStatusContext, trxId, msg string, status int) error {
-	return svc.transactionRepo.CreateLogTransAPI(&transaction.LogTransProCatRequest{
-		TransactionID:  trxId,
-		MemberID:       params.MemberId,
-		CompanyID:      params.CompanyId,
-		ProductID:      params.ProductId,
-		ProductGroupID: params.ProductGroupId,
-		JobID:          params.JobId,
-		Message:        msg,
-		Status:         status,
-		Success:        false,
-		LoanNo:         params.Request.LoanNo,
-		ResponseBody: &transaction.ResponseBody{
-			Input:    params.Request,
-			DateTime: time.Now().Format(constant.FormatDateAndTime),
-		},
-		RequestBody:  params.Request,
-		RequestTime:  time.Now(),
-		ResponseTime: time.Now(),
-	})
-}
-
 func mapToClientResponse(src *jobListRespData) *jobListClientRespData {
 	jobs := make([]mstPhoneLiveStatusClientJob, len(src.Jobs))
 	for i, j := range src.Jobs {
@@ -2,29 +2,36 @@ package phonenik
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, operationRepo, transactionRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[phoneNIKRequest, dataPhoneNIKAPI]{
+	Slug:       constant.SlugPhoneNIKMatching,
+	Route:      "phone-nik",
+	Name:       "phone to nik matching",
+	TrxPrefix:  constant.TrxIdPhoneNIK,
+	CSVHeaders: constant.CSVTemplateHeaderPhoneNIK,
+	FromCSV: func(record []string) *phoneNIKRequest {
+		return &phoneNIKRequest{
+			NIK:    record[0],
+			Phone:  record[1],
+			LoanNo: record[2],
+		}
+	},
+	LoanNo: func(req *phoneNIKRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventPhoneToNIKSingleReq,
+	BulkEvent:   constant.EventPhoneToNIKBulkReq,
+	Stub:        phoneNIKStub,
+}
 
-	phoneToNIKGroup := apiGroup.Group("phone-nik")
-	phoneToNIKGroup.Post("/single-request", middleware.ValidateRequest(phoneNIKRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleRequest)
-	phoneToNIKGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
@@ -0,0 +1,33 @@
+package phonenik
+
+import (
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"time"
+)
+
+// todo: remove once the partner endpoint is available
+func phoneNIKStub(trxId string, payload *phoneNIKRequest) *model.ProCatAPIResponse[dataPhoneNIKAPI] {
+	status := "not match"
+	if payload.Phone == "628111111110" && payload.NIK == "3576014403910003" {
+		status = "match"
+	}
+
+	return &model.ProCatAPIResponse[dataPhoneNIKAPI]{
+		Success: true,
+		Data: dataPhoneNIKAPI{
+			Status: status,
+		},
+		Input: phoneNIKRequest{
+			Phone:  payload.Phone,
+			NIK:    payload.NIK,
+			LoanNo: payload.LoanNo,
+		},
+		Message:         "Succeed to Request Data",
+		StatusCode:      http.StatusOK,
+		PricingStrategy: "FREE",
+		TransactionId:   trxId,
+		Date:            time.Now().Format(constant.FormatYYYYMMDD),
+	}
+}
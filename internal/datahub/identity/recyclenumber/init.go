@@ -2,29 +2,35 @@ package recyclenumber
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, operationRepo, transactionRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[recycleNumberRequest, dataRecycleNumberAPI]{
+	Slug:       constant.SlugRecycleNumber,
+	Route:      "recycle-number",
+	Name:       "recycle number",
+	TrxPrefix:  constant.TrxIdRecycleNumber,
+	Path:       "/product/identity/recycle-number",
+	CSVHeaders: constant.CSVTemplateHeaderRecycleNumber,
+	FromCSV: func(record []string) *recycleNumberRequest {
+		return &recycleNumberRequest{
+			Phone:  record[0],
+			LoanNo: record[1],
+		}
+	},
+	LoanNo: func(req *recycleNumberRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventRecycleNumberSingleReq,
+	BulkEvent:   constant.EventRecycleNumberBulkReq,
+}
 
-	recycleNumberGroup := apiGroup.Group("recycle-number")
-	recycleNumberGroup.Post("/single-request", middleware.ValidateRequest(recycleNumberRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleRequest)
-	recycleNumberGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
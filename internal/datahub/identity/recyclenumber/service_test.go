@@ -0,0 +1,115 @@
+package recyclenumber
+
+import (
+	"front-office/internal/datahub/pipeline/pipelinetest"
+	"front-office/internal/simulator"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const recycledPhone = "628111111110"
+
+// newSimulator starts a simulator where recycledPhone has been recycled and
+// the phone "628999999999" fails on the partner side.
+func newSimulator(t *testing.T) *simulator.TestServer {
+	t.Helper()
+
+	ts, err := simulator.NewTestServer(&simulator.Scenario{Products: map[string]*simulator.Product{
+		constant.SlugRecycleNumber: {Fixtures: []simulator.Fixture{
+			{Match: map[string]string{"phone_number": "628999999999"}, Status: http.StatusBadGateway, Message: "operator unavailable"},
+		}},
+	}})
+	require.NoError(t, err)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestSingleRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		result, err := svc.SingleRequest(authCtx, &recycleNumberRequest{Phone: recycledPhone, LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Equal(t, "phone number has been recycled", result.Data.Status)
+		assert.NotEmpty(t, result.TransactionId)
+		assert.Equal(t, constant.PaidStatus, result.PricingStrategy)
+
+		// the partner logs the transaction of a successful request
+		assert.Equal(t, []string{recycledPhone}, pipelinetest.Answered(ts.Sim.Transactions(), "phone_number"))
+		assert.Empty(t, pipelinetest.Failures(ts.Sim.Transactions()))
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		_, err := svc.SingleRequest(authCtx, &recycleNumberRequest{Phone: "628999999999", LoanNo: "L1"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadGateway, appErr.StatusCode)
+
+		assert.Equal(t, constant.JobStatusFailed, ts.Sim.Job(1)["status"])
+	})
+
+	t.Run("stub server", func(t *testing.T) {
+		ts := newSimulator(t)
+		cfg := ts.Config()
+		cfg.App.ProductCatalogHost = "http://127.0.0.1:1"
+		cfg.App.PartnerStubHost = ts.URL
+		cfg.App.PartnerStubProducts = "negative-record, recycle-number"
+		svc := pipelinetest.NewService(cfg, &product)
+
+		result, err := svc.SingleRequest(authCtx, &recycleNumberRequest{Phone: "6281234567890", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Equal(t, "phone number never happens recycled", result.Data.Status)
+		assert.Equal(t, []string{"6281234567890"}, pipelinetest.Answered(ts.Sim.Transactions(), "phone_number"))
+	})
+}
+
+func TestBulkRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+	header := strings.Join(constant.CSVTemplateHeaderRecycleNumber, ",")
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, header+"\n0811-1111-110,L1\n12345,L2\n+62 812 3456 7890,L3\n"))
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{recycledPhone, "6281234567890"}, pipelinetest.Answered(ts.Sim.Transactions(), "phone_number"))
+
+		logs := pipelinetest.Failures(ts.Sim.Transactions())
+		require.Len(t, logs, 1)
+		assert.Equal(t, "L2", logs[0].LoanNo)
+		assert.Equal(t, http.StatusBadRequest, logs[0].Status)
+		assert.False(t, logs[0].Success)
+
+		assert.Equal(t, constant.JobStatusDone, ts.Sim.Job(1)["status"])
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		ts := newSimulator(t)
+		svc := pipelinetest.NewService(ts.Config(), &product)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, header+"\n628999999999,L1\n"))
+		require.NoError(t, err)
+
+		logs := pipelinetest.Failures(ts.Sim.Transactions())
+		require.Len(t, logs, 1)
+		assert.Equal(t, http.StatusBadGateway, logs[0].Status)
+		assert.False(t, logs[0].Success)
+	})
+}
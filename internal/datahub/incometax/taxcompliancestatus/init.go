@@ -2,29 +2,31 @@ package taxcompliancestatus
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[taxComplianceStatusRequest, taxComplianceRespData]{
+	Slug:       constant.SlugTaxComplianceStatus,
+	Route:      "tax-compliance-status",
+	Name:       "tax compliance status",
+	TrxPrefix:  constant.TrxIdTaxCompliance,
+	Path:       "/product/incometax/tax-compliance-status",
+	CSVHeaders: constant.CSVTemplateHeaderTaxCompliance,
+	FromCSV: func(record []string) *taxComplianceStatusRequest {
+		return &taxComplianceStatusRequest{
+			Npwp: record[0],
+		}
+	},
+	SingleEvent: constant.EventTaxComplianceSingleReq,
+	BulkEvent:   constant.EventTaxComplianceBulkReq,
+}
 
-	taxComplianceGroup := apiGroup.Group("tax-compliance-status")
-	taxComplianceGroup.Post("/single-request", middleware.ValidateRequest(taxComplianceStatusRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	taxComplianceGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"front-office/configs/application"
+	"front-office/internal/datahub/pipeline"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"io"
@@ -26,15 +27,15 @@ func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
-func setupMockRepo(t *testing.T, response *http.Response, err error) (Repository, *MockClient) {
+func setupMockRepo(t *testing.T, response *http.Response, err error) (pipeline.Repository[taxComplianceStatusRequest, taxComplianceRespData], *MockClient) {
 	t.Helper()
 
 	mockClient := new(MockClient)
 	mockClient.On("Do", mock.Anything).Return(response, err)
 
-	repo := NewRepository(&application.Config{
+	repo := pipeline.NewRepository(&application.Config{
 		App: &application.Environment{ProductCatalogHost: constant.MockHost},
-	}, mockClient, nil)
+	}, mockClient, nil, &product)
 
 	return repo, mockClient
 }
@@ -59,7 +60,7 @@ func TestCallTaxComplianceStatusAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.TaxComplianceStatusAPI(constant.DummyAPIKey, constant.DummyJobId, &taxComplianceStatusRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &taxComplianceStatusRequest{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -75,11 +76,11 @@ func TestCallTaxComplianceStatusAPI(t *testing.T) {
 			return nil, errors.New(constant.ErrInvalidRequestPayload)
 		}
 
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockHost},
-		}, &MockClient{}, fakeMarshal)
+		}, &MockClient{}, fakeMarshal, &product)
 
-		result, err := repo.TaxComplianceStatusAPI(constant.DummyAPIKey, constant.DummyJobId, &taxComplianceStatusRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &taxComplianceStatusRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrInvalidRequestPayload)
@@ -87,11 +88,11 @@ func TestCallTaxComplianceStatusAPI(t *testing.T) {
 
 	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
 		mockClient := new(MockClient)
-		repo := NewRepository(&application.Config{
+		repo := pipeline.NewRepository(&application.Config{
 			App: &application.Environment{ProductCatalogHost: constant.MockInvalidHost},
-		}, mockClient, nil)
+		}, mockClient, nil, &product)
 
-		_, err := repo.TaxComplianceStatusAPI(constant.DummyAPIKey, constant.DummyJobId, &taxComplianceStatusRequest{})
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &taxComplianceStatusRequest{})
 		assert.Error(t, err)
 	})
 
@@ -101,7 +102,7 @@ func TestCallTaxComplianceStatusAPI(t *testing.T) {
 		repo, mockClient := setupMockRepo(t, nil, expectedErr)
 
 		req := &taxComplianceStatusRequest{}
-		_, err := repo.TaxComplianceStatusAPI(constant.DummyAPIKey, constant.DummyJobId, req)
+		_, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, req)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
@@ -116,7 +117,7 @@ func TestCallTaxComplianceStatusAPI(t *testing.T) {
 
 		repo, mockClient := setupMockRepo(t, resp, nil)
 
-		result, err := repo.TaxComplianceStatusAPI(constant.DummyAPIKey, constant.DummyJobId, &taxComplianceStatusRequest{})
+		result, err := repo.CallAPI(constant.DummyAPIKey, constant.DummyJobId, constant.DummyMemberId, constant.DummyCompanyId, &taxComplianceStatusRequest{})
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		mockClient.AssertExpectations(t)
@@ -0,0 +1,109 @@
+package taxreport
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(svc Service) Controller {
+	return &controller{svc}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	Report(c *fiber.Ctx) error
+	BulkReport(c *fiber.Ctx) error
+	GetTaxReport(c *fiber.Ctx) error
+	ExportTaxReport(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) Report(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*taxReportRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.Report(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
+func (ctrl *controller) BulkReport(c *fiber.Ctx) error {
+	file, ok := c.Locals(constant.ValidatedFile).(*multipart.FileHeader)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	result, err := ctrl.svc.BulkReport(authCtx, file)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
+func (ctrl *controller) GetTaxReport(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	result, err := ctrl.svc.GetTaxReport(authCtx, c.Params("id"), masked)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
+func (ctrl *controller) ExportTaxReport(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	result, err := ctrl.svc.ExportTaxReport(authCtx, c.Params("id"), masked)
+	if err != nil {
+		return err
+	}
+
+	c.Set(constant.HeaderContentType, result.ContentType)
+	c.Set(constant.HeaderContentDisposition, `attachment; filename="`+result.Filename+`"`)
+	c.Set("Content-Length", strconv.Itoa(len(result.Data)))
+
+	return c.Send(result.Data)
+}
@@ -0,0 +1,131 @@
+package taxreport
+
+import (
+	"bytes"
+	"fmt"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	reportSheet   = "Tax Report"
+	productsSheet = "Products"
+)
+
+func (row *taxpayerRow) mask() {
+	row.NpwpOrNik = helper.MaskingHead(row.NpwpOrNik, 10)
+
+	if field, ok := row.Fields["npwp"]; ok {
+		field.Value = helper.MaskingHead(field.Value, 10)
+		for i := range field.Conflicts {
+			field.Conflicts[i].Value = helper.MaskingHead(field.Conflicts[i].Value, 10)
+		}
+	}
+
+	// the partner data echoes the npwp as well
+	for i := range row.Products {
+		values := dataMap(row.Products[i].Data)
+		if npwp, ok := values["npwp"].(string); ok && npwp != "" {
+			values["npwp"] = helper.MaskingHead(npwp, 10)
+			row.Products[i].Data = values
+		}
+	}
+}
+
+// renderXlsx writes one row per taxpayer with the product each field was
+// taken from next to it, and one row per product result on a second sheet.
+func renderXlsx(report *taxReport) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(f.GetSheetName(0), reportSheet); err != nil {
+		return nil, err
+	}
+	if _, err := f.NewSheet(productsSheet); err != nil {
+		return nil, err
+	}
+
+	header := []any{constant.CSVHeaderNPWPOrNIK, constant.CSVHeaderLoanNumber, constant.CSVHeaderStatus}
+	for _, def := range reportFields {
+		header = append(header, def.header, def.header+" Source")
+	}
+	header = append(header, "Conflicts")
+
+	rows := [][]any{header}
+	for _, row := range report.Rows {
+		values := []any{row.NpwpOrNik, row.LoanNo, row.Status}
+		var conflicts []string
+		for _, def := range reportFields {
+			field, ok := row.Fields[def.name]
+			if !ok {
+				values = append(values, "", "")
+				continue
+			}
+
+			values = append(values, field.Value, field.Source)
+			for _, conflict := range field.Conflicts {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s (%s)", def.header, conflict.Value, conflict.Source))
+			}
+		}
+		values = append(values, strings.Join(conflicts, "; "))
+
+		rows = append(rows, values)
+	}
+	if err := writeSheet(f, reportSheet, rows); err != nil {
+		return nil, err
+	}
+
+	rows = [][]any{{
+		constant.CSVHeaderNPWPOrNIK, constant.CSVHeaderLoanNumber, constant.CSVHeaderProductName, constant.CSVHeaderStatus,
+		constant.CSVHeaderStatusCode, "Message", constant.CSVHeaderTransactionID, constant.CSVHeaderPricingStrategy,
+	}}
+	for _, row := range report.Rows {
+		for _, result := range row.Products {
+			rows = append(rows, []any{
+				row.NpwpOrNik, row.LoanNo, result.Name, result.Status,
+				result.StatusCode, result.Message, result.TransactionId, result.PricingStrategy,
+			})
+		}
+	}
+	if err := writeSheet(f, productsSheet, rows); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeSheet writes the rows from A1 with the first row frozen as header.
+func writeSheet(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+
+	lastCol, err := excelize.ColumnNumberToName(len(rows[0]))
+	if err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", lastCol, 20); err != nil {
+		return err
+	}
+
+	return f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
@@ -0,0 +1,28 @@
+package taxreport
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupInit must run after the tax products are registered, the report can
+// only run the products registered at that point.
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+	repository := NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+
+	service := NewService(repository, memberRepo, operationRepo, pipeline.Runners())
+	controller := NewController(service)
+
+	apiGroup.Post("/", middleware.ValidateRequest(taxReportRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.Report)
+	apiGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkReport)
+	apiGroup.Get("/:id", middleware.GetJWTPayloadFromCookie(cfg), controller.GetTaxReport)
+	apiGroup.Get("/:id/export", middleware.GetJWTPayloadFromCookie(cfg), controller.ExportTaxReport)
+}
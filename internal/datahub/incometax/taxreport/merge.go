@@ -0,0 +1,103 @@
+package taxreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"front-office/pkg/common/constant"
+	"strings"
+)
+
+// taxProducts are in order of precedence, the first product answering a
+// field provides its value.
+var taxProducts = []taxProduct{
+	{route: routeTaxVerification, slug: constant.SlugTaxVerificationDetail},
+	{route: routeTaxScore, slug: constant.SlugTaxScore},
+	{route: routeTaxCompliance, slug: constant.SlugTaxComplianceStatus},
+}
+
+// fieldSource is a field of the data of one product.
+type fieldSource struct {
+	route string
+	key   string
+}
+
+type reportFieldDef struct {
+	name    string
+	header  string
+	sources []fieldSource
+}
+
+// reportFields are the columns of the report. The status of the tax
+// compliance status product is its compliance result, so it is merged with
+// the tax compliance of the verification detail.
+var reportFields = []reportFieldDef{
+	{"nama", constant.CSVHeaderName, []fieldSource{{routeTaxVerification, "nama"}, {routeTaxScore, "nama"}, {routeTaxCompliance, "nama"}}},
+	{"alamat", constant.CSVHeaderAddress, []fieldSource{{routeTaxVerification, "alamat"}, {routeTaxScore, "alamat"}, {routeTaxCompliance, "alamat"}}},
+	{"npwp", constant.CSVHeaderNPWP, []fieldSource{{routeTaxVerification, "npwp"}}},
+	{"npwp_verification", constant.CSVHeaderNPWPVerification, []fieldSource{{routeTaxVerification, "npwp_verification"}}},
+	{"tax_compliance", constant.CSVHeaderTaxCompliance, []fieldSource{{routeTaxVerification, "tax_compliance"}, {routeTaxCompliance, "status"}}},
+	{"score", constant.CSVHeaderScore, []fieldSource{{routeTaxScore, "score"}}},
+	{"status", constant.CSVHeaderDataStatus, []fieldSource{{routeTaxVerification, "status"}, {routeTaxScore, "status"}}},
+}
+
+// mergeFields takes every report field from the succeeded products, values
+// that differ only in case or surrounding spaces are not a conflict. Fields
+// no product answered are left out.
+func mergeFields(results []productResult) map[string]*reportField {
+	data := make(map[string]map[string]any, len(results))
+	for _, result := range results {
+		if result.Status == productStatusSuccess {
+			data[result.Product] = dataMap(result.Data)
+		}
+	}
+
+	fields := map[string]*reportField{}
+	for _, def := range reportFields {
+		var field *reportField
+		for _, source := range def.sources {
+			value := stringValue(data[source.route][source.key])
+			if value == "" {
+				continue
+			}
+
+			if field == nil {
+				field = &reportField{Value: value, Source: source.route}
+				continue
+			}
+			if !strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(field.Value)) {
+				field.Conflicts = append(field.Conflicts, sourcedValue{Value: value, Source: source.route})
+			}
+		}
+
+		if field != nil {
+			fields[def.name] = field
+		}
+	}
+
+	return fields
+}
+
+func dataMap(data any) map[string]any {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil
+	}
+
+	return values
+}
+
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,104 @@
+package taxreport
+
+import "time"
+
+const (
+	productStatusSuccess = "success"
+	productStatusFailed  = "failed"
+
+	routeTaxVerification = "tax-verification-detail"
+	routeTaxScore        = "tax-score"
+	routeTaxCompliance   = "tax-compliance-status"
+)
+
+// taxProduct is a tax product the report runs when the company subscribes
+// to it.
+type taxProduct struct {
+	route string
+	slug  string
+}
+
+// taxpayerBody is sent to every tax product as its single request body, the
+// json names match the fields of the product requests.
+type taxpayerBody struct {
+	Npwp      string `json:"npwp"`
+	NpwpOrNik string `json:"npwp_or_nik"`
+	LoanNo    string `json:"loan_no"`
+}
+
+type taxpayer struct {
+	NpwpOrNik string `json:"npwp_or_nik"`
+	LoanNo    string `json:"loan_no"`
+}
+
+type taxReportRequest struct {
+	Taxpayers []taxpayer `json:"taxpayers"`
+}
+
+type createTaxReportRequest struct {
+	MemberId    uint     `json:"member_id"`
+	CompanyId   uint     `json:"company_id"`
+	APIClientId string   `json:"api_client_id,omitempty"`
+	Products    []string `json:"products"`
+	Total       int      `json:"total"`
+	Status      string   `json:"status"`
+}
+
+type createTaxReportRespData struct {
+	TaxReportId uint `json:"tax_report_id"`
+}
+
+type updateTaxReportRequest struct {
+	Status string         `json:"status"`
+	Rows   []*taxpayerRow `json:"rows"`
+}
+
+type taxReport struct {
+	Id        uint           `json:"id"`
+	MemberId  uint           `json:"member_id"`
+	CompanyId uint           `json:"company_id"`
+	Status    string         `json:"status"`
+	Products  []string       `json:"products"`
+	Rows      []*taxpayerRow `json:"rows"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// taxpayerRow merges the tax product results of one taxpayer, Fields are
+// keyed by the report field name, e.g. "nama".
+type taxpayerRow struct {
+	NpwpOrNik string                  `json:"npwp_or_nik"`
+	LoanNo    string                  `json:"loan_no"`
+	Status    string                  `json:"status"`
+	Fields    map[string]*reportField `json:"fields"`
+	Products  []productResult         `json:"products"`
+}
+
+// reportField is a merged value with the product it was taken from, the
+// other products that answered a different value are kept as conflicts.
+type reportField struct {
+	Value     string         `json:"value"`
+	Source    string         `json:"source"`
+	Conflicts []sourcedValue `json:"conflicts,omitempty"`
+}
+
+type sourcedValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+type productResult struct {
+	Product         string `json:"product"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	StatusCode      int    `json:"status_code"`
+	Message         string `json:"message"`
+	TransactionId   string `json:"transaction_id,omitempty"`
+	PricingStrategy string `json:"pricing_strategy,omitempty"`
+	Data            any    `json:"data,omitempty"`
+}
+
+type exportFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
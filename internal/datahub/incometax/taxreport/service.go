@@ -0,0 +1,370 @@
+package taxreport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"front-office/pkg/identifier"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(repo Repository, memberRepo member.Repository, operationRepo operation.Repository, runners map[string]pipeline.Runner) Service {
+	return &service{
+		repo,
+		memberRepo,
+		operationRepo,
+		runners,
+	}
+}
+
+type service struct {
+	repo          Repository
+	memberRepo    member.Repository
+	operationRepo operation.Repository
+	runners       map[string]pipeline.Runner
+}
+
+type Service interface {
+	Report(authCtx *model.AuthContext, req *taxReportRequest) (*taxReport, error)
+	BulkReport(authCtx *model.AuthContext, file *multipart.FileHeader) (*taxReport, error)
+	GetTaxReport(authCtx *model.AuthContext, id string, masked bool) (*taxReport, error)
+	ExportTaxReport(authCtx *model.AuthContext, id string, masked bool) (*exportFile, error)
+}
+
+func (svc *service) Report(authCtx *model.AuthContext, req *taxReportRequest) (*taxReport, error) {
+	return svc.run(authCtx, req.Taxpayers, constant.EventTaxReportReq)
+}
+
+// BulkReport reads the taxpayers from the CSV template, the whole file is
+// rejected when a row is invalid so no quota is spent on a broken upload.
+func (svc *service) BulkReport(authCtx *model.AuthContext, file *multipart.FileHeader) (*taxReport, error) {
+	records, err := helper.ParseCSVFile(file, constant.CSVTemplateHeaderTaxReport)
+	if err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+
+	taxpayers := make([]taxpayer, 0, len(records))
+	for i, record := range records {
+		if i == 0 || len(record) < len(constant.CSVTemplateHeaderTaxReport) {
+			continue
+		}
+
+		taxpayers = append(taxpayers, taxpayer{
+			NpwpOrNik: record[0],
+			LoanNo:    record[1],
+		})
+	}
+
+	return svc.run(authCtx, taxpayers, constant.EventTaxReportBulkReq)
+}
+
+// run runs the subscribed tax products for every taxpayer under one tax
+// report. A failing product is reported in its row and does not stop the
+// others, the row keeps the fields the other products answered. The quota of
+// every product is reserved for all taxpayers before any of them runs.
+func (svc *service) run(authCtx *model.AuthContext, taxpayers []taxpayer, event string) (*taxReport, error) {
+	if len(taxpayers) == 0 {
+		return nil, apperror.BadRequest(constant.TaxReportTaxpayersRequired)
+	}
+
+	taxpayers, err := normalizeTaxpayers(taxpayers)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := svc.subscribedProducts(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := pipeline.Reserve(authCtx, svc.runners, routes, len(taxpayers))
+	if err != nil {
+		return nil, err
+	}
+	defer reservations.Release()
+
+	created, err := svc.repo.CreateTaxReportAPI(&createTaxReportRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		APIClientId: authCtx.APIClientId,
+		Products:    routes,
+		Total:       len(taxpayers),
+		Status:      constant.JobStatusInProgress,
+	})
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedCreateTaxReport)
+	}
+
+	rows := make([]*taxpayerRow, len(taxpayers))
+
+	var (
+		wg         sync.WaitGroup
+		batchCount = 0
+	)
+	for i, tp := range taxpayers {
+		wg.Add(1)
+
+		go func(i int, tp taxpayer) {
+			defer wg.Done()
+
+			rows[i] = svc.runTaxpayer(authCtx, created.TaxReportId, reservations, routes, tp)
+		}(i, tp)
+
+		batchCount++
+		if batchCount == 100 {
+			time.Sleep(time.Second)
+			batchCount = 0
+		}
+	}
+	wg.Wait()
+
+	status := reportStatus(rows)
+	idStr := helper.ConvertUintToString(created.TaxReportId)
+	if err := svc.repo.UpdateTaxReportAPI(idStr, &updateTaxReportRequest{
+		Status: status,
+		Rows:   rows,
+	}); err != nil {
+		// the products have run and are logged, so the result is still returned
+		log.Error().
+			Err(err).
+			Str("tax_report_id", idStr).
+			Msg("failed to store tax report result")
+	}
+
+	svc.addLogOperation(authCtx, event)
+
+	return &taxReport{
+		Id:        created.TaxReportId,
+		MemberId:  authCtx.UserId,
+		CompanyId: authCtx.CompanyId,
+		Status:    status,
+		Products:  routes,
+		Rows:      rows,
+	}, nil
+}
+
+func (svc *service) GetTaxReport(authCtx *model.AuthContext, id string, masked bool) (*taxReport, error) {
+	report, err := svc.repo.GetTaxReportAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.NotFound(constant.TaxReportNotFound)
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchTaxReport)
+	}
+	if report == nil || report.Id == 0 || report.CompanyId != authCtx.CompanyId {
+		return nil, apperror.NotFound(constant.TaxReportNotFound)
+	}
+
+	if masked {
+		for _, row := range report.Rows {
+			row.mask()
+		}
+	}
+
+	return report, nil
+}
+
+func (svc *service) ExportTaxReport(authCtx *model.AuthContext, id string, masked bool) (*exportFile, error) {
+	report, err := svc.GetTaxReport(authCtx, id, masked)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := renderXlsx(report)
+	if err != nil {
+		return nil, apperror.Internal("failed to render tax report", err)
+	}
+
+	svc.addLogOperation(authCtx, constant.EventTaxReportDownload)
+
+	return &exportFile{
+		Filename:    fmt.Sprintf("tax_report_%d.xlsx", report.Id),
+		ContentType: constant.MimeXlsx,
+		Data:        data,
+	}, nil
+}
+
+// subscribedProducts returns the routes of the tax products the company
+// subscribes to, the core answers 404 for a product it does not.
+func (svc *service) subscribedProducts(authCtx *model.AuthContext) ([]string, error) {
+	var routes []string
+	for _, product := range taxProducts {
+		if _, ok := svc.runners[product.route]; !ok {
+			continue
+		}
+
+		if _, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), product.slug); err != nil {
+			var apiErr *apperror.ExternalAPIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+
+			return nil, apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
+		}
+
+		routes = append(routes, product.route)
+	}
+
+	if len(routes) == 0 {
+		return nil, apperror.BadRequest(constant.NoTaxProductSubscribed)
+	}
+
+	return routes, nil
+}
+
+func (svc *service) runTaxpayer(authCtx *model.AuthContext, reportId uint, reservations pipeline.Reservations, routes []string, tp taxpayer) *taxpayerRow {
+	row := &taxpayerRow{
+		NpwpOrNik: tp.NpwpOrNik,
+		LoanNo:    tp.LoanNo,
+		Products:  make([]productResult, 0, len(routes)),
+	}
+
+	body, err := json.Marshal(taxpayerBody{
+		Npwp:      tp.NpwpOrNik,
+		NpwpOrNik: tp.NpwpOrNik,
+		LoanNo:    tp.LoanNo,
+	})
+	if err != nil {
+		row.Status = constant.JobStatusFailed
+		return row
+	}
+
+	for _, route := range routes {
+		row.Products = append(row.Products, svc.runProduct(authCtx, &pipeline.Parent{
+			TaxReportId: reportId,
+			Reservation: reservations[route],
+		}, route, body))
+	}
+
+	row.Status = rowStatus(row.Products)
+	row.Fields = mergeFields(row.Products)
+
+	return row
+}
+
+func (svc *service) runProduct(authCtx *model.AuthContext, parent *pipeline.Parent, route string, body []byte) productResult {
+	runner := svc.runners[route]
+	result := productResult{
+		Product: route,
+		Name:    runner.Name(),
+	}
+
+	runResult, err := runner.Run(authCtx, parent, body)
+	if err != nil {
+		result.Status = productStatusFailed
+		result.StatusCode = http.StatusInternalServerError
+		result.Message = err.Error()
+
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) {
+			result.StatusCode = appErr.StatusCode
+			result.Message = appErr.Message
+		}
+
+		return result
+	}
+
+	result.Status = productStatusSuccess
+	result.StatusCode = runResult.StatusCode
+	result.Message = runResult.Message
+	result.TransactionId = runResult.TransactionId
+	result.PricingStrategy = runResult.PricingStrategy
+	if !runResult.Hidden {
+		result.Data = runResult.Data
+	}
+
+	return result
+}
+
+func (svc *service) addLogOperation(authCtx *model.AuthContext, event string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      event,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", event).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+// normalizeTaxpayers brings every NPWP or NIK to its 16 digit form, rows are
+// numbered from 1 in the errors.
+func normalizeTaxpayers(taxpayers []taxpayer) ([]taxpayer, error) {
+	normalized := make([]taxpayer, len(taxpayers))
+	for i, tp := range taxpayers {
+		npwpOrNik, err := identifier.NormalizeNPWP(tp.NpwpOrNik)
+		if err != nil {
+			return nil, apperror.BadRequest(fmt.Sprintf(constant.InvalidTaxReportTaxpayer, i+1, err.Error()))
+		}
+
+		loanNo := strings.TrimSpace(tp.LoanNo)
+		if loanNo == "" {
+			return nil, apperror.BadRequest(fmt.Sprintf(constant.InvalidTaxReportTaxpayer, i+1, constant.TaxReportLoanNoRequired))
+		}
+
+		normalized[i] = taxpayer{NpwpOrNik: npwpOrNik, LoanNo: loanNo}
+	}
+
+	return normalized, nil
+}
+
+// rowStatus is done when every product succeeded, failed when none did and
+// partial otherwise.
+func rowStatus(results []productResult) string {
+	succeeded := 0
+	for _, result := range results {
+		if result.Status == productStatusSuccess {
+			succeeded++
+		}
+	}
+
+	switch succeeded {
+	case len(results):
+		return constant.JobStatusDone
+	case 0:
+		return constant.JobStatusFailed
+	default:
+		return constant.JobStatusPartial
+	}
+}
+
+// reportStatus is done when every row is done, failed when every row failed
+// and partial otherwise.
+func reportStatus(rows []*taxpayerRow) string {
+	done, failed := 0, 0
+	for _, row := range rows {
+		switch row.Status {
+		case constant.JobStatusDone:
+			done++
+		case constant.JobStatusFailed:
+			failed++
+		}
+	}
+
+	switch {
+	case done == len(rows):
+		return constant.JobStatusDone
+	case failed == len(rows):
+		return constant.JobStatusFailed
+	default:
+		return constant.JobStatusPartial
+	}
+}
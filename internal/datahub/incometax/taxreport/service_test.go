@@ -0,0 +1,360 @@
+package taxreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/datahub/pipeline/pipelinetest"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// coreStub answers the core calls by "METHOD path".
+type coreStub struct {
+	mu     sync.Mutex
+	routes map[string]func(*http.Request) (int, any)
+	bodies map[string][][]byte
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+	}
+
+	s.mu.Lock()
+	s.bodies[key] = append(s.bodies[key], reqBody)
+	s.mu.Unlock()
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type fakeRunner struct {
+	name       string
+	run        func(parent *pipeline.Parent, body []byte) (*pipeline.RunResult, error)
+	reserveErr error
+	reserved   []int
+}
+
+func (r *fakeRunner) Name() string {
+	return r.name
+}
+
+func (r *fakeRunner) Reserve(_ *model.AuthContext, amount int) (*quota.Reservation, error) {
+	r.reserved = append(r.reserved, amount)
+	return nil, r.reserveErr
+}
+
+func (r *fakeRunner) Run(_ *model.AuthContext, parent *pipeline.Parent, body []byte) (*pipeline.RunResult, error) {
+	return r.run(parent, body)
+}
+
+func succeeding(name string, data any) *fakeRunner {
+	return &fakeRunner{name: name, run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+		return &pipeline.RunResult{StatusCode: http.StatusOK, Message: constant.Success, TransactionId: "TRX-" + name, Data: data}, nil
+	}}
+}
+
+func failing(name string, err error) *fakeRunner {
+	return &fakeRunner{name: name, run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+		return nil, err
+	}}
+}
+
+var storedReport = map[string]any{
+	"id":         6,
+	"member_id":  1,
+	"company_id": 9,
+	"status":     constant.JobStatusDone,
+	"products":   []string{routeTaxVerification, routeTaxScore},
+	"rows": []map[string]any{{
+		"npwp_or_nik": "0012345678901000",
+		"loan_no":     "L1",
+		"status":      constant.JobStatusDone,
+		"fields": map[string]any{
+			"nama":  map[string]any{"value": "BUDI SANTOSO", "source": routeTaxVerification, "conflicts": []map[string]any{{"value": "BUDI S", "source": routeTaxScore}}},
+			"npwp":  map[string]any{"value": "0012345678901000", "source": routeTaxVerification},
+			"score": map[string]any{"value": "A", "source": routeTaxScore},
+		},
+		"products": []map[string]any{
+			{"product": routeTaxVerification, "name": "tax verification detail", "status": productStatusSuccess, "status_code": 200, "message": "success", "transaction_id": "TVD1", "data": map[string]any{"nama": "BUDI SANTOSO", "npwp": "0012345678901000"}},
+			{"product": routeTaxScore, "name": "tax score", "status": productStatusSuccess, "status_code": 200, "message": "success", "transaction_id": "TXS1", "data": map[string]any{"nama": "BUDI S", "score": "A"}},
+		},
+	}},
+	"created_at": time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+}
+
+// setupService subscribes the company to the slugs given, the other tax
+// products answer 404.
+func setupService(t *testing.T, runners map[string]pipeline.Runner, subscribed ...string) (Service, *coreStub) {
+	t.Helper()
+
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{
+		routes: map[string]func(*http.Request) (int, any){
+			"POST /api/core/product/tax-reports":  ok(map[string]any{"tax_report_id": 6}),
+			"PUT /api/core/product/tax-reports/6": ok(nil),
+			"GET /api/core/product/tax-reports/6": ok(storedReport),
+			"POST /api/core/logging/operation":    ok(nil),
+		},
+		bodies: map[string][][]byte{},
+	}
+	for _, slug := range subscribed {
+		client.routes["GET /api/core/member/subscribed-product/"+slug] = ok(map[string]any{"subscribed_product_id": 1, "company_id": 9})
+	}
+
+	return NewService(NewRepository(cfg, client, nil), member.NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil), runners), client
+}
+
+func TestReport(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+	req := &taxReportRequest{Taxpayers: []taxpayer{
+		{NpwpOrNik: "01.234.567.8-901.000", LoanNo: "L1"},
+		{NpwpOrNik: "3201011501900001", LoanNo: "L2"},
+	}}
+
+	t.Run("merged with provenance", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			bodies []taxpayerBody
+			parent []uint
+		)
+		verification := succeeding("tax verification detail", map[string]string{
+			"nama": "BUDI SANTOSO", "alamat": "JL. MERDEKA NO. 1", "npwp": "0012345678901000",
+			"npwp_verification": "VALID", "tax_compliance": "VALID", "status": "VALID",
+		})
+		run := verification.run
+		verification.run = func(p *pipeline.Parent, body []byte) (*pipeline.RunResult, error) {
+			var b taxpayerBody
+			require.NoError(t, json.Unmarshal(body, &b))
+
+			mu.Lock()
+			bodies = append(bodies, b)
+			parent = append(parent, p.TaxReportId)
+			mu.Unlock()
+
+			return run(p, body)
+		}
+
+		compliance := succeeding("tax compliance status", map[string]string{"status": "NOT VALID"})
+
+		svc, client := setupService(t, map[string]pipeline.Runner{
+			routeTaxVerification: verification,
+			routeTaxScore:        succeeding("tax score", map[string]string{"nama": "budi santoso ", "alamat": "JL. MERDEKA 1", "score": "A", "status": "VALID"}),
+			routeTaxCompliance:   compliance,
+		}, constant.SlugTaxVerificationDetail, constant.SlugTaxScore)
+
+		report, err := svc.Report(authCtx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(6), report.Id)
+		assert.Equal(t, constant.JobStatusDone, report.Status)
+		// tax compliance status is not subscribed
+		assert.Equal(t, []string{routeTaxVerification, routeTaxScore}, report.Products)
+		require.Len(t, report.Rows, 2)
+
+		row := report.Rows[0]
+		assert.Equal(t, "0012345678901000", row.NpwpOrNik)
+		assert.Equal(t, constant.JobStatusDone, row.Status)
+		require.Len(t, row.Products, 2)
+		assert.Equal(t, "TRX-tax score", row.Products[1].TransactionId)
+
+		// the names differ in case and spaces only
+		assert.Equal(t, &reportField{Value: "BUDI SANTOSO", Source: routeTaxVerification}, row.Fields["nama"])
+		assert.Equal(t, &reportField{
+			Value:     "JL. MERDEKA NO. 1",
+			Source:    routeTaxVerification,
+			Conflicts: []sourcedValue{{Value: "JL. MERDEKA 1", Source: routeTaxScore}},
+		}, row.Fields["alamat"])
+		assert.Equal(t, &reportField{Value: "A", Source: routeTaxScore}, row.Fields["score"])
+		assert.Equal(t, &reportField{Value: "VALID", Source: routeTaxVerification}, row.Fields["tax_compliance"])
+
+		assert.ElementsMatch(t, []taxpayerBody{
+			{Npwp: "0012345678901000", NpwpOrNik: "0012345678901000", LoanNo: "L1"},
+			{Npwp: "3201011501900001", NpwpOrNik: "3201011501900001", LoanNo: "L2"},
+		}, bodies)
+		assert.Equal(t, []uint{6, 6}, parent)
+
+		// every subscribed product is reserved once for both taxpayers
+		assert.Equal(t, []int{2}, verification.reserved)
+		assert.Empty(t, compliance.reserved)
+
+		var created createTaxReportRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/product/tax-reports"][0], &created))
+		assert.Equal(t, 2, created.Total)
+		assert.Equal(t, constant.JobStatusInProgress, created.Status)
+
+		var updated updateTaxReportRequest
+		require.NoError(t, json.Unmarshal(client.bodies["PUT /api/core/product/tax-reports/6"][0], &updated))
+		assert.Equal(t, constant.JobStatusDone, updated.Status)
+		assert.Len(t, updated.Rows, 2)
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	t.Run("failed product", func(t *testing.T) {
+		svc, _ := setupService(t, map[string]pipeline.Runner{
+			routeTaxScore:      failing("tax score", apperror.Forbidden(constant.ErrQuotaExceeded)),
+			routeTaxCompliance: succeeding("tax compliance status", map[string]string{"nama": "BUDI SANTOSO", "status": "VALID"}),
+		}, constant.SlugTaxScore, constant.SlugTaxComplianceStatus)
+
+		report, err := svc.Report(authCtx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, constant.JobStatusPartial, report.Status)
+		row := report.Rows[0]
+		assert.Equal(t, constant.JobStatusPartial, row.Status)
+		assert.Equal(t, http.StatusForbidden, row.Products[0].StatusCode)
+		assert.Equal(t, &reportField{Value: "VALID", Source: routeTaxCompliance}, row.Fields["tax_compliance"])
+		assert.NotContains(t, row.Fields, "score")
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ran := false
+		score := &fakeRunner{name: "tax score", run: func(*pipeline.Parent, []byte) (*pipeline.RunResult, error) {
+			ran = true
+			return &pipeline.RunResult{}, nil
+		}}
+		svc, client := setupService(t, map[string]pipeline.Runner{
+			routeTaxScore:      score,
+			routeTaxCompliance: &fakeRunner{name: "tax compliance status", reserveErr: apperror.Forbidden(constant.ErrQuotaExceeded)},
+		}, constant.SlugTaxScore, constant.SlugTaxComplianceStatus)
+
+		_, err := svc.Report(authCtx, req)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+		assert.False(t, ran)
+		assert.Empty(t, client.bodies["POST /api/core/product/tax-reports"])
+	})
+
+	t.Run("bulk", func(t *testing.T) {
+		svc, client := setupService(t, map[string]pipeline.Runner{
+			routeTaxScore: succeeding("tax score", map[string]string{"score": "B"}),
+		}, constant.SlugTaxScore)
+
+		report, err := svc.BulkReport(authCtx, pipelinetest.CSVFile(t, "NPWP or NIK,Loan Number\n0012345678901000,L1\n3201011501900001,L2\n"))
+		require.NoError(t, err)
+
+		require.Len(t, report.Rows, 2)
+		assert.Equal(t, "L2", report.Rows[1].LoanNo)
+		assert.Equal(t, "B", report.Rows[1].Fields["score"].Value)
+
+		var logged operation.AddLogRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/logging/operation"][0], &logged))
+		assert.Equal(t, constant.EventTaxReportBulkReq, logged.Action)
+	})
+
+	invalid := map[string]struct {
+		req        *taxReportRequest
+		subscribed []string
+		message    string
+	}{
+		"no taxpayers":    {&taxReportRequest{}, []string{constant.SlugTaxScore}, constant.TaxReportTaxpayersRequired},
+		"invalid npwp":    {&taxReportRequest{Taxpayers: []taxpayer{req.Taxpayers[0], {NpwpOrNik: "123", LoanNo: "L3"}}}, []string{constant.SlugTaxScore}, "taxpayer 2: NPWP must be 15 or 16 digits"},
+		"missing loan no": {&taxReportRequest{Taxpayers: []taxpayer{{NpwpOrNik: "0012345678901000"}}}, []string{constant.SlugTaxScore}, "taxpayer 1: " + constant.TaxReportLoanNoRequired},
+		"none subscribed": {req, nil, constant.NoTaxProductSubscribed},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			svc, client := setupService(t, map[string]pipeline.Runner{
+				routeTaxScore: succeeding("tax score", nil),
+			}, tc.subscribed...)
+
+			_, err := svc.Report(authCtx, tc.req)
+
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Equal(t, tc.message, appErr.Message)
+			assert.Empty(t, client.bodies["POST /api/core/product/tax-reports"])
+		})
+	}
+}
+
+func TestExportTaxReport(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+
+	t.Run("masked", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		file, err := svc.ExportTaxReport(authCtx, "6", true)
+		require.NoError(t, err)
+
+		assert.Equal(t, "tax_report_6.xlsx", file.Filename)
+		assert.Equal(t, constant.MimeXlsx, file.ContentType)
+
+		f, err := excelize.OpenReader(bytes.NewReader(file.Data))
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := f.GetRows(reportSheet)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, []string{constant.CSVHeaderNPWPOrNIK, constant.CSVHeaderLoanNumber, constant.CSVHeaderStatus, constant.CSVHeaderName, constant.CSVHeaderName + " Source"}, rows[0][:5])
+		assert.Equal(t, []string{"******5678901000", "L1", constant.JobStatusDone, "BUDI SANTOSO", routeTaxVerification}, rows[1][:5])
+		assert.Equal(t, "Name: BUDI S (tax-score)", rows[1][len(rows[1])-1])
+		assert.NotContains(t, rows[1], "0012345678901000")
+
+		products, err := f.GetRows(productsSheet)
+		require.NoError(t, err)
+		require.Len(t, products, 3)
+		assert.Equal(t, "TXS1", products[2][6])
+	})
+
+	t.Run("other company", func(t *testing.T) {
+		svc, _ := setupService(t, nil)
+
+		_, err := svc.ExportTaxReport(&model.AuthContext{UserId: 2, CompanyId: 10}, "6", false)
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
+
+func TestGetTaxReportMasked(t *testing.T) {
+	svc, _ := setupService(t, nil)
+
+	report, err := svc.GetTaxReport(&model.AuthContext{UserId: 1, CompanyId: 9}, "6", true)
+	require.NoError(t, err)
+
+	row := report.Rows[0]
+	assert.Equal(t, "******5678901000", row.NpwpOrNik)
+	assert.Equal(t, "******5678901000", row.Fields["npwp"].Value)
+	assert.Equal(t, "******5678901000", row.Products[0].Data.(map[string]any)["npwp"])
+}
@@ -2,29 +2,35 @@ package taxscore
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[taxScoreRequest, taxScoreRespData]{
+	Slug:       constant.SlugTaxScore,
+	Route:      "tax-score",
+	Name:       "tax score",
+	TrxPrefix:  constant.TrxIdTaxScore,
+	Path:       "/product/incometax/tax-score",
+	CSVHeaders: constant.CSVTemplateHeaderTaxScore,
+	FromCSV: func(record []string) *taxScoreRequest {
+		return &taxScoreRequest{
+			Npwp:   record[0],
+			LoanNo: record[1],
+		}
+	},
+	LoanNo: func(req *taxScoreRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventTaxScoreSingleReq,
+	BulkEvent:   constant.EventTaxScoreBulkReq,
+}
 
-	taxComplianceGroup := apiGroup.Group("tax-score")
-	taxComplianceGroup.Post("/single-request", middleware.ValidateRequest(taxScoreRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	taxComplianceGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
@@ -2,31 +2,35 @@ package taxverificationdetail
 
 import (
 	"front-office/configs/application"
-	"front-office/internal/core/log/operation"
-	"front-office/internal/core/log/transaction"
-	"front-office/internal/core/member"
-	"front-office/internal/core/product"
-	"front-office/internal/datahub/job"
-	"front-office/internal/middleware"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/common/constant"
 	"front-office/pkg/httpclient"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repo := NewRepository(cfg, client, nil)
-	productRepo := product.NewRepository(cfg, client)
-	memberRepo := member.NewRepository(cfg, client, nil)
-	jobRepo := job.NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-
-	jobService := job.NewService(jobRepo, transactionRepo, operationRepo)
-	service := NewService(repo, productRepo, memberRepo, jobRepo, transactionRepo, operationRepo, jobService)
-
-	controller := NewController(service)
+var product = pipeline.Product[taxVerificationRequest, taxVerificationRespData]{
+	Slug:       constant.SlugTaxVerificationDetail,
+	Route:      "tax-verification-detail",
+	Name:       "tax verification detail",
+	TrxPrefix:  constant.TrxIdTaxVerification,
+	Path:       "/product/incometax/tax-verification-detail",
+	CSVHeaders: constant.CSVTemplateHeaderTaxVerification,
+	FromCSV: func(record []string) *taxVerificationRequest {
+		return &taxVerificationRequest{
+			NpwpOrNik: record[0],
+			LoanNo:    record[1],
+		}
+	},
+	LoanNo: func(req *taxVerificationRequest) string {
+		return req.LoanNo
+	},
+	SingleEvent: constant.EventTaxVerificationSingleReq,
+	BulkEvent:   constant.EventTaxVerificationBulkReq,
+}
 
-	taxComplianceGroup := apiGroup.Group("tax-verification-detail")
-	taxComplianceGroup.Post("/single-request", middleware.ValidateRequest(taxVerificationRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
-	taxComplianceGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
+	pipeline.Register(apiGroup, cfg, client, quotaReserver, evaluator, &product)
 }
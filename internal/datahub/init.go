@@ -2,6 +2,9 @@ package datahub
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/applicantcheck"
 	"front-office/internal/datahub/companylitigation/negativerecord"
 	"front-office/internal/datahub/compliance/loanrecordchecker"
 	"front-office/internal/datahub/compliance/multipleloan"
@@ -10,9 +13,12 @@ import (
 	"front-office/internal/datahub/identity/phonenik"
 	"front-office/internal/datahub/identity/recyclenumber"
 	"front-office/internal/datahub/incometax/taxcompliancestatus"
+	"front-office/internal/datahub/incometax/taxreport"
 	"front-office/internal/datahub/incometax/taxscore"
 	"front-office/internal/datahub/incometax/taxverificationdetail"
 	"front-office/internal/datahub/job"
+	"front-office/internal/datahub/monitoring"
+	"front-office/internal/mail"
 	"front-office/pkg/httpclient"
 
 	"time"
@@ -20,28 +26,34 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(routeAPI fiber.Router, cfg *application.Config) {
+func SetupInit(routeAPI fiber.Router, cfg *application.Config, quotaReserver quota.Reserver, evaluator decision.Evaluator, mailSvc *mail.SendMailService) {
 	client := httpclient.NewDefaultClient(10 * time.Second)
 
 	complianceGroupAPI := routeAPI.Group("compliance")
-	loanrecordchecker.SetupInit(complianceGroupAPI, cfg, client)
-	multipleloan.SetupInit(complianceGroupAPI, cfg, client)
+	loanrecordchecker.SetupInit(complianceGroupAPI, cfg, client, quotaReserver, evaluator)
+	multipleloan.SetupInit(complianceGroupAPI, cfg, client, quotaReserver, evaluator)
 	job.SetupInit(complianceGroupAPI, cfg, client)
 
 	incomeTaxGroupAPI := routeAPI.Group("incometax")
-	taxcompliancestatus.SetupInit(incomeTaxGroupAPI, cfg, client)
-	taxscore.SetupInit(incomeTaxGroupAPI, cfg, client)
-	taxverificationdetail.SetupInit(incomeTaxGroupAPI, cfg, client)
+	taxcompliancestatus.SetupInit(incomeTaxGroupAPI, cfg, client, quotaReserver, evaluator)
+	taxscore.SetupInit(incomeTaxGroupAPI, cfg, client, quotaReserver, evaluator)
+	taxverificationdetail.SetupInit(incomeTaxGroupAPI, cfg, client, quotaReserver, evaluator)
+	taxreport.SetupInit(incomeTaxGroupAPI.Group("tax-report"), cfg, client)
 	job.SetupInit(incomeTaxGroupAPI, cfg, client)
 
 	identityGroupAPI := routeAPI.Group("identity")
-	phonelivestatus.SetupInit(identityGroupAPI, cfg, client)
-	npwpverification.SetupInit(identityGroupAPI, cfg, client)
-	recyclenumber.SetupInit(identityGroupAPI, cfg, client)
-	phonenik.SetupInit(identityGroupAPI, cfg, client)
+	phonelivestatus.SetupInit(identityGroupAPI, cfg, client, quotaReserver, evaluator)
+	npwpverification.SetupInit(identityGroupAPI, cfg, client, quotaReserver, evaluator)
+	recyclenumber.SetupInit(identityGroupAPI, cfg, client, quotaReserver, evaluator)
+	phonenik.SetupInit(identityGroupAPI, cfg, client, quotaReserver, evaluator)
 	job.SetupInit(identityGroupAPI, cfg, client)
 
 	companyLitigationGroupAPI := routeAPI.Group("complit")
-	negativerecord.SetupInit(companyLitigationGroupAPI, cfg, client)
+	negativerecord.SetupInit(companyLitigationGroupAPI, cfg, client, quotaReserver, evaluator)
 	job.SetupInit(companyLitigationGroupAPI, cfg, client)
+
+	job.SetupHistoryInit(routeAPI.Group("subject-history"), cfg, client)
+
+	applicantcheck.SetupInit(routeAPI.Group("applicant-check"), cfg, client, evaluator)
+	monitoring.SetupInit(routeAPI.Group("monitoring"), cfg, client, mailSvc)
 }
@@ -27,6 +27,7 @@ type Controller interface {
 	ExportJobDetails(c *fiber.Ctx) error
 	GetJobDetailsByDateRange(c *fiber.Ctx) error
 	ExportJobDetailsByDateRange(c *fiber.Ctx) error
+	GetSubjectHistory(c *fiber.Ctx) error
 }
 
 func (ctrl *controller) GetJobs(c *fiber.Ctx) error {
@@ -228,6 +229,42 @@ func (ctrl *controller) ExportJobDetailsByDateRange(c *fiber.Ctx) error {
 	return c.SendStream(bytes.NewReader(buf.Bytes()))
 }
 
+func (ctrl *controller) GetSubjectHistory(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	startDate := c.Query(constant.StartDate)
+	endDate := c.Query(constant.EndDate)
+	if startDate != "" && endDate == "" {
+		endDate = startDate
+	}
+
+	filter := &historyFilter{
+		AuthCtx:     authCtx,
+		NIK:         c.Query("nik"),
+		PhoneNumber: c.Query("phone_number"),
+		NPWP:        c.Query("npwp"),
+		LoanNo:      c.Query("loan_no"),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		IsMasked:    masked,
+	}
+
+	result, err := ctrl.Svc.GetSubjectHistory(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		result,
+	))
+}
+
 var productSlugMap = map[string]string{
 	"loan-record-checker":     constant.SlugLoanRecordChecker,
 	"7d-multiple-loan":        constant.Slug7DaysMultipleLoan,
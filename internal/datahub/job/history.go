@@ -0,0 +1,227 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"front-office/pkg/identifier"
+	"sort"
+)
+
+// GetSubjectHistory lists every product result of one subject, newest
+// first. Each result is compared with the previous result of the same
+// product, so a reviewer sees what changed between two checks.
+func (svc *service) GetSubjectHistory(filter *historyFilter) (*subjectHistory, error) {
+	if filter.NIK == "" && filter.PhoneNumber == "" && filter.NPWP == "" && filter.LoanNo == "" {
+		return nil, apperror.BadRequest(constant.SubjectHistoryIdentifierRequired)
+	}
+
+	if err := identifier.NormalizeStruct(filter); err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+	filter.LegacyPhoneNumber = identifier.LegacyPhone(filter.PhoneNumber)
+	filter.LegacyNPWP = identifier.LegacyNPWP(filter.NPWP)
+
+	logs, err := svc.repo.GetSubjectLogsAPI(filter)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedFetchSubjectHistory)
+	}
+
+	var negativeRecords []*logTransProductCatalog
+	for _, l := range logs {
+		if err := remapLogTransData(l.ProductSlug, &l.logTransProductCatalog); err != nil {
+			return nil, apperror.Internal("failed to remap job detail data", err)
+		}
+		if l.ProductSlug == constant.SlugNegativeRecord {
+			negativeRecords = append(negativeRecords, &l.logTransProductCatalog)
+		}
+	}
+	svc.reviewNegativeRecords(&logFilter{AuthCtx: filter.AuthCtx, ProductSlug: constant.SlugNegativeRecord}, negativeRecords)
+
+	// the core datetime format sorts as text
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].DateTime < logs[j].DateTime
+	})
+
+	timeline := make([]*historyEntry, 0, len(logs))
+	previous := map[string]map[string]string{}
+	for _, l := range logs {
+		entry := &historyEntry{
+			ProductSlug:   l.ProductSlug,
+			ProductName:   l.ProductName,
+			JobId:         l.JobID,
+			TransactionId: l.TransactionId,
+			LoanNo:        l.LoanNo,
+			Status:        l.Status,
+			Message:       l.Message,
+			Input:         l.Input,
+			Data:          l.RawData,
+			Decision:      l.Decision,
+			ReviewStatus:  l.ReviewStatus,
+			DateTime:      l.DateTime,
+		}
+
+		if filter.IsMasked {
+			entry.Input = maskedInput(&l.logTransProductCatalog)
+			entry.Data = maskedData(entry.Data)
+		}
+
+		// failed requests have no data to compare
+		if entry.Data != nil {
+			fields := flattenData(entry.Data)
+			if before, ok := previous[l.ProductSlug]; ok {
+				entry.Changes = diffFields(before, fields)
+			}
+			previous[l.ProductSlug] = fields
+		}
+
+		timeline = append(timeline, entry)
+	}
+
+	for i, j := 0, len(timeline)-1; i < j; i, j = i+1, j-1 {
+		timeline[i], timeline[j] = timeline[j], timeline[i]
+	}
+
+	return &subjectHistory{
+		TotalData: len(timeline),
+		Timeline:  timeline,
+	}, nil
+}
+
+// maskedInput follows the exports: the core keeps a masked copy of the
+// input with the log, values missing from it are masked here.
+func maskedInput(d *logTransProductCatalog) *logTransInput {
+	if d.Input == nil {
+		return nil
+	}
+
+	var ref refTransProductCatalog
+	if raw, err := json.Marshal(d.RefTransProductCatalog); err == nil {
+		_ = json.Unmarshal(raw, &ref)
+	}
+
+	maskHead := func(s string) string { return helper.MaskingHead(s, 10) }
+
+	input := *d.Input
+	input.NIK = maskValue(d.Input.NIK, ref.Input.NIK, maskHead)
+	input.PhoneNumber = maskValue(d.Input.PhoneNumber, ref.Input.PhoneNumber, helper.MaskingMiddle)
+	input.NPWP = maskValue(d.Input.NPWP, ref.Input.NPWP, maskHead)
+	input.NPWPOrNIK = maskValue(d.Input.NPWPOrNIK, ref.Input.NPWPOrNIK, maskHead)
+
+	return &input
+}
+
+func maskValue(value *string, masked string, mask func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	if masked == "" {
+		masked = mask(*value)
+	}
+
+	return &masked
+}
+
+// maskedData masks the identifiers some partners echo in their data, e.g.
+// the npwp of the tax verification detail.
+func maskedData(data any) any {
+	values := dataMap(data)
+	if values == nil {
+		return data
+	}
+
+	for key, mask := range map[string]func(string) string{
+		"nik":          func(s string) string { return helper.MaskingHead(s, 10) },
+		"npwp":         func(s string) string { return helper.MaskingHead(s, 10) },
+		"phone_number": helper.MaskingMiddle,
+	} {
+		if value, ok := values[key].(string); ok && value != "" {
+			values[key] = mask(value)
+		}
+	}
+
+	return values
+}
+
+func dataMap(data any) map[string]any {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil
+	}
+
+	return values
+}
+
+// flattenData names every leaf of the data by its path. The elements of a
+// list are named by their case number when they have one, so negative
+// record hits coming back in another order are not a change.
+func flattenData(data any) map[string]string {
+	fields := map[string]string{}
+
+	var walk func(path string, value any)
+	walk = func(path string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				if path != "" {
+					key = path + "." + key
+				}
+				walk(key, child)
+			}
+		case []any:
+			for i, child := range v {
+				key := fmt.Sprint(i)
+				if hit, ok := child.(map[string]any); ok {
+					if caseNumber, ok := hit["case_number"].(string); ok && caseNumber != "" {
+						key = caseNumber
+					}
+				}
+				walk(fmt.Sprintf("%s[%s]", path, key), child)
+			}
+		case nil:
+			fields[path] = ""
+		default:
+			fields[path] = fmt.Sprint(v)
+		}
+	}
+
+	if values := dataMap(data); values != nil {
+		walk("", values)
+	}
+
+	return fields
+}
+
+// diffFields lists the fields that differ sorted by field.
+func diffFields(previous, current map[string]string) []fieldChange {
+	names := make([]string, 0, len(previous)+len(current))
+	for name := range previous {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []fieldChange{}
+	for _, name := range names {
+		// a field that appears empty is not a change either
+		before, after := previous[name], current[name]
+		if before == after {
+			continue
+		}
+
+		changes = append(changes, fieldChange{Field: name, Previous: before, Current: after})
+	}
+
+	return changes
+}
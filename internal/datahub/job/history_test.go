@@ -0,0 +1,154 @@
+package job
+
+import (
+	"front-office/internal/datahub/companylitigation/review"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subjectLogsStub answers the subject history lookup, the other calls are
+// not used.
+type subjectLogsStub struct {
+	Repository
+	logs   []*subjectLogTrans
+	filter *historyFilter
+}
+
+func (s *subjectLogsStub) GetSubjectLogsAPI(filter *historyFilter) ([]*subjectLogTrans, error) {
+	s.filter = filter
+	return s.logs, nil
+}
+
+func subjectLog(slug, trxId, datetime string, input *logTransInput, data any) *subjectLogTrans {
+	return &subjectLogTrans{
+		logTransProductCatalog: logTransProductCatalog{
+			TransactionId: trxId,
+			Status:        "success",
+			Input:         input,
+			RawData:       data,
+			DateTime:      datetime,
+			RefTransProductCatalog: map[string]any{"input": map[string]any{
+				"phone_number": "6281****7890",
+			}},
+		},
+		ProductSlug: slug,
+	}
+}
+
+func TestGetSubjectHistory(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+	phone := &logTransInput{PhoneNumber: helper.StringPtr("6281234567890"), NIK: helper.StringPtr("3201011501900001"), LoanNo: "L1"}
+
+	logs := func() []*subjectLogTrans {
+		return []*subjectLogTrans{
+			subjectLog(constant.SlugPhoneLiveStatus, "PLS-2", "2026-10-02 09:00:00", phone, map[string]any{"live_status": "not active", "operator": "telkomsel"}),
+			subjectLog(constant.SlugPhoneLiveStatus, "PLS-1", "2026-10-01 09:00:00", phone, map[string]any{"live_status": "active", "operator": "telkomsel"}),
+			subjectLog(constant.SlugLoanRecordChecker, "LRC-1", "2026-10-01 10:00:00", phone, map[string]any{"status": "found", "remarks": "", "npwp": "0012345678901000"}),
+			subjectLog(constant.SlugPhoneLiveStatus, "PLS-3", "2026-10-03 09:00:00", phone, nil),
+			subjectLog(constant.SlugNegativeRecord, "CNR-1", "2026-09-01 09:00:00", &logTransInput{LoanNo: "L1"}, map[string]any{"result": []any{
+				map[string]any{"case_number": "12", "status": "Banding", "similarity_score": "100.0"},
+				map[string]any{"case_number": "98", "status": "Putus", "similarity_score": "60.0"},
+			}}),
+			subjectLog(constant.SlugNegativeRecord, "CNR-2", "2026-09-15 09:00:00", &logTransInput{LoanNo: "L1"}, map[string]any{"result": []any{
+				map[string]any{"case_number": "13", "status": "Pendaftaran", "similarity_score": "90.0"},
+				map[string]any{"case_number": "12", "status": "Kasasi", "similarity_score": "100.0"},
+			}}),
+		}
+	}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		repo := &subjectLogsStub{logs: logs()}
+		svc := &service{repo: repo, reviewLookup: &reviewLookupStub{threshold: 80}}
+
+		history, err := svc.GetSubjectHistory(&historyFilter{AuthCtx: authCtx, PhoneNumber: "0812-3456-7890"})
+		require.NoError(t, err)
+		assert.Equal(t, "6281234567890", repo.filter.PhoneNumber)
+
+		require.Equal(t, 6, history.TotalData)
+		trxIds := []string{}
+		for _, entry := range history.Timeline {
+			trxIds = append(trxIds, entry.TransactionId)
+		}
+		assert.Equal(t, []string{"PLS-3", "PLS-2", "LRC-1", "PLS-1", "CNR-2", "CNR-1"}, trxIds)
+
+		// failed request without data
+		assert.Nil(t, history.Timeline[0].Changes)
+		assert.Equal(t, []fieldChange{{Field: "live_status", Previous: "active", Current: "not active"}}, history.Timeline[1].Changes)
+		// first result of its product
+		assert.Nil(t, history.Timeline[2].Changes)
+		assert.Nil(t, history.Timeline[3].Changes)
+		assert.Equal(t, "6281234567890", *history.Timeline[1].Input.PhoneNumber)
+
+		// hits below the threshold are dropped before comparing, the hits
+		// are matched by case number
+		assert.Equal(t, review.StatusPending, history.Timeline[4].ReviewStatus)
+		assert.Equal(t, []fieldChange{
+			{Field: "result[12].case_status", Previous: "Banding", Current: "Kasasi"},
+			{Field: "result[13].case_number", Current: "13"},
+			{Field: "result[13].case_status", Current: "Pendaftaran"},
+			{Field: "result[13].similarity_score", Current: "90.0"},
+		}, history.Timeline[4].Changes)
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		svc := &service{repo: &subjectLogsStub{logs: logs()}}
+
+		history, err := svc.GetSubjectHistory(&historyFilter{AuthCtx: authCtx, NIK: "3201011501900001", IsMasked: true})
+		require.NoError(t, err)
+
+		entry := history.Timeline[2]
+		assert.Equal(t, "LRC-1", entry.TransactionId)
+		// the core copy is used, the NIK it has not masked is masked here
+		assert.Equal(t, "6281****7890", *entry.Input.PhoneNumber)
+		assert.Equal(t, helper.MaskingHead("3201011501900001", 10), *entry.Input.NIK)
+		assert.Equal(t, helper.MaskingHead("0012345678901000", 10), entry.Data.(map[string]any)["npwp"])
+		assert.Equal(t, "L1", entry.Input.LoanNo)
+	})
+
+	t.Run("legacy logs", func(t *testing.T) {
+		// logged before identifiers were normalized, with the local phone
+		legacy := &logTransInput{PhoneNumber: helper.StringPtr("081234567890"), LoanNo: "L0"}
+		repo := &subjectLogsStub{logs: []*subjectLogTrans{
+			subjectLog(constant.SlugPhoneLiveStatus, "PLS-2", "2026-10-02 09:00:00", phone, map[string]any{"live_status": "not active"}),
+			subjectLog(constant.SlugPhoneLiveStatus, "PLS-0", "2025-06-01 09:00:00", legacy, map[string]any{"live_status": "active"}),
+		}}
+		svc := &service{repo: repo}
+
+		history, err := svc.GetSubjectHistory(&historyFilter{AuthCtx: authCtx, PhoneNumber: "+62 812-3456-7890", NPWP: "01.234.567.8-901.000"})
+		require.NoError(t, err)
+		assert.Equal(t, "6281234567890", repo.filter.PhoneNumber)
+		assert.Equal(t, "081234567890", repo.filter.LegacyPhoneNumber)
+		assert.Equal(t, "0012345678901000", repo.filter.NPWP)
+		assert.Equal(t, "012345678901000", repo.filter.LegacyNPWP)
+
+		require.Equal(t, 2, history.TotalData)
+		assert.Equal(t, "PLS-0", history.Timeline[1].TransactionId)
+		assert.Equal(t, "081234567890", *history.Timeline[1].Input.PhoneNumber)
+		assert.Equal(t, []fieldChange{{Field: "live_status", Previous: "active", Current: "not active"}}, history.Timeline[0].Changes)
+	})
+
+	invalid := map[string]*historyFilter{
+		"no identifier": {AuthCtx: authCtx},
+		"invalid phone": {AuthCtx: authCtx, PhoneNumber: "12345"},
+	}
+	for name, filter := range invalid {
+		t.Run(name, func(t *testing.T) {
+			repo := &subjectLogsStub{}
+			svc := &service{repo: repo}
+
+			_, err := svc.GetSubjectHistory(filter)
+
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Nil(t, repo.filter)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"front-office/configs/application"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
+	"front-office/internal/datahub/companylitigation/review"
 	"front-office/internal/middleware"
 	"front-office/pkg/httpclient"
 
@@ -11,12 +12,7 @@ import (
 )
 
 func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
-	repository := NewRepository(cfg, client, nil)
-	transactionRepo := transaction.NewRepository(cfg, client, nil)
-	operationRepo := operation.NewRepository(cfg, client, nil)
-
-	service := NewService(repository, transactionRepo, operationRepo)
-	controller := NewController(service)
+	controller := NewController(newService(cfg, client))
 
 	apiGroup.Get("/gen-retail/jobs", middleware.GetJWTPayloadFromCookie(cfg), controller.GetGenRetailJobs)
 	apiGroup.Get("/:product_slug/jobs", middleware.GetJWTPayloadFromCookie(cfg), controller.GetJobs)
@@ -25,3 +21,19 @@ func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient
 	apiGroup.Get("/:product_slug/jobs-summary", middleware.GetJWTPayloadFromCookie(cfg), controller.GetJobDetailsByDateRange)
 	apiGroup.Get("/:product_slug/jobs-summary/export", middleware.GetJWTPayloadFromCookie(cfg), controller.ExportJobDetailsByDateRange)
 }
+
+// SetupHistoryInit registers the subject history once, it spans the
+// products of every group.
+func SetupHistoryInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+	controller := NewController(newService(cfg, client))
+
+	apiGroup.Get("/", middleware.GetJWTPayloadFromCookie(cfg), controller.GetSubjectHistory)
+}
+
+func newService(cfg *application.Config, client httpclient.HTTPClient) Service {
+	transactionRepo := transaction.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	reviewService := review.NewService(review.NewRepository(cfg, client, nil), transactionRepo, operationRepo)
+
+	return NewService(NewRepository(cfg, client, nil), transactionRepo, operationRepo, reviewService)
+}
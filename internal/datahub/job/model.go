@@ -1,6 +1,8 @@
 package job
 
 import (
+	"encoding/json"
+	"front-office/internal/datahub/companylitigation/review"
 	"front-office/pkg/common/model"
 	"time"
 )
@@ -20,6 +22,9 @@ type logTransProductCatalog struct {
 	TransactionId          string           `json:"transaction_id"`
 	DateTime               string           `json:"datetime"`
 	RefTransProductCatalog any              `json:"ref_trans_product_catalog"`
+	Decision               string           `json:"decision,omitempty"`
+	FiredRules             []string         `json:"fired_rules,omitempty"`
+	ReviewStatus           string           `json:"review_status,omitempty"`
 }
 
 type refTransProductCatalog struct {
@@ -102,17 +107,52 @@ type jobGenRetailData struct {
 	TotalData int64          `json:"total_data"`
 }
 
+// dataNegativeRecordAPI is a hit as the partner answers it. Job details keep
+// answering it under the field names they had before the partner renamed
+// them, see negativeRecordHitResponse.
 type dataNegativeRecordAPI struct {
-	CompanyName         string `json:"company_name"`
-	CaseStatus          string `json:"case_status"`
-	Court               string `json:"court"`
-	CaseNumber          string `json:"case_number"`
-	CaseCodeDescription string `json:"case_code_description"`
-	PartyStatus         string `json:"party_status"`
-	CaseClassification  string `json:"case_classification"`
-	RegistrationDate    string `json:"registration_date"`
-	CaseDuration        string `json:"case_duration"`
-	SimilarityScore     string `json:"similarity_score"`
+	CompanyName      string `json:"company_name"`
+	SimilarityScore  string `json:"similarity_score"`
+	Status           string `json:"status"`
+	CaseNumber       string `json:"case_number"`
+	Court            string `json:"court"`
+	Province         string `json:"province"`
+	CaseType         string `json:"case_type"`
+	RegistrationDate string `json:"registration_date"`
+	ProcessDuration  string `json:"process_duration"`
+	LastUpdated      string `json:"last_updated"`
+	// Review is the reviewer decision on the hit, nil until reviewed
+	Review *review.Review `json:"review,omitempty"`
+}
+
+type negativeRecordHitResponse struct {
+	CompanyName         string         `json:"company_name"`
+	CaseStatus          string         `json:"case_status"`
+	Court               string         `json:"court"`
+	CaseNumber          string         `json:"case_number"`
+	CaseCodeDescription string         `json:"case_code_description"`
+	PartyStatus         string         `json:"party_status"`
+	CaseClassification  string         `json:"case_classification"`
+	RegistrationDate    string         `json:"registration_date"`
+	CaseDuration        string         `json:"case_duration"`
+	SimilarityScore     string         `json:"similarity_score"`
+	Review              *review.Review `json:"review,omitempty"`
+}
+
+func (d dataNegativeRecordAPI) MarshalJSON() ([]byte, error) {
+	return json.Marshal(negativeRecordHitResponse{
+		CompanyName:         d.CompanyName,
+		CaseStatus:          d.Status,
+		Court:               d.Court,
+		CaseNumber:          d.CaseNumber,
+		CaseCodeDescription: d.Province,
+		PartyStatus:         d.CaseType,
+		CaseClassification:  d.ProcessDuration,
+		RegistrationDate:    d.RegistrationDate,
+		CaseDuration:        d.LastUpdated,
+		SimilarityScore:     d.SimilarityScore,
+		Review:              d.Review,
+	})
 }
 
 type jobsScoreezy struct {
@@ -139,6 +179,11 @@ type CreateJobRequest struct {
 	MemberId  string `json:"member_id" validate:"required~Field member id is required"`
 	CompanyId string `json:"company_id" validate:"required~Field company id is required"`
 	Total     int    `json:"total" validate:"required~Field total is required"`
+
+	APIClientId string `json:"api_client_id,omitempty"`
+	// link the job to the applicant check or tax report it was run for
+	ApplicantCheckId uint `json:"applicant_check_id,omitempty"`
+	TaxReportId      uint `json:"tax_report_id,omitempty"`
 }
 
 type UpdateJobRequest struct {
@@ -165,3 +210,59 @@ type logFilter struct {
 	IsMasked    bool
 	Keyword     string
 }
+
+// historyFilter finds the results of one subject, at least one identifier
+// is needed.
+type historyFilter struct {
+	NIK         string `normalize:"nik"`
+	PhoneNumber string `normalize:"phone"`
+	NPWP        string `normalize:"npwp"`
+	LoanNo      string
+	StartDate   string
+	EndDate     string
+	AuthCtx     *model.AuthContext
+	IsMasked    bool
+
+	// the forms stored by logs written before identifiers were normalized
+	LegacyPhoneNumber string
+	LegacyNPWP        string
+}
+
+// subjectLogTrans is a transaction log of any product, the product is
+// named since the logs of several products are mixed.
+type subjectLogTrans struct {
+	logTransProductCatalog
+	ProductSlug string `json:"product_slug"`
+	ProductName string `json:"product_name"`
+}
+
+type subjectHistory struct {
+	TotalData int             `json:"total_data"`
+	Timeline  []*historyEntry `json:"timeline"`
+}
+
+type historyEntry struct {
+	ProductSlug   string         `json:"product_slug"`
+	ProductName   string         `json:"product_name"`
+	JobId         uint           `json:"job_id"`
+	TransactionId string         `json:"transaction_id"`
+	LoanNo        string         `json:"loan_no"`
+	Status        string         `json:"status"`
+	Message       *string        `json:"message"`
+	Input         *logTransInput `json:"input"`
+	Data          any            `json:"data"`
+	Decision      string         `json:"decision,omitempty"`
+	ReviewStatus  string         `json:"review_status,omitempty"`
+	DateTime      string         `json:"datetime"`
+	// Changes compare the data with the previous result of the same
+	// product, nil for the first result
+	Changes []fieldChange `json:"changes"`
+}
+
+// fieldChange is a data field that differs from the previous result, nested
+// fields are named by their path, e.g. "result[12/Pdt.G/2025].status".
+type fieldChange struct {
+	Field    string `json:"field"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
@@ -40,6 +40,7 @@ type Repository interface {
 	GetJobsAPI(filter *logFilter) (*model.AifcoreAPIResponse[*jobListResponse], error)
 	GetJobDetailAPI(filter *logFilter) (*model.AifcoreAPIResponse[*jobDetailResponse], error)
 	GetJobsSummaryAPI(filter *logFilter) (*model.AifcoreAPIResponse[*jobDetailResponse], error)
+	GetSubjectLogsAPI(filter *historyFilter) ([]*subjectLogTrans, error)
 }
 
 func (repo *repository) CreateJobAPI(payload *CreateJobRequest) (*createJobRespData, error) {
@@ -201,3 +202,49 @@ func (repo *repository) GetJobsSummaryAPI(filter *logFilter) (*model.AifcoreAPIR
 
 	return helper.ParseAifcoreAPIResponse[*jobDetailResponse](resp)
 }
+
+// GetSubjectLogsAPI returns the company transaction logs of every product
+// matching any of the identifiers, in their normalized or legacy form.
+func (repo *repository) GetSubjectLogsAPI(filter *historyFilter) ([]*subjectLogTrans, error) {
+	url := fmt.Sprintf("%s/api/core/product/subject-history", repo.cfg.App.AifcoreHost)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	req.Header.Set(constant.XMemberId, filter.AuthCtx.UserIdStr())
+	req.Header.Set(constant.XCompanyId, filter.AuthCtx.CompanyIdStr())
+
+	q := req.URL.Query()
+	q.Add("nik", filter.NIK)
+	q.Add("phone_number", filter.PhoneNumber)
+	q.Add("npwp", filter.NPWP)
+	if filter.LegacyPhoneNumber != "" {
+		q.Add("phone_number", filter.LegacyPhoneNumber)
+	}
+	if filter.LegacyNPWP != "" {
+		q.Add("npwp", filter.LegacyNPWP)
+	}
+	q.Add("loan_no", filter.LoanNo)
+	q.Add(constant.StartDate, filter.StartDate)
+	q.Add(constant.EndDate, filter.EndDate)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*subjectLogTrans](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
@@ -411,3 +411,100 @@ func TestCallGetProCatJobDetailsAPI(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestCallGetSubjectLogsAPI(t *testing.T) {
+	filter := &historyFilter{
+		NIK: "3201011501900001",
+		AuthCtx: &model.AuthContext{
+			UserId:    constant.DummyIdInt,
+			CompanyId: constant.DummyIdInt,
+			RoleId:    constant.DummyIdInt,
+		},
+	}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		mockData := model.AifcoreAPIResponse[any]{
+			Success: true,
+			Data: []*subjectLogTrans{
+				{ProductSlug: constant.SlugPhoneLiveStatus},
+			},
+		}
+		body, err := json.Marshal(mockData)
+		require.NoError(t, err)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetSubjectLogsAPI(filter)
+
+		assert.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, constant.SlugPhoneLiveStatus, result[0].ProductSlug)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("legacy forms", func(t *testing.T) {
+		body, err := json.Marshal(model.AifcoreAPIResponse[any]{Success: true})
+		require.NoError(t, err)
+
+		repo, mockClient := setupMockRepo(t, &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil)
+
+		_, err = repo.GetSubjectLogsAPI(&historyFilter{
+			PhoneNumber:       "6281234567890",
+			NPWP:              "0012345678901000",
+			LegacyPhoneNumber: "081234567890",
+			LegacyNPWP:        "012345678901000",
+			AuthCtx:           filter.AuthCtx,
+		})
+		require.NoError(t, err)
+
+		query := mockClient.Calls[0].Arguments.Get(0).(*http.Request).URL.Query()
+		assert.Equal(t, []string{"6281234567890", "081234567890"}, query["phone_number"])
+		assert.Equal(t, []string{"0012345678901000", "012345678901000"}, query["npwp"])
+	})
+
+	t.Run(constant.TestCaseNewRequestError, func(t *testing.T) {
+		mockClient := new(MockClient)
+		repo := NewRepository(&application.Config{
+			App: &application.Environment{AifcoreHost: constant.MockInvalidHost},
+		}, mockClient, nil)
+
+		_, err := repo.GetSubjectLogsAPI(filter)
+
+		assert.Error(t, err)
+	})
+
+	t.Run(constant.TestCaseHTTPRequestError, func(t *testing.T) {
+		expectedErr := errors.New(constant.ErrUpstreamUnavailable)
+
+		repo, mockClient := setupMockRepo(t, nil, expectedErr)
+
+		_, err := repo.GetSubjectLogsAPI(filter)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), constant.ErrUpstreamUnavailable)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run(constant.TestCaseParseError, func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(constant.InvalidJSON)),
+		}
+
+		repo, mockClient := setupMockRepo(t, resp, nil)
+
+		result, err := repo.GetSubjectLogsAPI(filter)
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -7,21 +7,27 @@ import (
 	"fmt"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
+	"front-office/internal/datahub/companylitigation/review"
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
-func NewService(repo Repository, transactionRepo transaction.Repository, operationRepo operation.Repository) Service {
+// NewService takes the review lookup of the negative record hits, it is only
+// needed by the job details and exports and may be nil elsewhere.
+func NewService(repo Repository, transactionRepo transaction.Repository, operationRepo operation.Repository, reviewLookup review.Lookup) Service {
 	return &service{
 		repo,
 		transactionRepo,
 		operationRepo,
+		reviewLookup,
 	}
 }
 
@@ -29,6 +35,7 @@ type service struct {
 	repo            Repository
 	transactionRepo transaction.Repository
 	operationRepo   operation.Repository
+	reviewLookup    review.Lookup
 }
 
 type Service interface {
@@ -40,6 +47,7 @@ type Service interface {
 	ExportJobDetails(filter *logFilter, buf *bytes.Buffer) (string, error)
 	GetJobDetailsByDateRange(filter *logFilter) (*model.AifcoreAPIResponse[*jobDetailResponse], error)
 	ExportJobDetailsByDateRange(filter *logFilter, buf *bytes.Buffer) (string, error)
+	GetSubjectHistory(filter *historyFilter) (*subjectHistory, error)
 	FinalizeJob(jobIdStr string) error
 	FinalizeFailedJob(jobIdStr string) error
 }
@@ -137,6 +145,7 @@ func (svc *service) GetJobDetails(filter *logFilter) (*model.AifcoreAPIResponse[
 				return nil, err
 			}
 		}
+		svc.reviewNegativeRecords(filter, result.Data.JobDetails)
 	}
 
 	return result, nil
@@ -154,6 +163,7 @@ func (svc *service) GetJobDetailsByDateRange(filter *logFilter) (*model.AifcoreA
 				return nil, err
 			}
 		}
+		svc.reviewNegativeRecords(filter, result.Data.JobDetails)
 	}
 
 	return result, nil
@@ -185,6 +195,7 @@ func (svc *service) exportJobDetailsToCSV(
 				return "", apperror.Internal("failed to remap job detail data", err)
 			}
 		}
+		svc.reviewNegativeRecords(filter, resp.Data.JobDetails)
 	}
 
 	cfg, ok := exportProductMap[filter.ProductSlug]
@@ -203,6 +214,11 @@ func (svc *service) exportJobDetailsToCSV(
 		mapper = withDateColumn(mapper)
 	}
 
+	if resp.Data != nil && hasDecision(resp.Data.JobDetails) {
+		headers = append(slices.Clone(headers), constant.CSVExportHeaderDecision...)
+		mapper = withDecisionColumns(mapper)
+	}
+
 	err = writeToCSV(buf, headers, resp.Data.JobDetails, mapper)
 	if err != nil {
 		return "", apperror.Internal("failed to write CSV", err)
@@ -211,9 +227,10 @@ func (svc *service) exportJobDetailsToCSV(
 	filename := formatCSVFileName("job_detail", filter.StartDate, filter.EndDate, filter.JobId)
 
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  filter.AuthCtx.UserId,
-		CompanyId: filter.AuthCtx.CompanyId,
-		Action:    eventName,
+		MemberId:    filter.AuthCtx.UserId,
+		CompanyId:   filter.AuthCtx.CompanyId,
+		APIClientId: filter.AuthCtx.APIClientId,
+		Action:      eventName,
 	}); err != nil {
 		log.Warn().
 			Err(err).
@@ -224,6 +241,67 @@ func (svc *service) exportJobDetailsToCSV(
 	return filename, nil
 }
 
+// reviewNegativeRecords drops the negative record hits below the company
+// threshold and adds the reviews of the hits left. A failing review lookup
+// leaves the hits pending rather than failing the job details.
+func (svc *service) reviewNegativeRecords(filter *logFilter, details []*logTransProductCatalog) {
+	if svc.reviewLookup == nil || filter.ProductSlug != constant.SlugNegativeRecord || len(details) == 0 {
+		return
+	}
+
+	companyId := filter.AuthCtx.CompanyId
+	threshold := svc.reviewLookup.Threshold(companyId)
+
+	trxIds := make([]string, 0, len(details))
+	for _, d := range details {
+		if d.TransactionId != "" {
+			trxIds = append(trxIds, d.TransactionId)
+		}
+	}
+
+	reviews, err := svc.reviewLookup.Reviews(companyId, trxIds)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", filter.JobId).
+			Msg("failed to fetch negative record reviews")
+	}
+
+	reviewsByTrx := make(map[string][]*review.Review, len(trxIds))
+	for _, r := range reviews {
+		reviewsByTrx[r.TransactionId] = append(reviewsByTrx[r.TransactionId], r)
+	}
+
+	for _, d := range details {
+		data, ok := d.Data.(*logTransDataNegativeRecord)
+		if !ok {
+			continue
+		}
+
+		trxReviews := reviewsByTrx[d.TransactionId]
+		hits := make([]dataNegativeRecordAPI, 0, len(data.Result))
+		caseNumbers := make([]string, 0, len(data.Result))
+		for _, hit := range data.Result {
+			if !review.AboveThreshold(hit.SimilarityScore, threshold) {
+				continue
+			}
+
+			for _, r := range trxReviews {
+				if r.CaseNumber == hit.CaseNumber {
+					hit.Review = r
+				}
+			}
+
+			hits = append(hits, hit)
+			caseNumbers = append(caseNumbers, hit.CaseNumber)
+		}
+
+		data.Result = hits
+		d.RawData = data
+		d.ReviewStatus = review.Status(caseNumbers, trxReviews)
+	}
+}
+
 func (svc *service) FinalizeJob(jobIdStr string) error {
 	count, err := svc.transactionRepo.ProcessedLogCountAPI(jobIdStr)
 	if err != nil {
@@ -299,6 +377,24 @@ func withDateColumn(mapper rowMapper) rowMapper {
 	}
 }
 
+// withDecisionColumns adds the decision of the company rules, only added
+// when a row was decided so exports of companies without rules are unchanged.
+func withDecisionColumns(mapper rowMapper) rowMapper {
+	return func(d *logTransProductCatalog) []string {
+		return append(mapper(d), d.Decision, strings.Join(d.FiredRules, "; "))
+	}
+}
+
+func hasDecision(details []*logTransProductCatalog) bool {
+	for _, d := range details {
+		if d.Decision != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 type exportProductConfig struct {
 	headers []string
 	event   func(summary bool) string
@@ -432,6 +528,20 @@ var exportProductMap = map[string]exportProductConfig{
 		},
 	},
 
+	// Negative Record
+	constant.SlugNegativeRecord: {
+		headers: constant.CSVExportHeaderNegativeRecord,
+		event: func(summary bool) string {
+			if summary {
+				return constant.EventNegativeRecordDownloadSummary
+			}
+			return constant.EventNegativeRecordDownload
+		},
+		mapper: func(isMasked bool, d *logTransProductCatalog) []string {
+			return mapNegativeRecordRow(d)
+		},
+	},
+
 	// Phone NIK Matching
 	constant.SlugPhoneNIKMatching: {
 		headers: constant.CSVExportHeaderPhoneNIk,
@@ -805,6 +915,51 @@ func mapPhoneNIKRow(isMasked bool, d *logTransProductCatalog) []string {
 	}
 }
 
+// mapNegativeRecordRow joins the hits of a transaction, company names are
+// not personal data so the row is never masked.
+func mapNegativeRecordRow(d *logTransProductCatalog) []string {
+	var (
+		description string
+		loanNo      string
+		companyName string
+		matched     []string
+		caseNumbers []string
+		confirmed   []string
+	)
+
+	if d.Message != nil {
+		description = *d.Message
+	}
+
+	if d.Input != nil {
+		loanNo = d.Input.LoanNo
+		if d.Input.CompanyName != nil {
+			companyName = *d.Input.CompanyName
+		}
+	}
+
+	if data, ok := d.Data.(*logTransDataNegativeRecord); ok {
+		for _, hit := range data.Result {
+			matched = append(matched, fmt.Sprintf("%s (%s)", hit.CompanyName, hit.SimilarityScore))
+			caseNumbers = append(caseNumbers, hit.CaseNumber)
+			if hit.Review != nil && hit.Review.Decision == review.DecisionConfirmed {
+				confirmed = append(confirmed, hit.CaseNumber)
+			}
+		}
+	}
+
+	return []string{
+		loanNo,
+		companyName,
+		strings.Join(matched, "; "),
+		strings.Join(caseNumbers, "; "),
+		d.ReviewStatus,
+		strings.Join(confirmed, "; "),
+		d.Status,
+		description,
+	}
+}
+
 func mapToClientResponse(src *model.AifcoreAPIResponse[*jobListResponse]) *jobListClientResponse {
 	jobs := make([]jobClient, len(src.Data.Jobs))
 	for i, j := range src.Data.Jobs {
@@ -1,11 +1,20 @@
 package job
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/datahub/companylitigation/review"
 	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMapLoanRecordCheckerRow(t *testing.T) {
@@ -74,3 +83,206 @@ func TestMapLoanRecordCheckerRow(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 }
+
+type reviewLookupStub struct {
+	threshold float64
+	reviews   []*review.Review
+	err       error
+}
+
+func (s *reviewLookupStub) Threshold(uint) float64 {
+	return s.threshold
+}
+
+func (s *reviewLookupStub) Reviews(uint, []string) ([]*review.Review, error) {
+	return s.reviews, s.err
+}
+
+func negativeRecordDetails(t *testing.T) []*logTransProductCatalog {
+	t.Helper()
+
+	message := "Succeed"
+	details := []*logTransProductCatalog{
+		{
+			TransactionId: "CNR-1",
+			Status:        "success",
+			Message:       &message,
+			Input:         &logTransInput{LoanNo: "L1", CompanyName: helper.StringPtr("PT Sengketa")},
+			RawData: map[string]any{"result": []any{
+				map[string]any{"company_name": "PT SENGKETA", "similarity_score": "100.0", "case_number": "12"},
+				map[string]any{"company_name": "PT SENGKETA RAYA", "similarity_score": "72.5", "case_number": "98"},
+			}},
+		},
+		{
+			TransactionId: "CNR-2",
+			Status:        "success",
+			Message:       &message,
+			Input:         &logTransInput{LoanNo: "L2", CompanyName: helper.StringPtr("PT Bersih")},
+			RawData:       map[string]any{"result": []any{}},
+		},
+	}
+
+	for _, d := range details {
+		require.NoError(t, remapLogTransData(constant.SlugNegativeRecord, d))
+	}
+
+	return details
+}
+
+func TestReviewNegativeRecords(t *testing.T) {
+	filter := &logFilter{ProductSlug: constant.SlugNegativeRecord, AuthCtx: &model.AuthContext{CompanyId: 9}}
+
+	t.Run("threshold and reviews", func(t *testing.T) {
+		confirmed := &review.Review{TransactionId: "CNR-1", CaseNumber: "12", Decision: review.DecisionConfirmed, ReviewerId: 4}
+		svc := &service{reviewLookup: &reviewLookupStub{threshold: 80, reviews: []*review.Review{confirmed}}}
+
+		details := negativeRecordDetails(t)
+		svc.reviewNegativeRecords(filter, details)
+
+		data := details[0].Data.(*logTransDataNegativeRecord)
+		require.Len(t, data.Result, 1)
+		assert.Equal(t, confirmed, data.Result[0].Review)
+		assert.Equal(t, data, details[0].RawData)
+		assert.Equal(t, review.DecisionConfirmed, details[0].ReviewStatus)
+		assert.Empty(t, details[1].ReviewStatus)
+
+		row := mapNegativeRecordRow(details[0])
+		assert.Equal(t, []string{"L1", "PT Sengketa", "PT SENGKETA (100.0)", "12", review.DecisionConfirmed, "12", "success", "Succeed"}, row)
+	})
+
+	t.Run("failing lookup leaves the hits pending", func(t *testing.T) {
+		svc := &service{reviewLookup: &reviewLookupStub{err: errors.New("core unavailable")}}
+
+		details := negativeRecordDetails(t)
+		svc.reviewNegativeRecords(filter, details)
+
+		assert.Len(t, details[0].Data.(*logTransDataNegativeRecord).Result, 2)
+		assert.Equal(t, review.StatusPending, details[0].ReviewStatus)
+
+		row := mapNegativeRecordRow(details[0])
+		assert.Equal(t, []string{"L1", "PT Sengketa", "PT SENGKETA (100.0); PT SENGKETA RAYA (72.5)", "12; 98", review.StatusPending, "", "success", "Succeed"}, row)
+	})
+
+	t.Run("other products", func(t *testing.T) {
+		svc := &service{reviewLookup: &reviewLookupStub{threshold: 80}}
+
+		details := negativeRecordDetails(t)
+		svc.reviewNegativeRecords(&logFilter{ProductSlug: constant.SlugRecycleNumber, AuthCtx: filter.AuthCtx}, details)
+
+		assert.Len(t, details[0].Data.(*logTransDataNegativeRecord).Result, 2)
+		assert.Empty(t, details[0].ReviewStatus)
+	})
+}
+
+func TestNegativeRecordHitResponse(t *testing.T) {
+	var data logTransDataNegativeRecord
+	require.NoError(t, json.Unmarshal([]byte(`{"result":[{
+		"company_name": "PT SENGKETA",
+		"similarity_score": "100.0",
+		"status": "Banding",
+		"case_number": "12",
+		"court": "PN Jakarta Pusat",
+		"province": "DKI Jakarta",
+		"case_type": "Perdata",
+		"registration_date": "2024-01-02",
+		"process_duration": "120 hari",
+		"last_updated": "2024-05-01"
+	}]}`), &data))
+
+	raw, err := json.Marshal(data.Result[0])
+	require.NoError(t, err)
+
+	// job details answer with the field names they had before the rename
+	assert.JSONEq(t, `{
+		"company_name": "PT SENGKETA",
+		"case_status": "Banding",
+		"court": "PN Jakarta Pusat",
+		"case_number": "12",
+		"case_code_description": "DKI Jakarta",
+		"party_status": "Perdata",
+		"case_classification": "120 hari",
+		"registration_date": "2024-01-02",
+		"case_duration": "2024-05-01",
+		"similarity_score": "100.0"
+	}`, string(raw))
+}
+
+// jobDetailStub answers the job detail lookup, the other calls are not used.
+type jobDetailStub struct {
+	Repository
+	details []*logTransProductCatalog
+}
+
+func (s *jobDetailStub) GetJobDetailAPI(*logFilter) (*model.AifcoreAPIResponse[*jobDetailResponse], error) {
+	return &model.AifcoreAPIResponse[*jobDetailResponse]{
+		Success: true,
+		Data:    &jobDetailResponse{TotalData: int64(len(s.details)), JobDetails: s.details},
+	}, nil
+}
+
+type operationStub struct {
+	operation.Repository
+	logs []*operation.AddLogRequest
+}
+
+func (s *operationStub) AddLogOperation(req *operation.AddLogRequest) error {
+	s.logs = append(s.logs, req)
+	return nil
+}
+
+func loanRecordInput() *logTransInput {
+	return &logTransInput{
+		Name:        helper.StringPtr(constant.DummyName),
+		NIK:         helper.StringPtr(constant.DummyNIK),
+		PhoneNumber: helper.StringPtr(constant.DummyPhoneNumber),
+		LoanNo:      constant.DummyLoanNo,
+	}
+}
+
+func TestExportJobDetails(t *testing.T) {
+	filter := &logFilter{
+		JobId:       "7",
+		ProductSlug: constant.SlugLoanRecordChecker,
+		AuthCtx:     &model.AuthContext{UserId: 3, CompanyId: 9, APIClientId: "12"},
+	}
+
+	t.Run("decided single request", func(t *testing.T) {
+		message := "Succeed"
+		repo := &jobDetailStub{details: []*logTransProductCatalog{{
+			TransactionId: "CHK-1",
+			Input:         loanRecordInput(),
+			RawData:       map[string]any{"remarks": "-", "status": "default"},
+			Message:       &message,
+			Decision:      "reject",
+			FiredRules:    []string{"not clear", "late payment"},
+		}}}
+		operationRepo := &operationStub{}
+		svc := &service{repo: repo, operationRepo: operationRepo}
+
+		var buf bytes.Buffer
+		_, err := svc.ExportJobDetails(filter, &buf)
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, append(slices.Clone(constant.CSVExportHeaderLoanRecord), constant.CSVExportHeaderDecision...), rows[0])
+		assert.Equal(t, []string{"reject", "not clear; late payment"}, rows[1][len(rows[1])-2:])
+		require.Len(t, operationRepo.logs, 1)
+		assert.Equal(t, constant.EventLoanRecordDownload, operationRepo.logs[0].Action)
+		assert.Equal(t, "12", operationRepo.logs[0].APIClientId)
+	})
+
+	t.Run("undecided rows", func(t *testing.T) {
+		repo := &jobDetailStub{details: []*logTransProductCatalog{{TransactionId: "CHK-2", Input: loanRecordInput()}}}
+		svc := &service{repo: repo, operationRepo: &operationStub{}}
+
+		var buf bytes.Buffer
+		_, err := svc.ExportJobDetails(filter, &buf)
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, constant.CSVExportHeaderLoanRecord, rows[0])
+	})
+}
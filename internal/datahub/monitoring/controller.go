@@ -0,0 +1,112 @@
+package monitoring
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NewController(svc Service) Controller {
+	return &controller{svc}
+}
+
+type controller struct {
+	svc Service
+}
+
+type Controller interface {
+	AddSubject(c *fiber.Ctx) error
+	GetSubjects(c *fiber.Ctx) error
+	RemoveSubject(c *fiber.Ctx) error
+	GetAlerts(c *fiber.Ctx) error
+}
+
+func (ctrl *controller) AddSubject(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*addSubjectRequest)
+	if !ok {
+		return apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	monitored, err := ctrl.svc.AddSubject(authCtx, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		monitored,
+	))
+}
+
+func (ctrl *controller) GetSubjects(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	subjects, meta, err := ctrl.svc.GetSubjects(authCtx, &subjectFilter{
+		Page:   c.Query(constant.Page, "1"),
+		Size:   c.Query(constant.Size, "10"),
+		Masked: masked,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		subjects,
+		meta,
+	))
+}
+
+func (ctrl *controller) RemoveSubject(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	if err := ctrl.svc.RemoveSubject(authCtx, c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+		constant.Success,
+		nil,
+	))
+}
+
+func (ctrl *controller) GetAlerts(c *fiber.Ctx) error {
+	authCtx, err := helper.GetAuthContext(c)
+	if err != nil {
+		return apperror.Unauthorized(err.Error())
+	}
+
+	masked, _ := strconv.ParseBool(c.Query("masked"))
+
+	alerts, meta, err := ctrl.svc.GetAlerts(authCtx, &alertFilter{
+		SubjectId: c.Query("subject_id"),
+		Page:      c.Query(constant.Page, "1"),
+		Size:      c.Query(constant.Size, "10"),
+		Masked:    masked,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse(
+		constant.Success,
+		alerts,
+		meta,
+	))
+}
@@ -0,0 +1,56 @@
+package monitoring
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/mail"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// SetupInit must run after the products are registered, only the products
+// registered at that point can be monitored.
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, mailSvc *mail.SendMailService) {
+	repository := NewRepository(cfg, client, nil)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+
+	service := NewService(repository, memberRepo, operationRepo, mailSvc, pipeline.Runners())
+	controller := NewController(service)
+
+	apiGroup.Post("/subjects", middleware.ValidateRequest(addSubjectRequest{}), middleware.GetJWTPayloadFromCookie(cfg), controller.AddSubject)
+	apiGroup.Get("/subjects", middleware.GetJWTPayloadFromCookie(cfg), controller.GetSubjects)
+	apiGroup.Delete("/subjects/:id", middleware.GetJWTPayloadFromCookie(cfg), controller.RemoveSubject)
+	apiGroup.Get("/alerts", middleware.GetJWTPayloadFromCookie(cfg), controller.GetAlerts)
+
+	setupCron(service)
+}
+
+// setupCron looks for due subjects every hour, each subject's interval
+// decides whether it is checked.
+func setupCron(service Service) {
+	jakartaTime, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load Asia/Jakarta timezone")
+	}
+
+	scd := gocron.NewScheduler(jakartaTime)
+
+	_, err = scd.Cron("0 * * * *").Do(func() {
+		if err := service.RunScheduledChecks(time.Now().In(jakartaTime)); err != nil {
+			log.Error().Err(err).Msg("failed to run scheduled monitoring checks")
+		}
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to register monitoring cron")
+	}
+
+	scd.StartAsync()
+}
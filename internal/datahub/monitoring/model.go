@@ -0,0 +1,131 @@
+package monitoring
+
+import (
+	"front-office/pkg/helper"
+	"time"
+)
+
+const (
+	routePhoneLiveStatus   = "phone-live-status"
+	routeLoanRecordChecker = "loan-record-checker"
+	routeNegativeRecord    = "negative-record"
+
+	defaultIntervalDays = 1
+	maxIntervalDays     = 30
+)
+
+// subject is sent to every monitored product as its request body, the json
+// names match the fields of the product requests.
+type subject struct {
+	Name        string `json:"name"`
+	NIK         string `json:"nik"`
+	PhoneNumber string `json:"phone_number"`
+	CompanyName string `json:"company_name"`
+	LoanNo      string `json:"loan_no"`
+}
+
+func (s subject) masked() subject {
+	s.NIK = helper.MaskingHead(s.NIK, 10)
+	s.PhoneNumber = helper.MaskingMiddle(s.PhoneNumber)
+
+	return s
+}
+
+// fields returns the subject by json name, used to check what a product
+// needs.
+func (s subject) fields() map[string]string {
+	return map[string]string{
+		"name":         s.Name,
+		"nik":          s.NIK,
+		"phone_number": s.PhoneNumber,
+		"company_name": s.CompanyName,
+		"loan_no":      s.LoanNo,
+	}
+}
+
+type addSubjectRequest struct {
+	Name        string `json:"name"`
+	NIK         string `json:"nik" normalize:"nik"`
+	PhoneNumber string `json:"phone_number" normalize:"phone"`
+	CompanyName string `json:"company_name"`
+	LoanNo      string `json:"loan_no" validate:"required~Loan No cannot be empty."`
+	// Products are the product routes to re-run, any of
+	// "phone-live-status", "loan-record-checker" and "negative-record"
+	Products []string `json:"products"`
+	// IntervalDays is the number of days between checks, defaults to 1
+	IntervalDays int `json:"interval_days"`
+}
+
+func (req *addSubjectRequest) subject() subject {
+	return subject{
+		Name:        req.Name,
+		NIK:         req.NIK,
+		PhoneNumber: req.PhoneNumber,
+		CompanyName: req.CompanyName,
+		LoanNo:      req.LoanNo,
+	}
+}
+
+// snapshot holds the watched fields of one product result keyed by field.
+type snapshot map[string]string
+
+type monitoredSubject struct {
+	Id           uint     `json:"id"`
+	MemberId     uint     `json:"member_id"`
+	CompanyId    uint     `json:"company_id"`
+	Subject      subject  `json:"subject"`
+	Products     []string `json:"products"`
+	IntervalDays int      `json:"interval_days"`
+	// Snapshots are the results of the last check keyed by product route
+	Snapshots     map[string]snapshot `json:"snapshots"`
+	LastCheckedAt *time.Time          `json:"last_checked_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+type createSubjectRespData struct {
+	SubjectId uint `json:"subject_id"`
+}
+
+type updateSubjectRequest struct {
+	Snapshots     map[string]snapshot `json:"snapshots"`
+	LastCheckedAt time.Time           `json:"last_checked_at"`
+}
+
+// alert is one watched field that changed between two checks, Previous is
+// empty for a new value, e.g. a new court case, and Current for a value
+// that is gone.
+type alert struct {
+	Id          uint      `json:"id"`
+	CompanyId   uint      `json:"company_id"`
+	SubjectId   uint      `json:"subject_id"`
+	Subject     subject   `json:"subject"`
+	Product     string    `json:"product"`
+	ProductName string    `json:"product_name"`
+	Field       string    `json:"field"`
+	Previous    string    `json:"previous"`
+	Current     string    `json:"current"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type subjectFilter struct {
+	CompanyId string
+	Page      string
+	Size      string
+	Masked    bool
+}
+
+type alertFilter struct {
+	CompanyId string
+	SubjectId string
+	Page      string
+	Size      string
+	Masked    bool
+}
+
+type AlertTemplateData struct {
+	Subject string
+	Name    string
+	Target  subject
+	Alerts  []*alert
+	Year    int
+}
@@ -0,0 +1,257 @@
+package monitoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/configs/application"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"front-office/pkg/httpclient"
+	"front-office/pkg/jsonutil"
+	"net/http"
+)
+
+func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+	if marshalFn == nil {
+		marshalFn = json.Marshal
+	}
+
+	return &repository{
+		cfg:       cfg,
+		client:    client,
+		marshalFn: marshalFn,
+	}
+}
+
+type repository struct {
+	cfg       *application.Config
+	client    httpclient.HTTPClient
+	marshalFn jsonutil.Marshaller
+}
+
+type Repository interface {
+	CreateSubjectAPI(payload *monitoredSubject) (*createSubjectRespData, error)
+	GetSubjectsAPI(filter *subjectFilter) ([]*monitoredSubject, *model.Meta, error)
+	GetScheduledSubjectsAPI() ([]*monitoredSubject, error)
+	GetSubjectAPI(id string) (*monitoredSubject, error)
+	UpdateSubjectAPI(id string, payload *updateSubjectRequest) error
+	DeleteSubjectAPI(id string) error
+	CreateAlertsAPI(payload []*alert) error
+	GetAlertsAPI(filter *alertFilter) ([]*alert, *model.Meta, error)
+}
+
+func (repo *repository) CreateSubjectAPI(payload *monitoredSubject) (*createSubjectRespData, error) {
+	url := fmt.Sprintf(`%v/api/core/monitoring/subjects`, repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*createSubjectRespData](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetSubjectsAPI(filter *subjectFilter) ([]*monitoredSubject, *model.Meta, error) {
+	url := fmt.Sprintf(`%v/api/core/monitoring/subjects`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", filter.CompanyId)
+	q.Add(constant.Page, filter.Page)
+	q.Add(constant.Size, filter.Size)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*monitoredSubject](resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiResp.Data, apiResp.Meta, nil
+}
+
+// GetScheduledSubjectsAPI returns the subjects of every company, the
+// scheduler decides which of them are due.
+func (repo *repository) GetScheduledSubjectsAPI() ([]*monitoredSubject, error) {
+	url := fmt.Sprintf(`%v/api/core/monitoring/schedule`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*monitoredSubject](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) GetSubjectAPI(id string) (*monitoredSubject, error) {
+	url := fmt.Sprintf(`%v/api/core/monitoring/subjects/%v`, repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[*monitoredSubject](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiResp.Data, nil
+}
+
+func (repo *repository) UpdateSubjectAPI(id string, payload *updateSubjectRequest) error {
+	url := fmt.Sprintf(`%v/api/core/monitoring/subjects/%v`, repo.cfg.App.AifcoreHost, id)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+func (repo *repository) DeleteSubjectAPI(id string) error {
+	url := fmt.Sprintf(`%v/api/core/monitoring/subjects/%v`, repo.cfg.App.AifcoreHost, id)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+func (repo *repository) CreateAlertsAPI(payload []*alert) error {
+	url := fmt.Sprintf(`%v/api/core/monitoring/alerts`, repo.cfg.App.AifcoreHost)
+
+	bodyBytes, err := repo.marshalFn(payload)
+	if err != nil {
+		return errors.New(constant.ErrInvalidRequestPayload)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	_, err = helper.ParseAifcoreAPIResponse[any](resp)
+
+	return err
+}
+
+func (repo *repository) GetAlertsAPI(filter *alertFilter) ([]*alert, *model.Meta, error) {
+	url := fmt.Sprintf(`%v/api/core/monitoring/alerts`, repo.cfg.App.AifcoreHost)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, errors.New(constant.ErrMsgHTTPReqFailed)
+	}
+
+	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+
+	q := req.URL.Query()
+	q.Add("company_id", filter.CompanyId)
+	q.Add("subject_id", filter.SubjectId)
+	q.Add(constant.Page, filter.Page)
+	q.Add(constant.Size, filter.Size)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := repo.client.Do(req)
+	if err != nil {
+		return nil, nil, errors.New(constant.ErrUpstreamUnavailable)
+	}
+	defer resp.Body.Close()
+
+	apiResp, err := helper.ParseAifcoreAPIResponse[[]*alert](resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiResp.Data, apiResp.Meta, nil
+}
@@ -0,0 +1,354 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/mail"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewService(repo Repository, memberRepo member.Repository, operationRepo operation.Repository, mailSvc *mail.SendMailService, runners map[string]pipeline.Runner) Service {
+	return &service{
+		repo,
+		memberRepo,
+		operationRepo,
+		mailSvc,
+		runners,
+	}
+}
+
+type service struct {
+	repo          Repository
+	memberRepo    member.Repository
+	operationRepo operation.Repository
+	mailSvc       *mail.SendMailService
+	runners       map[string]pipeline.Runner
+}
+
+type Service interface {
+	AddSubject(authCtx *model.AuthContext, req *addSubjectRequest) (*monitoredSubject, error)
+	GetSubjects(authCtx *model.AuthContext, filter *subjectFilter) ([]*monitoredSubject, *model.Meta, error)
+	RemoveSubject(authCtx *model.AuthContext, id string) error
+	GetAlerts(authCtx *model.AuthContext, filter *alertFilter) ([]*alert, *model.Meta, error)
+	RunScheduledChecks(now time.Time) error
+}
+
+// AddSubject puts a subject on the company monitoring list. Its first
+// scheduled check records the baseline, changes are alerted from the second
+// check on.
+func (svc *service) AddSubject(authCtx *model.AuthContext, req *addSubjectRequest) (*monitoredSubject, error) {
+	target := req.subject()
+
+	routes, err := svc.products(req.Products, target)
+	if err != nil {
+		return nil, err
+	}
+
+	intervalDays := req.IntervalDays
+	if intervalDays == 0 {
+		intervalDays = defaultIntervalDays
+	}
+	if intervalDays < 1 || intervalDays > maxIntervalDays {
+		return nil, apperror.BadRequest(constant.InvalidMonitoringInterval)
+	}
+
+	monitored := &monitoredSubject{
+		MemberId:     authCtx.UserId,
+		CompanyId:    authCtx.CompanyId,
+		Subject:      target,
+		Products:     routes,
+		IntervalDays: intervalDays,
+	}
+
+	created, err := svc.repo.CreateSubjectAPI(monitored)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedCreateMonitoredSubject)
+	}
+	monitored.Id = created.SubjectId
+
+	svc.addLogOperation(authCtx, constant.EventMonitoringAddSubject)
+
+	return monitored, nil
+}
+
+func (svc *service) GetSubjects(authCtx *model.AuthContext, filter *subjectFilter) ([]*monitoredSubject, *model.Meta, error) {
+	filter.CompanyId = authCtx.CompanyIdStr()
+
+	subjects, meta, err := svc.repo.GetSubjectsAPI(filter)
+	if err != nil {
+		return nil, nil, apperror.MapRepoError(err, constant.FailedFetchMonitoredSubjects)
+	}
+
+	if filter.Masked {
+		for _, monitored := range subjects {
+			monitored.Subject = monitored.Subject.masked()
+		}
+	}
+
+	return subjects, meta, nil
+}
+
+func (svc *service) RemoveSubject(authCtx *model.AuthContext, id string) error {
+	if _, err := svc.getCompanySubject(authCtx.CompanyId, id); err != nil {
+		return err
+	}
+
+	if err := svc.repo.DeleteSubjectAPI(id); err != nil {
+		return apperror.MapRepoError(err, constant.FailedDeleteMonitoredSubject)
+	}
+
+	svc.addLogOperation(authCtx, constant.EventMonitoringRemoveSubject)
+
+	return nil
+}
+
+func (svc *service) GetAlerts(authCtx *model.AuthContext, filter *alertFilter) ([]*alert, *model.Meta, error) {
+	filter.CompanyId = authCtx.CompanyIdStr()
+
+	alerts, meta, err := svc.repo.GetAlertsAPI(filter)
+	if err != nil {
+		return nil, nil, apperror.MapRepoError(err, constant.FailedFetchMonitoringAlerts)
+	}
+
+	if filter.Masked {
+		for _, a := range alerts {
+			a.Subject = a.Subject.masked()
+		}
+	}
+
+	return alerts, meta, nil
+}
+
+// RunScheduledChecks re-runs the products of every subject that is due, a
+// failing subject is logged and does not stop the others.
+func (svc *service) RunScheduledChecks(now time.Time) error {
+	subjects, err := svc.repo.GetScheduledSubjectsAPI()
+	if err != nil {
+		return apperror.MapRepoError(err, constant.FailedFetchMonitoredSubjects)
+	}
+
+	for _, monitored := range subjects {
+		if !due(monitored, now) {
+			continue
+		}
+
+		if err := svc.checkSubject(monitored, now); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("subject_id", monitored.Id).
+				Uint("company_id", monitored.CompanyId).
+				Msg("failed to check monitored subject")
+		}
+	}
+
+	return nil
+}
+
+// checkSubject runs the products as the member who added the subject, so
+// quota and usage are booked the same way as their own requests. A product
+// that fails keeps its previous snapshot and is compared again next time.
+func (svc *service) checkSubject(monitored *monitoredSubject, now time.Time) error {
+	authCtx, owner, err := svc.ownerAuthContext(monitored)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(monitored.Subject)
+	if err != nil {
+		return err
+	}
+
+	snapshots := make(map[string]snapshot, len(monitored.Products))
+	for route, previous := range monitored.Snapshots {
+		snapshots[route] = previous
+	}
+
+	var alerts []*alert
+	for _, route := range monitored.Products {
+		w, watched := watchers[route]
+		runner, registered := svc.runners[route]
+		if !watched || !registered {
+			continue
+		}
+
+		result, err := runner.Run(authCtx, nil, body)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint("subject_id", monitored.Id).
+				Str("product", route).
+				Msg("failed to run monitored product")
+
+			continue
+		}
+
+		current := w.watch(dataMap(result.Data))
+		if previous, ok := monitored.Snapshots[route]; ok {
+			for _, change := range diff(previous, current) {
+				change.CompanyId = monitored.CompanyId
+				change.SubjectId = monitored.Id
+				change.Subject = monitored.Subject
+				change.Product = route
+				change.ProductName = runner.Name()
+				change.CreatedAt = now
+				alerts = append(alerts, change)
+			}
+		}
+		snapshots[route] = current
+	}
+
+	if len(alerts) > 0 {
+		// stored before the snapshots move on, so a failed store is
+		// detected again by the next check
+		if err := svc.repo.CreateAlertsAPI(alerts); err != nil {
+			return err
+		}
+
+		if err := svc.notify(owner, monitored, alerts, now); err != nil {
+			log.Warn().
+				Err(err).
+				Uint("subject_id", monitored.Id).
+				Msg("failed to send monitoring alert")
+		}
+	}
+
+	return svc.repo.UpdateSubjectAPI(helper.ConvertUintToString(monitored.Id), &updateSubjectRequest{
+		Snapshots:     snapshots,
+		LastCheckedAt: now,
+	})
+}
+
+// ownerAuthContext stands in for the session a scheduled check does not
+// have.
+func (svc *service) ownerAuthContext(monitored *monitoredSubject) (*model.AuthContext, *member.MstMember, error) {
+	owner, err := svc.memberRepo.GetMemberAPI(&member.MemberParams{
+		Id:        helper.ConvertUintToString(monitored.MemberId),
+		CompanyId: helper.ConvertUintToString(monitored.CompanyId),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if owner == nil || owner.MemberId == 0 || owner.CompanyId != monitored.CompanyId {
+		return nil, nil, errors.New("subject owner not found")
+	}
+	if !owner.Active {
+		return nil, nil, errors.New("subject owner is not active")
+	}
+
+	return &model.AuthContext{
+		UserId:    owner.MemberId,
+		CompanyId: owner.CompanyId,
+		RoleId:    owner.RoleId,
+		QuotaType: uint(owner.QuotaType),
+		APIKey:    owner.Key,
+	}, owner, nil
+}
+
+func (svc *service) notify(owner *member.MstMember, monitored *monitoredSubject, alerts []*alert, now time.Time) error {
+	subject := fmt.Sprintf("Monitoring Alert: %d change(s) detected", len(alerts))
+
+	return svc.mailSvc.SendWithTemplateToList(
+		[]string{owner.Email},
+		nil,
+		nil,
+		subject,
+		"monitoring_alert.html",
+		AlertTemplateData{
+			Subject: subject,
+			Name:    owner.Name,
+			Target:  monitored.Subject.masked(),
+			Alerts:  alerts,
+			Year:    now.Year(),
+		},
+	)
+}
+
+// products checks the requested routes can be monitored and the subject
+// has what each of them needs, duplicates are run once.
+func (svc *service) products(requested []string, target subject) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, apperror.BadRequest(constant.MonitoredProductsRequired)
+	}
+
+	fields := target.fields()
+	seen := make(map[string]bool, len(requested))
+	routes := make([]string, 0, len(requested))
+	for _, route := range requested {
+		w, watched := watchers[route]
+		if _, registered := svc.runners[route]; !watched || !registered {
+			return nil, apperror.BadRequest(fmt.Sprintf(constant.UnmonitoredProduct, route))
+		}
+		for _, field := range w.requires {
+			if fields[field] == "" {
+				return nil, apperror.BadRequest(fmt.Sprintf(constant.MonitoredSubjectMissingField, route, field))
+			}
+		}
+		if seen[route] {
+			continue
+		}
+
+		seen[route] = true
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (svc *service) getCompanySubject(companyId uint, id string) (*monitoredSubject, error) {
+	monitored, err := svc.repo.GetSubjectAPI(id)
+	if err != nil {
+		var apiErr *apperror.ExternalAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, apperror.NotFound(constant.MonitoredSubjectNotFound)
+		}
+
+		return nil, apperror.MapRepoError(err, constant.FailedFetchMonitoredSubjects)
+	}
+	if monitored == nil || monitored.Id == 0 || monitored.CompanyId != companyId {
+		return nil, apperror.NotFound(constant.MonitoredSubjectNotFound)
+	}
+
+	return monitored, nil
+}
+
+func (svc *service) addLogOperation(authCtx *model.AuthContext, event string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      event,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", event).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+// due compares whole hours, the hourly cron would otherwise drift by the
+// time a check takes.
+func due(monitored *monitoredSubject, now time.Time) bool {
+	if monitored.LastCheckedAt == nil {
+		return true
+	}
+
+	intervalDays := monitored.IntervalDays
+	if intervalDays < 1 {
+		intervalDays = defaultIntervalDays
+	}
+
+	next := monitored.LastCheckedAt.Truncate(time.Hour).AddDate(0, 0, intervalDays)
+
+	return !now.Truncate(time.Hour).Before(next)
+}
@@ -0,0 +1,448 @@
+package monitoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/identity/phonelivestatus"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/mail"
+	"front-office/internal/simulator"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/httpclient"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coreStub answers the core calls by "METHOD path".
+type coreStub struct {
+	mu     sync.Mutex
+	routes map[string]func(*http.Request) (int, any)
+	bodies map[string][][]byte
+}
+
+func (s *coreStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	s.mu.Lock()
+	s.bodies[key] = append(s.bodies[key], reqBody)
+	s.mu.Unlock()
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type stubMailSender struct {
+	sent []mail.Mail
+}
+
+func (s *stubMailSender) Send(m mail.Mail) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// fakeRunner answers with the next queued result, the last one repeats.
+type fakeRunner struct {
+	name    string
+	results []any
+	authCtx *model.AuthContext
+	bodies  [][]byte
+}
+
+func (r *fakeRunner) Name() string {
+	return r.name
+}
+
+func (r *fakeRunner) Reserve(*model.AuthContext, int) (*quota.Reservation, error) {
+	return nil, nil
+}
+
+func (r *fakeRunner) Run(authCtx *model.AuthContext, _ *pipeline.Parent, body []byte) (*pipeline.RunResult, error) {
+	r.authCtx = authCtx
+	r.bodies = append(r.bodies, body)
+
+	next := r.results[0]
+	if len(r.results) > 1 {
+		r.results = r.results[1:]
+	}
+	if err, ok := next.(error); ok {
+		return nil, err
+	}
+
+	return &pipeline.RunResult{StatusCode: http.StatusOK, Message: constant.Success, Data: next}, nil
+}
+
+func ok(data any) func(*http.Request) (int, any) {
+	return func(*http.Request) (int, any) { return http.StatusOK, data }
+}
+
+var owner = map[string]any{"member_id": 1, "company_id": 9, "role_id": 2, "quota_type": 1, "api_key": "key", "email": "budi@example.com", "name": "Budi", "active": true}
+
+func setupService(t *testing.T, routes map[string]func(*http.Request) (int, any), runners map[string]pipeline.Runner) (Service, *coreStub, *stubMailSender) {
+	t.Helper()
+
+	cfg := &application.Config{App: &application.Environment{AifcoreHost: constant.MockHost}}
+	client := &coreStub{
+		routes: map[string]func(*http.Request) (int, any){
+			"POST /api/core/logging/operation": ok(nil),
+			"GET /api/core/member/by":          ok(owner),
+		},
+		bodies: map[string][][]byte{},
+	}
+	for key, handler := range routes {
+		client.routes[key] = handler
+	}
+
+	renderer, err := mail.NewTemplateRenderer("../../mail/template")
+	require.NoError(t, err)
+
+	sender := &stubMailSender{}
+	mailSvc := mail.NewMailService(sender, renderer, nil, "3")
+
+	svc := NewService(NewRepository(cfg, client, nil), member.NewRepository(cfg, client, nil), operation.NewRepository(cfg, client, nil), mailSvc, runners)
+
+	return svc, client, sender
+}
+
+func allRunners() map[string]pipeline.Runner {
+	return map[string]pipeline.Runner{
+		routePhoneLiveStatus:   &fakeRunner{name: "phone live status", results: []any{nil}},
+		routeLoanRecordChecker: &fakeRunner{name: "loan record checker", results: []any{nil}},
+		routeNegativeRecord:    &fakeRunner{name: "negative record", results: []any{nil}},
+		"tax-score":            &fakeRunner{name: "tax score", results: []any{nil}},
+	}
+}
+
+func TestAddSubject(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client, _ := setupService(t, map[string]func(*http.Request) (int, any){
+			"POST /api/core/monitoring/subjects": ok(map[string]any{"subject_id": 5}),
+		}, allRunners())
+
+		monitored, err := svc.AddSubject(authCtx, &addSubjectRequest{
+			PhoneNumber: "6281234567890",
+			CompanyName: "PT Artha Mulia",
+			LoanNo:      "L1",
+			Products:    []string{routePhoneLiveStatus, routeNegativeRecord, routePhoneLiveStatus},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), monitored.Id)
+		assert.Equal(t, []string{routePhoneLiveStatus, routeNegativeRecord}, monitored.Products)
+		assert.Equal(t, defaultIntervalDays, monitored.IntervalDays)
+
+		var stored monitoredSubject
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/monitoring/subjects"][0], &stored))
+		assert.Equal(t, uint(9), stored.CompanyId)
+		assert.Equal(t, "PT Artha Mulia", stored.Subject.CompanyName)
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	invalid := map[string]struct {
+		req  *addSubjectRequest
+		want string
+	}{
+		"no products":      {&addSubjectRequest{PhoneNumber: "6281234567890"}, constant.MonitoredProductsRequired},
+		"not monitorable":  {&addSubjectRequest{Products: []string{"tax-score"}}, "product tax-score cannot be monitored"},
+		"not registered":   {&addSubjectRequest{Products: []string{"unknown"}}, "product unknown cannot be monitored"},
+		"missing field":    {&addSubjectRequest{Name: "Budi", PhoneNumber: "6281234567890", Products: []string{routeLoanRecordChecker}}, "loan-record-checker requires nik"},
+		"invalid interval": {&addSubjectRequest{PhoneNumber: "6281234567890", Products: []string{routePhoneLiveStatus}, IntervalDays: 31}, constant.InvalidMonitoringInterval},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			svc, client, _ := setupService(t, nil, allRunners())
+
+			_, err := svc.AddSubject(authCtx, tc.req)
+
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Equal(t, tc.want, appErr.Message)
+			assert.Empty(t, client.bodies["POST /api/core/monitoring/subjects"])
+		})
+	}
+}
+
+func TestRemoveSubject(t *testing.T) {
+	routes := map[string]func(*http.Request) (int, any){
+		"GET /api/core/monitoring/subjects/5":    ok(map[string]any{"id": 5, "company_id": 9}),
+		"DELETE /api/core/monitoring/subjects/5": ok(nil),
+	}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client, _ := setupService(t, routes, nil)
+
+		require.NoError(t, svc.RemoveSubject(&model.AuthContext{UserId: 1, CompanyId: 9}, "5"))
+		assert.Len(t, client.bodies["DELETE /api/core/monitoring/subjects/5"], 1)
+	})
+
+	t.Run("other company", func(t *testing.T) {
+		svc, client, _ := setupService(t, routes, nil)
+
+		err := svc.RemoveSubject(&model.AuthContext{UserId: 1, CompanyId: 3}, "5")
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+		assert.Empty(t, client.bodies["DELETE /api/core/monitoring/subjects/5"])
+	})
+
+	t.Run("unknown subject", func(t *testing.T) {
+		svc, _, _ := setupService(t, nil, nil)
+
+		err := svc.RemoveSubject(&model.AuthContext{UserId: 1, CompanyId: 9}, "6")
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, constant.MonitoredSubjectNotFound, appErr.Message)
+	})
+}
+
+func TestGetAlerts(t *testing.T) {
+	svc, _, _ := setupService(t, map[string]func(*http.Request) (int, any){
+		"GET /api/core/monitoring/alerts": func(req *http.Request) (int, any) {
+			if req.URL.Query().Get("subject_id") != "5" || req.URL.Query().Get("company_id") != "9" {
+				return http.StatusOK, []map[string]any{}
+			}
+
+			return http.StatusOK, []map[string]any{{
+				"id":         1,
+				"subject_id": 5,
+				"subject":    map[string]any{"nik": "3201234567890001", "phone_number": "6281234567890"},
+				"field":      "live_status",
+			}}
+		},
+	}, nil)
+
+	alerts, _, err := svc.GetAlerts(&model.AuthContext{CompanyId: 9}, &alertFilter{SubjectId: "5", Masked: true})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.NotEqual(t, "3201234567890001", alerts[0].Subject.NIK)
+	assert.NotEqual(t, "6281234567890", alerts[0].Subject.PhoneNumber)
+}
+
+func TestRunScheduledChecks(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 0, 5, 0, time.UTC)
+	yesterday := now.Add(-24*time.Hour + time.Minute)
+	lastHour := now.Add(-time.Hour)
+
+	newCase := map[string]any{"result": []any{
+		map[string]any{"case_number": "411/Pdt.G/2025", "status": "Penyerahan Memori Kasasi"},
+	}}
+
+	scheduled := func(subjects ...map[string]any) map[string]func(*http.Request) (int, any) {
+		return map[string]func(*http.Request) (int, any){
+			"GET /api/core/monitoring/schedule":   ok(subjects),
+			"PUT /api/core/monitoring/subjects/5": ok(nil),
+			"PUT /api/core/monitoring/subjects/6": ok(nil),
+			"POST /api/core/monitoring/alerts":    ok(nil),
+		}
+	}
+
+	subject := func(id uint, lastCheckedAt *time.Time, snapshots map[string]snapshot) map[string]any {
+		return map[string]any{
+			"id":              id,
+			"member_id":       1,
+			"company_id":      9,
+			"subject":         map[string]any{"phone_number": "6281234567890", "company_name": "PT Artha Mulia", "loan_no": "L1"},
+			"products":        []string{routePhoneLiveStatus, routeNegativeRecord},
+			"interval_days":   1,
+			"snapshots":       snapshots,
+			"last_checked_at": lastCheckedAt,
+		}
+	}
+
+	updates := func(t *testing.T, client *coreStub, key string) []updateSubjectRequest {
+		t.Helper()
+
+		var decoded []updateSubjectRequest
+		for _, body := range client.bodies[key] {
+			var update updateSubjectRequest
+			require.NoError(t, json.Unmarshal(body, &update))
+			decoded = append(decoded, update)
+		}
+
+		return decoded
+	}
+
+	t.Run("first check records the baseline", func(t *testing.T) {
+		phone := &fakeRunner{name: "phone live status", results: []any{map[string]any{"live_status": "active", "operator": "telkomsel"}}}
+		runners := map[string]pipeline.Runner{routePhoneLiveStatus: phone, routeNegativeRecord: &fakeRunner{name: "negative record", results: []any{newCase}}}
+		svc, client, sender := setupService(t, scheduled(subject(5, nil, nil)), runners)
+
+		require.NoError(t, svc.RunScheduledChecks(now))
+
+		assert.Equal(t, &model.AuthContext{UserId: 1, CompanyId: 9, RoleId: 2, QuotaType: 1, APIKey: "key"}, phone.authCtx)
+		assert.JSONEq(t, `{"name":"","nik":"","phone_number":"6281234567890","company_name":"PT Artha Mulia","loan_no":"L1"}`, string(phone.bodies[0]))
+
+		stored := updates(t, client, "PUT /api/core/monitoring/subjects/5")
+		require.Len(t, stored, 1)
+		assert.Equal(t, map[string]snapshot{
+			routePhoneLiveStatus: {"live_status": "active"},
+			routeNegativeRecord:  {"case 411/Pdt.G/2025": "Penyerahan Memori Kasasi"},
+		}, stored[0].Snapshots)
+		assert.True(t, now.Equal(stored[0].LastCheckedAt))
+
+		assert.Empty(t, client.bodies["POST /api/core/monitoring/alerts"])
+		assert.Empty(t, sender.sent)
+	})
+
+	t.Run("changes are alerted", func(t *testing.T) {
+		runners := map[string]pipeline.Runner{
+			routePhoneLiveStatus: &fakeRunner{name: "phone live status", results: []any{map[string]any{"live_status": "not active"}}},
+			routeNegativeRecord:  &fakeRunner{name: "negative record", results: []any{newCase}},
+		}
+		svc, client, sender := setupService(t, scheduled(subject(5, &yesterday, map[string]snapshot{
+			routePhoneLiveStatus: {"live_status": "active"},
+			routeNegativeRecord:  {},
+		})), runners)
+
+		require.NoError(t, svc.RunScheduledChecks(now))
+
+		var alerts []alert
+		require.Len(t, client.bodies["POST /api/core/monitoring/alerts"], 1)
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/monitoring/alerts"][0], &alerts))
+		require.Len(t, alerts, 2)
+		assert.Equal(t, "phone live status", alerts[0].ProductName)
+		assert.Equal(t, "live_status", alerts[0].Field)
+		assert.Equal(t, "active", alerts[0].Previous)
+		assert.Equal(t, "not active", alerts[0].Current)
+		assert.Equal(t, routeNegativeRecord, alerts[1].Product)
+		assert.Equal(t, "case 411/Pdt.G/2025", alerts[1].Field)
+		assert.Empty(t, alerts[1].Previous)
+		assert.Equal(t, uint(5), alerts[1].SubjectId)
+		assert.Equal(t, uint(9), alerts[1].CompanyId)
+
+		require.Len(t, sender.sent, 1)
+		assert.Equal(t, []string{"budi@example.com"}, sender.sent[0].ToList)
+		assert.Contains(t, sender.sent[0].Subject, "2 change(s)")
+		assert.Contains(t, sender.sent[0].Body, "not active")
+		assert.Contains(t, sender.sent[0].Body, "411/Pdt.G/2025")
+		assert.NotContains(t, sender.sent[0].Body, "6281234567890")
+
+		assert.Len(t, updates(t, client, "PUT /api/core/monitoring/subjects/5"), 1)
+	})
+
+	t.Run("failed product keeps its snapshot", func(t *testing.T) {
+		runners := map[string]pipeline.Runner{
+			routePhoneLiveStatus: &fakeRunner{name: "phone live status", results: []any{errors.New("partner down")}},
+			routeNegativeRecord:  &fakeRunner{name: "negative record", results: []any{map[string]any{"result": []any{}}}},
+		}
+		svc, client, sender := setupService(t, scheduled(subject(5, &yesterday, map[string]snapshot{
+			routePhoneLiveStatus: {"live_status": "active"},
+			routeNegativeRecord:  {},
+		})), runners)
+
+		require.NoError(t, svc.RunScheduledChecks(now))
+
+		stored := updates(t, client, "PUT /api/core/monitoring/subjects/5")
+		require.Len(t, stored, 1)
+		assert.Equal(t, snapshot{"live_status": "active"}, stored[0].Snapshots[routePhoneLiveStatus])
+		assert.Empty(t, client.bodies["POST /api/core/monitoring/alerts"])
+		assert.Empty(t, sender.sent)
+	})
+
+	t.Run("only due subjects", func(t *testing.T) {
+		phone := &fakeRunner{name: "phone live status", results: []any{map[string]any{"live_status": "active"}}}
+		runners := map[string]pipeline.Runner{routePhoneLiveStatus: phone, routeNegativeRecord: &fakeRunner{name: "negative record", results: []any{newCase}}}
+		svc, client, _ := setupService(t, scheduled(subject(5, &lastHour, nil), subject(6, &yesterday, nil)), runners)
+
+		require.NoError(t, svc.RunScheduledChecks(now))
+
+		assert.Empty(t, client.bodies["PUT /api/core/monitoring/subjects/5"])
+		assert.Len(t, client.bodies["PUT /api/core/monitoring/subjects/6"], 1)
+		assert.Len(t, phone.bodies, 1)
+	})
+
+	t.Run("inactive owner", func(t *testing.T) {
+		phone := &fakeRunner{name: "phone live status", results: []any{map[string]any{"live_status": "active"}}}
+		routes := scheduled(subject(5, nil, nil))
+		routes["GET /api/core/member/by"] = ok(map[string]any{"member_id": 1, "company_id": 9, "active": false})
+		svc, client, _ := setupService(t, routes, map[string]pipeline.Runner{routePhoneLiveStatus: phone})
+
+		require.NoError(t, svc.RunScheduledChecks(now))
+
+		assert.Empty(t, phone.bodies)
+		assert.Empty(t, client.bodies["PUT /api/core/monitoring/subjects/5"])
+	})
+
+	t.Run("schedule unavailable", func(t *testing.T) {
+		svc, _, _ := setupService(t, nil, nil)
+
+		assert.Error(t, svc.RunScheduledChecks(now))
+	})
+}
+
+// TestRunScheduledChecksPhoneLiveStatus runs the registered phone live status
+// product against the simulator, its single request hides the partner data
+// but monitoring still has to see it.
+func TestRunScheduledChecksPhoneLiveStatus(t *testing.T) {
+	ts, err := simulator.NewTestServer(&simulator.Scenario{})
+	require.NoError(t, err)
+	t.Cleanup(ts.Close)
+
+	cfg := ts.Config()
+	client := httpclient.NewDefaultClient(5 * time.Second)
+	phonelivestatus.SetupInit(fiber.New(), cfg, client, quota.NewReserver(quota.NewRedisStore(nil), member.NewRepository(cfg, client, nil)), nil)
+
+	now := time.Date(2026, 10, 19, 8, 0, 5, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	svc, core, sender := setupService(t, map[string]func(*http.Request) (int, any){
+		"GET /api/core/monitoring/schedule": ok([]map[string]any{{
+			"id":              5,
+			"member_id":       1,
+			"company_id":      9,
+			"subject":         map[string]any{"phone_number": "628111111110", "loan_no": "L1"},
+			"products":        []string{routePhoneLiveStatus},
+			"interval_days":   1,
+			"snapshots":       map[string]snapshot{routePhoneLiveStatus: {"live_status": "active"}},
+			"last_checked_at": yesterday,
+		}}),
+		"PUT /api/core/monitoring/subjects/5": ok(nil),
+		"POST /api/core/monitoring/alerts":    ok(nil),
+	}, pipeline.Runners())
+
+	require.NoError(t, svc.RunScheduledChecks(now))
+
+	var alerts []alert
+	require.Len(t, core.bodies["POST /api/core/monitoring/alerts"], 1)
+	require.NoError(t, json.Unmarshal(core.bodies["POST /api/core/monitoring/alerts"][0], &alerts))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "live_status", alerts[0].Field)
+	assert.Equal(t, "active", alerts[0].Previous)
+	assert.Equal(t, "not active", alerts[0].Current)
+	assert.Len(t, sender.sent, 1)
+}
@@ -0,0 +1,108 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// watcher knows which subject fields a monitored product needs and which
+// fields of its result are compared between checks.
+type watcher struct {
+	requires []string
+	watch    func(data map[string]any) snapshot
+}
+
+var watchers = map[string]watcher{
+	routePhoneLiveStatus: {
+		requires: []string{"phone_number"},
+		watch:    watchFields("live_status"),
+	},
+	routeLoanRecordChecker: {
+		requires: []string{"name", "nik", "phone_number"},
+		watch:    watchFields("status"),
+	},
+	routeNegativeRecord: {
+		requires: []string{"company_name"},
+		watch:    watchCases,
+	},
+}
+
+func watchFields(fields ...string) func(map[string]any) snapshot {
+	return func(data map[string]any) snapshot {
+		watched := snapshot{}
+		for _, field := range fields {
+			if value, ok := data[field]; ok && value != nil {
+				watched[field] = fmt.Sprint(value)
+			}
+		}
+
+		return watched
+	}
+}
+
+// watchCases keeps every court case by its number, so a new case and a
+// case whose status moved both show up as a change.
+func watchCases(data map[string]any) snapshot {
+	watched := snapshot{}
+
+	cases, _ := data["result"].([]any)
+	for _, c := range cases {
+		courtCase, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		number, _ := courtCase["case_number"].(string)
+		if number == "" {
+			continue
+		}
+
+		status, _ := courtCase["status"].(string)
+		watched["case "+number] = status
+	}
+
+	return watched
+}
+
+// dataMap turns the typed product data into its json fields.
+func dataMap(data any) map[string]any {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil
+	}
+
+	return values
+}
+
+// diff lists the fields that differ between two snapshots sorted by field.
+func diff(previous, current snapshot) []*alert {
+	fields := make([]string, 0, len(previous)+len(current))
+	for field := range previous {
+		fields = append(fields, field)
+	}
+	for field := range current {
+		if _, ok := previous[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []*alert
+	for _, field := range fields {
+		before, hadBefore := previous[field]
+		after, hasAfter := current[field]
+		if hadBefore == hasAfter && before == after {
+			continue
+		}
+
+		changes = append(changes, &alert{Field: field, Previous: before, Current: after})
+	}
+
+	return changes
+}
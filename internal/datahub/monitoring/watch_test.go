@@ -0,0 +1,39 @@
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	changes := diff(
+		snapshot{"live_status": "active", "case 1": "Banding", "case 2": "Putus"},
+		snapshot{"live_status": "active", "case 1": "Kasasi", "case 3": "Pendaftaran"},
+	)
+
+	assert.Equal(t, []*alert{
+		{Field: "case 1", Previous: "Banding", Current: "Kasasi"},
+		{Field: "case 2", Previous: "Putus"},
+		{Field: "case 3", Current: "Pendaftaran"},
+	}, changes)
+
+	assert.Empty(t, diff(snapshot{"status": "found"}, snapshot{"status": "found"}))
+}
+
+func TestWatchers(t *testing.T) {
+	phone := watchers[routePhoneLiveStatus].watch(dataMap(struct {
+		LiveStatus string `json:"live_status"`
+		Operator   string `json:"operator"`
+	}{"not active", "telkomsel"}))
+	assert.Equal(t, snapshot{"live_status": "not active"}, phone)
+
+	cases := watchers[routeNegativeRecord].watch(map[string]any{"result": []any{
+		map[string]any{"case_number": "411/Pdt.G/2025", "status": "Kasasi"},
+		map[string]any{"status": "without number"},
+		"invalid",
+	}})
+	assert.Equal(t, snapshot{"case 411/Pdt.G/2025": "Kasasi"}, cases)
+
+	assert.Empty(t, watchers[routeLoanRecordChecker].watch(nil))
+}
@@ -1,4 +1,4 @@
-package taxcompliancestatus
+package pipeline
 
 import (
 	"front-office/pkg/apperror"
@@ -9,15 +9,16 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewController(
-	svc Service,
-
+func NewController[Req, Resp any](
+	product *Product[Req, Resp],
+	svc Service[Req, Resp],
 ) Controller {
-	return &controller{svc}
+	return &controller[Req, Resp]{product, svc}
 }
 
-type controller struct {
-	svc Service
+type controller[Req, Resp any] struct {
+	product *Product[Req, Resp]
+	svc     Service[Req, Resp]
 }
 
 type Controller interface {
@@ -25,8 +26,8 @@ type Controller interface {
 	BulkSearch(c *fiber.Ctx) error
 }
 
-func (ctrl *controller) SingleSearch(c *fiber.Ctx) error {
-	reqBody, ok := c.Locals(constant.Request).(*taxComplianceStatusRequest)
+func (ctrl *controller[Req, Resp]) SingleSearch(c *fiber.Ctx) error {
+	reqBody, ok := c.Locals(constant.Request).(*Req)
 	if !ok {
 		return apperror.BadRequest(constant.InvalidRequestFormat)
 	}
@@ -36,15 +37,22 @@ func (ctrl *controller) SingleSearch(c *fiber.Ctx) error {
 		return apperror.Unauthorized(err.Error())
 	}
 
-	result, err := ctrl.svc.TaxComplianceStatus(authCtx, reqBody)
+	result, err := ctrl.svc.SingleRequest(authCtx, reqBody)
 	if err != nil {
 		return err
 	}
 
+	if ctrl.product.HideResult {
+		return c.Status(fiber.StatusOK).JSON(helper.SuccessResponse[any](
+			constant.Success,
+			result.Decision,
+		))
+	}
+
 	return c.Status(result.StatusCode).JSON(result)
 }
 
-func (ctrl *controller) BulkSearch(c *fiber.Ctx) error {
+func (ctrl *controller[Req, Resp]) BulkSearch(c *fiber.Ctx) error {
 	file, ok := c.Locals(constant.ValidatedFile).(*multipart.FileHeader)
 	if !ok {
 		return apperror.BadRequest(constant.InvalidRequestFormat)
@@ -55,7 +63,7 @@ func (ctrl *controller) BulkSearch(c *fiber.Ctx) error {
 		return apperror.Unauthorized(err.Error())
 	}
 
-	if err := ctrl.svc.BulkTaxComplianceStatus(authCtx, file); err != nil {
+	if err := ctrl.svc.BulkRequest(authCtx, file); err != nil {
 		return err
 	}
 
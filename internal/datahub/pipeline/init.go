@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/job"
+	"front-office/internal/middleware"
+	"front-office/pkg/httpclient"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Register serves the product under apiGroup.Group(product.Route), the job
+// endpoints of the group are shared by every product and set up by the
+// job package. The product group is returned for products with extra routes.
+// The product is also added to Runners for the applicant check.
+func Register[Req, Resp any](apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator, product *Product[Req, Resp]) fiber.Router {
+	repo := NewRepository(cfg, client, nil, product)
+	memberRepo := member.NewRepository(cfg, client, nil)
+	jobRepo := job.NewRepository(cfg, client, nil)
+	transactionRepo := transaction.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+
+	jobService := job.NewService(jobRepo, transactionRepo, operationRepo, nil)
+	service := NewService(product, repo, memberRepo, jobRepo, transactionRepo, operationRepo, jobService, quotaReserver, evaluator)
+
+	if host := cfg.App.PartnerHost(product.Route); host != cfg.App.ProductCatalogHost {
+		log.Warn().
+			Str("product", product.Route).
+			Str("host", host).
+			Msg("partner requests go to the stub server")
+	}
+
+	controller := NewController(product, service)
+	addRunner(product.Route, NewRunner(product, service))
+
+	var request Req
+	productGroup := apiGroup.Group(product.Route)
+	productGroup.Post("/single-request", middleware.ValidateRequest(request), middleware.GetJWTPayloadFromCookie(cfg), controller.SingleSearch)
+	productGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkSearch)
+
+	return productGroup
+}
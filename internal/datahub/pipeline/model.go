@@ -0,0 +1,14 @@
+package pipeline
+
+type requestContext[Req any] struct {
+	APIKey         string `json:"api_key"`
+	JobIdStr       string `json:"job_id_str"`
+	MemberIdStr    string `json:"member_id_str"`
+	CompanyIdStr   string `json:"company_id_str"`
+	MemberId       uint   `json:"member_id"`
+	CompanyId      uint   `json:"company_id"`
+	ProductId      uint   `json:"product_id"`
+	ProductGroupId uint   `json:"product_group_id"`
+	JobId          uint   `json:"job_id"`
+	Request        *Req   `json:"request"`
+}
@@ -0,0 +1,58 @@
+package pipelinetest
+
+import (
+	"bytes"
+	"front-office/internal/core/log/transaction"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// CSVFile returns content as the uploaded file of a bulk request.
+func CSVFile(t *testing.T, content string) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "upload.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+
+	return form.File["file"][0]
+}
+
+// Answered returns the field of the request body of every successful
+// transaction, in the order they were logged.
+func Answered(logs []transaction.LogTransProCatRequest, field string) []string {
+	var values []string
+	for _, log := range logs {
+		if !log.Success {
+			continue
+		}
+
+		if body, ok := log.RequestBody.(map[string]any); ok {
+			value, _ := body[field].(string)
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Failures returns the failed transactions.
+func Failures(logs []transaction.LogTransProCatRequest) []transaction.LogTransProCatRequest {
+	var failed []transaction.LogTransProCatRequest
+	for _, log := range logs {
+		if !log.Success {
+			failed = append(failed, log)
+		}
+	}
+
+	return failed
+}
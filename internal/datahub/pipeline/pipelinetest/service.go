@@ -0,0 +1,39 @@
+// Package pipelinetest wires datahub products for tests, so a product can be
+// run end to end against the simulator.
+package pipelinetest
+
+import (
+	"front-office/configs/application"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/job"
+	"front-office/internal/datahub/pipeline"
+	"front-office/pkg/httpclient"
+	"time"
+)
+
+// NewService wires the product the way pipeline.Register does, without
+// decision rules or a quota store.
+func NewService[Req, Resp any](cfg *application.Config, product *pipeline.Product[Req, Resp]) pipeline.Service[Req, Resp] {
+	client := httpclient.NewDefaultClient(5 * time.Second)
+
+	memberRepo := member.NewRepository(cfg, client, nil)
+	jobRepo := job.NewRepository(cfg, client, nil)
+	transactionRepo := transaction.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	jobService := job.NewService(jobRepo, transactionRepo, operationRepo, nil)
+
+	return pipeline.NewService(
+		product,
+		pipeline.NewRepository(cfg, client, nil, product),
+		memberRepo,
+		jobRepo,
+		transactionRepo,
+		operationRepo,
+		jobService,
+		quota.NewReserver(nil, memberRepo),
+		nil,
+	)
+}
@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/model"
+)
+
+// Product declares a datahub product, Register turns it into the single and
+// bulk endpoints. Req is the body of a single request and a row of the bulk
+// CSV, Resp is the data returned by the partner.
+type Product[Req, Resp any] struct {
+	// Slug is the product slug used for the subscription lookup
+	Slug string
+	// Route is the group the endpoints are registered under, e.g. "tax-score"
+	Route string
+	// Name is used in error and log messages, e.g. "tax score"
+	Name      string
+	TrxPrefix string
+	// Path is the partner endpoint on the product catalog host, e.g.
+	// "/product/incometax/tax-score"
+	Path string
+	// ForwardMember sends the member and company id headers to the partner
+	ForwardMember bool
+
+	CSVHeaders []string
+	FromCSV    func(record []string) *Req
+	LoanNo     func(req *Req) string
+
+	SingleEvent string
+	BulkEvent   string
+
+	// MapExternalError maps the partner error of a single request, other
+	// errors are mapped with apperror.MapRepoError
+	MapExternalError func(err *apperror.ExternalAPIError) error
+	// Stub answers in place of the partner for products that have none yet,
+	// the stub response is logged as a free transaction
+	Stub func(trxId string, req *Req) *model.ProCatAPIResponse[Resp]
+	// HideResult answers single requests without the partner data
+	HideResult bool
+	// Refine adjusts the partner data of a single request for the company
+	// before it is answered and decided, e.g. drops the hits below the
+	// company threshold
+	Refine func(companyId uint, data *Resp)
+}
+
+func (p *Product[Req, Resp]) loanNo(req *Req) string {
+	if p.LoanNo == nil {
+		return ""
+	}
+
+	return p.LoanNo(req)
+}
+
+func (p *Product[Req, Resp]) failedMessage() string {
+	return "failed to process " + p.Name
+}
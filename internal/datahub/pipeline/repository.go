@@ -1,10 +1,9 @@
-package taxverificationdetail
+package pipeline
 
 import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"front-office/configs/application"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
@@ -14,30 +13,32 @@ import (
 	"net/http"
 )
 
-func NewRepository(cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller) Repository {
+func NewRepository[Req, Resp any](cfg *application.Config, client httpclient.HTTPClient, marshalFn jsonutil.Marshaller, product *Product[Req, Resp]) Repository[Req, Resp] {
 	if marshalFn == nil {
 		marshalFn = json.Marshal
 	}
 
-	return &repository{
+	return &repository[Req, Resp]{
 		cfg:       cfg,
 		client:    client,
 		marshalFn: marshalFn,
+		product:   product,
 	}
 }
 
-type repository struct {
+type repository[Req, Resp any] struct {
 	cfg       *application.Config
 	client    httpclient.HTTPClient
 	marshalFn jsonutil.Marshaller
+	product   *Product[Req, Resp]
 }
 
-type Repository interface {
-	TaxVerificationAPI(apiKey, jobId string, reqBody *taxVerificationRequest) (*model.ProCatAPIResponse[taxVerificationRespData], error)
+type Repository[Req, Resp any] interface {
+	CallAPI(apiKey, jobId, memberId, companyId string, reqBody *Req) (*model.ProCatAPIResponse[Resp], error)
 }
 
-func (repo *repository) TaxVerificationAPI(apiKey, jobId string, reqBody *taxVerificationRequest) (*model.ProCatAPIResponse[taxVerificationRespData], error) {
-	url := fmt.Sprintf("%s/product/incometax/tax-verification-detail", repo.cfg.App.ProductCatalogHost)
+func (repo *repository[Req, Resp]) CallAPI(apiKey, jobId, memberId, companyId string, reqBody *Req) (*model.ProCatAPIResponse[Resp], error) {
+	url := repo.cfg.App.PartnerHost(repo.product.Route) + repo.product.Path
 
 	bodyBytes, err := repo.marshalFn(reqBody)
 	if err != nil {
@@ -51,6 +52,10 @@ func (repo *repository) TaxVerificationAPI(apiKey, jobId string, reqBody *taxVer
 
 	req.Header.Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
 	req.Header.Set(constant.XAPIKey, apiKey)
+	if repo.product.ForwardMember {
+		req.Header.Set(constant.XMemberId, memberId)
+		req.Header.Set(constant.XCompanyId, companyId)
+	}
 
 	q := req.URL.Query()
 	q.Add("job_id", jobId)
@@ -62,5 +67,5 @@ func (repo *repository) TaxVerificationAPI(apiKey, jobId string, reqBody *taxVer
 	}
 	defer resp.Body.Close()
 
-	return helper.ParseProCatAPIResponse[taxVerificationRespData](resp)
+	return helper.ParseProCatAPIResponse[Resp](resp)
 }
@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"front-office/internal/core/quota"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"sync"
+)
+
+// Runner runs a single request of a registered product from a JSON body, so
+// several products can be run together without knowing their request types.
+type Runner interface {
+	Name() string
+	// Reserve holds amount units of the product quota for a parent that runs
+	// the product several times.
+	Reserve(authCtx *model.AuthContext, amount int) (*quota.Reservation, error)
+	// Run runs the product on its own when parent is nil.
+	Run(authCtx *model.AuthContext, parent *Parent, body []byte) (*RunResult, error)
+}
+
+// Parent is the request a product is run for, e.g. an applicant check. It
+// reserves the quota of the product up front, the run consumes one unit of
+// it instead of reserving its own.
+type Parent struct {
+	ApplicantCheckId uint
+	TaxReportId      uint
+	Reservation      *quota.Reservation
+}
+
+// Reservations are the reservations of a parent keyed by route.
+type Reservations map[string]*quota.Reservation
+
+// Reserve holds amount units of every route, nothing is held when the quota
+// of one of them does not cover it.
+func Reserve(authCtx *model.AuthContext, runners map[string]Runner, routes []string, amount int) (Reservations, error) {
+	reservations := make(Reservations, len(routes))
+	for _, route := range routes {
+		reservation, err := runners[route].Reserve(authCtx, amount)
+		if err != nil {
+			reservations.Release()
+			return nil, err
+		}
+
+		reservations[route] = reservation
+	}
+
+	return reservations, nil
+}
+
+// Release gives back the units the runs have not consumed.
+func (r Reservations) Release() {
+	for _, reservation := range r {
+		reservation.Release()
+	}
+}
+
+// RunResult always carries the partner data, so it can be watched and
+// evaluated. Hidden marks a product answering without it, the caller leaves
+// the data out of its own response.
+type RunResult struct {
+	StatusCode      int    `json:"status_code"`
+	Message         string `json:"message"`
+	TransactionId   string `json:"transaction_id"`
+	PricingStrategy string `json:"pricing_strategy"`
+	Data            any    `json:"data,omitempty"`
+	Hidden          bool   `json:"-"`
+}
+
+var (
+	runnersMu sync.RWMutex
+	runners   = map[string]Runner{}
+)
+
+// Runners returns the products registered so far keyed by their route.
+func Runners() map[string]Runner {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+
+	registered := make(map[string]Runner, len(runners))
+	for route, runner := range runners {
+		registered[route] = runner
+	}
+
+	return registered
+}
+
+func addRunner(route string, runner Runner) {
+	runnersMu.Lock()
+	defer runnersMu.Unlock()
+
+	runners[route] = runner
+}
+
+func NewRunner[Req, Resp any](product *Product[Req, Resp], svc Service[Req, Resp]) Runner {
+	return &runner[Req, Resp]{product, svc}
+}
+
+type runner[Req, Resp any] struct {
+	product *Product[Req, Resp]
+	svc     Service[Req, Resp]
+}
+
+func (r *runner[Req, Resp]) Name() string {
+	return r.product.Name
+}
+
+// Reserve holds the units on the member quota of the product through the
+// product service.
+func (r *runner[Req, Resp]) Reserve(authCtx *model.AuthContext, amount int) (*quota.Reservation, error) {
+	return r.svc.Reserve(authCtx, amount)
+}
+
+// Run decodes body into the product request, fields the product does not
+// know are ignored, and validates it the way the single request route does.
+func (r *runner[Req, Resp]) Run(authCtx *model.AuthContext, parent *Parent, body []byte) (*RunResult, error) {
+	req := new(Req)
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, apperror.BadRequest(constant.InvalidRequestFormat)
+	}
+
+	if err := validateRequest(req); err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+
+	result, err := r.svc.ChildRequest(authCtx, parent, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RunResult{
+		StatusCode:      result.StatusCode,
+		Message:         result.Message,
+		TransactionId:   result.TransactionId,
+		PricingStrategy: result.PricingStrategy,
+		Data:            result.Data,
+		Hidden:          r.product.HideResult,
+	}, nil
+}
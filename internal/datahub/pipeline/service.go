@@ -0,0 +1,431 @@
+package pipeline
+
+import (
+	"errors"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/job"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"front-office/pkg/identifier"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/usepzaka/validator"
+)
+
+func NewService[Req, Resp any](
+	product *Product[Req, Resp],
+	repo Repository[Req, Resp],
+	memberRepo member.Repository,
+	jobRepo job.Repository,
+	transactionRepo transaction.Repository,
+	operationRepo operation.Repository,
+	jobService job.Service,
+	quotaReserver quota.Reserver,
+	evaluator decision.Evaluator,
+) Service[Req, Resp] {
+	return &service[Req, Resp]{
+		product,
+		repo,
+		memberRepo,
+		jobRepo,
+		transactionRepo,
+		operationRepo,
+		jobService,
+		quotaReserver,
+		evaluator,
+	}
+}
+
+type service[Req, Resp any] struct {
+	product         *Product[Req, Resp]
+	repo            Repository[Req, Resp]
+	memberRepo      member.Repository
+	jobRepo         job.Repository
+	transactionRepo transaction.Repository
+	operationRepo   operation.Repository
+	jobService      job.Service
+	quotaReserver   quota.Reserver
+	evaluator       decision.Evaluator
+}
+
+type Service[Req, Resp any] interface {
+	SingleRequest(authCtx *model.AuthContext, reqBody *Req) (*model.ProCatAPIResponse[Resp], error)
+	ChildRequest(authCtx *model.AuthContext, parent *Parent, reqBody *Req) (*model.ProCatAPIResponse[Resp], error)
+	Reserve(authCtx *model.AuthContext, amount int) (*quota.Reservation, error)
+	BulkRequest(authCtx *model.AuthContext, file *multipart.FileHeader) error
+}
+
+func (svc *service[Req, Resp]) SingleRequest(authCtx *model.AuthContext, reqBody *Req) (*model.ProCatAPIResponse[Resp], error) {
+	result, jobIdStr, err := svc.singleRequest(authCtx, nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Decision = svc.decide(authCtx, jobIdStr, result.TransactionId, result.Data)
+
+	svc.addLogOperation(authCtx, svc.product.SingleEvent)
+
+	return result, nil
+}
+
+// ChildRequest runs a single request for a parent, e.g. an applicant check.
+// The operation is logged once by the parent.
+func (svc *service[Req, Resp]) ChildRequest(authCtx *model.AuthContext, parent *Parent, reqBody *Req) (*model.ProCatAPIResponse[Resp], error) {
+	result, _, err := svc.singleRequest(authCtx, parent, reqBody)
+
+	return result, err
+}
+
+func (svc *service[Req, Resp]) Reserve(authCtx *model.AuthContext, amount int) (*quota.Reservation, error) {
+	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), svc.product.Slug)
+	if err != nil {
+		return nil, apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
+	}
+
+	return svc.quotaReserver.Reserve(authCtx, strconv.Itoa(int(subscribedResp.Data.SubsribedProductID)), amount)
+}
+
+// singleRequest returns the result with the id of the job it ran under.
+func (svc *service[Req, Resp]) singleRequest(authCtx *model.AuthContext, parent *Parent, reqBody *Req) (*model.ProCatAPIResponse[Resp], string, error) {
+	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), svc.product.Slug)
+	if err != nil {
+		return nil, "", apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
+	}
+
+	createReq := &job.CreateJobRequest{
+		ProductId:   subscribedResp.Data.ProductId,
+		MemberId:    authCtx.UserIdStr(),
+		CompanyId:   authCtx.CompanyIdStr(),
+		Total:       1,
+		APIClientId: authCtx.APIClientId,
+	}
+
+	if parent != nil {
+		createReq.ApplicantCheckId = parent.ApplicantCheckId
+		createReq.TaxReportId = parent.TaxReportId
+		defer parent.Reservation.Consume(1)
+	} else {
+		reservation, err := svc.quotaReserver.Reserve(authCtx, strconv.Itoa(int(subscribedResp.Data.SubsribedProductID)), 1)
+		if err != nil {
+			return nil, "", err
+		}
+		defer reservation.Release()
+	}
+
+	jobRes, err := svc.jobRepo.CreateJobAPI(createReq)
+	if err != nil {
+		return nil, "", apperror.MapRepoError(err, constant.FailedCreateJob)
+	}
+
+	params := newRequestContext(authCtx, subscribedResp.Data.ProductId, subscribedResp.Data.Product.ProductGroupId, jobRes.JobId, reqBody)
+
+	var result *model.ProCatAPIResponse[Resp]
+	if svc.product.Stub != nil {
+		result, err = svc.callStub(params, helper.GenerateTrx(svc.product.TrxPrefix))
+	} else {
+		result, err = svc.callPartner(params)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if svc.product.Refine != nil {
+		svc.product.Refine(authCtx.CompanyId, &result.Data)
+	}
+
+	if err := svc.jobService.FinalizeJob(params.JobIdStr); err != nil {
+		return nil, "", err
+	}
+
+	return result, params.JobIdStr, nil
+}
+
+func (svc *service[Req, Resp]) BulkRequest(authCtx *model.AuthContext, file *multipart.FileHeader) error {
+	records, err := helper.ParseCSVFile(file, svc.product.CSVHeaders)
+	if err != nil {
+		return apperror.BadRequest(err.Error())
+	}
+
+	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(authCtx.CompanyIdStr(), svc.product.Slug)
+	if err != nil {
+		return apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
+	}
+
+	subscribedIdStr := strconv.Itoa(int(subscribedResp.Data.SubsribedProductID))
+
+	totalRequests := len(records) - 1
+	reservation, err := svc.quotaReserver.Reserve(authCtx, subscribedIdStr, totalRequests)
+	if err != nil {
+		return err
+	}
+	defer reservation.Release()
+
+	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
+		ProductId:   subscribedResp.Data.ProductId,
+		MemberId:    authCtx.UserIdStr(),
+		CompanyId:   authCtx.CompanyIdStr(),
+		Total:       totalRequests,
+		APIClientId: authCtx.APIClientId,
+	})
+	if err != nil {
+		return apperror.MapRepoError(err, constant.FailedCreateJob)
+	}
+	jobIdStr := helper.ConvertUintToString(jobRes.JobId)
+
+	productId := subscribedResp.Data.ProductId
+	productGroupId := subscribedResp.Data.Product.ProductGroupId
+
+	var requests []*Req
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+
+		requests = append(requests, svc.product.FromCSV(record))
+	}
+
+	var (
+		wg         sync.WaitGroup
+		errChan    = make(chan error, len(requests))
+		batchCount = 0
+	)
+
+	for _, req := range requests {
+		wg.Add(1)
+
+		go func(req *Req) {
+			defer wg.Done()
+			defer reservation.Consume(1)
+
+			if err := svc.processRow(newRequestContext(authCtx, productId, productGroupId, jobRes.JobId, req)); err != nil {
+				errChan <- err
+			}
+		}(req)
+
+		batchCount++
+		if batchCount == 100 {
+			time.Sleep(time.Second)
+			batchCount = 0
+		}
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		log.Error().Err(err).Str("job_id", jobIdStr).Msgf("error during bulk %s processing", svc.product.Name)
+	}
+
+	if err := svc.jobService.FinalizeJob(jobIdStr); err != nil {
+		return err
+	}
+
+	svc.addLogOperation(authCtx, svc.product.BulkEvent)
+
+	return nil
+}
+
+func (svc *service[Req, Resp]) processRow(params *requestContext[Req]) error {
+	trxId := helper.GenerateTrx(svc.product.TrxPrefix)
+	if err := validateRequest(params.Request); err != nil {
+		_ = svc.logFailedTransaction(params, trxId, err.Error(), http.StatusBadRequest)
+
+		return apperror.BadRequest(err.Error())
+	}
+
+	if svc.product.Stub != nil {
+		_, err := svc.callStub(params, trxId)
+
+		return err
+	}
+
+	result, err := svc.repo.CallAPI(params.APIKey, params.JobIdStr, params.MemberIdStr, params.CompanyIdStr, params.Request)
+	if err != nil {
+		// the row is logged with the partner status, 502 when it did not answer
+		statusCode := http.StatusBadGateway
+		var apiErr *apperror.ExternalAPIError
+		if result != nil {
+			statusCode = result.StatusCode
+		} else if errors.As(err, &apiErr) {
+			statusCode = apiErr.StatusCode
+		}
+
+		_ = svc.logFailedTransaction(params, trxId, err.Error(), statusCode)
+		_ = svc.jobService.FinalizeFailedJob(params.JobIdStr)
+
+		return apperror.Internal(svc.product.failedMessage(), err)
+	}
+
+	return nil
+}
+
+func (svc *service[Req, Resp]) callPartner(params *requestContext[Req]) (*model.ProCatAPIResponse[Resp], error) {
+	result, err := svc.repo.CallAPI(params.APIKey, params.JobIdStr, params.MemberIdStr, params.CompanyIdStr, params.Request)
+	if err != nil {
+		if err := svc.jobService.FinalizeFailedJob(params.JobIdStr); err != nil {
+			return nil, err
+		}
+
+		var apiErr *apperror.ExternalAPIError
+		if svc.product.MapExternalError != nil && errors.As(err, &apiErr) {
+			return nil, svc.product.MapExternalError(apiErr)
+		}
+
+		return nil, apperror.MapRepoError(err, svc.product.failedMessage())
+	}
+
+	return result, nil
+}
+
+// callStub answers from the product stub, the partner would log the
+// transaction itself so the stub response is logged here.
+func (svc *service[Req, Resp]) callStub(params *requestContext[Req], trxId string) (*model.ProCatAPIResponse[Resp], error) {
+	result := svc.product.Stub(trxId, params.Request)
+
+	if err := svc.transactionRepo.CreateLogTransAPI(&transaction.LogTransProCatRequest{
+		TransactionID:   trxId,
+		MemberID:        params.MemberId,
+		CompanyID:       params.CompanyId,
+		ProductID:       params.ProductId,
+		ProductGroupID:  params.ProductGroupId,
+		JobID:           params.JobId,
+		Message:         constant.Success,
+		Status:          http.StatusOK,
+		Success:         true,
+		PricingStrategy: result.PricingStrategy,
+		LoanNo:          svc.product.loanNo(params.Request),
+		ResponseBody: &transaction.ResponseBody{
+			Data:            result.Data,
+			Input:           params.Request,
+			TransactionId:   trxId,
+			PricingStrategy: result.PricingStrategy,
+			DateTime:        time.Now().Format(constant.FormatDateAndTime),
+		},
+		Data:         result.Data,
+		RequestBody:  params.Request,
+		RequestTime:  time.Now(),
+		ResponseTime: time.Now(),
+	}); err != nil {
+		return nil, apperror.MapRepoError(err, constant.FailedCreateJob)
+	}
+
+	return result, nil
+}
+
+func (svc *service[Req, Resp]) logFailedTransaction(params *requestContext[Req], trxId, msg string, status int) error {
+	return svc.transactionRepo.CreateLogTransAPI(&transaction.LogTransProCatRequest{
+		TransactionID:  trxId,
+		MemberID:       params.MemberId,
+		CompanyID:      params.CompanyId,
+		ProductID:      params.ProductId,
+		ProductGroupID: params.ProductGroupId,
+		JobID:          params.JobId,
+		Message:        msg,
+		Status:         status,
+		Success:        false,
+		LoanNo:         svc.product.loanNo(params.Request),
+		ResponseBody: &transaction.ResponseBody{
+			Input:    params.Request,
+			DateTime: time.Now().Format(constant.FormatDateAndTime),
+		},
+		RequestBody:  params.Request,
+		RequestTime:  time.Now(),
+		ResponseTime: time.Now(),
+	})
+}
+
+// decide evaluates the company decision rules over the result and stores the
+// decision on the job and its transaction. A failing evaluation leaves the
+// request undecided rather than failing a request that has already been
+// charged.
+func (svc *service[Req, Resp]) decide(authCtx *model.AuthContext, jobIdStr, trxId string, data Resp) *model.Decision {
+	if svc.evaluator == nil {
+		return nil
+	}
+
+	result, err := svc.evaluator.Evaluate(authCtx.CompanyId, decision.Facts{svc.product.Route: data})
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", jobIdStr).
+			Msg("failed to evaluate decision rules")
+
+		return nil
+	}
+	if result == nil {
+		return nil
+	}
+
+	stored := map[string]interface{}{
+		"decision":    result.Decision,
+		"fired_rules": result.FiredRules,
+	}
+	if err := svc.jobRepo.UpdateJobAPI(jobIdStr, stored); err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", jobIdStr).
+			Msg("failed to store decision")
+	}
+
+	// the exports and the subject history read it from the transaction
+	if trxId != "" {
+		if err := svc.transactionRepo.UpdateLogTransAPI(trxId, stored); err != nil {
+			log.Warn().
+				Err(err).
+				Str("transaction_id", trxId).
+				Msg("failed to store decision on transaction")
+		}
+	}
+
+	return result
+}
+
+func (svc *service[Req, Resp]) addLogOperation(authCtx *model.AuthContext, event string) {
+	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
+		MemberId:    authCtx.UserId,
+		CompanyId:   authCtx.CompanyId,
+		Action:      event,
+		APIClientId: authCtx.APIClientId,
+	}); err != nil {
+		log.Warn().
+			Err(err).
+			Str("action", event).
+			Msg(constant.MsgFailedAddOperationLog)
+	}
+}
+
+// validateRequest normalizes the identifiers of a request before validating
+// it, so a bulk row is checked like a single request.
+func validateRequest(req any) error {
+	if err := identifier.NormalizeStruct(req); err != nil {
+		return err
+	}
+
+	return validator.ValidateStruct(req)
+}
+
+func newRequestContext[Req any](authCtx *model.AuthContext, productId, productGroupId, jobId uint, req *Req) *requestContext[Req] {
+	return &requestContext[Req]{
+		APIKey:         authCtx.APIKey,
+		JobIdStr:       helper.ConvertUintToString(jobId),
+		MemberIdStr:    authCtx.UserIdStr(),
+		CompanyIdStr:   authCtx.CompanyIdStr(),
+		MemberId:       authCtx.UserId,
+		CompanyId:      authCtx.CompanyId,
+		ProductId:      productId,
+		ProductGroupId: productGroupId,
+		JobId:          jobId,
+		Request:        req,
+	}
+}
@@ -0,0 +1,478 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/core/member"
+	"front-office/internal/core/quota"
+	"front-office/internal/datahub/job"
+	"front-office/internal/datahub/pipeline"
+	"front-office/internal/datahub/pipeline/pipelinetest"
+	"front-office/pkg/apperror"
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/identifier"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type checkRequest struct {
+	Nik    string `json:"nik" validate:"required~NIK cannot be empty., numeric~ID Card No is only number"`
+	Phone  string `json:"phone_number" normalize:"phone"`
+	LoanNo string `json:"loan_no" validate:"required~Loan No cannot be empty."`
+}
+
+type checkResponse struct {
+	Status string `json:"status"`
+}
+
+func testProduct() *pipeline.Product[checkRequest, checkResponse] {
+	return &pipeline.Product[checkRequest, checkResponse]{
+		Slug:          "TEST_check",
+		Route:         "check",
+		Name:          "check",
+		TrxPrefix:     "CHK",
+		Path:          "/product/test/check",
+		ForwardMember: true,
+		CSVHeaders:    []string{"NIK", "Loan No"},
+		FromCSV: func(record []string) *checkRequest {
+			return &checkRequest{Nik: record[0], LoanNo: record[1]}
+		},
+		LoanNo: func(req *checkRequest) string {
+			return req.LoanNo
+		},
+		SingleEvent: "single check",
+		BulkEvent:   "bulk check",
+	}
+}
+
+// upstreamStub answers the core and product catalog calls by "METHOD path".
+type upstreamStub struct {
+	mu       sync.Mutex
+	routes   map[string]func(*http.Request) (int, any)
+	requests []*http.Request
+	bodies   map[string][][]byte
+	// unreachable is the route answering with a connection error
+	unreachable string
+}
+
+func (s *upstreamStub) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.bodies[key] = append(s.bodies[key], reqBody)
+	s.mu.Unlock()
+
+	if key == s.unreachable {
+		return nil, errors.New("connection refused")
+	}
+
+	status, data := http.StatusNotFound, any(nil)
+	if handler, ok := s.routes[key]; ok {
+		status, data = handler(req)
+	}
+
+	// the transaction id is read from the partner answers only
+	body, err := json.Marshal(map[string]any{"success": status < 400, "data": data, "message": http.StatusText(status), "transaction_id": "CHK-1"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (s *upstreamStub) logs(t *testing.T) []transaction.LogTransProCatRequest {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var logs []transaction.LogTransProCatRequest
+	for _, body := range s.bodies["POST /api/core/logging/transaction/product-catalog"] {
+		var entry transaction.LogTransProCatRequest
+		require.NoError(t, json.Unmarshal(body, &entry))
+		logs = append(logs, entry)
+	}
+
+	return logs
+}
+
+func (s *upstreamStub) jobUpdates(t *testing.T) []map[string]any {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var updates []map[string]any
+	for _, body := range s.bodies["PUT /api/core/product/jobs/7"] {
+		var update map[string]any
+		require.NoError(t, json.Unmarshal(body, &update))
+		updates = append(updates, update)
+	}
+
+	return updates
+}
+
+func coreRoutes(partner func(*http.Request) (int, any)) map[string]func(*http.Request) (int, any) {
+	ok := func(data any) func(*http.Request) (int, any) {
+		return func(*http.Request) (int, any) { return http.StatusOK, data }
+	}
+
+	return map[string]func(*http.Request) (int, any){
+		"GET /api/core/member/subscribed-product/TEST_check": ok(map[string]any{
+			"subscribed_product_id": 3,
+			"product_id":            5,
+			"product":               map[string]any{"product_group_id": 2},
+		}),
+		"POST /api/core/product/jobs":                                         ok(map[string]any{"job_id": 7}),
+		"GET /api/core/logging/transaction/product-catalog/7/processed_count": ok(map[string]any{"processed_count": 1}),
+		"PUT /api/core/product/jobs/7":                                        ok(nil),
+		"POST /api/core/logging/transaction/product-catalog":                  ok(nil),
+		"POST /api/core/logging/operation":                                    ok(nil),
+		"PUT /api/core/logging/transaction/product-catalog/CHK-1":             ok(nil),
+		"POST /product/test/check":                                            partner,
+	}
+}
+
+type stubEvaluator struct {
+	result *model.Decision
+	facts  decision.Facts
+}
+
+func (e *stubEvaluator) Evaluate(companyId uint, facts decision.Facts) (*model.Decision, error) {
+	e.facts = facts
+
+	return e.result, nil
+}
+
+func setupService(t *testing.T, product *pipeline.Product[checkRequest, checkResponse], partner func(*http.Request) (int, any)) (pipeline.Service[checkRequest, checkResponse], *upstreamStub) {
+	t.Helper()
+
+	return setupServiceWithEvaluator(t, product, partner, nil)
+}
+
+func setupServiceWithEvaluator(t *testing.T, product *pipeline.Product[checkRequest, checkResponse], partner func(*http.Request) (int, any), evaluator decision.Evaluator) (pipeline.Service[checkRequest, checkResponse], *upstreamStub) {
+	t.Helper()
+
+	cfg := &application.Config{App: &application.Environment{
+		AifcoreHost:        constant.MockHost,
+		ProductCatalogHost: constant.MockHost,
+	}}
+	client := &upstreamStub{routes: coreRoutes(partner), bodies: map[string][][]byte{}}
+
+	memberRepo := member.NewRepository(cfg, client, nil)
+	jobRepo := job.NewRepository(cfg, client, nil)
+	transactionRepo := transaction.NewRepository(cfg, client, nil)
+	operationRepo := operation.NewRepository(cfg, client, nil)
+	jobService := job.NewService(jobRepo, transactionRepo, operationRepo, nil)
+	reserver := quota.NewReserver(nil, memberRepo)
+
+	svc := pipeline.NewService(product, pipeline.NewRepository(cfg, client, nil, product), memberRepo, jobRepo, transactionRepo, operationRepo, jobService, reserver, evaluator)
+
+	return svc, client
+}
+
+func partnerOK(*http.Request) (int, any) {
+	return http.StatusOK, map[string]any{"status": "clear"}
+}
+
+func TestSingleRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), partnerOK)
+
+		result, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Equal(t, "clear", result.Data.Status)
+
+		var partnerReq *http.Request
+		for _, req := range client.requests {
+			if req.URL.Path == "/product/test/check" {
+				partnerReq = req
+			}
+		}
+		require.NotNil(t, partnerReq)
+		assert.Equal(t, "7", partnerReq.URL.Query().Get("job_id"))
+		assert.Equal(t, "key", partnerReq.Header.Get(constant.XAPIKey))
+		assert.Equal(t, "1", partnerReq.Header.Get(constant.XMemberId))
+		assert.Equal(t, "9", partnerReq.Header.Get(constant.XCompanyId))
+
+		updates := client.jobUpdates(t)
+		require.Len(t, updates, 1)
+		assert.Equal(t, constant.JobStatusDone, updates[0]["status"])
+		assert.Len(t, client.bodies["POST /api/core/logging/operation"], 1)
+	})
+
+	t.Run("decision", func(t *testing.T) {
+		evaluator := &stubEvaluator{result: &model.Decision{Decision: "reject", FiredRules: []string{"not clear"}}}
+		svc, client := setupServiceWithEvaluator(t, testProduct(), partnerOK, evaluator)
+
+		result, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Equal(t, evaluator.result, result.Decision)
+		assert.Equal(t, checkResponse{Status: "clear"}, evaluator.facts["check"])
+
+		updates := client.jobUpdates(t)
+		require.Len(t, updates, 2)
+		assert.Equal(t, "reject", updates[1]["decision"])
+		assert.Equal(t, []any{"not clear"}, updates[1]["fired_rules"])
+
+		// stored on the transaction too, the exports read it from there
+		trxUpdates := client.bodies["PUT /api/core/logging/transaction/product-catalog/CHK-1"]
+		require.Len(t, trxUpdates, 1)
+		var trxUpdate map[string]any
+		require.NoError(t, json.Unmarshal(trxUpdates[0], &trxUpdate))
+		assert.Equal(t, "reject", trxUpdate["decision"])
+		assert.Equal(t, []any{"not clear"}, trxUpdate["fired_rules"])
+	})
+
+	t.Run("no decision rules", func(t *testing.T) {
+		svc, client := setupServiceWithEvaluator(t, testProduct(), partnerOK, &stubEvaluator{})
+
+		result, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Nil(t, result.Decision)
+		assert.Len(t, client.jobUpdates(t), 1)
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), func(*http.Request) (int, any) {
+			return http.StatusBadGateway, nil
+		})
+
+		_, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadGateway, appErr.StatusCode)
+
+		updates := client.jobUpdates(t)
+		require.Len(t, updates, 1)
+		assert.Equal(t, constant.JobStatusFailed, updates[0]["status"])
+		assert.Empty(t, client.bodies["POST /api/core/logging/operation"])
+	})
+
+	t.Run("product error mapper", func(t *testing.T) {
+		product := testProduct()
+		product.MapExternalError = func(err *apperror.ExternalAPIError) error {
+			return apperror.Conflict("mapped")
+		}
+		svc, _ := setupService(t, product, func(*http.Request) (int, any) {
+			return http.StatusBadGateway, nil
+		})
+
+		_, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, "mapped", appErr.Message)
+	})
+
+	t.Run("stub product", func(t *testing.T) {
+		product := testProduct()
+		product.Stub = func(trxId string, req *checkRequest) *model.ProCatAPIResponse[checkResponse] {
+			return &model.ProCatAPIResponse[checkResponse]{
+				Success:         true,
+				Data:            checkResponse{Status: "stubbed"},
+				StatusCode:      http.StatusOK,
+				PricingStrategy: constant.FreeStatus,
+				TransactionId:   trxId,
+			}
+		}
+		svc, client := setupService(t, product, partnerOK)
+
+		result, err := svc.SingleRequest(authCtx, &checkRequest{Nik: "1", LoanNo: "L1"})
+		require.NoError(t, err)
+		assert.Equal(t, "stubbed", result.Data.Status)
+		assert.True(t, strings.HasPrefix(result.TransactionId, "CHK"))
+		assert.Empty(t, client.bodies["POST /product/test/check"])
+
+		logs := client.logs(t)
+		require.Len(t, logs, 1)
+		assert.True(t, logs[0].Success)
+		assert.Equal(t, result.TransactionId, logs[0].TransactionID)
+		assert.Equal(t, constant.FreeStatus, logs[0].PricingStrategy)
+		assert.Equal(t, "L1", logs[0].LoanNo)
+		assert.Equal(t, uint(7), logs[0].JobID)
+	})
+}
+
+func TestBulkRequest(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), partnerOK)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, "NIK,Loan No\n123,L1\nabc,L2\n456,L3\n"))
+		require.NoError(t, err)
+
+		assert.Len(t, client.bodies["POST /product/test/check"], 2)
+
+		logs := client.logs(t)
+		require.Len(t, logs, 1)
+		assert.Equal(t, http.StatusBadRequest, logs[0].Status)
+		assert.Equal(t, "L2", logs[0].LoanNo)
+		assert.Equal(t, uint(5), logs[0].ProductID)
+		assert.Equal(t, uint(2), logs[0].ProductGroupID)
+
+		var created job.CreateJobRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/product/jobs"][0], &created))
+		assert.Equal(t, 3, created.Total)
+
+		updates := client.jobUpdates(t)
+		require.Len(t, updates, 1)
+		assert.Equal(t, constant.JobStatusDone, updates[0]["status"])
+	})
+
+	t.Run("normalized identifiers", func(t *testing.T) {
+		product := testProduct()
+		product.CSVHeaders = []string{"NIK", "Phone", "Loan No"}
+		product.FromCSV = func(record []string) *checkRequest {
+			return &checkRequest{Nik: record[0], Phone: record[1], LoanNo: record[2]}
+		}
+		svc, client := setupService(t, product, partnerOK)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, "NIK,Phone,Loan No\n123,0812-3456-7890,L1\n456,0112345678,L2\n"))
+		require.NoError(t, err)
+
+		sent := client.bodies["POST /product/test/check"]
+		require.Len(t, sent, 1)
+		assert.Contains(t, string(sent[0]), `"phone_number":"6281234567890"`)
+
+		logs := client.logs(t)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "L2", logs[0].LoanNo)
+		assert.Equal(t, http.StatusBadRequest, logs[0].Status)
+	})
+
+	t.Run("partner error", func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), func(*http.Request) (int, any) {
+			return http.StatusServiceUnavailable, nil
+		})
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, "NIK,Loan No\n123,L1\n"))
+		require.NoError(t, err)
+
+		logs := client.logs(t)
+		require.Len(t, logs, 1)
+		assert.Equal(t, http.StatusServiceUnavailable, logs[0].Status)
+		assert.False(t, logs[0].Success)
+	})
+
+	t.Run("partner unreachable", func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), partnerOK)
+		client.unreachable = "POST /product/test/check"
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, "NIK,Loan No\n123,L1\n"))
+		require.NoError(t, err)
+
+		logs := client.logs(t)
+		require.Len(t, logs, 1)
+		assert.Equal(t, http.StatusBadGateway, logs[0].Status)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		svc, client := setupService(t, testProduct(), partnerOK)
+
+		err := svc.BulkRequest(authCtx, pipelinetest.CSVFile(t, "Phone,Loan No\n0812,L1\n"))
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Empty(t, client.requests)
+	})
+}
+
+func TestRunner(t *testing.T) {
+	authCtx := &model.AuthContext{UserId: 1, CompanyId: 9, APIKey: "key"}
+
+	t.Run(constant.TestCaseSuccess, func(t *testing.T) {
+		product := testProduct()
+		svc, client := setupService(t, product, partnerOK)
+
+		result, err := pipeline.NewRunner(product, svc).Run(authCtx, &pipeline.Parent{ApplicantCheckId: 4}, []byte(`{"name":"Budi","nik":"123","loan_no":"L1"}`))
+		require.NoError(t, err)
+		assert.Equal(t, checkResponse{Status: "clear"}, result.Data)
+
+		var created job.CreateJobRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/product/jobs"][0], &created))
+		assert.Equal(t, uint(4), created.ApplicantCheckId)
+		assert.Empty(t, client.bodies["POST /api/core/logging/operation"])
+	})
+
+	t.Run("quota reserved by the parent", func(t *testing.T) {
+		product := testProduct()
+		svc, client := setupService(t, product, partnerOK)
+
+		quotaCtx := *authCtx
+		quotaCtx.QuotaType = 1
+		_, err := pipeline.NewRunner(product, svc).Run(&quotaCtx, &pipeline.Parent{TaxReportId: 6}, []byte(`{"nik":"123","loan_no":"L1"}`))
+		require.NoError(t, err)
+
+		// the run draws from the parent reservation instead of its own
+		assert.Empty(t, client.bodies["GET /api/core/member/quota"])
+
+		var created job.CreateJobRequest
+		require.NoError(t, json.Unmarshal(client.bodies["POST /api/core/product/jobs"][0], &created))
+		assert.Equal(t, uint(6), created.TaxReportId)
+	})
+
+	t.Run("hidden result", func(t *testing.T) {
+		product := testProduct()
+		product.HideResult = true
+		svc, _ := setupService(t, product, partnerOK)
+
+		result, err := pipeline.NewRunner(product, svc).Run(authCtx, &pipeline.Parent{ApplicantCheckId: 4}, []byte(`{"nik":"123","loan_no":"L1"}`))
+		require.NoError(t, err)
+		assert.True(t, result.Hidden)
+		assert.Equal(t, checkResponse{Status: "clear"}, result.Data)
+	})
+
+	t.Run("invalid applicant", func(t *testing.T) {
+		product := testProduct()
+		svc, client := setupService(t, product, partnerOK)
+
+		_, err := pipeline.NewRunner(product, svc).Run(authCtx, &pipeline.Parent{ApplicantCheckId: 4}, []byte(`{"nik":"abc","loan_no":"L1"}`))
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Empty(t, client.requests)
+	})
+
+	t.Run("invalid phone", func(t *testing.T) {
+		product := testProduct()
+		svc, client := setupService(t, product, partnerOK)
+
+		_, err := pipeline.NewRunner(product, svc).Run(authCtx, &pipeline.Parent{ApplicantCheckId: 4}, []byte(`{"nik":"123","phone_number":"12345","loan_no":"L1"}`))
+
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Equal(t, identifier.ErrInvalidPhone.Error(), appErr.Message)
+		assert.Empty(t, client.requests)
+	})
+}
@@ -4,6 +4,7 @@ type Mail struct {
 	To          string
 	ToList      []string
 	CC          []string
+	BCC         []string
 	Subject     string
 	Body        string
 	Retry       int
@@ -31,7 +31,7 @@ func (svc *SendMailService) Execute(mail Mail) error {
 		mail.MaxRetry = maxRetry
 	}
 
-	if mail.To == "" {
+	if mail.To == "" && len(mail.ToList) == 0 {
 		return apperror.BadRequest("recipient is required")
 	}
 
@@ -61,3 +61,27 @@ func (svc *SendMailService) SendWithTemplate(
 		Attachments: attachment,
 	})
 }
+
+// SendWithTemplateToList sends a single mail addressed to every recipient in
+// to, with optional cc and bcc lists.
+func (svc *SendMailService) SendWithTemplateToList(
+	to, cc, bcc []string,
+	subject string,
+	templateName string,
+	data any,
+	attachment ...MailAttachment,
+) error {
+	body, err := svc.renderer.Render(templateName, data)
+	if err != nil {
+		return apperror.Internal("failed to render template", err)
+	}
+
+	return svc.Execute(Mail{
+		ToList:      to,
+		CC:          cc,
+		BCC:         bcc,
+		Subject:     subject,
+		Body:        body,
+		Attachments: attachment,
+	})
+}
@@ -23,8 +23,12 @@ func NewSMTPService(host, port, user, pass string) *SMTPService {
 func (s *SMTPService) Send(mail Mail) error {
 	e := email.NewEmail()
 	e.From = fmt.Sprintf("AIForesee <%s>", s.user)
-	e.To = []string{mail.To}
+	e.To = mail.ToList
+	if mail.To != "" {
+		e.To = append([]string{mail.To}, mail.ToList...)
+	}
 	e.Cc = mail.CC
+	e.Bcc = mail.BCC
 
 	e.Subject = mail.Subject
 	e.HTML = []byte(mail.Body)
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"front-office/pkg/common/constant"
+	"front-office/pkg/common/model"
+	"front-office/pkg/helper"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIClientAuthenticator interface {
+	AuthenticateClient(cred *model.APIClientCredential) (*model.APIClientPrincipal, error)
+}
+
+var productRouteSlugs = map[string]string{
+	"phone-live-status":       constant.SlugPhoneLiveStatus,
+	"phone-nik":               constant.SlugPhoneNIKMatching,
+	"recycle-number":          constant.SlugRecycleNumber,
+	"npwp-verification":       constant.SlugNPWPVerification,
+	"loan-record-checker":     constant.SlugLoanRecordChecker,
+	"7d-multiple-loan":        constant.Slug7DaysMultipleLoan,
+	"30d-multiple-loan":       constant.Slug30DaysMultipleLoan,
+	"90d-multiple-loan":       constant.Slug90DaysMultipleLoan,
+	"tax-compliance-status":   constant.SlugTaxComplianceStatus,
+	"tax-score":               constant.SlugTaxScore,
+	"tax-verification-detail": constant.SlugTaxVerificationDetail,
+	"negative-record":         constant.SlugNegativeRecord,
+	"gen-retail":              constant.SlugGenRetailV3,
+}
+
+// APIClientAuth authenticates machine-to-machine calls sent with a bearer
+// token or an HMAC signature and fills the same locals as the cookie auth.
+// Requests without api client credentials are passed on untouched so browser
+// sessions keep working on the same routes.
+func APIClientAuth(authenticator APIClientAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cred, ok := apiClientCredentialFromRequest(c)
+		if !ok {
+			return c.Next()
+		}
+
+		principal, err := authenticator.AuthenticateClient(cred)
+		if err != nil {
+			return err
+		}
+
+		scope := constant.ScopeProductRequest
+		if c.Method() == fiber.MethodGet {
+			scope = constant.ScopeJobRead
+		}
+
+		if !principal.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(helper.ErrorResponse(constant.APIClientScopeDenied))
+		}
+
+		slug, ok := productSlugFromPath(c.Path())
+		if !ok || !principal.AllowsProduct(slug) {
+			return c.Status(fiber.StatusForbidden).JSON(helper.ErrorResponse(constant.APIClientProductDenied))
+		}
+
+		c.Locals(constant.UserId, principal.UserId)
+		c.Locals(constant.CompanyId, principal.CompanyId)
+		c.Locals(constant.RoleId, principal.RoleId)
+		c.Locals(constant.QuotaType, principal.QuotaType)
+		c.Locals(constant.APIKey, principal.APIKey)
+		c.Locals(constant.APIClientId, principal.APIClientId)
+
+		return c.Next()
+	}
+}
+
+func apiClientCredentialFromRequest(c *fiber.Ctx) (*model.APIClientCredential, bool) {
+	if authz := c.Get(constant.HeaderAuthorization); authz != "" {
+		token, found := strings.CutPrefix(authz, "Bearer ")
+		if !found {
+			return nil, false
+		}
+
+		clientId, secret, _ := strings.Cut(strings.TrimSpace(token), ".")
+
+		return &model.APIClientCredential{
+			ClientId: clientId,
+			Secret:   secret,
+		}, true
+	}
+
+	clientId := c.Get(constant.XClientId)
+	if clientId == "" {
+		return nil, false
+	}
+
+	return &model.APIClientCredential{
+		ClientId:  clientId,
+		Timestamp: c.Get(constant.XTimestamp),
+		Signature: c.Get(constant.XSignature),
+		Method:    c.Method(),
+		Path:      c.OriginalURL(),
+		Body:      c.Body(),
+	}, true
+}
+
+func productSlugFromPath(path string) (string, bool) {
+	for _, segment := range strings.Split(path, "/") {
+		if slug, ok := productRouteSlugs[segment]; ok {
+			return slug, true
+		}
+	}
+
+	return "", false
+}
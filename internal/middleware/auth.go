@@ -57,6 +57,11 @@ func SetCookiePasswordResetToken(c *fiber.Ctx) error {
 
 func GetJWTPayloadFromCookie(cfg *application.Config) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// already authenticated by APIClientAuth
+		if _, ok := c.Locals(constant.APIClientId).(string); ok {
+			return c.Next()
+		}
+
 		secret := cfg.App.JwtSecretKey
 		token := c.Cookies("aif_token")
 		if token == "" {
@@ -120,6 +125,10 @@ func GetJWTPayloadFromCookie(cfg *application.Config) fiber.Handler {
 		c.Locals(constant.QuotaType, quotaType)
 		c.Locals(constant.APIKey, apiKey)
 
+		if impersonatorId, ok := helper.ExtractImpersonatorIdFromClaims(claims); ok {
+			c.Locals(constant.ImpersonatorId, impersonatorId)
+		}
+
 		return c.Next()
 	}
 }
@@ -11,19 +11,59 @@ import (
 	"github.com/google/uuid"
 )
 
+// UploadConfig describes where an uploaded form file is stored and what it
+// may contain. The stored filename is put in the "filename" local.
+type UploadConfig struct {
+	Field      string
+	Dir        string
+	Extensions []string
+	MaxSize    int64
+	// ReplacePrevious removes the earlier uploads of the same user
+	ReplacePrevious bool
+	InvalidMessage  string
+	TooLargeMessage string
+	FailedMessage   string
+}
+
+var profileImageUpload = UploadConfig{
+	Field:           "image",
+	Dir:             "./storage/uploads/profile",
+	Extensions:      []string{".jpg", ".jpeg", ".png"},
+	MaxSize:         200 * 1024, // 200kb
+	ReplacePrevious: true,
+	InvalidMessage:  constant.InvalidImageFile,
+	TooLargeMessage: constant.FileSizeIsTooLarge,
+	FailedMessage:   constant.FailedToUploadImage,
+}
+
+// PaymentProofUpload stores proofs outside the public uploads directory, they
+// are only served to the company and the internal team.
+var PaymentProofUpload = UploadConfig{
+	Field:           "proof",
+	Dir:             "./storage/payment-proof",
+	Extensions:      []string{".jpg", ".jpeg", ".png", ".pdf"},
+	MaxSize:         2 * 1024 * 1024, // 2mb
+	InvalidMessage:  constant.InvalidPaymentProofFile,
+	TooLargeMessage: constant.PaymentProofTooLarge,
+	FailedMessage:   constant.FailedToUploadPaymentProof,
+}
+
 func FileUpload() fiber.Handler {
+	return FileUploadWithConfig(profileImageUpload)
+}
+
+func FileUploadWithConfig(cfg UploadConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userId := fmt.Sprintf("%v", c.Locals(constant.UserId))
 
-		file, err := c.FormFile("image")
+		file, err := c.FormFile(cfg.Field)
 		if err != nil {
 			return apperror.BadRequest(err.Error())
 		}
 
-		validExtensions := []string{".jpg", ".jpeg", ".png"}
 		ext := filepath.Ext(file.Filename)
 		valid := false
-		for _, allowedExt := range validExtensions {
+		for _, allowedExt := range cfg.Extensions {
 			if ext == allowedExt {
 				valid = true
 				break
@@ -31,26 +71,31 @@ func FileUpload() fiber.Handler {
 		}
 
 		if !valid {
-			return apperror.BadRequest(constant.InvalidImageFile)
+			return apperror.BadRequest(cfg.InvalidMessage)
 		}
 
-		const maxSize = 200 * 1024 // 200kb
-		if file.Size >= maxSize {
-			return apperror.BadRequest(constant.FileSizeIsTooLarge)
+		if file.Size >= cfg.MaxSize {
+			return apperror.BadRequest(cfg.TooLargeMessage)
+		}
+
+		if cfg.ReplacePrevious {
+			pattern := fmt.Sprintf("%s/%s_*", cfg.Dir, userId)
+			oldFiles, _ := filepath.Glob(pattern)
+			for _, oldFile := range oldFiles {
+				_ = os.Remove(oldFile)
+			}
 		}
 
-		pattern := fmt.Sprintf("./storage/uploads/profile/%s_*", userId)
-		oldFiles, _ := filepath.Glob(pattern)
-		for _, oldFile := range oldFiles {
-			_ = os.Remove(oldFile)
+		if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+			return apperror.Internal(cfg.FailedMessage, err)
 		}
 
 		id := uuid.NewString()
 		filename := fmt.Sprintf("%s_%s%s", userId, id, ext)
-		filePath := fmt.Sprintf("./storage/uploads/profile/%s", filename)
+		filePath := fmt.Sprintf("%s/%s", cfg.Dir, filename)
 
 		if err := c.SaveFile(file, filePath); err != nil {
-			return apperror.Internal(constant.FailedToUploadImage, err)
+			return apperror.Internal(cfg.FailedMessage, err)
 		}
 
 		c.Locals("filename", filename)
@@ -5,6 +5,7 @@ import (
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/helper"
+	"front-office/pkg/identifier"
 	"reflect"
 	"strings"
 
@@ -22,6 +23,14 @@ func ValidateRequest(model interface{}) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(resp)
 		}
 
+		// identifiers are normalized first so the format rules apply to the
+		// form sent to the partners
+		if err := identifier.NormalizeStruct(request); err != nil {
+			resp := helper.ErrorResponse(err.Error())
+
+			return c.Status(fiber.StatusBadRequest).JSON(resp)
+		}
+
 		if errValid := validator.ValidateStruct(request); errValid != nil {
 			resp := helper.ErrorResponse(errValid.Error())
 
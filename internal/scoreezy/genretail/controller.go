@@ -8,7 +8,6 @@ import (
 	"mime/multipart"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -28,8 +27,6 @@ type controller struct {
 }
 
 type Controller interface {
-	DummyRequestScore(c *fiber.Ctx) error
-	DummyRequest201(c *fiber.Ctx) error
 	SingleRequest(c *fiber.Ctx) error
 	BulkRequest(c *fiber.Ctx) error
 	GetLogsScoreezy(c *fiber.Ctx) error
@@ -40,37 +37,6 @@ type Controller interface {
 	// GetBulkSearch(c *fiber.Ctx) error
 }
 
-func (ctrl *controller) DummyRequestScore(c *fiber.Ctx) error {
-	response := genRetailV3ClientReturnSuccess{
-		Message: "Succeed to Request Scores",
-		Success: true,
-		Data: &dataGenRetailV3{
-			TransactionId:        "TRX123456789",
-			Name:                 "John Doe",
-			IdCardNo:             "1234567890123456",
-			PhoneNo:              "081234567890",
-			LoanNo:               "LN987654321",
-			ProbabilityToDefault: 0.12345,
-			Grade:                "A",
-			Identity:             "Verified in more than 50% social media platform and registered on one of the telecommunication platforms",
-			Behavior:             "This individual is not identified to have a history of loan applications and is not indicated to have defaulted on payments.",
-			Date:                 time.Now().Format("2006-01-02 15:04:05"),
-		},
-	}
-
-	return c.Status(200).JSON(response)
-}
-
-func (ctrl *controller) DummyRequest201(c *fiber.Ctx) error {
-	response := genRetailV3ClientReturnSuccess{
-		Message: "failed to calculate",
-		Success: true,
-		Data:    nil,
-	}
-
-	return c.Status(201).JSON(response)
-}
-
 func (ctrl *controller) SingleRequest(c *fiber.Ctx) error {
 	reqBody, ok := c.Locals(constant.Request).(*genRetailRequest)
 	if !ok {
@@ -82,7 +48,7 @@ func (ctrl *controller) SingleRequest(c *fiber.Ctx) error {
 		return apperror.Unauthorized(err.Error())
 	}
 
-	result, err := ctrl.service.GenRetailV3(authCtx.UserId, authCtx.CompanyId, reqBody)
+	result, err := ctrl.service.GenRetailV3(authCtx, reqBody)
 	if err != nil {
 		return err
 	}
@@ -101,7 +67,7 @@ func (ctrl *controller) BulkRequest(c *fiber.Ctx) error {
 		return apperror.Unauthorized(err.Error())
 	}
 
-	jobId, err := ctrl.service.BulkGenRetailV3(authCtx.UserId, authCtx.CompanyId, authCtx.QuotaTypeStr(), file)
+	jobId, err := ctrl.service.BulkGenRetailV3(authCtx, file)
 	if err != nil {
 		return err
 	}
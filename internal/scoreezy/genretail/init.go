@@ -2,11 +2,13 @@ package genretail
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/decision"
 	"front-office/internal/core/grade"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
 	"front-office/internal/core/member"
 	"front-office/internal/core/product"
+	"front-office/internal/core/quota"
 	"front-office/internal/datahub/job"
 	"front-office/internal/middleware"
 	"front-office/pkg/httpclient"
@@ -14,7 +16,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
 	repo := NewRepository(cfg, client, nil)
 	gradeRepo := grade.NewRepository(cfg, client, nil)
 	transRepo := transaction.NewRepository(cfg, client, nil)
@@ -23,11 +25,10 @@ func SetupInit(apiGroup fiber.Router, cfg *application.Config, client httpclient
 	jobRepo := job.NewRepository(cfg, client, nil)
 	memberRepo := member.NewRepository(cfg, client, nil)
 
-	service := NewService(repo, gradeRepo, transRepo, productRepo, logRepo, jobRepo, memberRepo)
+	service := NewService(repo, gradeRepo, transRepo, productRepo, logRepo, jobRepo, memberRepo, quotaReserver, evaluator)
 
 	controller := NewController(service)
 
-	apiGroup.Post("/dummy-request", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(genRetailRequest{}), controller.DummyRequest201)
 	apiGroup.Post("/single-request", middleware.GetJWTPayloadFromCookie(cfg), middleware.ValidateRequest(genRetailRequest{}), controller.SingleRequest)
 	apiGroup.Post("/bulk-request", middleware.ValidateCSVFile(), middleware.GetJWTPayloadFromCookie(cfg), controller.BulkRequest)
 	apiGroup.Get("/logs", middleware.GetJWTPayloadFromCookie(cfg), controller.GetLogsScoreezy)
@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"front-office/internal/core/decision"
 	"front-office/internal/core/grade"
 	"front-office/internal/core/log/operation"
 	"front-office/internal/core/log/transaction"
 	"front-office/internal/core/member"
 	"front-office/internal/core/product"
+	"front-office/internal/core/quota"
 	"front-office/internal/datahub/job"
 	"front-office/pkg/apperror"
 	"front-office/pkg/common/constant"
 	"front-office/pkg/common/model"
 	"front-office/pkg/helper"
+	"front-office/pkg/identifier"
 	"mime/multipart"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +36,8 @@ func NewService(
 	operationRepo operation.Repository,
 	jobRepo job.Repository,
 	memberRepo member.Repository,
+	quotaReserver quota.Reserver,
+	evaluator decision.Evaluator,
 ) Service {
 	return &service{
 		repo,
@@ -41,6 +47,8 @@ func NewService(
 		operationRepo,
 		jobRepo,
 		memberRepo,
+		quotaReserver,
+		evaluator,
 	}
 }
 
@@ -52,16 +60,21 @@ type service struct {
 	operationRepo operation.Repository
 	jobRepo       job.Repository
 	memberRepo    member.Repository
+	quotaReserver quota.Reserver
+	evaluator     decision.Evaluator
 }
 
 const (
 	typePersonal = "personal"
 	// typeCompany  = "company"
+
+	// route names the product in decision rules, the same as in its URL
+	route = "gen-retail"
 )
 
 type Service interface {
-	GenRetailV3(memberId, companyId uint, payload *genRetailRequest) (*model.ScoreezyAPIResponse[dataGenRetailV3], error)
-	BulkGenRetailV3(memberId, companyId uint, quotaType string, file *multipart.FileHeader) (uint, error)
+	GenRetailV3(authCtx *model.AuthContext, payload *genRetailRequest) (*model.ScoreezyAPIResponse[dataGenRetailV3], error)
+	BulkGenRetailV3(authCtx *model.AuthContext, file *multipart.FileHeader) (uint, error)
 	GetLogsScoreezy(filter *filterLogs) (*model.AifcoreAPIResponse[[]*logTransScoreezy], error)
 	GetLogScoreezy(filter *filterLogs) (*logTransScoreezy, error)
 	ExportJobDetails(filter *filterLogs, buf *bytes.Buffer) (string, error)
@@ -70,7 +83,8 @@ type Service interface {
 	// GetTotalDataBulk(tierLevel uint, userId, companyId string) (int64, error)
 }
 
-func (svc *service) GenRetailV3(memberId, companyId uint, payload *genRetailRequest) (*model.ScoreezyAPIResponse[dataGenRetailV3], error) {
+func (svc *service) GenRetailV3(authCtx *model.AuthContext, payload *genRetailRequest) (*model.ScoreezyAPIResponse[dataGenRetailV3], error) {
+	memberId, companyId := authCtx.UserId, authCtx.CompanyId
 	memberIdStr := helper.ConvertUintToString(memberId)
 	companyIdStr := helper.ConvertUintToString(companyId)
 	subscribedResp, err := svc.memberRepo.GetSubscribedProducts(companyIdStr, constant.SlugGenRetailV3)
@@ -78,6 +92,12 @@ func (svc *service) GenRetailV3(memberId, companyId uint, payload *genRetailRequ
 		return nil, apperror.MapRepoError(err, constant.ErrFetchSubscribedProduct)
 	}
 
+	reservation, err := svc.quotaReserver.Reserve(authCtx, strconv.Itoa(int(subscribedResp.Data.SubsribedProductID)), 1)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.Release()
+
 	// make sure parameter settings are set
 	gradeResp, err := svc.gradeRepo.GetGradesAPI(constant.SlugGenRetailV3, strconv.FormatUint(uint64(companyId), 10))
 	if err != nil {
@@ -89,10 +109,11 @@ func (svc *service) GenRetailV3(memberId, companyId uint, payload *genRetailRequ
 	}
 
 	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  memberIdStr,
-		CompanyId: companyIdStr,
-		Total:     1,
+		ProductId:   subscribedResp.Data.ProductId,
+		MemberId:    memberIdStr,
+		CompanyId:   companyIdStr,
+		Total:       1,
+		APIClientId: authCtx.APIClientId,
 	})
 	if err != nil {
 		return nil, apperror.MapRepoError(err, constant.FailedCreateJob)
@@ -105,11 +126,13 @@ func (svc *service) GenRetailV3(memberId, companyId uint, payload *genRetailRequ
 	}
 
 	result.Data.JobId = jobRes.JobId
+	result.Decision = svc.decide(companyId, jobIdStr, result.Data)
 
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  memberId,
-		CompanyId: companyId,
-		Action:    constant.EventScoreezySingleReq,
+		MemberId:    memberId,
+		CompanyId:   companyId,
+		Action:      constant.EventScoreezySingleReq,
+		APIClientId: authCtx.APIClientId,
 	}); err != nil {
 		log.Warn().
 			Err(err).
@@ -120,7 +143,53 @@ func (svc *service) GenRetailV3(memberId, companyId uint, payload *genRetailRequ
 	return result, err
 }
 
-func (svc *service) BulkGenRetailV3(memberId, companyId uint, quotaType string, file *multipart.FileHeader) (uint, error) {
+// decide evaluates the company decision rules over the score and stores the
+// decision on the job and its transaction, exports read it from the
+// transaction. The score is returned undecided when the evaluation fails.
+func (svc *service) decide(companyId uint, jobIdStr string, data *dataGenRetailV3) *model.Decision {
+	if svc.evaluator == nil {
+		return nil
+	}
+
+	result, err := svc.evaluator.Evaluate(companyId, decision.Facts{route: data})
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", jobIdStr).
+			Msg("failed to evaluate decision rules")
+
+		return nil
+	}
+	if result == nil {
+		return nil
+	}
+
+	stored := map[string]interface{}{
+		"decision":    result.Decision,
+		"fired_rules": result.FiredRules,
+	}
+	if err := svc.jobRepo.UpdateJobAPI(jobIdStr, stored); err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", jobIdStr).
+			Msg("failed to store decision")
+	}
+
+	if data.TransactionId != "" {
+		if err := svc.transRepo.UpdateLogScoreezyAPI(data.TransactionId, stored); err != nil {
+			log.Warn().
+				Err(err).
+				Str("job_id", jobIdStr).
+				Str("trx_id", data.TransactionId).
+				Msg("failed to store decision on transaction")
+		}
+	}
+
+	return result
+}
+
+func (svc *service) BulkGenRetailV3(authCtx *model.AuthContext, file *multipart.FileHeader) (uint, error) {
+	memberId, companyId := authCtx.UserId, authCtx.CompanyId
 	records, err := helper.ParseCSVFile(file, []string{"Name", "Loan Number", "ID Card Number", "Phone Number"})
 	if err != nil {
 		return 0, apperror.BadRequest(err.Error())
@@ -134,20 +203,13 @@ func (svc *service) BulkGenRetailV3(memberId, companyId uint, quotaType string,
 	}
 
 	subscribedIdStr := strconv.Itoa(int(subscribedResp.Data.SubsribedProductID))
-	quotaResp, err := svc.memberRepo.GetQuotaAPI(&member.QuotaParams{
-		MemberId:     memberIdStr,
-		CompanyId:    companyIdStr,
-		SubscribedId: subscribedIdStr,
-		QuotaType:    quotaType,
-	})
-	if err != nil {
-		return 0, apperror.MapRepoError(err, constant.FailedFetchQuota)
-	}
 
 	totalRequests := len(records) - 1
-	if quotaType != "0" && quotaResp.Data.Quota < totalRequests {
-		return 0, apperror.Forbidden(constant.ErrQuotaExceeded)
+	reservation, err := svc.quotaReserver.Reserve(authCtx, subscribedIdStr, totalRequests)
+	if err != nil {
+		return 0, err
 	}
+	defer reservation.Release()
 
 	// make sure parameter settings are set
 	gradeResp, err := svc.gradeRepo.GetGradesAPI(constant.SlugGenRetailV3, strconv.FormatUint(uint64(companyId), 10))
@@ -174,10 +236,11 @@ func (svc *service) BulkGenRetailV3(memberId, companyId uint, quotaType string,
 	}
 
 	jobRes, err := svc.jobRepo.CreateJobAPI(&job.CreateJobRequest{
-		ProductId: subscribedResp.Data.ProductId,
-		MemberId:  memberIdStr,
-		CompanyId: companyIdStr,
-		Total:     totalRequests,
+		ProductId:   subscribedResp.Data.ProductId,
+		MemberId:    memberIdStr,
+		CompanyId:   companyIdStr,
+		Total:       totalRequests,
+		APIClientId: authCtx.APIClientId,
 	})
 	if err != nil {
 		return 0, apperror.MapRepoError(err, constant.FailedCreateJob)
@@ -194,6 +257,7 @@ func (svc *service) BulkGenRetailV3(memberId, companyId uint, quotaType string,
 
 		go func(req *genRetailRequest) {
 			defer wg.Done()
+			defer reservation.Consume(1)
 
 			if err := svc.processSingleGenRetail(&genRetailContext{
 				MemberId:  memberId,
@@ -221,9 +285,10 @@ func (svc *service) BulkGenRetailV3(memberId, companyId uint, quotaType string,
 	}
 
 	if err := svc.operationRepo.AddLogOperation(&operation.AddLogRequest{
-		MemberId:  memberId,
-		CompanyId: companyId,
-		Action:    constant.EventScoreezyBulkReq,
+		MemberId:    memberId,
+		CompanyId:   companyId,
+		Action:      constant.EventScoreezyBulkReq,
+		APIClientId: authCtx.APIClientId,
 	}); err != nil {
 		log.Warn().
 			Err(err).
@@ -388,6 +453,10 @@ func writeToCSV(buf *bytes.Buffer, includeDate, masked bool, logs []*logTransSco
 	defer w.Flush()
 
 	headers := buildHeaders(constant.CSVExportHeaderGenRetail, includeDate)
+	decided := hasDecision(logs)
+	if decided {
+		headers = append(slices.Clone(headers), constant.CSVExportHeaderDecision...)
+	}
 	if err := w.Write(headers); err != nil {
 		return err
 	}
@@ -447,6 +516,16 @@ func writeToCSV(buf *bytes.Buffer, includeDate, masked bool, logs []*logTransSco
 			row = append([]string{createdAt}, row...)
 		}
 
+		if decided {
+			var outcome, firedRules string
+			if log.Data != nil {
+				outcome = log.Data.Decision
+				firedRules = strings.Join(log.Data.FiredRules, "; ")
+			}
+
+			row = append(row, outcome, firedRules)
+		}
+
 		if err := w.Write(row); err != nil {
 			return err
 		}
@@ -455,6 +534,18 @@ func writeToCSV(buf *bytes.Buffer, includeDate, masked bool, logs []*logTransSco
 	return w.Error()
 }
 
+// hasDecision reports whether any log was decided by the company rules, the
+// decision columns are left out otherwise.
+func hasDecision(logs []*logTransScoreezy) bool {
+	for _, log := range logs {
+		if log.Data != nil && log.Data.Decision != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func buildHeaders(base []string, includeDate bool) []string {
 	if includeDate {
 		return append([]string{"Date"}, base...)
@@ -471,7 +562,11 @@ func formatCSVFileName(base, startDate, endDate string) string {
 }
 
 func (svc *service) processSingleGenRetail(params *genRetailContext) error {
-	if err := validator.ValidateStruct(params.Request); err != nil {
+	err := identifier.NormalizeStruct(params.Request)
+	if err == nil {
+		err = validator.ValidateStruct(params.Request)
+	}
+	if err != nil {
 		_ = svc.transRepo.CreateLogScoreezyAPI(&transaction.LogTransScoreezy{
 			TrxId:     helper.GenerateTrx(constant.TrxIdGenRetailV3),
 			MemberId:  params.MemberId,
@@ -486,7 +581,7 @@ func (svc *service) processSingleGenRetail(params *genRetailContext) error {
 		return apperror.BadRequest(err.Error())
 	}
 
-	_, err := svc.repo.GenRetailV3API(
+	_, err = svc.repo.GenRetailV3API(
 		strconv.FormatUint(uint64(params.MemberId), 10),
 		strconv.FormatUint(uint64(params.JobId), 10),
 		params.Request,
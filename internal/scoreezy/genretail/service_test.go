@@ -0,0 +1,62 @@
+package genretail
+
+import (
+	"front-office/internal/core/decision"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/datahub/job"
+	"front-office/pkg/common/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubEvaluator struct {
+	facts decision.Facts
+}
+
+func (e *stubEvaluator) Evaluate(_ uint, facts decision.Facts) (*model.Decision, error) {
+	e.facts = facts
+	return &model.Decision{Decision: "reject", FiredRules: []string{"grade E"}}, nil
+}
+
+// jobUpdateStub records the job updates, the other calls are not used.
+type jobUpdateStub struct {
+	job.Repository
+	updates map[string]map[string]interface{}
+}
+
+func (s *jobUpdateStub) UpdateJobAPI(jobId string, req map[string]interface{}) error {
+	s.updates[jobId] = req
+	return nil
+}
+
+// logUpdateStub records the transaction updates, the other calls are not
+// used.
+type logUpdateStub struct {
+	transaction.Repository
+	updates map[string]map[string]interface{}
+}
+
+func (s *logUpdateStub) UpdateLogScoreezyAPI(trxId string, req map[string]interface{}) error {
+	s.updates[trxId] = req
+	return nil
+}
+
+func TestDecide(t *testing.T) {
+	jobRepo := &jobUpdateStub{updates: map[string]map[string]interface{}{}}
+	transRepo := &logUpdateStub{updates: map[string]map[string]interface{}{}}
+	evaluator := &stubEvaluator{}
+	svc := &service{jobRepo: jobRepo, transRepo: transRepo, evaluator: evaluator}
+
+	data := &dataGenRetailV3{TransactionId: "GRV3-1", Grade: "E"}
+	result := svc.decide(9, "7", data)
+
+	require.NotNil(t, result)
+	assert.Equal(t, decision.Facts{route: data}, evaluator.facts)
+
+	stored := map[string]interface{}{"decision": "reject", "fired_rules": []string{"grade E"}}
+	assert.Equal(t, stored, jobRepo.updates["7"])
+	// exports read the decision from the transaction log
+	assert.Equal(t, stored, transRepo.updates["GRV3-1"])
+}
@@ -2,6 +2,8 @@ package scoreezy
 
 import (
 	"front-office/configs/application"
+	"front-office/internal/core/decision"
+	"front-office/internal/core/quota"
 	"front-office/internal/datahub/job"
 	"front-office/internal/scoreezy/genretail"
 	"front-office/pkg/httpclient"
@@ -9,10 +11,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupInit(routeAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient) {
+func SetupInit(routeAPI fiber.Router, cfg *application.Config, client httpclient.HTTPClient, quotaReserver quota.Reserver, evaluator decision.Evaluator) {
 	scoreezyGroup := routeAPI.Group("scoreezy")
 	job.SetupInit(scoreezyGroup, cfg, client)
 
 	genRetailGroupAPI := scoreezyGroup.Group("gen-retail")
-	genretail.SetupInit(genRetailGroupAPI, cfg, client)
+	genretail.SetupInit(genRetailGroupAPI, cfg, client, quotaReserver, evaluator)
 }
@@ -0,0 +1,172 @@
+package simulator
+
+import "front-office/pkg/common/constant"
+
+const (
+	groupIdentity uint = iota + 1
+	groupCompliance
+	groupIncomeTax
+	groupCompanyLitigation
+	groupScoreezy
+)
+
+// catalogProduct is a product the front office calls, with the partner
+// path it is served on and the built-in answers.
+type catalogProduct struct {
+	slug    string
+	name    string
+	groupId uint
+	// path is on the datahub host, or on the scoreezy host for scoreezy
+	// products
+	path     string
+	scoreezy bool
+	fixtures []Fixture
+}
+
+var catalog = []catalogProduct{
+	{
+		slug:    constant.SlugPhoneLiveStatus,
+		name:    constant.PhoneLiveStatus,
+		groupId: groupIdentity,
+		path:    "/product/identity/phone-live-status",
+		fixtures: []Fixture{
+			{Match: map[string]string{"phone_number": "628111111110"}, Data: map[string]any{"live_status": "not active", "phone_type": "prepaid", "operator": "telkomsel", "errors": []any{}}},
+			{Data: map[string]any{"live_status": "active", "phone_type": "prepaid", "operator": "telkomsel", "errors": []any{}}},
+		},
+	},
+	{
+		slug:    constant.SlugRecycleNumber,
+		name:    constant.RecycleNumber,
+		groupId: groupIdentity,
+		path:    "/product/identity/recycle-number",
+		fixtures: []Fixture{
+			{Match: map[string]string{"phone_number": "628111111110"}, Data: map[string]any{"status": "phone number has been recycled"}},
+			{Data: map[string]any{"status": "phone number never happens recycled"}},
+		},
+	},
+	{
+		slug:    constant.SlugNPWPVerification,
+		name:    constant.NPWPVerification,
+		groupId: groupIdentity,
+		path:    "/product/identity/npwp-verification",
+		fixtures: []Fixture{
+			{Data: map[string]any{"nama": "BUDI SANTOSO"}},
+		},
+	},
+	{
+		slug:    constant.SlugLoanRecordChecker,
+		name:    constant.LoanRecordChecker,
+		groupId: groupCompliance,
+		path:    "/product/compliance/loan-record-checker",
+		fixtures: []Fixture{
+			{Match: map[string]string{"nik": "3201011203900001"}, Data: map[string]any{"status": "found", "remarks": "overdue more than 90 days"}},
+			{Data: map[string]any{"status": "not found", "remarks": ""}},
+		},
+	},
+	{
+		slug:     constant.Slug7DaysMultipleLoan,
+		name:     constant.MultipleLoan7D,
+		groupId:  groupCompliance,
+		path:     "/product/compliance/multiple-loan/7-days",
+		fixtures: []Fixture{{Data: map[string]any{"query_count": 1}}},
+	},
+	{
+		slug:     constant.Slug30DaysMultipleLoan,
+		name:     constant.MultipleLoan30D,
+		groupId:  groupCompliance,
+		path:     "/product/compliance/multiple-loan/30-days",
+		fixtures: []Fixture{{Data: map[string]any{"query_count": 3}}},
+	},
+	{
+		slug:     constant.Slug90DaysMultipleLoan,
+		name:     constant.MultipleLoan90D,
+		groupId:  groupCompliance,
+		path:     "/product/compliance/multiple-loan/90-days",
+		fixtures: []Fixture{{Data: map[string]any{"query_count": 5}}},
+	},
+	{
+		slug:    constant.SlugTaxComplianceStatus,
+		name:    constant.TaxCompliance,
+		groupId: groupIncomeTax,
+		path:    "/product/incometax/tax-compliance-status",
+		fixtures: []Fixture{
+			{Data: map[string]any{"nama": "BUDI SANTOSO", "alamat": "JL. MERDEKA NO. 1 JAKARTA", "status": "VALID"}},
+		},
+	},
+	{
+		slug:    constant.SlugTaxScore,
+		name:    constant.TaxScore,
+		groupId: groupIncomeTax,
+		path:    "/product/incometax/tax-score",
+		fixtures: []Fixture{
+			{Data: map[string]any{"nama": "BUDI SANTOSO", "alamat": "JL. MERDEKA NO. 1 JAKARTA", "score": "A", "status": "VALID"}},
+		},
+	},
+	{
+		slug:    constant.SlugTaxVerificationDetail,
+		name:    constant.TaxVerification,
+		groupId: groupIncomeTax,
+		path:    "/product/incometax/tax-verification-detail",
+		fixtures: []Fixture{
+			{Data: map[string]any{"nama": "BUDI SANTOSO", "alamat": "JL. MERDEKA NO. 1 JAKARTA", "npwp": "0012345678901000", "npwp_verification": "VALID", "tax_compliance": "VALID", "status": "VALID"}},
+		},
+	},
+	{
+		slug:    constant.SlugNegativeRecord,
+		name:    "Negative Record",
+		groupId: groupCompanyLitigation,
+		path:    "/product/complit/negative-record",
+		fixtures: []Fixture{
+			{Match: map[string]string{"company_name": "PT Artha Mulia"}, Data: map[string]any{"result": []any{
+				map[string]any{
+					"company_name":      "KOPERASI SIMPAN PINJAM ARTHA MULIA",
+					"similarity_score":  "100.0",
+					"status":            "Penyerahan Memori Kasasi",
+					"case_number":       "411/Pdt.G/2025/PN JKT.SEL",
+					"court":             "PENGADILAN NEGERI JAKARTA SELATAN",
+					"province":          "DKI Jakarta",
+					"case_type":         "Wanprestasi",
+					"registration_date": "2025-04-28",
+					"process_duration":  "165 Hari",
+					"last_updated":      "01 October 2025 10:00 WIB",
+				},
+			}}},
+			{Data: map[string]any{"result": []any{}}},
+		},
+	},
+	{
+		slug:     constant.SlugGenRetailV3,
+		name:     constant.GenRetail,
+		groupId:  groupScoreezy,
+		path:     "/api/score/genretail/v3",
+		scoreezy: true,
+		fixtures: []Fixture{
+			{Data: map[string]any{
+				"probability_to_default": 0.12345,
+				"grade":                  "A",
+				"identity":               "Verified in more than 50% social media platform and registered on one of the telecommunication platforms",
+				"behavior":               "This individual is not identified to have a history of loan applications and is not indicated to have defaulted on payments.",
+			}},
+		},
+	},
+}
+
+var catalogBySlug = func() map[string]*catalogProduct {
+	bySlug := make(map[string]*catalogProduct, len(catalog))
+	for i := range catalog {
+		bySlug[catalog[i].slug] = &catalog[i]
+	}
+
+	return bySlug
+}()
+
+// productId numbers the products by their place in the catalog.
+func productId(slug string) uint {
+	for i, product := range catalog {
+		if product.slug == slug {
+			return uint(i + 1)
+		}
+	}
+
+	return 0
+}
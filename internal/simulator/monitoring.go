@@ -0,0 +1,160 @@
+package simulator
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+)
+
+func (s *Server) handleCreateSubject(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var subject map[string]any
+	if !decodeBody(w, r, &subject) {
+		return
+	}
+
+	s.mu.Lock()
+	s.nextSubjectId++
+	id := s.nextSubjectId
+	subject["id"] = id
+	subject["created_at"] = time.Now()
+	s.subjects[id] = subject
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, map[string]any{"subject_id": id})
+}
+
+// handleGetSubjects filters by company_id, without it every subject is
+// returned the way the schedule endpoint does.
+func (s *Server) handleGetSubjects(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	companyId := r.URL.Query().Get("company_id")
+
+	s.mu.Lock()
+	subjects := []map[string]any{}
+	for id := uint(1); id <= s.nextSubjectId; id++ {
+		subject, ok := s.subjects[id]
+		if ok && (companyId == "" || fmt.Sprint(subject["company_id"]) == companyId) {
+			subjects = append(subjects, maps.Clone(subject))
+		}
+	}
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, subjects)
+}
+
+func (s *Server) handleGetSubject(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	s.mu.Lock()
+	subject, ok := s.subjects[pathId(r, "id")]
+	subject = maps.Clone(subject)
+	s.mu.Unlock()
+
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, subject)
+}
+
+func (s *Server) handleUpdateSubject(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var update map[string]any
+	if !decodeBody(w, r, &update) {
+		return
+	}
+
+	s.mu.Lock()
+	subject, ok := s.subjects[pathId(r, "id")]
+	if ok {
+		maps.Copy(subject, update)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleDeleteSubject(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	id := pathId(r, "id")
+
+	s.mu.Lock()
+	_, ok := s.subjects[id]
+	delete(s.subjects, id)
+	s.mu.Unlock()
+
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleCreateAlerts(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var alerts []map[string]any
+	if !decodeBody(w, r, &alerts) {
+		return
+	}
+
+	s.mu.Lock()
+	for _, alert := range alerts {
+		alert["id"] = len(s.alerts) + 1
+		s.alerts = append(s.alerts, alert)
+	}
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+// handleGetAlerts returns the newest alerts first.
+func (s *Server) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	companyId, subjectId := r.URL.Query().Get("company_id"), r.URL.Query().Get("subject_id")
+
+	s.mu.Lock()
+	alerts := []map[string]any{}
+	for i := len(s.alerts) - 1; i >= 0; i-- {
+		alert := s.alerts[i]
+		if fmt.Sprint(alert["company_id"]) != companyId {
+			continue
+		}
+		if subjectId != "" && fmt.Sprint(alert["subject_id"]) != subjectId {
+			continue
+		}
+
+		alerts = append(alerts, maps.Clone(alert))
+	}
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, alerts)
+}
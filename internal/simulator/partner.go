@@ -0,0 +1,132 @@
+package simulator
+
+import (
+	"fmt"
+	"front-office/internal/core/log/transaction"
+	"front-office/pkg/common/constant"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// handlePartner answers a product request from the first matching fixture.
+// Like the real partners it logs the successful datahub transactions on the
+// core itself.
+func (s *Server) handlePartner(w http.ResponseWriter, r *http.Request, product *catalogProduct) {
+	s.mu.Lock()
+	scripted := s.scenario.Products[product.slug]
+	s.mu.Unlock()
+
+	var fixtures []Fixture
+	if scripted != nil {
+		if s.misbehave(w, scripted.Behavior) {
+			return
+		}
+		fixtures = scripted.Fixtures
+	}
+	fixtures = append(append([]Fixture(nil), fixtures...), product.fixtures...)
+
+	var input map[string]any
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	fixture := matchFixture(fixtures, input)
+	status := fixture.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	message := fixture.Message
+	if message == "" {
+		message = "Succeed to Request Data"
+		if status >= http.StatusBadRequest {
+			message = http.StatusText(status)
+		}
+	}
+
+	if status >= http.StatusBadRequest {
+		writeJSON(w, status, map[string]any{"success": false, "message": message})
+		return
+	}
+
+	pricingStrategy := fixture.PricingStrategy
+	if pricingStrategy == "" {
+		pricingStrategy = constant.PaidStatus
+	}
+
+	s.mu.Lock()
+	s.nextTrxId++
+	trxId := fmt.Sprintf("SIM%08d", s.nextTrxId)
+	s.mu.Unlock()
+
+	now := time.Now()
+
+	if product.scoreezy {
+		data := map[string]any{}
+		maps.Copy(data, input)
+		if fixtureData, ok := fixture.Data.(map[string]any); ok {
+			maps.Copy(data, fixtureData)
+		}
+		data["transaction_id"] = trxId
+		data["date"] = now.Format(constant.FormatDateAndTime)
+
+		writeJSON(w, status, map[string]any{"success": true, "message": message, "data": data})
+		return
+	}
+
+	jobId, _ := strconv.ParseUint(r.URL.Query().Get("job_id"), 10, 64)
+	memberId, _ := strconv.ParseUint(r.Header.Get(constant.XMemberId), 10, 64)
+	companyId, _ := strconv.ParseUint(r.Header.Get(constant.XCompanyId), 10, 64)
+	loanNo, _ := input["loan_no"].(string)
+
+	s.mu.Lock()
+	s.transactions = append(s.transactions, transaction.LogTransProCatRequest{
+		TransactionID:   trxId,
+		MemberID:        uint(memberId),
+		CompanyID:       uint(companyId),
+		JobID:           uint(jobId),
+		ProductID:       productId(product.slug),
+		ProductGroupID:  product.groupId,
+		RequestBody:     input,
+		Data:            fixture.Data,
+		Status:          status,
+		Success:         true,
+		Message:         message,
+		PricingStrategy: pricingStrategy,
+		LoanNo:          loanNo,
+		RequestTime:     now,
+		ResponseTime:    now,
+	})
+	s.mu.Unlock()
+
+	writeJSON(w, status, map[string]any{
+		"success":          true,
+		"message":          message,
+		"data":             fixture.Data,
+		"input":            input,
+		"pricing_strategy": pricingStrategy,
+		"transaction_id":   trxId,
+		"datetime":         now.Format(constant.FormatDateAndTime),
+	})
+}
+
+// matchFixture returns the first fixture whose Match fields all equal the
+// input, the catalog always ends with a fixture matching anything.
+func matchFixture(fixtures []Fixture, input map[string]any) Fixture {
+	for _, fixture := range fixtures {
+		matched := true
+		for field, want := range fixture.Match {
+			if fmt.Sprint(input[field]) != want {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return fixture
+		}
+	}
+
+	return Fixture{}
+}
@@ -0,0 +1,86 @@
+package simulator
+
+import (
+	"front-office/internal/datahub/companylitigation/review"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) handleGetReviewSettings(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	s.mu.Lock()
+	settings, ok := s.reviewSettings[r.PathValue("companyId")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, settings)
+}
+
+func (s *Server) handleSaveReviewSettings(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var settings review.Settings
+	if !decodeBody(w, r, &settings) {
+		return
+	}
+
+	s.mu.Lock()
+	s.reviewSettings[r.PathValue("companyId")] = settings
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, settings)
+}
+
+func (s *Server) handleGetHitReviews(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reviews := []review.Review{}
+	for _, trxId := range strings.Split(r.URL.Query().Get("transaction_ids"), ",") {
+		for _, hitReview := range s.hitReviews[r.PathValue("companyId")][trxId] {
+			reviews = append(reviews, hitReview)
+		}
+	}
+
+	writeCore(w, http.StatusOK, reviews)
+}
+
+// handleSaveHitReview replaces an earlier review of the same hit.
+func (s *Server) handleSaveHitReview(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var hitReview review.Review
+	if !decodeBody(w, r, &hitReview) {
+		return
+	}
+
+	companyId, trxId := r.PathValue("companyId"), r.PathValue("trxId")
+	hitReview.TransactionId = trxId
+
+	s.mu.Lock()
+	if s.hitReviews[companyId] == nil {
+		s.hitReviews[companyId] = map[string]map[string]review.Review{}
+	}
+	if s.hitReviews[companyId][trxId] == nil {
+		s.hitReviews[companyId][trxId] = map[string]review.Review{}
+	}
+	s.hitReviews[companyId][trxId][hitReview.CaseNumber] = hitReview
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, hitReview)
+}
@@ -0,0 +1,170 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultQuota = 1000
+
+// Behavior shapes how an endpoint answers: every response waits LatencyMs
+// plus up to JitterMs, and fails with ErrorStatus at ErrorRate (0 to 1).
+type Behavior struct {
+	LatencyMs   int     `json:"latency_ms" yaml:"latency_ms"`
+	JitterMs    int     `json:"jitter_ms" yaml:"jitter_ms"`
+	ErrorRate   float64 `json:"error_rate" yaml:"error_rate"`
+	ErrorStatus int     `json:"error_status" yaml:"error_status"`
+}
+
+// Fixture is a partner answer. It is used when every Match field equals the
+// field of the request body, a fixture without Match answers any request.
+type Fixture struct {
+	Match           map[string]string `json:"match" yaml:"match"`
+	Status          int               `json:"status" yaml:"status"`
+	Message         string            `json:"message" yaml:"message"`
+	PricingStrategy string            `json:"pricing_strategy" yaml:"pricing_strategy"`
+	Data            any               `json:"data" yaml:"data"`
+}
+
+// Product scripts the partner of one product slug. Its fixtures are tried
+// before the built-in ones.
+type Product struct {
+	Behavior `yaml:",inline"`
+	Fixtures []Fixture `json:"fixtures" yaml:"fixtures"`
+}
+
+// Route is a static core answer for endpoints the simulator does not keep
+// state for, e.g. the member or company lookups.
+type Route struct {
+	Status int `json:"status" yaml:"status"`
+	Data   any `json:"data" yaml:"data"`
+}
+
+// Scenario is everything that can be scripted.
+type Scenario struct {
+	// Seed makes the latency jitter and the errors repeatable, 0 seeds from
+	// the clock
+	Seed int64 `json:"seed" yaml:"seed"`
+	// Quota is left for every member, defaults to 1000
+	Quota int `json:"quota" yaml:"quota"`
+	// Core applies to every core endpoint
+	Core Behavior `json:"core" yaml:"core"`
+	// Products are keyed by product slug, e.g. "IDENTITY_recycle_number"
+	Products map[string]*Product `json:"products" yaml:"products"`
+	// Routes are keyed by a ServeMux pattern, e.g.
+	// "GET /api/core/member/by", and take precedence over the built-in
+	// core endpoints
+	Routes map[string]Route `json:"routes" yaml:"routes"`
+}
+
+// LoadScenario reads a YAML or JSON scenario, an empty path gives the
+// default scenario.
+func LoadScenario(path string) (*Scenario, error) {
+	scenario := &Scenario{}
+	if path == "" {
+		return scenario, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scenario: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(scenario)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		err = dec.Decode(scenario)
+	default:
+		return nil, fmt.Errorf("unsupported scenario file %s, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode scenario: %w", err)
+	}
+
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+func (s *Scenario) validate() error {
+	if err := s.Core.validate(); err != nil {
+		return fmt.Errorf("core: %w", err)
+	}
+
+	for slug, product := range s.Products {
+		if _, ok := catalogBySlug[slug]; !ok {
+			return fmt.Errorf("unknown product slug %s", slug)
+		}
+		if product == nil {
+			continue
+		}
+		if err := product.validate(); err != nil {
+			return fmt.Errorf("product %s: %w", slug, err)
+		}
+	}
+
+	for pattern := range s.Routes {
+		if err := checkPattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b Behavior) validate() error {
+	if b.LatencyMs < 0 || b.JitterMs < 0 {
+		return fmt.Errorf("latency and jitter cannot be negative")
+	}
+	if b.ErrorRate < 0 || b.ErrorRate > 1 {
+		return fmt.Errorf("error rate must be between 0 and 1")
+	}
+	if b.ErrorStatus != 0 && (b.ErrorStatus < 400 || b.ErrorStatus > 599) {
+		return fmt.Errorf("error status must be between 400 and 599")
+	}
+
+	return nil
+}
+
+func (p *Product) validate() error {
+	if err := p.Behavior.validate(); err != nil {
+		return err
+	}
+
+	for i, fixture := range p.Fixtures {
+		if fixture.Status != 0 && (fixture.Status < 100 || fixture.Status > 599) {
+			return fmt.Errorf("fixture %d has an invalid status %d", i, fixture.Status)
+		}
+	}
+
+	return nil
+}
+
+// checkPattern registers the pattern on a throwaway mux, which panics on an
+// invalid pattern.
+func checkPattern(pattern string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid route %q: %v", pattern, r)
+		}
+	}()
+
+	noop := func(http.ResponseWriter, *http.Request) {}
+	http.NewServeMux().HandleFunc(pattern, noop)
+
+	return nil
+}
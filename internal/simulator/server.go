@@ -0,0 +1,510 @@
+// Package simulator stands in for the core service, the datahub partners
+// and scoreezy, so the front office runs in local development and in
+// integration tests without external services. It keeps the jobs and logs
+// of the product requests in memory and answers the partners from fixtures,
+// with scriptable latency and error rates per product slug.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"front-office/internal/core/log/operation"
+	"front-office/internal/core/log/transaction"
+	"front-office/internal/datahub/companylitigation/review"
+	"front-office/pkg/common/constant"
+	"io"
+	"maps"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultErrorStatus = http.StatusServiceUnavailable
+
+// Server is the simulator as an http.Handler.
+type Server struct {
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	scenario *Scenario
+	routes   *http.ServeMux
+	rand     *rand.Rand
+
+	nextJobId    uint
+	nextTrxId    int
+	jobs         map[uint]map[string]any
+	transactions []transaction.LogTransProCatRequest
+	scoreezyLogs []map[string]any
+	operations   []operation.AddLogRequest
+	// reviewSettings are keyed by company id, hitReviews by company id,
+	// transaction id and case number
+	reviewSettings map[string]review.Settings
+	hitReviews     map[string]map[string]map[string]review.Review
+	// subjects are the monitored subjects keyed by id, alerts are kept in
+	// the order they were raised
+	nextSubjectId uint
+	subjects      map[uint]map[string]any
+	alerts        []map[string]any
+}
+
+// New returns a simulator playing the scenario, a nil scenario plays the
+// built-in fixtures without latency or errors.
+func New(scenario *Scenario) (*Server, error) {
+	s := &Server{
+		mux:            http.NewServeMux(),
+		jobs:           map[uint]map[string]any{},
+		reviewSettings: map[string]review.Settings{},
+		hitReviews:     map[string]map[string]map[string]review.Review{},
+		subjects:       map[uint]map[string]any{},
+	}
+
+	if err := s.SetScenario(scenario); err != nil {
+		return nil, err
+	}
+
+	s.mux.HandleFunc("GET /api/core/member/subscribed-product/{slug}", s.handleSubscribedProduct)
+	s.mux.HandleFunc("GET /api/core/member/quota", s.handleQuota)
+	// the product, grades and job lookups share their path shape, which
+	// ServeMux cannot tell apart by pattern
+	s.mux.HandleFunc("GET /api/core/product/{first}/{second}", s.handleProductLookup)
+	s.mux.HandleFunc("POST /api/core/product/jobs", s.handleCreateJob)
+	s.mux.HandleFunc("PUT /api/core/product/jobs/{id}", s.handleUpdateJob)
+	s.mux.HandleFunc("POST /api/core/logging/transaction/product-catalog", s.handleLogTransaction)
+	s.mux.HandleFunc("GET /api/core/logging/transaction/product-catalog/{jobId}/processed_count", s.handleProcessedCount)
+	s.mux.HandleFunc("POST /api/core/logging/transaction/scoreezy", s.handleLogScoreezy)
+	s.mux.HandleFunc("POST /api/core/logging/operation", s.handleLogOperation)
+	s.mux.HandleFunc("GET /api/core/negative-record/settings/{companyId}", s.handleGetReviewSettings)
+	s.mux.HandleFunc("PUT /api/core/negative-record/settings/{companyId}", s.handleSaveReviewSettings)
+	s.mux.HandleFunc("GET /api/core/negative-record/reviews/{companyId}", s.handleGetHitReviews)
+	s.mux.HandleFunc("PUT /api/core/negative-record/reviews/{companyId}/{trxId}", s.handleSaveHitReview)
+	s.mux.HandleFunc("POST /api/core/monitoring/subjects", s.handleCreateSubject)
+	s.mux.HandleFunc("GET /api/core/monitoring/subjects", s.handleGetSubjects)
+	s.mux.HandleFunc("GET /api/core/monitoring/schedule", s.handleGetSubjects)
+	s.mux.HandleFunc("GET /api/core/monitoring/subjects/{id}", s.handleGetSubject)
+	s.mux.HandleFunc("PUT /api/core/monitoring/subjects/{id}", s.handleUpdateSubject)
+	s.mux.HandleFunc("DELETE /api/core/monitoring/subjects/{id}", s.handleDeleteSubject)
+	s.mux.HandleFunc("POST /api/core/monitoring/alerts", s.handleCreateAlerts)
+	s.mux.HandleFunc("GET /api/core/monitoring/alerts", s.handleGetAlerts)
+
+	for i := range catalog {
+		product := &catalog[i]
+		s.mux.HandleFunc("POST "+product.path, func(w http.ResponseWriter, r *http.Request) {
+			s.handlePartner(w, r, product)
+		})
+	}
+
+	s.mux.HandleFunc("PUT /simulator/scenario", s.handleSetScenario)
+	s.mux.HandleFunc("PUT /simulator/products/{slug}", s.handleSetProduct)
+
+	return s, nil
+}
+
+// SetScenario replaces the scenario, the jobs and logs are kept.
+func (s *Server) SetScenario(scenario *Scenario) error {
+	if scenario == nil {
+		scenario = &Scenario{}
+	}
+	if err := scenario.validate(); err != nil {
+		return err
+	}
+
+	routes := http.NewServeMux()
+	for pattern, route := range scenario.Routes {
+		routes.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			status := route.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			writeCore(w, status, route.Data)
+		})
+	}
+
+	seed := scenario.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scenario = scenario
+	s.routes = routes
+	s.rand = rand.New(rand.NewSource(seed))
+
+	return nil
+}
+
+// SetProduct scripts the partner of one product slug.
+func (s *Server) SetProduct(slug string, product *Product) error {
+	s.mu.Lock()
+	scenario := *s.scenario
+	s.mu.Unlock()
+
+	scenario.Products = maps.Clone(scenario.Products)
+	if scenario.Products == nil {
+		scenario.Products = map[string]*Product{}
+	}
+	scenario.Products[slug] = product
+
+	return s.SetScenario(&scenario)
+}
+
+// Transactions returns the product catalog transactions logged so far, by
+// the front office or by the simulated partners.
+func (s *Server) Transactions() []transaction.LogTransProCatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]transaction.LogTransProCatRequest(nil), s.transactions...)
+}
+
+// Operations returns the operations logged so far.
+func (s *Server) Operations() []operation.AddLogRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]operation.AddLogRequest(nil), s.operations...)
+}
+
+// Job returns the fields of a job as last updated, nil when unknown.
+func (s *Server) Job(id uint) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.jobs[id])
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	routes := s.routes
+	s.mu.Unlock()
+
+	if handler, pattern := routes.Handler(r); pattern != "" {
+		if !s.core(w) {
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// misbehave waits the latency of the behavior and answers with the error
+// status when the error is drawn, it reports whether the request failed.
+func (s *Server) misbehave(w http.ResponseWriter, behavior Behavior) bool {
+	s.mu.Lock()
+	delay := time.Duration(behavior.LatencyMs) * time.Millisecond
+	if behavior.JitterMs > 0 {
+		delay += time.Duration(s.rand.Intn(behavior.JitterMs+1)) * time.Millisecond
+	}
+	failed := behavior.ErrorRate > 0 && s.rand.Float64() < behavior.ErrorRate
+	s.mu.Unlock()
+
+	time.Sleep(delay)
+
+	if !failed {
+		return false
+	}
+
+	status := behavior.ErrorStatus
+	if status == 0 {
+		status = defaultErrorStatus
+	}
+	writeJSON(w, status, map[string]any{"success": false, "message": "simulated error"})
+
+	return true
+}
+
+func (s *Server) core(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	behavior := s.scenario.Core
+	s.mu.Unlock()
+
+	return !s.misbehave(w, behavior)
+}
+
+func (s *Server) handleSubscribedProduct(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	product, ok := catalogBySlug[r.PathValue("slug")]
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	companyId, _ := strconv.Atoi(r.Header.Get(constant.XCompanyId))
+	id := productId(product.slug)
+	writeCore(w, http.StatusOK, map[string]any{
+		"subscribed_product_id": 100 + id,
+		"company_id":            companyId,
+		"product_id":            id,
+		"product":               productData(product),
+	})
+}
+
+func (s *Server) handleQuota(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	s.mu.Lock()
+	quota := s.scenario.Quota
+	s.mu.Unlock()
+
+	if quota == 0 {
+		quota = defaultQuota
+	}
+
+	writeCore(w, http.StatusOK, map[string]any{"quota": quota})
+}
+
+func (s *Server) handleProductLookup(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.PathValue("first") == "slug":
+		s.handleProduct(w, r.PathValue("second"))
+	case r.PathValue("first") == "jobs":
+		s.handleGetJob(w, r.PathValue("second"))
+	case r.PathValue("second") == "grades":
+		s.handleGrades(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleProduct(w http.ResponseWriter, slug string) {
+	if !s.core(w) {
+		return
+	}
+
+	product, ok := catalogBySlug[slug]
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, productData(product))
+}
+
+func (s *Server) handleGrades(w http.ResponseWriter) {
+	if !s.core(w) {
+		return
+	}
+
+	writeCore(w, http.StatusOK, map[string]any{"grades": []map[string]any{
+		{"grade": "A", "start": 0, "end": 0.2},
+		{"grade": "B", "start": 0.2, "end": 0.4},
+		{"grade": "C", "start": 0.4, "end": 0.6},
+		{"grade": "D", "start": 0.6, "end": 0.8},
+		{"grade": "E", "start": 0.8, "end": 1},
+	}})
+}
+
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var job map[string]any
+	if !decodeBody(w, r, &job) {
+		return
+	}
+
+	s.mu.Lock()
+	s.nextJobId++
+	id := s.nextJobId
+	job["id"] = id
+	job["status"] = constant.JobStatusInProgress
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, map[string]any{"job_id": id})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, id string) {
+	if !s.core(w) {
+		return
+	}
+
+	jobId, _ := strconv.ParseUint(id, 10, 64)
+	job := s.Job(uint(jobId))
+	if job == nil {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, job)
+}
+
+func (s *Server) handleUpdateJob(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var update map[string]any
+	if !decodeBody(w, r, &update) {
+		return
+	}
+
+	id := pathId(r, "id")
+
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if ok {
+		maps.Copy(job, update)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeCore(w, http.StatusNotFound, nil)
+		return
+	}
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleLogTransaction(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var entry transaction.LogTransProCatRequest
+	if !decodeBody(w, r, &entry) {
+		return
+	}
+
+	s.mu.Lock()
+	s.transactions = append(s.transactions, entry)
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleProcessedCount(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	jobId := pathId(r, "jobId")
+
+	s.mu.Lock()
+	count := 0
+	for _, entry := range s.transactions {
+		if entry.JobID == jobId {
+			count++
+		}
+	}
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, map[string]any{"processed_count": count})
+}
+
+func (s *Server) handleLogScoreezy(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var entry map[string]any
+	if !decodeBody(w, r, &entry) {
+		return
+	}
+
+	s.mu.Lock()
+	s.scoreezyLogs = append(s.scoreezyLogs, entry)
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleLogOperation(w http.ResponseWriter, r *http.Request) {
+	if !s.core(w) {
+		return
+	}
+
+	var entry operation.AddLogRequest
+	if !decodeBody(w, r, &entry) {
+		return
+	}
+
+	s.mu.Lock()
+	s.operations = append(s.operations, entry)
+	s.mu.Unlock()
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleSetScenario(w http.ResponseWriter, r *http.Request) {
+	var scenario Scenario
+	if !decodeBody(w, r, &scenario) {
+		return
+	}
+
+	if err := s.SetScenario(&scenario); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": err.Error()})
+		return
+	}
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleSetProduct(w http.ResponseWriter, r *http.Request) {
+	var product Product
+	if !decodeBody(w, r, &product) {
+		return
+	}
+
+	if err := s.SetProduct(r.PathValue("slug"), &product); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": err.Error()})
+		return
+	}
+
+	writeCore(w, http.StatusOK, nil)
+}
+
+func productData(product *catalogProduct) map[string]any {
+	return map[string]any{
+		"product_id":        productId(product.slug),
+		"product_group_id":  product.groupId,
+		"product_name":      product.name,
+		"product_slug_name": product.slug,
+	}
+}
+
+func pathId(r *http.Request, name string) uint {
+	id, _ := strconv.ParseUint(r.PathValue(name), 10, 64)
+
+	return uint(id)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": fmt.Sprintf("invalid body: %v", err)})
+		return false
+	}
+
+	return true
+}
+
+// writeCore answers in the core response format.
+func writeCore(w http.ResponseWriter, status int, data any) {
+	writeJSON(w, status, map[string]any{
+		"success": status < http.StatusBadRequest,
+		"message": http.StatusText(status),
+		"data":    data,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set(constant.HeaderContentType, constant.HeaderApplicationJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
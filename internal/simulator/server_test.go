@@ -0,0 +1,300 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"front-office/pkg/common/constant"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, scenario *Scenario) *TestServer {
+	t.Helper()
+
+	ts, err := NewTestServer(scenario)
+	require.NoError(t, err)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func call(t *testing.T, ts *TestServer, method, path string, body any) (int, map[string]any) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set(constant.XMemberId, "1")
+	req.Header.Set(constant.XCompanyId, "9")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+
+	return resp.StatusCode, decoded
+}
+
+func TestPartner(t *testing.T) {
+	t.Run("built-in fixtures", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		status, body := call(t, ts, http.MethodPost, "/product/identity/recycle-number?job_id=3", map[string]any{"phone_number": "628111111110", "loan_no": "L1"})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]any{"status": "phone number has been recycled"}, body["data"])
+		assert.Equal(t, constant.PaidStatus, body["pricing_strategy"])
+
+		_, body = call(t, ts, http.MethodPost, "/product/identity/recycle-number", map[string]any{"phone_number": "6281234567890"})
+		assert.Equal(t, map[string]any{"status": "phone number never happens recycled"}, body["data"])
+
+		// the partner logs its transactions on the core
+		logs := ts.Sim.Transactions()
+		require.Len(t, logs, 2)
+		assert.Equal(t, uint(3), logs[0].JobID)
+		assert.Equal(t, uint(9), logs[0].CompanyID)
+		assert.Equal(t, "L1", logs[0].LoanNo)
+	})
+
+	t.Run("scripted fixtures come first", func(t *testing.T) {
+		ts := startServer(t, &Scenario{Products: map[string]*Product{
+			constant.SlugTaxScore: {Fixtures: []Fixture{
+				{Match: map[string]string{"npwp": "0012345678901000"}, Data: map[string]any{"score": "E"}},
+				{Match: map[string]string{"npwp": "0000000000000000"}, Status: http.StatusNotFound, Message: "npwp not found"},
+			}},
+		}})
+
+		_, body := call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0012345678901000"})
+		assert.Equal(t, map[string]any{"score": "E"}, body["data"])
+
+		status, body := call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0000000000000000"})
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "npwp not found", body["message"])
+
+		_, body = call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0099999999999999"})
+		assert.Equal(t, "A", body["data"].(map[string]any)["score"])
+	})
+
+	t.Run("error rate", func(t *testing.T) {
+		ts := startServer(t, &Scenario{Seed: 1, Products: map[string]*Product{
+			constant.SlugTaxScore: {Behavior: Behavior{ErrorRate: 1, ErrorStatus: http.StatusGatewayTimeout}},
+		}})
+
+		status, body := call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0012345678901000"})
+		assert.Equal(t, http.StatusGatewayTimeout, status)
+		assert.Equal(t, false, body["success"])
+		assert.Empty(t, ts.Sim.Transactions())
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		ts := startServer(t, &Scenario{Products: map[string]*Product{
+			constant.SlugTaxScore: {Behavior: Behavior{LatencyMs: 50}},
+		}})
+
+		start := time.Now()
+		call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0012345678901000"})
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("scoreezy", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		_, body := call(t, ts, http.MethodPost, "/api/score/genretail/v3", map[string]any{"name": "Budi", "loan_no": "L1"})
+		data := body["data"].(map[string]any)
+		assert.Equal(t, "Budi", data["name"])
+		assert.Equal(t, "A", data["grade"])
+		assert.NotEmpty(t, data["transaction_id"])
+	})
+
+	t.Run("scripted at runtime", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		status, _ := call(t, ts, http.MethodPut, "/simulator/products/"+constant.SlugTaxScore, map[string]any{"error_rate": 1})
+		require.Equal(t, http.StatusOK, status)
+
+		status, _ = call(t, ts, http.MethodPost, "/product/incometax/tax-score", map[string]any{"npwp": "0012345678901000"})
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+
+		status, _ = call(t, ts, http.MethodPut, "/simulator/products/UNKNOWN", map[string]any{})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestCore(t *testing.T) {
+	t.Run("jobs", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		_, body := call(t, ts, http.MethodGet, "/api/core/member/subscribed-product/"+constant.SlugRecycleNumber, nil)
+		subscribed := body["data"].(map[string]any)
+		assert.Equal(t, float64(productId(constant.SlugRecycleNumber)), subscribed["product_id"])
+
+		_, body = call(t, ts, http.MethodPost, "/api/core/product/jobs", map[string]any{"total": 2})
+		jobId := body["data"].(map[string]any)["job_id"]
+		assert.Equal(t, float64(1), jobId)
+
+		call(t, ts, http.MethodPost, "/product/identity/recycle-number?job_id=1", map[string]any{"phone_number": "6281234567890"})
+		_, body = call(t, ts, http.MethodGet, "/api/core/logging/transaction/product-catalog/1/processed_count", nil)
+		assert.Equal(t, float64(1), body["data"].(map[string]any)["processed_count"])
+
+		status, _ := call(t, ts, http.MethodPut, "/api/core/product/jobs/1", map[string]any{"status": constant.JobStatusDone})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, constant.JobStatusDone, ts.Sim.Job(1)["status"])
+
+		_, body = call(t, ts, http.MethodGet, "/api/core/product/jobs/1", nil)
+		assert.Equal(t, float64(2), body["data"].(map[string]any)["total"])
+
+		_, body = call(t, ts, http.MethodGet, "/api/core/product/"+constant.SlugGenRetailV3+"/grades", nil)
+		assert.Len(t, body["data"].(map[string]any)["grades"], 5)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		status, _ := call(t, ts, http.MethodGet, "/api/core/member/subscribed-product/UNKNOWN", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		ts := startServer(t, &Scenario{Routes: map[string]Route{
+			"GET /api/core/member/by":    {Data: map[string]any{"member_id": 1}},
+			"GET /api/core/member/quota": {Data: map[string]any{"quota": 0}},
+		}})
+
+		_, body := call(t, ts, http.MethodGet, "/api/core/member/by", nil)
+		assert.Equal(t, map[string]any{"member_id": float64(1)}, body["data"])
+
+		_, body = call(t, ts, http.MethodGet, "/api/core/member/quota", nil)
+		assert.Equal(t, map[string]any{"quota": float64(0)}, body["data"])
+	})
+
+	t.Run("negative record reviews", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		status, _ := call(t, ts, http.MethodGet, "/api/core/negative-record/settings/9", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		call(t, ts, http.MethodPut, "/api/core/negative-record/settings/9", map[string]any{"company_id": 9, "similarity_threshold": 80})
+		_, body := call(t, ts, http.MethodGet, "/api/core/negative-record/settings/9", nil)
+		assert.Equal(t, float64(80), body["data"].(map[string]any)["similarity_threshold"])
+
+		call(t, ts, http.MethodPut, "/api/core/negative-record/reviews/9/CNR-1", map[string]any{"case_number": "12", "decision": "dismissed"})
+		call(t, ts, http.MethodPut, "/api/core/negative-record/reviews/9/CNR-1", map[string]any{"case_number": "12", "decision": "confirmed"})
+		call(t, ts, http.MethodPut, "/api/core/negative-record/reviews/9/CNR-2", map[string]any{"case_number": "98", "decision": "dismissed"})
+
+		_, body = call(t, ts, http.MethodGet, "/api/core/negative-record/reviews/9?transaction_ids=CNR-1", nil)
+		reviews := body["data"].([]any)
+		require.Len(t, reviews, 1)
+		assert.Equal(t, "confirmed", reviews[0].(map[string]any)["decision"])
+		assert.Equal(t, "CNR-1", reviews[0].(map[string]any)["transaction_id"])
+	})
+
+	t.Run("monitoring", func(t *testing.T) {
+		ts := startServer(t, nil)
+
+		_, body := call(t, ts, http.MethodPost, "/api/core/monitoring/subjects", map[string]any{"company_id": 9, "products": []string{"phone-live-status"}})
+		assert.Equal(t, float64(1), body["data"].(map[string]any)["subject_id"])
+		call(t, ts, http.MethodPost, "/api/core/monitoring/subjects", map[string]any{"company_id": 3})
+
+		_, body = call(t, ts, http.MethodGet, "/api/core/monitoring/subjects?company_id=9", nil)
+		assert.Len(t, body["data"], 1)
+		_, body = call(t, ts, http.MethodGet, "/api/core/monitoring/schedule", nil)
+		assert.Len(t, body["data"], 2)
+
+		call(t, ts, http.MethodPut, "/api/core/monitoring/subjects/1", map[string]any{"snapshots": map[string]any{"phone-live-status": map[string]any{"live_status": "active"}}})
+		_, body = call(t, ts, http.MethodGet, "/api/core/monitoring/subjects/1", nil)
+		assert.NotNil(t, body["data"].(map[string]any)["snapshots"])
+
+		call(t, ts, http.MethodPost, "/api/core/monitoring/alerts", []map[string]any{{"company_id": 9, "subject_id": 1, "field": "live_status"}})
+		_, body = call(t, ts, http.MethodGet, "/api/core/monitoring/alerts?company_id=9&subject_id=1", nil)
+		assert.Len(t, body["data"], 1)
+		_, body = call(t, ts, http.MethodGet, "/api/core/monitoring/alerts?company_id=3", nil)
+		assert.Empty(t, body["data"])
+
+		status, _ := call(t, ts, http.MethodDelete, "/api/core/monitoring/subjects/1", nil)
+		assert.Equal(t, http.StatusOK, status)
+		status, _ = call(t, ts, http.MethodGet, "/api/core/monitoring/subjects/1", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("core errors", func(t *testing.T) {
+		ts := startServer(t, &Scenario{Core: Behavior{ErrorRate: 1}})
+
+		status, _ := call(t, ts, http.MethodPost, "/api/core/product/jobs", map[string]any{})
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	})
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("yaml", func(t *testing.T) {
+		path := filepath.Join(dir, "scenario.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+seed: 7
+products:
+  IDENTITY_recycle_number:
+    latency_ms: 200
+    error_rate: 0.1
+    fixtures:
+      - match: {phone_number: "628111111111"}
+        data: {status: phone number has been recycled}
+routes:
+  GET /api/core/member/by:
+    data: {member_id: 1}
+`), 0o600))
+
+		scenario, err := LoadScenario(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), scenario.Seed)
+		product := scenario.Products[constant.SlugRecycleNumber]
+		assert.Equal(t, 200, product.LatencyMs)
+		assert.Equal(t, 0.1, product.ErrorRate)
+		assert.Equal(t, "628111111111", product.Fixtures[0].Match["phone_number"])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "scenario.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"core": {"latency_ms": 5}}`), 0o600))
+
+		scenario, err := LoadScenario(path)
+		require.NoError(t, err)
+		assert.Equal(t, 5, scenario.Core.LatencyMs)
+	})
+
+	t.Run("example", func(t *testing.T) {
+		_, err := LoadScenario("../../cmd/simulator/scenario.example.yaml")
+		assert.NoError(t, err)
+	})
+
+	invalid := map[string]string{
+		"unknown.json":  `{"products": {"UNKNOWN": {}}}`,
+		"rate.json":     `{"core": {"error_rate": 2}}`,
+		"route.json":    `{"routes": {"GET": {}}}`,
+		"field.yaml":    `latency: 5`,
+		"scenario.toml": ``,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			_, err := LoadScenario(path)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package simulator
+
+import (
+	"front-office/configs/application"
+	"net/http/httptest"
+)
+
+// TestServer runs the simulator in process.
+type TestServer struct {
+	*httptest.Server
+
+	Sim *Server
+}
+
+// NewTestServer starts a simulator playing the scenario. Call Close when
+// done.
+func NewTestServer(scenario *Scenario) (*TestServer, error) {
+	sim, err := New(scenario)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestServer{
+		Server: httptest.NewServer(sim),
+		Sim:    sim,
+	}, nil
+}
+
+// Config points the core, datahub and scoreezy hosts at the simulator.
+func (ts *TestServer) Config() *application.Config {
+	return &application.Config{App: &application.Environment{
+		AifcoreHost:        ts.URL,
+		ProductCatalogHost: ts.URL,
+		ScoreezyHost:       ts.URL,
+	}}
+}
@@ -6,6 +6,7 @@ const (
 	JobStatusDone       = "done"
 	JobStatusFailed     = "failed"
 	JobStatusError      = "error"
+	JobStatusPartial    = "partial"
 
 	FormatDateAndTime = "2006-01-02 15:04:05"
 	FormatYYYYMMDD    = "2006-01-02"
@@ -22,8 +23,11 @@ const (
 	InvalidJSON              = `{invalid-json`
 
 	PaidStatus = "PAY"
+	FreeStatus = "FREE"
 )
 
 const (
 	MimeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MimeZip  = "application/zip"
+	MimePdf  = "application/pdf"
 )
@@ -16,6 +16,7 @@ const (
 	CSVHeaderPhone                = "Phone Number"
 	CSVHeaderLoanNumber           = "Loan Number"
 	CSVHeaderNPWP                 = "NPWP"
+	CSVHeaderNPWPOrNIK            = "NPWP or NIK"
 	CSVHeaderRemarks              = "Remarks"
 	CSVHeaderQueryCount           = "Query Count"
 	CSVHeaderDataStatus           = "Data Status"
@@ -34,6 +35,12 @@ const (
 	CSVHeaderIdentity             = "Identity"
 	CSVHeaderStatus               = "Status"
 	CSVHeaderDescription          = "Description"
+	CSVHeaderDecision             = "Decision"
+	CSVHeaderFiredRules           = "Fired Rules"
+	CSVHeaderMatchedCompanies     = "Matched Companies"
+	CSVHeaderCaseNumbers          = "Case Numbers"
+	CSVHeaderConfirmedCases       = "Confirmed Cases"
+	CSVHeaderReviewStatus         = "Review Status"
 )
 
 // CSV Template Header
@@ -77,6 +84,11 @@ var CSVTemplateHeaderTaxVerification = []string{
 	CSVHeaderLoanNumber,
 }
 
+var CSVTemplateHeaderTaxReport = []string{
+	CSVHeaderNPWPOrNIK,
+	CSVHeaderLoanNumber,
+}
+
 var CSVTemplateHeaderNPWPVerification = []string{
 	CSVHeaderNPWP,
 	CSVHeaderLoanNumber,
@@ -176,6 +188,17 @@ var CSVExportHeaderRecycleNumber = []string{
 	CSVHeaderDescription,
 }
 
+var CSVExportHeaderNegativeRecord = []string{
+	CSVHeaderLoanNumber,
+	CSVHeaderCompanyName,
+	CSVHeaderMatchedCompanies,
+	CSVHeaderCaseNumbers,
+	CSVHeaderReviewStatus,
+	CSVHeaderConfirmedCases,
+	CSVHeaderStatus,
+	CSVHeaderDescription,
+}
+
 var CSVExportHeaderPhoneNIk = []string{
 	CSVHeaderLoanNumber,
 	CSVHeaderPhone,
@@ -197,3 +220,9 @@ var CSVExportHeaderGenRetail = []string{
 	CSVHeaderIdentity,
 	CSVHeaderDescription,
 }
+
+// CSVExportHeaderDecision is appended to job exports with decided rows.
+var CSVExportHeaderDecision = []string{
+	CSVHeaderDecision,
+	CSVHeaderFiredRules,
+}
@@ -33,6 +33,13 @@ const (
 	TokenExpired               = "Token is expired"
 	BcryptPasswordMismatch     = "crypto/bcrypt: hashedPassword is not the hash of the given password"
 	WrongCurrentPassword       = "current password is wrong"
+	SSONotConfigured           = "single sign-on is not configured for this company"
+	InvalidSSOState            = "invalid or expired single sign-on session"
+	SSOLoginFailed             = "single sign-on login failed"
+	SSOUserNotAllowed          = "your identity provider account is not linked to an active member"
+	PasswordRecentlyUsed       = "password must not match any of your last %d passwords"
+	PasswordExpired            = "your password has expired, please change your password"
+	PasswordExpiresSoon        = "your password will expire in %d day(s), please change your password"
 
 	//grading
 	DuplicateGrading       = "duplicate grading"
@@ -77,4 +84,127 @@ const (
 	ErrQuotaExceeded          = "insufficient quota to complete request"
 	ErrFetchPhoneLiveDetail   = "failed to fetch phone live status job detail"
 	ErrFetchJobMetrics        = "failed to fetch job metrics"
+
+	// personal data
+	DeletionRequestNotFound        = "deletion request not found"
+	DeletionRequestAlreadyPending  = "you already have a pending deletion request"
+	DeletionRequestAlreadyReviewed = "deletion request has already been reviewed"
+	FailedFetchDeletionRequest     = "failed to fetch deletion request"
+
+	// api client
+	APIClientNotFound         = "api client not found"
+	APIClientAlreadyRevoked   = "api client has already been revoked"
+	InvalidAPIClientAuth      = "invalid api client credentials"
+	ExpiredAPIClientSignature = "api client signature timestamp is outside the allowed window"
+	APIClientScopeDenied      = "api client is not allowed to perform this request"
+	APIClientProductDenied    = "api client is not allowed to access this product"
+	InvalidAPIClientScope     = "unknown api client scope: %s"
+	FailedFetchAPIClient      = "failed to fetch api client"
+
+	// impersonation
+	NotInternalStaff          = "only internal staff can impersonate members"
+	ImpersonationReadOnly     = "impersonation sessions are read-only"
+	AlreadyImpersonating      = "end the current impersonation session before starting another"
+	NotImpersonating          = "no active impersonation session"
+	ImpersonationEnded        = "impersonation session has ended"
+	ImpersonationNoExport     = "exports are not available while impersonating"
+	InvalidImpersonationTime  = "duration_minutes must be between 1 and %d"
+	CannotImpersonateInactive = "inactive members cannot be impersonated"
+
+	// usage report schedule
+	InvalidReportRecipient    = "invalid report recipient email: %s"
+	InvalidReportDeliveryDay  = "delivery_day must be between 1 and %d"
+	InvalidReportDeliveryTime = "delivery_time must use the HH:MM format"
+	FailedFetchReportSchedule = "failed to fetch usage report schedule"
+
+	// invoice
+	InvalidInvoiceFormat   = "format must be pdf or xlsx"
+	InvoicePeriodNotClosed = "invoices are only issued for months that have ended"
+	FailedFetchInvoice     = "failed to fetch invoice"
+
+	// billing policy
+	FailedFetchBillingPolicy = "failed to fetch billing policy"
+
+	// workbook password
+	WorkbookPasswordNotFound  = "workbook password not found"
+	WorkbookPasswordExpired   = "workbook password has expired"
+	WorkbookPasswordRetrieved = "workbook password was already retrieved"
+
+	// usage breakdown
+	InvalidUsageRange         = "end_date must not be before start_date"
+	UsageRangeTooLong         = "date range must not exceed %d days"
+	InvalidUsageGroupBy       = "group_by must be one of day, week, month, member, product"
+	InvalidMemberIds          = "member_ids must be a comma separated list of member ids"
+	FailedFetchUsageBreakdown = "failed to fetch usage breakdown"
+
+	// reconciliation
+	FailedFetchJobs = "failed to fetch jobs"
+
+	// balance top-up
+	InvalidTopupAmount         = "amount must be at least %s"
+	InvalidPaidAtDate          = "invalid paid_at format, use YYYY-MM-DD"
+	InvalidPaymentProofFile    = "payment proof must be a jpg, png or pdf file"
+	PaymentProofTooLarge       = "payment proof should not exceed 2 MB"
+	FailedToUploadPaymentProof = "failed to upload payment proof"
+	TopupNotFound              = "top-up not found"
+	TopupExpired               = "the payment window of this top-up has closed"
+	TopupNotAwaitingPayment    = "payment can only be confirmed for unpaid or rejected top-ups"
+	TopupNotAwaitingApproval   = "top-up is not waiting for approval"
+	TopupRejectReasonRequired  = "note is required when rejecting a top-up"
+	PaymentProofNotFound       = "payment proof not found"
+	NotInternalTeam            = "only internal team members can review top-ups"
+	FailedFetchTopup           = "failed to fetch top-up"
+
+	// applicant check
+	ApplicantCheckProductsRequired = "products cannot be empty"
+	UnknownApplicantCheckProduct   = "unknown product %s"
+	InvalidApplicantCheckFormat    = "format must be pdf or csv"
+	ApplicantCheckNotFound         = "applicant check not found"
+	FailedCreateApplicantCheck     = "failed to create applicant check"
+	FailedFetchApplicantCheck      = "failed to fetch applicant check"
+
+	// tax report
+	TaxReportTaxpayersRequired = "taxpayers cannot be empty"
+	InvalidTaxReportTaxpayer   = "taxpayer %d: %s"
+	TaxReportLoanNoRequired    = "loan_no cannot be empty"
+	NoTaxProductSubscribed     = "no tax product is subscribed"
+	TaxReportNotFound          = "tax report not found"
+	FailedCreateTaxReport      = "failed to create tax report"
+	FailedFetchTaxReport       = "failed to fetch tax report"
+
+	// decision rules
+	InvalidDecision          = "decision must be one of approve, review, reject"
+	InvalidDecisionOperator  = "operator must be one of eq, neq, gt, gte, lt, lte, in, contains"
+	DecisionRuleNameRequired = "every rule needs a name"
+	DecisionRuleNoConditions = "rule %s has no conditions"
+	InvalidDecisionCondition = "rule %s has a condition without product or field"
+	DuplicateDecisionRule    = "duplicate rule: %s"
+	FailedFetchDecisionRules = "failed to fetch decision rules"
+	FailedSaveDecisionRules  = "failed to save decision rules"
+
+	// negative record review
+	InvalidSimilarityThreshold     = "similarity threshold must be between 0 and 100"
+	InvalidHitReviewDecision       = "decision must be one of confirmed, dismissed"
+	FailedFetchReviewSettings      = "failed to fetch review settings"
+	FailedSaveReviewSettings       = "failed to save review settings"
+	FailedFetchHitReviews          = "failed to fetch hit reviews"
+	FailedSaveHitReview            = "failed to save hit review"
+	ReviewedTransactionNotFound    = "negative record transaction not found"
+	FailedFetchReviewedTransaction = "failed to fetch negative record transaction"
+	UnknownHitCaseNumber           = "case number is not a hit of the transaction"
+
+	// monitoring
+	MonitoredProductsRequired    = "products cannot be empty"
+	UnmonitoredProduct           = "product %s cannot be monitored"
+	MonitoredSubjectMissingField = "%s requires %s"
+	InvalidMonitoringInterval    = "interval days must be between 1 and 30"
+	MonitoredSubjectNotFound     = "monitored subject not found"
+	FailedCreateMonitoredSubject = "failed to create monitored subject"
+	FailedFetchMonitoredSubjects = "failed to fetch monitored subjects"
+	FailedDeleteMonitoredSubject = "failed to delete monitored subject"
+	FailedFetchMonitoringAlerts  = "failed to fetch monitoring alerts"
+
+	// subject history
+	SubjectHistoryIdentifierRequired = "at least one of nik, phone_number, npwp or loan_no is required"
+	FailedFetchSubjectHistory        = "failed to fetch subject history"
 )
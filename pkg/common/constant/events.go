@@ -2,8 +2,9 @@ package constant
 
 const (
 	// auth
-	EventSignIn  = "sign in"
-	EventSignOut = "sign out"
+	EventSignIn    = "sign in"
+	EventSignInSSO = "sign in sso"
+	EventSignOut   = "sign out"
 
 	// user
 	EventChangePassword       = "change password"
@@ -14,11 +15,36 @@ const (
 	EventUpdateUserData       = "update user data"
 	EventActivateUser         = "activate user"
 	EventInactivateUser       = "inactivate user"
+	EventUpdatePasswordPolicy = "update password policy"
+
+	// personal data
+	EventRequestDataExport      = "request data export"
+	EventRequestAccountDeletion = "request account deletion"
+	EventApproveAccountDeletion = "approve account deletion"
+	EventRejectAccountDeletion  = "reject account deletion"
+
+	// api client
+	EventCreateAPIClient       = "create api client"
+	EventRotateAPIClientSecret = "rotate api client secret"
+	EventRevokeAPIClient       = "revoke api client"
+
+	// impersonation
+	EventStartImpersonation  = "start impersonation"
+	EventEndImpersonation    = "end impersonation"
+	EventImpersonatedRequest = "impersonated request"
 
 	// billing
 	EventChangeBillingInformation  = "update billing information"
 	EventTopupBalance              = "topup balance"
 	EventSubmitPaymentConfirmation = "submit payment confirmation"
+	EventUpdateReportSchedule      = "update usage report schedule"
+	EventUpdateQuotaAlert          = "update quota alert"
+	EventIssueWorkbookPassword     = "issue workbook password"
+	EventSendWorkbookPassword      = "send workbook password"
+	EventRetrieveWorkbookPassword  = "retrieve workbook password"
+
+	// decision rules
+	EventUpdateDecisionRules = "update decision rules"
 
 	// scoreezy
 	EventScoreezySingleReq       = "scoreezy single request"
@@ -80,6 +106,8 @@ const (
 	EventNegativeRecordBulkReq         = "negative record bulk request"
 	EventNegativeRecordDownload        = "negative record download result"
 	EventNegativeRecordDownloadSummary = "negative record download result summary"
+	EventNegativeRecordReviewHit       = "review negative record hit"
+	EventNegativeRecordThreshold       = "update negative record threshold"
 
 	// tax compliance status
 	EventTaxComplianceSingleReq       = "tax compliance single request"
@@ -98,4 +126,17 @@ const (
 	EventTaxVerificationBulkReq         = "tax verification bulk request"
 	EventTaxVerificationDownload        = "tax verification download result"
 	EventTaxVerificationDownloadSummary = "tax verification download result summary"
+
+	// applicant check
+	EventApplicantCheck         = "applicant check request"
+	EventApplicantCheckDownload = "applicant check download result"
+
+	// tax report
+	EventTaxReportReq      = "tax report request"
+	EventTaxReportBulkReq  = "tax report bulk request"
+	EventTaxReportDownload = "tax report download result"
+
+	// monitoring
+	EventMonitoringAddSubject    = "add monitored subject"
+	EventMonitoringRemoveSubject = "remove monitored subject"
 )
@@ -3,22 +3,31 @@ package constant
 const (
 	HeaderContentType        = "Content-Type"
 	HeaderContentDisposition = "Content-Disposition"
+	HeaderWorkbookPasswordId = "X-Workbook-Password-Id"
 	HeaderApplicationJSON    = "application/json"
 	XAPIKey                  = "X-API-KEY"
 	XUIDKey                  = "X-UID-KEY"
 	XMemberId                = "X-Member-ID"
 	XCompanyId               = "X-Company-ID"
 	XTierLevel               = "X-Tier-Level"
+	XClientId                = "X-Client-ID"
+	XTimestamp               = "X-Timestamp"
+	XSignature               = "X-Signature"
+	HeaderAuthorization      = "Authorization"
+	XImpersonatedBy          = "X-Impersonated-By"
+	XImpersonationExpiresAt  = "X-Impersonation-Expires-At"
 	TextOrCSVContentType     = "text/csv"
 
 	SizeUnlimited = "-1"
 
-	Request       = "request"
-	APIKey        = "apiKey"
-	UserId        = "userId"
-	CompanyId     = "companyId"
-	RoleId        = "roleId"
-	ValidatedFile = "validatedFile"
+	Request        = "request"
+	APIKey         = "apiKey"
+	UserId         = "userId"
+	CompanyId      = "companyId"
+	RoleId         = "roleId"
+	ValidatedFile  = "validatedFile"
+	APIClientId    = "apiClientId"
+	ImpersonatorId = "impersonatorId"
 
 	QuotaType  = "quota_type"
 	Page       = "page"
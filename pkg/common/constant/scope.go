@@ -0,0 +1,7 @@
+package constant
+
+// scopes granted to machine-to-machine api clients
+const (
+	ScopeProductRequest = "products:request"
+	ScopeJobRead        = "jobs:read"
+)
@@ -5,4 +5,5 @@ const (
 	TokenTypeActivation    = "activation"
 	TokenTypeRefresh       = "refresh"
 	TokenTypeResetPassword = "reset-password"
+	TokenTypeSSOState      = "sso-state"
 )
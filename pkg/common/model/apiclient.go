@@ -0,0 +1,33 @@
+package model
+
+import "slices"
+
+// APIClientCredential holds what an api client sent to authenticate, either a
+// bearer secret or an HMAC signature over the request.
+type APIClientCredential struct {
+	ClientId  string
+	Secret    string
+	Timestamp string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+func (c *APIClientCredential) IsSigned() bool {
+	return c.Signature != ""
+}
+
+type APIClientPrincipal struct {
+	AuthContext
+	Scopes       []string
+	ProductSlugs []string
+}
+
+func (p *APIClientPrincipal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func (p *APIClientPrincipal) AllowsProduct(slug string) bool {
+	return slices.Contains(p.ProductSlugs, slug)
+}
@@ -35,12 +35,20 @@ type ProCatAPIResponse[T any] struct {
 	PricingStrategy string      `json:"pricing_strategy"`
 	TransactionId   string      `json:"transaction_id"`
 	Date            string      `json:"datetime"`
+	Decision        *Decision   `json:"decision,omitempty"`
 }
 
 type ScoreezyAPIResponse[T any] struct {
-	Success      bool   `json:"success"`
-	Data         *T     `json:"data"`
-	Message      string `json:"message"`
-	ErrorMessage string `json:"error_message,omitempty"`
-	StatusCode   int    `json:"-"`
+	Success      bool      `json:"success"`
+	Data         *T        `json:"data"`
+	Message      string    `json:"message"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	StatusCode   int       `json:"-"`
+	Decision     *Decision `json:"decision,omitempty"`
+}
+
+// Decision is the outcome of the company decision rules for a request.
+type Decision struct {
+	Decision   string   `json:"decision"`
+	FiredRules []string `json:"fired_rules"`
 }
@@ -10,6 +10,14 @@ type AuthContext struct {
 	RoleId    uint
 	QuotaType uint
 	APIKey    string
+
+	// APIClientId is set when the request was made by a machine-to-machine
+	// api client instead of a browser session.
+	APIClientId string
+
+	// ImpersonatorId is the internal staff member viewing the application as
+	// this member, zero outside impersonation.
+	ImpersonatorId uint
 }
 
 func (a *AuthContext) UserIdStr() string {
@@ -62,11 +62,17 @@ func GetAuthContext(c *fiber.Ctx) (*model.AuthContext, error) {
 		return nil, fmt.Errorf("invalid or missing quota type")
 	}
 
+	// only present for requests authenticated by an api client
+	apiClientId, _ := c.Locals(constant.APIClientId).(string)
+	impersonatorId, _ := c.Locals(constant.ImpersonatorId).(uint)
+
 	return &model.AuthContext{
-		UserId:    userId,
-		CompanyId: companyId,
-		RoleId:    roleID,
-		APIKey:    apiKey,
-		QuotaType: quotaType,
+		UserId:         userId,
+		CompanyId:      companyId,
+		RoleId:         roleID,
+		APIKey:         apiKey,
+		QuotaType:      quotaType,
+		APIClientId:    apiClientId,
+		ImpersonatorId: impersonatorId,
 	}, nil
 }
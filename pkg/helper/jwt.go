@@ -37,6 +37,19 @@ func GenerateToken(
 	return t, nil
 }
 
+// GenerateTokenWithClaims signs a short lived token carrying arbitrary claims,
+// used for flows that are not tied to a member session.
+func GenerateTokenWithClaims(secret string, minutesToExpired int, tokenType string, extra map[string]any) (string, error) {
+	claims := jwt.MapClaims{}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	claims["token_type"] = tokenType
+	claims["exp"] = time.Now().Add(time.Duration(minutesToExpired) * time.Minute).Unix()
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
 func ExtractClaimsFromJWT(tokenStr, secret string) (*jwt.MapClaims, error) {
 	claims := &jwt.MapClaims{}
 
@@ -127,3 +140,11 @@ func ExtractApiKeyFromClaims(claims *jwt.MapClaims) (string, error) {
 func ExtractTokenTypeFromClaims(claims *jwt.MapClaims) (string, error) {
 	return extractStringClaim(claims, "token_type")
 }
+
+// ExtractImpersonatorIdFromClaims returns the internal staff member behind an
+// impersonation token, ok is false for regular member tokens.
+func ExtractImpersonatorIdFromClaims(claims *jwt.MapClaims) (uint, bool) {
+	id, err := extractUintClaim(claims, "impersonator_id")
+
+	return id, err == nil && id != 0
+}
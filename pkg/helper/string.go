@@ -58,33 +58,6 @@ func GenerateTrx(productInitial string) string {
 	)
 }
 
-func ValidatePasswordStrength(password string) bool {
-	var (
-		upp, low, num, sym bool
-	)
-
-	for _, char := range password {
-		switch {
-		case unicode.IsLower(char):
-			low = true
-		case unicode.IsUpper(char):
-			upp = true
-		case unicode.IsNumber(char):
-			num = true
-		case unicode.IsPunct(char) || unicode.IsSymbol(char):
-			sym = true
-		default:
-			return false
-		}
-	}
-
-	if !upp || !low || !num || !sym {
-		return false
-	}
-
-	return true
-}
-
 func ValidateDateYYYYMMDD(value string) error {
 	_, err := time.Parse("2006-01-02", value)
 	if err != nil {
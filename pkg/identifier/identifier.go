@@ -0,0 +1,265 @@
+// Package identifier normalizes and checks Indonesian personal identifiers,
+// phone numbers, NPWP and NIK, before they are sent to partner APIs.
+package identifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	phoneCountryCode = "62"
+	nikLength        = 16
+	npwpLength       = 16
+	npwpLegacyLength = 15
+
+	// female NIKs have 40 added to the birth day
+	femaleDayOffset = 40
+)
+
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+)
+
+var (
+	ErrInvalidPhone = errors.New("phone number must be an Indonesian mobile or fixed line number, e.g. 081234567890 or 0215551234")
+	ErrInvalidNPWP  = errors.New("NPWP must be 15 or 16 digits")
+	ErrInvalidNIK   = errors.New("NIK must be 16 digits")
+)
+
+// provinces are the first two digits of a NIK.
+var provinces = map[string]string{
+	"11": "Aceh",
+	"12": "Sumatera Utara",
+	"13": "Sumatera Barat",
+	"14": "Riau",
+	"15": "Jambi",
+	"16": "Sumatera Selatan",
+	"17": "Bengkulu",
+	"18": "Lampung",
+	"19": "Kepulauan Bangka Belitung",
+	"21": "Kepulauan Riau",
+	"31": "DKI Jakarta",
+	"32": "Jawa Barat",
+	"33": "Jawa Tengah",
+	"34": "DI Yogyakarta",
+	"35": "Jawa Timur",
+	"36": "Banten",
+	"51": "Bali",
+	"52": "Nusa Tenggara Barat",
+	"53": "Nusa Tenggara Timur",
+	"61": "Kalimantan Barat",
+	"62": "Kalimantan Tengah",
+	"63": "Kalimantan Selatan",
+	"64": "Kalimantan Timur",
+	"65": "Kalimantan Utara",
+	"71": "Sulawesi Utara",
+	"72": "Sulawesi Tengah",
+	"73": "Sulawesi Selatan",
+	"74": "Sulawesi Tenggara",
+	"75": "Gorontalo",
+	"76": "Sulawesi Barat",
+	"81": "Maluku",
+	"82": "Maluku Utara",
+	"91": "Papua",
+	"92": "Papua Barat",
+	"93": "Papua Selatan",
+	"94": "Papua Tengah",
+	"95": "Papua Pegunungan",
+	"96": "Papua Barat Daya",
+}
+
+// NIK is the information encoded in a national identity number.
+type NIK struct {
+	Province   string
+	Regency    string
+	District   string
+	BirthDay   int
+	BirthMonth int
+	// BirthYear is the last two digits of the year, the century is not encoded
+	BirthYear int
+	Gender    string
+	Serial    string
+}
+
+// NormalizePhone returns the phone number in the 62 prefixed form, e.g.
+// "0812-3456-7890" and "+62 812 3456 7890" both become "6281234567890".
+// Mobile and fixed line numbers are accepted, "(021) 555-1234" becomes
+// "62215551234". A number without a prefix is taken as a mobile number.
+func NormalizePhone(phone string) (string, error) {
+	digits := strip(phone, " -.()")
+	digits = strings.TrimPrefix(digits, "+")
+
+	switch {
+	case strings.HasPrefix(digits, phoneCountryCode):
+	case strings.HasPrefix(digits, "0"):
+		digits = phoneCountryCode + digits[1:]
+	case strings.HasPrefix(digits, "8"):
+		digits = phoneCountryCode + digits
+	default:
+		return "", ErrInvalidPhone
+	}
+
+	// the number after 62 is 8xx and 9 to 12 digits for mobile numbers, an
+	// area code of 2 to 4 digits and the subscriber, 8 to 11 digits, for
+	// fixed lines. Area codes do not start with 0 or 1.
+	national := digits[len(phoneCountryCode):]
+	if !isDigits(national) {
+		return "", ErrInvalidPhone
+	}
+
+	switch {
+	case national[0] == '8':
+		if len(national) < 9 || len(national) > 12 {
+			return "", ErrInvalidPhone
+		}
+	case national[0] >= '2':
+		if len(national) < 8 || len(national) > 11 {
+			return "", ErrInvalidPhone
+		}
+	default:
+		return "", ErrInvalidPhone
+	}
+
+	return digits, nil
+}
+
+// NormalizeNPWP removes the formatting of an NPWP and converts the 15 digit
+// format to the 16 digit one by prepending 0, e.g. "01.234.567.8-901.000"
+// becomes "0012345678901000".
+func NormalizeNPWP(npwp string) (string, error) {
+	digits := strip(npwp, " -.")
+	if !isDigits(digits) {
+		return "", ErrInvalidNPWP
+	}
+
+	switch len(digits) {
+	case npwpLength:
+		return digits, nil
+	case npwpLegacyLength:
+		return "0" + digits, nil
+	default:
+		return "", ErrInvalidNPWP
+	}
+}
+
+// LegacyPhone returns the local form of a normalized phone number, e.g.
+// "6281234567890" becomes "081234567890". Records stored before phone
+// numbers were normalized use it.
+func LegacyPhone(phone string) string {
+	if !strings.HasPrefix(phone, phoneCountryCode) {
+		return ""
+	}
+
+	return "0" + phone[len(phoneCountryCode):]
+}
+
+// LegacyNPWP returns the 15 digit form of a normalized NPWP, or "" when the
+// NPWP has none because it was issued in the 16 digit format.
+func LegacyNPWP(npwp string) string {
+	if len(npwp) != npwpLength || npwp[0] != '0' {
+		return ""
+	}
+
+	return npwp[1:]
+}
+
+// ParseNIK checks the structure of a NIK: the province and regency codes,
+// the birth date with the gender encoded in the day and the serial number.
+func ParseNIK(nik string) (*NIK, error) {
+	nik = strings.TrimSpace(nik)
+	if len(nik) != nikLength || !isDigits(nik) {
+		return nil, ErrInvalidNIK
+	}
+
+	parsed := &NIK{
+		Province: nik[0:2],
+		Regency:  nik[2:4],
+		District: nik[4:6],
+		Serial:   nik[12:16],
+		Gender:   GenderMale,
+	}
+
+	if _, ok := provinces[parsed.Province]; !ok {
+		return nil, fmt.Errorf("NIK has an unknown province code %s", parsed.Province)
+	}
+	if parsed.Regency == "00" || parsed.District == "00" {
+		return nil, errors.New("NIK has an invalid regency or district code")
+	}
+
+	parsed.BirthDay = atoi(nik[6:8])
+	parsed.BirthMonth = atoi(nik[8:10])
+	parsed.BirthYear = atoi(nik[10:12])
+	if parsed.BirthDay > femaleDayOffset {
+		parsed.BirthDay -= femaleDayOffset
+		parsed.Gender = GenderFemale
+	}
+
+	if !validDate(parsed.BirthDay, parsed.BirthMonth, parsed.BirthYear) {
+		return nil, errors.New("NIK has an invalid birth date")
+	}
+	if parsed.Serial == "0000" {
+		return nil, errors.New("NIK has an invalid serial number")
+	}
+
+	return parsed, nil
+}
+
+// NormalizeNIK trims the NIK and checks its structure.
+func NormalizeNIK(nik string) (string, error) {
+	if _, err := ParseNIK(nik); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(nik), nil
+}
+
+// validDate checks the day against the month, 29 February is allowed when
+// the two digit year is a leap year in either century.
+func validDate(day, month, year int) bool {
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+
+	days := [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if month == 2 && year%4 == 0 {
+		return day <= 29
+	}
+
+	return day <= days[month-1]
+}
+
+func strip(s, chars string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(chars, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// atoi converts a string already checked by isDigits.
+func atoi(s string) int {
+	n := 0
+	for _, r := range s {
+		n = n*10 + int(r-'0')
+	}
+
+	return n
+}
@@ -0,0 +1,183 @@
+package identifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{"081234567890", "6281234567890", false},
+		{"+62 812-3456-7890", "6281234567890", false},
+		{"6281234567890", "6281234567890", false},
+		{"81234567890", "6281234567890", false},
+		{"(0812) 345 678", "62812345678", false},
+		{"0215551234", "62215551234", false},
+		{"02155512345", "622155512345", false},
+		{"(0361) 123-456", "62361123456", false},
+		{"+62 21 5551 2345", "622155512345", false},
+		{"0112345678", "", true},
+		{"02123", "", true},
+		{"08123", "", true},
+		{"0812345678901234", "", true},
+		{"0812abc45678", "", true},
+		{"+1 415 555 0100", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizePhone(tt.input)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidPhone)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeNPWP(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{"0012345678901000", "0012345678901000", false},
+		{"012345678901000", "0012345678901000", false},
+		{"01.234.567.8-901.000", "0012345678901000", false},
+		{"01234567890100", "", true},
+		{"01234567890100A", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeNPWP(tt.input)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidNPWP)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLegacyForms(t *testing.T) {
+	assert.Equal(t, "081234567890", LegacyPhone("6281234567890"))
+	assert.Empty(t, LegacyPhone(""))
+	assert.Equal(t, "012345678901000", LegacyNPWP("0012345678901000"))
+	assert.Empty(t, LegacyNPWP("3201011501900001"))
+	assert.Empty(t, LegacyNPWP(""))
+}
+
+func TestParseNIK(t *testing.T) {
+	t.Run("male", func(t *testing.T) {
+		nik, err := ParseNIK("3201011203900001")
+		require.NoError(t, err)
+		assert.Equal(t, &NIK{
+			Province:   "32",
+			Regency:    "01",
+			District:   "01",
+			BirthDay:   12,
+			BirthMonth: 3,
+			BirthYear:  90,
+			Gender:     GenderMale,
+			Serial:     "0001",
+		}, nik)
+	})
+
+	t.Run("female", func(t *testing.T) {
+		nik, err := ParseNIK("3171055202880003")
+		require.NoError(t, err)
+		assert.Equal(t, GenderFemale, nik.Gender)
+		assert.Equal(t, 12, nik.BirthDay)
+		assert.Equal(t, 2, nik.BirthMonth)
+	})
+
+	t.Run("leap day", func(t *testing.T) {
+		_, err := ParseNIK("3201012902040001")
+		assert.NoError(t, err)
+	})
+
+	invalid := []struct {
+		name string
+		nik  string
+		msg  string
+	}{
+		{"length", "320101120390001", ErrInvalidNIK.Error()},
+		{"letters", "32010112039000A1", ErrInvalidNIK.Error()},
+		{"province", "9901011203900001", "NIK has an unknown province code 99"},
+		{"regency", "3200011203900001", "NIK has an invalid regency or district code"},
+		{"month", "3201011213900001", "NIK has an invalid birth date"},
+		{"day", "3201013102900001", "NIK has an invalid birth date"},
+		{"female day", "3201017203900001", "NIK has an invalid birth date"},
+		{"no leap day", "3201012902030001", "NIK has an invalid birth date"},
+		{"serial", "3201011203900000", "NIK has an invalid serial number"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNIK(tt.nik)
+			require.Error(t, err)
+			assert.Equal(t, tt.msg, err.Error())
+		})
+	}
+}
+
+type embedded struct {
+	NIK string `normalize:"nik"`
+}
+
+type request struct {
+	embedded
+	Phone  string `json:"phone_number" normalize:"phone"`
+	NPWP   string `json:"npwp" normalize:"npwp"`
+	LoanNo string `json:"loan_no"`
+}
+
+func TestNormalizeStruct(t *testing.T) {
+	t.Run("normalizes tagged fields", func(t *testing.T) {
+		req := &request{
+			embedded: embedded{NIK: " 3201011203900001 "},
+			Phone:    "0812-3456-7890",
+			NPWP:     "012345678901000",
+			LoanNo:   "0812",
+		}
+
+		require.NoError(t, NormalizeStruct(req))
+		assert.Equal(t, "3201011203900001", req.NIK)
+		assert.Equal(t, "6281234567890", req.Phone)
+		assert.Equal(t, "0012345678901000", req.NPWP)
+		assert.Equal(t, "0812", req.LoanNo)
+	})
+
+	t.Run("empty fields are skipped", func(t *testing.T) {
+		req := &request{}
+
+		require.NoError(t, NormalizeStruct(req))
+		assert.Equal(t, &request{}, req)
+	})
+
+	t.Run("invalid identifier", func(t *testing.T) {
+		req := &request{Phone: "0112345678"}
+
+		assert.ErrorIs(t, NormalizeStruct(req), ErrInvalidPhone)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		req := &struct {
+			Email string `normalize:"email"`
+		}{Email: "a@b.c"}
+
+		assert.Error(t, NormalizeStruct(req))
+	})
+}
@@ -0,0 +1,76 @@
+package identifier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const tagName = "normalize"
+
+// Kinds of identifier used as the value of the normalize tag.
+const (
+	KindPhone = "phone"
+	KindNPWP  = "npwp"
+	KindNIK   = "nik"
+)
+
+var normalizers = map[string]func(string) (string, error){
+	KindPhone: NormalizePhone,
+	KindNPWP:  NormalizeNPWP,
+	KindNIK:   NormalizeNIK,
+}
+
+// NormalizeStruct rewrites the string fields of the struct s points to that
+// carry a normalize tag, e.g.
+//
+//	Phone string `json:"phone_number" normalize:"phone"`
+//
+// Empty fields are left to the required validation. The first invalid
+// identifier is returned as the error.
+func NormalizeStruct(s any) error {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return normalizeFields(v)
+}
+
+func normalizeFields(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			if err := normalizeFields(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		kind, ok := field.Tag.Lookup(tagName)
+		if !ok || value.Kind() != reflect.String || !value.CanSet() || value.String() == "" {
+			continue
+		}
+
+		normalize, ok := normalizers[kind]
+		if !ok {
+			return fmt.Errorf("unknown identifier kind %q on field %s", kind, field.Name)
+		}
+
+		normalized, err := normalize(value.String())
+		if err != nil {
+			return err
+		}
+
+		value.SetString(normalized)
+	}
+
+	return nil
+}
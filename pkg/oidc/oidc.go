@@ -0,0 +1,329 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"front-office/pkg/httpclient"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const discoveryTTL = time.Hour
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNonceMismatch  = errors.New("id token nonce mismatch")
+	ErrUnknownKey     = errors.New("id token signed with unknown key")
+)
+
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type AuthCodeParams struct {
+	ClientId      string
+	RedirectURI   string
+	Scopes        []string
+	State         string
+	Nonce         string
+	CodeChallenge string
+}
+
+type ExchangeParams struct {
+	ClientId     string
+	ClientSecret string
+	RedirectURI  string
+	Code         string
+	CodeVerifier string
+}
+
+// Client resolves providers by issuer and caches their discovery document
+// and signing keys.
+type Client struct {
+	httpClient httpclient.HTTPClient
+	mu         sync.Mutex
+	providers  map[string]*Provider
+}
+
+type Provider struct {
+	Discovery
+	client    *Client
+	fetchedAt time.Time
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+func NewClient(httpClient httpclient.HTTPClient) *Client {
+	return &Client{
+		httpClient: httpClient,
+		providers:  make(map[string]*Provider),
+	}
+}
+
+func (c *Client) Provider(issuer string) (*Provider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.providers[issuer]; ok && time.Since(p.fetchedAt) < discoveryTTL {
+		return p, nil
+	}
+
+	var doc Discovery
+	if err := c.getJSON(issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: %s", doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is incomplete")
+	}
+
+	p := &Provider{
+		Discovery: doc,
+		client:    c,
+		fetchedAt: time.Now(),
+	}
+	c.providers[issuer] = p
+
+	return p, nil
+}
+
+func (p *Provider) AuthCodeURL(params *AuthCodeParams) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", params.ClientId)
+	q.Set("redirect_uri", params.RedirectURI)
+	q.Set("scope", strings.Join(params.Scopes, " "))
+	q.Set("state", params.State)
+	q.Set("nonce", params.Nonce)
+	q.Set("code_challenge", params.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return p.AuthorizationEndpoint + sep + q.Encode()
+}
+
+func (p *Provider) Exchange(params *ExchangeParams) (*TokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", params.Code)
+	form.Set("redirect_uri", params.RedirectURI)
+	form.Set("client_id", params.ClientId)
+	form.Set("client_secret", params.ClientSecret)
+	form.Set("code_verifier", params.CodeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, p.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return &token, nil
+}
+
+// VerifyIDToken checks signature, issuer, audience, expiry and nonce and
+// returns the token claims.
+func (p *Provider) VerifyIDToken(rawIDToken, clientId, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	token, err := jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+
+		kid, _ := t.Header["kid"].(string)
+
+		return p.key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+
+	if !claims.VerifyIssuer(p.Issuer, true) {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidIDToken)
+	}
+	if !claims.VerifyAudience(clientId, true) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidIDToken)
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: missing exp", ErrInvalidIDToken)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) key(kid string) (*rsa.PublicKey, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	p.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	// unknown kid, the provider may have rotated its keys
+	if err := p.refreshKeys(); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// tokens without kid are accepted only when the provider publishes a single key
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (p *Provider) refreshKeys() error {
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+
+	if err := p.client.getJSON(p.JWKSURI, &set); err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) getJSON(endpoint string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GeneratePKCE returns a code verifier and its S256 code challenge.
+func GeneratePKCE() (verifier, challenge string, err error) {
+	verifier, err = RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func RandomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package oidc_test
+
+import (
+	"front-office/pkg/httpclient"
+	"front-office/pkg/oidc"
+	"front-office/pkg/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientId     = "front-office"
+	clientSecret = "secret"
+	redirectURI  = "http://localhost/callback"
+)
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server := oidctest.NewServer(clientId, clientSecret)
+	defer server.Close()
+
+	server.SetClaims(map[string]any{"sub": "user-1", "email": "user@bank.co.id"})
+
+	client := oidc.NewClient(httpclient.NewDefaultClient(5 * time.Second))
+
+	provider, err := client.Provider(server.Issuer())
+	require.NoError(t, err)
+
+	verifier, challenge, err := oidc.GeneratePKCE()
+	require.NoError(t, err)
+
+	location, err := server.Authorize(provider.AuthCodeURL(&oidc.AuthCodeParams{
+		ClientId:      clientId,
+		RedirectURI:   redirectURI,
+		Scopes:        []string{"openid", "email"},
+		State:         "state-1",
+		Nonce:         "nonce-1",
+		CodeChallenge: challenge,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", location.Query().Get("state"))
+
+	exchange := &oidc.ExchangeParams{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		RedirectURI:  redirectURI,
+		Code:         location.Query().Get("code"),
+		CodeVerifier: verifier,
+	}
+
+	token, err := provider.Exchange(exchange)
+	require.NoError(t, err)
+
+	claims, err := provider.VerifyIDToken(token.IDToken, clientId, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user@bank.co.id", claims["email"])
+
+	t.Run("CodeCannotBeReused", func(t *testing.T) {
+		_, err := provider.Exchange(exchange)
+		assert.Error(t, err)
+	})
+
+	t.Run("NonceMismatch", func(t *testing.T) {
+		_, err := provider.VerifyIDToken(token.IDToken, clientId, "other-nonce")
+		assert.ErrorIs(t, err, oidc.ErrNonceMismatch)
+	})
+
+	t.Run("AudienceMismatch", func(t *testing.T) {
+		_, err := provider.VerifyIDToken(token.IDToken, "other-client", "nonce-1")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
+
+func TestPKCEVerifierMismatch(t *testing.T) {
+	server := oidctest.NewServer(clientId, clientSecret)
+	defer server.Close()
+
+	client := oidc.NewClient(httpclient.NewDefaultClient(5 * time.Second))
+	provider, err := client.Provider(server.Issuer())
+	require.NoError(t, err)
+
+	_, challenge, err := oidc.GeneratePKCE()
+	require.NoError(t, err)
+
+	location, err := server.Authorize(provider.AuthCodeURL(&oidc.AuthCodeParams{
+		ClientId:      clientId,
+		RedirectURI:   redirectURI,
+		State:         "state",
+		Nonce:         "nonce",
+		CodeChallenge: challenge,
+	}))
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(&oidc.ExchangeParams{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		RedirectURI:  redirectURI,
+		Code:         location.Query().Get("code"),
+		CodeVerifier: "wrong-verifier",
+	})
+	assert.Error(t, err)
+}
+
+func TestVerifyIDTokenRejectsExpiredAndForeignIssuer(t *testing.T) {
+	server := oidctest.NewServer(clientId, clientSecret)
+	defer server.Close()
+
+	client := oidc.NewClient(httpclient.NewDefaultClient(5 * time.Second))
+	provider, err := client.Provider(server.Issuer())
+	require.NoError(t, err)
+
+	expired, err := server.IssueIDToken(map[string]any{
+		"nonce": "n",
+		"exp":   time.Now().Add(-time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(expired, clientId, "n")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+
+	foreign, err := server.IssueIDToken(map[string]any{"nonce": "n", "iss": "https://evil.example"})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(foreign, clientId, "n")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 278.0, TextWidth(Helvetica, 1000, " "))
+	assert.Equal(t, 500.0, TextWidth(Helvetica, 1000, "z"))
+	assert.Equal(t, 584.0, TextWidth(Helvetica, 1000, "~"))
+	assert.Equal(t, 722.0, TextWidth(HelveticaBold, 1000, "A"))
+	assert.Equal(t, 584.0, TextWidth(HelveticaBold, 1000, "~"))
+	assert.Equal(t, 6.0, TextWidth(Courier, 10, "0"))
+	assert.Equal(t, TextWidth(Helvetica, 10, "?"), TextWidth(Helvetica, 10, "é"))
+}
+
+func TestDocumentBytes(t *testing.T) {
+	doc := New()
+	doc.Text(40, 40, HelveticaBold, 12, "Invoice (draft)")
+	doc.AddPage()
+	doc.TextRight(555, 40, Courier, 10, "1.000")
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 2")
+
+	// every xref entry points at the object it names
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)
+	require.NotNil(t, startxref)
+	xrefOffset, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xrefOffset:], -1)
+	require.Len(t, entries, 9)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")))
+	}
+
+	stream := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindSubmatch(data)
+	require.NotNil(t, stream)
+
+	zr, err := zlib.NewReader(bytes.NewReader(stream[1]))
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), `(Invoice \(draft\)) Tj`)
+}